@@ -10,7 +10,9 @@ import (
 	"github.com/onsi/ginkgo/v2/types"
 	"github.com/onsi/gomega"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
+	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
 	_ "github.com/redhat-appstudio/e2e-tests/tests/build"
 	_ "github.com/redhat-appstudio/e2e-tests/tests/byoc"
 	_ "github.com/redhat-appstudio/e2e-tests/tests/enterprise-contract"
@@ -29,15 +31,19 @@ import (
 
 var generateRPPreprocReport bool
 var rpPreprocDir string
+var timingReportPath string
+var htmlReportPath string
 
 func init() {
 	flag.BoolVar(&generateRPPreprocReport, "generate-rppreproc-report", false, "Generate report and folders for RP Preproc")
 	flag.StringVar(&rpPreprocDir, "rp-preproc-dir", ".", "Folder for RP Preproc")
+	flag.StringVar(&timingReportPath, "timing-report", "", "Path of a JSON file for storing per-step timings of executed specs")
+	flag.StringVar(&htmlReportPath, "html-report", "", "Path of a static HTML report with links to artifacts of failed specs")
 
 	klog.SetLogger(ginkgo.GinkgoLogr)
 
 	verbosity := 1
-	if v, err := strconv.ParseUint(os.Getenv("KLOG_VERBOSITY"), 10, 8); err == nil {
+	if v, err := strconv.ParseUint(config.Get("KLOG_VERBOSITY"), 10, 8); err == nil {
 		verbosity = int(v)
 	}
 
@@ -54,6 +60,24 @@ func TestE2E(t *testing.T) {
 	ginkgo.RunSpecs(t, "Red Hat App Studio E2E tests")
 }
 
+// One-time cluster setup runs on the first parallel process, its results are shared with all processes
+var _ = ginkgo.SynchronizedBeforeSuite(framework.RunSuiteSetup, framework.ReceiveSuiteSetup)
+
+// Every parallel process deletes idle users of its pools
+var _ = ginkgo.SynchronizedAfterSuite(framework.CloseUserPools, func() {})
+
+var _ = ginkgo.ReportAfterSuite("Step timing reporter", func(report types.Report) {
+	if timingReportPath == "" {
+		return
+	}
+	timingReport, err := framework.GenerateTimingReport(report, timingReportPath)
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+	klog.Info(timingReport.SlowestStepsSummary())
+})
+
 var _ = ginkgo.ReportAfterSuite("RP Preproc reporter", func(report types.Report) {
 	if generateRPPreprocReport {
 		//Generate Logs in dirs
@@ -69,3 +93,17 @@ var _ = ginkgo.ReportAfterSuite("RP Preproc reporter", func(report types.Report)
 		}
 	}
 })
+
+// HTML reporter has to be registered after the RP Preproc reporter, because the latter moves spec artifacts to its own folder structure
+var _ = ginkgo.ReportAfterSuite("HTML reporter", func(report types.Report) {
+	if htmlReportPath == "" {
+		return
+	}
+	artifactsDir := logs.ArtifactDir()
+	if generateRPPreprocReport {
+		artifactsDir = rpPreprocDir + "/rp_preproc/attachments/xunit"
+	}
+	if err := framework.GenerateHTMLReport(report, htmlReportPath, artifactsDir); err != nil {
+		klog.Error(err)
+	}
+})
@@ -21,6 +21,7 @@ import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	loadtestUtils "github.com/redhat-appstudio/e2e-tests/pkg/utils/loadtests"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 	integrationv1beta1 "github.com/redhat-appstudio/integration-service/api/v1beta1"
 	"github.com/spf13/cobra"
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -55,6 +56,7 @@ var (
 	enableProgressBars            bool
 	pushGatewayURI                string = ""
 	jobName                       string = ""
+	collectPipelineTraces         bool
 )
 
 var (
@@ -115,6 +117,8 @@ var (
 	pipelinesBarMutex                 = &sync.Mutex{}
 	deploymentsBarMutex               = &sync.Mutex{}
 	integrationTestsPipelinesBarMutex = &sync.Mutex{}
+	pipelineRunTracesMutex            = &sync.Mutex{}
+	pipelineRunTraces                 []*tekton.PipelineRunTrace
 	threadsWG                         *sync.WaitGroup
 	logData                           LogData
 	stageUsers                        []loadtestUtils.User
@@ -198,6 +202,8 @@ type LogData struct {
 
 	WorkloadKPI float64 `json:"workloadKPI"`
 
+	PipelineTaskDurations []tekton.TaskDurationStats `json:"pipelineTaskDurations,omitempty"`
+
 	ErrorCounts []ErrorCount      `json:"errorCounts"`
 	Errors      []ErrorOccurrence `json:"errors"`
 	ErrorsTotal int               `json:"errorsTotal"`
@@ -297,6 +303,7 @@ func init() {
 	rootCmd.Flags().BoolVar(&enableProgressBars, "enable-progress-bars", false, "if you want to enable progress bars")
 	rootCmd.Flags().StringVar(&pushGatewayURI, "pushgateway-url", pushGatewayURI, "PushGateway url (needs to be set if metrics are enabled)")
 	rootCmd.Flags().StringVar(&jobName, "job-name", jobName, "Job Name to track Metrics (needs to be set if metrics are enabled)")
+	rootCmd.Flags().BoolVar(&collectPipelineTraces, "collect-pipeline-traces", false, "if you want to collect timelines of successful build pipeline runs and per-task duration percentiles (stored in pipelineruns-trace.json)")
 }
 
 func logError(errCode int, message string) {
@@ -768,6 +775,10 @@ func setup(cmd *cobra.Command, args []string) {
 	logData.ErrorsTotal = len(logData.Errors)
 	klog.Infof("Total number of errors occured: %d", logData.ErrorsTotal)
 
+	if err := storePipelineRunTraces(fmt.Sprintf("%s/pipelineruns-trace.json", outputDir)); err != nil {
+		klog.Errorf("error while storing pipeline run traces: %v\n", err)
+	}
+
 	err = createLogDataJSON(fmt.Sprintf("%s/load-tests.json", outputDir), logData)
 	if err != nil {
 		klog.Errorf("error while marshalling JSON: %v\n", err)
@@ -1612,6 +1623,9 @@ func (h *ConcreteHandlerPipelines) validatePipeline(ctx *JourneyContext, framewo
 				}
 				SuccessfulPipelineRunsPerThread[threadIndex] += 1
 				MetricsWrapper(MetricsController, metricsConstants.CollectorPipelines, metricsConstants.MetricTypeCounter, metricsConstants.MetricSuccessfulPipelineRunsCreationCounter)
+				if collectPipelineTraces {
+					h.collectPipelineRunTrace(framework, pipelineRun)
+				}
 
 				chIntegrationTestsPipelines <- username
 			}
@@ -1665,6 +1679,33 @@ func (h *ConcreteHandlerPipelines) handlePVCS(threadIndex int, framework *framew
 	}
 }
 
+func (h *ConcreteHandlerPipelines) collectPipelineRunTrace(framework *framework.Framework, pipelineRun *pipeline.PipelineRun) {
+	trace, err := framework.AsKubeAdmin.TektonController.GetPipelineRunTrace(pipelineRun)
+	if err != nil {
+		logError(30, fmt.Sprintf("Error getting trace of PipelineRun %s/%s: %v\n", pipelineRun.Namespace, pipelineRun.Name, err))
+		return
+	}
+	pipelineRunTracesMutex.Lock()
+	defer pipelineRunTracesMutex.Unlock()
+	pipelineRunTraces = append(pipelineRunTraces, trace)
+}
+
+func storePipelineRunTraces(outputFile string) error {
+	pipelineRunTracesMutex.Lock()
+	defer pipelineRunTracesMutex.Unlock()
+	if len(pipelineRunTraces) == 0 {
+		return nil
+	}
+	logData.PipelineTaskDurations = tekton.AggregateTaskDurations(pipelineRunTraces...)
+	klog.Infof("Durations of pipeline tasks across %d pipeline runs:\n%s", len(pipelineRunTraces), tekton.FormatTaskDurationStats(logData.PipelineTaskDurations))
+
+	chromeTrace, err := tekton.ChromeTrace(pipelineRunTraces...)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, chromeTrace, 0644)
+}
+
 type ConcreteHandlerItsPipelines struct {
 	BaseHandler
 }
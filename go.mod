@@ -4,6 +4,7 @@ go 1.20
 
 require (
 	github.com/argoproj/argo-cd/v2 v2.8.3
+	github.com/argoproj/gitops-engine v0.7.1-0.20230607163028-425d65e07695
 	github.com/avast/retry-go/v4 v4.3.3
 	github.com/aws/aws-sdk-go-v2 v1.23.0
 	github.com/aws/aws-sdk-go-v2/config v1.19.1
@@ -51,6 +52,7 @@ require (
 	golang.org/x/tools v0.16.1
 	gopkg.in/yaml.v2 v2.4.0
 	k8s.io/api v0.28.5
+	k8s.io/apiextensions-apiserver v0.28.5
 	k8s.io/apimachinery v0.28.5
 	k8s.io/cli-runtime v0.28.5
 	k8s.io/client-go v1.5.2
@@ -117,7 +119,6 @@ require (
 	github.com/Microsoft/go-winio v0.6.1 // indirect
 	github.com/Microsoft/hcsshim v0.11.4 // indirect
 	github.com/ProtonMail/go-crypto v0.0.0-20230828082145-3c4c8a2d2371 // indirect
-	github.com/argoproj/gitops-engine v0.7.1-0.20230607163028-425d65e07695 // indirect
 	github.com/argoproj/pkg v0.13.7-0.20230626144333-d56162821bd1 // indirect
 	github.com/aws/aws-sdk-go v1.50.3 // indirect
 	github.com/aws/aws-sdk-go-v2/credentials v1.13.43 // indirect
@@ -319,7 +320,6 @@ require (
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
-	k8s.io/apiextensions-apiserver v0.28.5 // indirect
 	k8s.io/apiserver v0.28.5 // indirect
 	k8s.io/component-base v0.28.5 // indirect
 	k8s.io/component-helpers v0.28.5 // indirect
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+)
+
+const configDocsPath = "docs/Configuration.md"
+
+// Prints effective values of all settings with masked secrets and validates settings required by specs selected by E2E_TEST_SUITE_LABEL
+func (Config) Print() error {
+	cfg, err := config.Load(config.Get(config.E2ETestSuiteLabelEnv))
+	if cfg != nil {
+		if printErr := cfg.Print(os.Stdout); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
+
+// Generates docs/Configuration.md from settings declared in pkg/config
+func (Config) Docs() error {
+	if err := os.WriteFile(configDocsPath, []byte(config.Markdown()), 0644); err != nil {
+		return fmt.Errorf("error when writing %s: %v", configDocsPath, err)
+	}
+	return nil
+}
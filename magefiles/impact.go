@@ -0,0 +1,414 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/magefile/mage/sh"
+	"k8s.io/klog/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/magefiles/testspecs"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+)
+
+const (
+	modulePath = "github.com/redhat-appstudio/e2e-tests"
+	// controllerHubFile declares the ControllerHub, tests use clients through its fields without importing their packages
+	controllerHubFile = "pkg/framework/framework.go"
+)
+
+// frameworkRoots are the packages running and setting up all suites, they and all packages they import are framework-wide
+// (see frameworkPackages)
+var frameworkRoots = []string{"cmd", "cmd/mage", "pkg/framework"}
+
+// runAllPaths are paths outside of the framework-wide Go packages whose changes affect all suites. Patterns ending
+// with "/..." match all files under the directory, patterns without a slash match file names and other patterns
+// match a file or files of the Go package in the directory.
+var runAllPaths = []string{"go.mod", "go.sum", "Dockerfile", "Makefile", "default.env", "magefiles/..."}
+
+// ignoredPaths are paths whose changes don't affect any suite. They are matched after runAllPaths and the framework-wide
+// packages, so unit tests of framework-wide packages and the e2e entry point in cmd/ still run all suites.
+var ignoredPaths = []string{"docs/...", ".github/...", "tests/load-tests/...", "*.md", "OWNERS", "OWNERS_ALIASES", "LICENSE", "*_test.go"}
+
+// TestImpact is the result of the test impact analysis of changed files
+type TestImpact struct {
+	// RunAllReasons explain why changes affect all suites
+	RunAllReasons []string
+	// Labels of suites affected by the changed files
+	Labels map[string][]string
+}
+
+// RunAll returns true if changes affect all suites
+func (t *TestImpact) RunAll() bool {
+	return len(t.RunAllReasons) != 0
+}
+
+// SuiteLabels returns labels of all affected suites
+func (t *TestImpact) SuiteLabels() []string {
+	labels := []string{}
+	for _, l := range t.Labels {
+		labels = append(labels, l...)
+	}
+	return uniqueSorted(labels)
+}
+
+// LabelFilter narrows down the base label filter to the affected suites. The base filter is kept if changes
+// affect all suites or if they don't affect any suite.
+func (t *TestImpact) LabelFilter(baseFilter string) string {
+	labels := t.SuiteLabels()
+	if t.RunAll() || len(labels) == 0 {
+		return baseFilter
+	}
+	filter := strings.Join(labels, " || ")
+	if baseFilter == "" {
+		return filter
+	}
+	return fmt.Sprintf("(%s) && (%s)", filter, baseFilter)
+}
+
+// Print writes suites affected by each changed file
+func (t *TestImpact) Print(w io.Writer) error {
+	files := make([]string, 0, len(t.Labels))
+	for f := range t.Labels {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	lines := []string{}
+	for _, f := range files {
+		lines = append(lines, fmt.Sprintf("%s: %s", f, strings.Join(t.Labels[f], ", ")))
+	}
+	for _, r := range t.RunAllReasons {
+		lines = append(lines, "all suites: "+r)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "no suites are affected by the changes")
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// impactAnalyzer maps changed files to labels of the affected suites
+type impactAnalyzer struct {
+	// frameworkWide are directories of framework-wide Go packages (see frameworkPackages)
+	frameworkWide map[string]bool
+	// importers maps directories of Go packages to directories of test packages importing them
+	importers map[string][]string
+	// suiteLabels returns labels of suites described in the directory (see dirSuiteLabels)
+	suiteLabels func(dir string) []string
+}
+
+func (a *impactAnalyzer) analyze(files []string) *TestImpact {
+	impact := &TestImpact{Labels: map[string][]string{}}
+	for _, f := range files {
+		if matchesAnyPath(runAllPaths, f) || a.frameworkWide[path.Dir(f)] {
+			impact.RunAllReasons = append(impact.RunAllReasons, fmt.Sprintf("%s is framework-wide", f))
+			continue
+		}
+		if matchesAnyPath(ignoredPaths, f) {
+			continue
+		}
+		dir := path.Dir(f)
+		if !strings.HasPrefix(dir, "pkg/") && !strings.HasPrefix(dir, "tests/") {
+			impact.RunAllReasons = append(impact.RunAllReasons, fmt.Sprintf("%s is not in a package of the tests", f))
+			continue
+		}
+		labels := a.suiteLabels(dir)
+		for _, t := range a.importers[dir] {
+			labels = append(labels, a.suiteLabels(t)...)
+		}
+		if len(labels) == 0 {
+			impact.RunAllReasons = append(impact.RunAllReasons, fmt.Sprintf("%s is not mapped to any suite - its package doesn't describe labeled specs and no test package imports it", f))
+			continue
+		}
+		impact.Labels[f] = uniqueSorted(labels)
+	}
+	return impact
+}
+
+// matchesAnyPath returns true if the file matches any of the patterns (see runAllPaths)
+func matchesAnyPath(patterns []string, file string) bool {
+	for _, p := range patterns {
+		switch {
+		case strings.HasSuffix(p, "/..."):
+			if strings.HasPrefix(file, strings.TrimSuffix(p, "...")) {
+				return true
+			}
+		case !strings.Contains(p, "/"):
+			if ok, _ := path.Match(p, path.Base(file)); ok {
+				return true
+			}
+		case file == p || path.Dir(file) == p:
+			return true
+		}
+	}
+	return false
+}
+
+// frameworkPackages returns the framework roots and packages imported by them, directly or transitively. Packages
+// of the ControllerHub fields are not followed from pkg/framework, tests use them through the hub and usedControllers
+// maps them to the suites using them - unless they are imported by another framework-wide package.
+func frameworkPackages(imports map[string][]string, hub map[string]string) map[string]bool {
+	hubPackages := map[string]bool{}
+	for _, pkg := range hub {
+		hubPackages[pkg] = true
+	}
+	framework := map[string]bool{}
+	queue := append([]string{}, frameworkRoots...)
+	for _, root := range frameworkRoots {
+		framework[root] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range imports[current] {
+			if framework[dep] || (current == "pkg/framework" && hubPackages[dep]) {
+				continue
+			}
+			framework[dep] = true
+			queue = append(queue, dep)
+		}
+	}
+	return framework
+}
+
+// testImporters returns test packages importing each package of the module, directly or through fields of the ControllerHub.
+// Imports of framework-wide packages are not followed, changes of those packages affect all suites anyway.
+func testImporters(imports map[string][]string, frameworkWide map[string]bool) map[string][]string {
+	importers := map[string][]string{}
+	for pkg := range imports {
+		if !strings.HasPrefix(pkg, "tests/") {
+			continue
+		}
+		visited := map[string]bool{pkg: true}
+		queue := []string{pkg}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, dep := range imports[current] {
+				if visited[dep] || frameworkWide[dep] {
+					continue
+				}
+				visited[dep] = true
+				importers[dep] = append(importers[dep], pkg)
+				queue = append(queue, dep)
+			}
+		}
+	}
+	for dep := range importers {
+		sort.Strings(importers[dep])
+	}
+	return importers
+}
+
+// listImports returns module packages imported by each package of the module in the repository. Test packages
+// also import packages of the ControllerHub fields they use.
+func listImports(root string, hub map[string]string) (map[string][]string, error) {
+	output, err := sh.Output("go", "list", "-e", "-f", "{{.ImportPath}}{{range .Imports}} {{.}}{{end}}", root+"/...")
+	if err != nil {
+		return nil, fmt.Errorf("error when listing imports of packages: %v", err)
+	}
+	imports := map[string][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], modulePath+"/") {
+			continue
+		}
+		pkg := strings.TrimPrefix(fields[0], modulePath+"/")
+		imports[pkg] = []string{}
+		for _, i := range fields[1:] {
+			if strings.HasPrefix(i, modulePath+"/") {
+				imports[pkg] = append(imports[pkg], strings.TrimPrefix(i, modulePath+"/"))
+			}
+		}
+	}
+	for pkg := range imports {
+		if !strings.HasPrefix(pkg, "tests/") {
+			continue
+		}
+		used, err := usedControllers(filepath.Join(root, pkg), hub)
+		if err != nil {
+			return nil, err
+		}
+		imports[pkg] = append(imports[pkg], used...)
+	}
+	return imports, nil
+}
+
+// controllerHubPackages returns packages of the ControllerHub fields by field name
+func controllerHubPackages(file string) (map[string]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing %s: %v", file, err)
+	}
+	importPaths := map[string]string{}
+	for _, i := range f.Imports {
+		p, _ := strconv.Unquote(i.Path.Value)
+		name := path.Base(p)
+		if i.Name != nil {
+			name = i.Name.Name
+		}
+		importPaths[name] = p
+	}
+	packages := map[string]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "ControllerHub" {
+			return true
+		}
+		for _, field := range spec.Type.(*ast.StructType).Fields.List {
+			star, ok := field.Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			sel, ok := star.X.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok || !strings.HasPrefix(importPaths[pkg.Name], modulePath+"/") {
+				continue
+			}
+			for _, name := range field.Names {
+				packages[name.Name] = strings.TrimPrefix(importPaths[pkg.Name], modulePath+"/")
+			}
+		}
+		return false
+	})
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("ControllerHub not found in %s", file)
+	}
+	return packages, nil
+}
+
+// usedControllers returns packages of the ControllerHub fields used by the Go files in the directory
+func usedControllers(dir string, hub map[string]string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]*regexp.Regexp{}
+	for field := range hub {
+		fields[field] = regexp.MustCompile(`\.` + field + `\b`)
+	}
+	used := []string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading %s: %v", file, err)
+		}
+		for field, re := range fields {
+			if re.Match(data) {
+				used = append(used, hub[field])
+			}
+		}
+	}
+	return uniqueSorted(used), nil
+}
+
+// dirSuiteLabels returns a function returning suite labels of a directory, taken from the labels of the directory
+// or of its nearest parent directory with labels. The labels are read from the specs (see testspecs.PackageLabels)
+// instead of being declared e.g. in OWNERS files, where Prow reads labels of PRs changing the directory.
+func dirSuiteLabels(labels map[string][]string) func(dir string) []string {
+	return func(dir string) []string {
+		for ; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if l, ok := labels[dir]; ok {
+				return l
+			}
+		}
+		return nil
+	}
+}
+
+// analyzeTestImpact maps files changed against the base ref to affected suites
+func analyzeTestImpact(baseRef string) (*TestImpact, error) {
+	output, err := sh.Output("git", "diff", "--name-only", baseRef+"...HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("error when getting files changed against %s: %v", baseRef, err)
+	}
+	if strings.TrimSpace(output) == "" {
+		klog.Warningf("no files changed against %s, HEAD is probably not a descendant of the base of the changes", baseRef)
+	}
+	hub, err := controllerHubPackages(controllerHubFile)
+	if err != nil {
+		return nil, err
+	}
+	imports, err := listImports(".", hub)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := testspecs.PackageLabels("tests")
+	if err != nil {
+		return nil, fmt.Errorf("error when reading labels of specs: %v", err)
+	}
+	frameworkWide := frameworkPackages(imports, hub)
+	analyzer := &impactAnalyzer{frameworkWide: frameworkWide, importers: testImporters(imports, frameworkWide), suiteLabels: dirSuiteLabels(labels)}
+	return analyzer.analyze(strings.Fields(output)), nil
+}
+
+// setTestImpactLabelFilter narrows down the tests of e2e-tests PRs to suites affected by the PR, changes are compared
+// to the base commit of the PR provided by the CI system. All suites run if the PR is paired with PRs of other
+// repositories or if the CI system doesn't provide the base commit.
+func setTestImpactLabelFilter() error {
+	for _, repo := range pairingManifest.Repos() {
+		if repo != "e2e-tests" {
+			klog.Infof("running all suites, the PR is paired with a PR of %s repository", repo)
+			return nil
+		}
+	}
+	if paired := pairedPRLookup("infra-deployments"); paired != nil {
+		klog.Infof("running all suites, the PR is paired with infra-deployments PR #%d", paired.Number)
+		return nil
+	}
+	if pr.BaseSHA == "" {
+		klog.Warningf("running all suites, %s doesn't provide the base commit of the tested PR", ciProvider.Name())
+		return nil
+	}
+	impact, err := analyzeTestImpact(pr.BaseSHA)
+	if err != nil {
+		klog.Warningf("running all suites, test impact analysis failed: %v", err)
+		return nil
+	}
+	if err := impact.Print(os.Stdout); err != nil {
+		return err
+	}
+	labelFilter := impact.LabelFilter(config.Get(config.E2ETestSuiteLabelEnv))
+	klog.Infof("running tests selected by label filter %q", labelFilter)
+	os.Setenv(config.E2ETestSuiteLabelEnv, labelFilter)
+	return nil
+}
+
+func uniqueSorted(items []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, i := range items {
+		if !seen[i] {
+			seen[i] = true
+			unique = append(unique, i)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// Prints suites affected by changes of the current branch against E2E_TEST_IMPACT_BASE_REF and the label filter selecting them
+func (Local) TestImpact() error {
+	impact, err := analyzeTestImpact(config.Get(config.TestImpactBaseRefEnv))
+	if err != nil {
+		return err
+	}
+	if err := impact.Print(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println(impact.LabelFilter(config.Get(config.E2ETestSuiteLabelEnv)))
+	return nil
+}
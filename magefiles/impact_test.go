@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/e2e-tests/magefiles/testspecs"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestImpact(t *testing.T) {
+	imports := map[string][]string{
+		"tests/build":             {"pkg/framework", "pkg/utils/build"},
+		"tests/release":           {"pkg/framework"},
+		"tests/release/service":   {"tests/release", "pkg/clients/release"},
+		"tests/release/pipelines": {"tests/release", "pkg/clients/release", "pkg/utils/build"},
+		"pkg/framework":           {"pkg/clients/release", "pkg/clients/spi", "pkg/suites"},
+		"pkg/suites":              {"pkg/clients/kubernetes"},
+		"pkg/utils/build":         {"pkg/clients/has"},
+	}
+	frameworkWide := frameworkPackages(imports, map[string]string{"ReleaseController": "pkg/clients/release", "SPIController": "pkg/clients/spi"})
+	assert.Equal(t, map[string]bool{"cmd": true, "cmd/mage": true, "pkg/framework": true, "pkg/suites": true, "pkg/clients/kubernetes": true}, frameworkWide)
+	importers := testImporters(imports, frameworkWide)
+	assert.Equal(t, []string{"tests/release/pipelines", "tests/release/service"}, importers["pkg/clients/release"])
+	assert.Equal(t, []string{"tests/build", "tests/release/pipelines"}, importers["pkg/clients/has"])
+	// imports of the framework are not followed
+	assert.Empty(t, importers["pkg/clients/spi"])
+
+	labels := map[string][]string{
+		"tests/build":             {"build", "jvm-build"},
+		"tests/release":           {"release-service", "release-pipelines"},
+		"tests/release/service":   {"release-service"},
+		"tests/release/pipelines": {"release-pipelines"},
+	}
+	analyzer := &impactAnalyzer{frameworkWide: frameworkWide, importers: importers, suiteLabels: func(dir string) []string { return labels[dir] }}
+
+	impact := analyzer.analyze([]string{"tests/release/service/happy_path.go", "pkg/clients/has/components.go", "docs/Guidelines.md", "pkg/utils/tekton/bundles_test.go"})
+	assert.False(t, impact.RunAll())
+	assert.Equal(t, map[string][]string{
+		"tests/release/service/happy_path.go": {"release-service"},
+		"pkg/clients/has/components.go":       {"build", "jvm-build", "release-pipelines"},
+	}, impact.Labels)
+	assert.Equal(t, "(build || jvm-build || release-pipelines || release-service) && (!upgrade-create)", impact.LabelFilter("!upgrade-create"))
+	assert.Equal(t, "build || jvm-build || release-pipelines || release-service", impact.LabelFilter(""))
+
+	impact = analyzer.analyze([]string{"tests/release/quay.go", "pkg/framework/describe.go", "pkg/suites/suites.go", "scripts/install.sh", "pkg/clients/slack/slack.go"})
+	assert.True(t, impact.RunAll())
+	assert.Equal(t, []string{
+		"pkg/framework/describe.go is framework-wide",
+		"pkg/suites/suites.go is framework-wide",
+		"scripts/install.sh is not in a package of the tests",
+		"pkg/clients/slack/slack.go is not mapped to any suite - its package doesn't describe labeled specs and no test package imports it",
+	}, impact.RunAllReasons)
+	assert.Equal(t, config.DefaultLabelFilter, impact.LabelFilter(config.DefaultLabelFilter))
+
+	// the e2e entry point is framework-wide although it's a test file
+	impact = analyzer.analyze([]string{"cmd/e2e_test.go", "pkg/framework/describe_test.go"})
+	assert.Equal(t, []string{"cmd/e2e_test.go is framework-wide", "pkg/framework/describe_test.go is framework-wide"}, impact.RunAllReasons)
+
+	// changes not affecting any suite keep the base filter
+	impact = analyzer.analyze([]string{"README.md", "tests/load-tests/loadtest.go"})
+	assert.False(t, impact.RunAll())
+	assert.Equal(t, config.DefaultLabelFilter, impact.LabelFilter(config.DefaultLabelFilter))
+	out := &strings.Builder{}
+	assert.NoError(t, impact.Print(out))
+	assert.Equal(t, "no suites are affected by the changes\n", out.String())
+}
+
+func TestMatchesAnyPath(t *testing.T) {
+	assert.True(t, matchesAnyPath(runAllPaths, "go.mod"))
+	assert.True(t, matchesAnyPath(runAllPaths, "magefiles/installation/install.go"))
+	assert.False(t, matchesAnyPath(runAllPaths, "pkg/utils/tekton/bundles.go"))
+	assert.True(t, matchesAnyPath(ignoredPaths, "tests/build/OWNERS"))
+	assert.True(t, matchesAnyPath(ignoredPaths, "pkg/utils/tekton/bundles_test.go"))
+	assert.False(t, matchesAnyPath(ignoredPaths, "tests/build/build.go"))
+}
+
+func TestControllerHubPackages(t *testing.T) {
+	hub, err := controllerHubPackages(filepath.Join("..", controllerHubFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "pkg/clients/release", hub["ReleaseController"])
+	assert.Equal(t, "pkg/clients/common", hub["CommonController"])
+
+	used, err := usedControllers(filepath.Join("..", "tests", "enterprise-contract"), hub)
+	assert.NoError(t, err)
+	assert.Contains(t, used, "pkg/clients/tekton")
+	assert.NotContains(t, used, "pkg/clients/release")
+}
+
+// Packages imported by the framework run all suites, the other packages run only suites of test packages importing them
+func TestFrameworkPackages(t *testing.T) {
+	hub, err := controllerHubPackages(filepath.Join("..", controllerHubFile))
+	assert.NoError(t, err)
+	imports, err := listImports("..", hub)
+	assert.NoError(t, err)
+	frameworkWide := frameworkPackages(imports, hub)
+	for _, pkg := range []string{"cmd", "pkg/framework", "pkg/suites", "pkg/config", "pkg/ci", "pkg/utils", "pkg/clients/kubernetes"} {
+		assert.True(t, frameworkWide[pkg], "%s is not framework-wide", pkg)
+	}
+	for _, pkg := range []string{"tests/build", "tests/release", "pkg/clients/release", "pkg/clients/spi"} {
+		assert.False(t, frameworkWide[pkg], "%s is framework-wide", pkg)
+	}
+
+	importers := testImporters(imports, frameworkWide)
+	assert.Contains(t, importers["pkg/clients/release"], "tests/release/service")
+	assert.Contains(t, importers["tests/release"], "tests/release/pipelines")
+	assert.NotContains(t, importers["pkg/clients/release"], "tests/byoc")
+}
+
+// Every test package has to describe labeled specs or be imported by such a package, the labels have to select some suite
+func TestTestSuiteLabels(t *testing.T) {
+	packageLabels, err := testspecs.PackageLabels(testsDir)
+	assert.NoError(t, err)
+	labels := map[string][]string{}
+	for dir, l := range packageLabels {
+		labels[strings.TrimPrefix(dir, "../")] = l
+	}
+	suiteLabels := dirSuiteLabels(labels)
+	assert.Equal(t, []string{"release-service"}, suiteLabels("tests/release/service"))
+	assert.Equal(t, []string{"build", "jvm-build", "multi-platform"}, suiteLabels("tests/build/testdata"))
+	assert.Equal(t, []string{"upgrade-cleanup", "upgrade-create", "upgrade-verify"}, suiteLabels("tests/upgrade"))
+	assert.Empty(t, suiteLabels("tests/release"))
+	assert.Empty(t, suiteLabels("pkg/clients/release"))
+
+	hub, err := controllerHubPackages(filepath.Join("..", controllerHubFile))
+	assert.NoError(t, err)
+	imports, err := listImports("..", hub)
+	assert.NoError(t, err)
+	frameworkWide := frameworkPackages(imports, hub)
+	analyzer := &impactAnalyzer{frameworkWide: frameworkWide, importers: testImporters(imports, frameworkWide), suiteLabels: suiteLabels}
+	specLabels, err := testspecs.SpecLabels(testsDir)
+	assert.NoError(t, err)
+
+	err = filepath.Walk(testsDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(p, ".go") {
+			return err
+		}
+		file := filepath.ToSlash(strings.TrimPrefix(p, "../"))
+		if matchesAnyPath(ignoredPaths, file) {
+			return nil
+		}
+		impact := analyzer.analyze([]string{file})
+		assert.False(t, impact.RunAll(), "%s is not mapped to any suite: %v", file, impact.RunAllReasons)
+		for _, l := range impact.Labels[file] {
+			selected, err := suites.Selected(l, specLabels)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, selected, "label %s of %s doesn't select any suite", l, file)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
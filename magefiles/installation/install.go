@@ -3,13 +3,10 @@ package installation
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
 
-	"strings"
-
 	appsv1 "k8s.io/api/apps/v1"
 
 	"github.com/devfile/library/v2/pkg/util"
@@ -18,7 +15,9 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+	e2eConfig "github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
@@ -44,12 +43,6 @@ var (
 	previewInstallArgs = []string{"preview", "--keycloak", "--toolchain"}
 )
 
-type patchStringValue struct {
-	Op    string `json:"op"`
-	Path  string `json:"path"`
-	Value string `json:"value"`
-}
-
 type InstallAppStudio struct {
 	// Kubernetes Client to interact with Openshift Cluster
 	KubernetesClient *kubeCl.CustomClient
@@ -66,6 +59,9 @@ type InstallAppStudio struct {
 	// Github organization from where will be cloned
 	InfraDeploymentsOrganizationName string
 
+	// PRs merged in order into the cloned infra-deployments branch
+	InfraDeploymentsPullRequests []*ci.PullRequest
+
 	// Desired fork name for testing
 	LocalForkName string
 
@@ -100,14 +96,14 @@ func NewAppStudioInstallController() (*InstallAppStudio, error) {
 		KubernetesClient:                 k8sClient,
 		TmpDirectory:                     DEFAULT_TMP_DIR,
 		InfraDeploymentsCloneDir:         fmt.Sprintf("%s/%s/infra-deployments", cwd, DEFAULT_TMP_DIR),
-		InfraDeploymentsBranch:           utils.GetEnv("INFRA_DEPLOYMENTS_BRANCH", DEFAULT_INFRA_DEPLOYMENTS_BRANCH),
-		InfraDeploymentsOrganizationName: utils.GetEnv("INFRA_DEPLOYMENTS_ORG", DEFAULT_INFRA_DEPLOYMENTS_GH_ORG),
+		InfraDeploymentsBranch:           e2eConfig.Get("INFRA_DEPLOYMENTS_BRANCH"),
+		InfraDeploymentsOrganizationName: e2eConfig.Get("INFRA_DEPLOYMENTS_ORG"),
 		LocalForkName:                    DEFAULT_LOCAL_FORK_NAME,
-		LocalGithubForkOrganization:      utils.GetEnv("MY_GITHUB_ORG", DEFAULT_LOCAL_FORK_ORGANIZATION),
-		QuayToken:                        utils.GetEnv("QUAY_TOKEN", ""),
-		DefaultImageQuayOrg:              utils.GetEnv("DEFAULT_QUAY_ORG", DEFAULT_E2E_QUAY_ORG),
-		DefaultImageQuayOrgOAuth2Token:   utils.GetEnv("DEFAULT_QUAY_ORG_TOKEN", ""),
-		DefaultImageTagExpiration:        utils.GetEnv(constants.IMAGE_TAG_EXPIRATION_ENV, constants.DefaultImageTagExpiration),
+		LocalGithubForkOrganization:      e2eConfig.Get(constants.GITHUB_E2E_ORGANIZATION_ENV),
+		QuayToken:                        e2eConfig.Get("QUAY_TOKEN"),
+		DefaultImageQuayOrg:              e2eConfig.Get(constants.DEFAULT_QUAY_ORG_ENV),
+		DefaultImageQuayOrgOAuth2Token:   e2eConfig.Get("DEFAULT_QUAY_ORG_TOKEN"),
+		DefaultImageTagExpiration:        e2eConfig.Get(constants.IMAGE_TAG_EXPIRATION_ENV),
 	}, nil
 }
 
@@ -126,11 +122,11 @@ func NewAppStudioInstallControllerUpgrade(infraFork string, infraBranch string)
 		InfraDeploymentsBranch:           infraBranch,
 		InfraDeploymentsOrganizationName: infraFork,
 		LocalForkName:                    DEFAULT_LOCAL_FORK_NAME,
-		LocalGithubForkOrganization:      utils.GetEnv("MY_GITHUB_ORG", DEFAULT_LOCAL_FORK_ORGANIZATION),
-		QuayToken:                        utils.GetEnv("QUAY_TOKEN", ""),
-		DefaultImageQuayOrg:              utils.GetEnv("DEFAULT_QUAY_ORG", ""),
-		DefaultImageQuayOrgOAuth2Token:   utils.GetEnv("DEFAULT_QUAY_ORG_TOKEN", ""),
-		DefaultImageTagExpiration:        utils.GetEnv(constants.IMAGE_TAG_EXPIRATION_ENV, constants.DefaultImageTagExpiration),
+		LocalGithubForkOrganization:      e2eConfig.Get(constants.GITHUB_E2E_ORGANIZATION_ENV),
+		QuayToken:                        e2eConfig.Get("QUAY_TOKEN"),
+		DefaultImageQuayOrg:              e2eConfig.Get(constants.DEFAULT_QUAY_ORG_ENV),
+		DefaultImageQuayOrgOAuth2Token:   e2eConfig.Get("DEFAULT_QUAY_ORG_TOKEN"),
+		DefaultImageTagExpiration:        e2eConfig.Get(constants.IMAGE_TAG_EXPIRATION_ENV),
 	}, nil
 }
 
@@ -139,6 +135,9 @@ func (i *InstallAppStudio) InstallAppStudioPreviewMode() error {
 	if _, err := i.cloneInfraDeployments(); err != nil {
 		return err
 	}
+	if err := ci.MergePullRequests(i.InfraDeploymentsCloneDir, i.InfraDeploymentsPullRequests); err != nil {
+		return err
+	}
 	i.setInstallationEnvironments()
 
 	if err := utils.ExecuteCommandInASpecificDirectory("hack/bootstrap-cluster.sh", previewInstallArgs, i.InfraDeploymentsCloneDir); err != nil {
@@ -152,15 +151,15 @@ func (i *InstallAppStudio) InstallAppStudioPreviewMode() error {
 
 func (i *InstallAppStudio) setInstallationEnvironments() {
 	os.Setenv("MY_GITHUB_ORG", i.LocalGithubForkOrganization)
-	os.Setenv("MY_GITHUB_TOKEN", utils.GetEnv("GITHUB_TOKEN", ""))
+	os.Setenv("MY_GITHUB_TOKEN", e2eConfig.Get(constants.GITHUB_TOKEN_ENV))
 	os.Setenv("MY_GIT_FORK_REMOTE", i.LocalForkName)
 	os.Setenv("TEST_BRANCH_ID", util.GenerateRandomString(4))
 	os.Setenv("QUAY_TOKEN", i.QuayToken)
 	os.Setenv("IMAGE_CONTROLLER_QUAY_ORG", i.DefaultImageQuayOrg)
 	os.Setenv("IMAGE_CONTROLLER_QUAY_TOKEN", i.DefaultImageQuayOrgOAuth2Token)
 	os.Setenv("BUILD_SERVICE_IMAGE_TAG_EXPIRATION", i.DefaultImageTagExpiration)
-	os.Setenv("PAC_GITHUB_APP_ID", utils.GetEnv("E2E_PAC_GITHUB_APP_ID", ""))                   // #nosec G104
-	os.Setenv("PAC_GITHUB_APP_PRIVATE_KEY", utils.GetEnv("E2E_PAC_GITHUB_APP_PRIVATE_KEY", "")) // #nosec G104
+	os.Setenv("PAC_GITHUB_APP_ID", e2eConfig.Get("E2E_PAC_GITHUB_APP_ID"))                   // #nosec G104
+	os.Setenv("PAC_GITHUB_APP_PRIVATE_KEY", e2eConfig.Get("E2E_PAC_GITHUB_APP_PRIVATE_KEY")) // #nosec G104
 }
 
 func (i *InstallAppStudio) cloneInfraDeployments() (*git.Remote, error) {
@@ -187,80 +186,35 @@ func (i *InstallAppStudio) cloneInfraDeployments() (*git.Remote, error) {
 	return repo.CreateRemote(&config.RemoteConfig{Name: i.LocalForkName, URLs: []string{fmt.Sprintf("https://github.com/%s/infra-deployments.git", i.LocalGithubForkOrganization)}})
 }
 
-func (i *InstallAppStudio) CheckOperatorsReady() (err error) {
+// CheckOperatorsReady waits until all ArgoCD Applications are synced and healthy, see ReadinessChecker
+func (i *InstallAppStudio) CheckOperatorsReady() error {
 	apiConfig, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
 	if err != nil {
-		klog.Fatal(err)
+		return fmt.Errorf("error when loading kubeconfig: %v", err)
 	}
 	config, err := clientcmd.NewDefaultClientConfig(*apiConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
 	if err != nil {
-		klog.Fatal(err)
-	}
-	appClientset := appclientset.NewForConfigOrDie(config)
-
-	patchPayload := []patchStringValue{{
-		Op:    "replace",
-		Path:  "/metadata/annotations/argocd.argoproj.io~1refresh",
-		Value: "hard",
-	}}
-	patchPayloadBytes, err := json.Marshal(patchPayload)
-	if err != nil {
-		klog.Fatal(err)
+		return fmt.Errorf("error when creating client config: %v", err)
 	}
-	_, err = appClientset.ArgoprojV1alpha1().Applications("openshift-gitops").Patch(context.Background(), "all-application-sets", types.JSONPatchType, patchPayloadBytes, metav1.PatchOptions{})
+	appClientset, err := appclientset.NewForConfig(config)
 	if err != nil {
-		klog.Fatal(err)
+		return fmt.Errorf("error when creating ArgoCD client: %v", err)
 	}
 
-	for {
-		var count = 0
-		appsListFor, err := appClientset.ArgoprojV1alpha1().Applications("openshift-gitops").List(context.Background(), metav1.ListOptions{})
-		for _, app := range appsListFor.Items {
-			fmt.Printf("Check application: %s\n", app.Name)
-			application, err := appClientset.ArgoprojV1alpha1().Applications("openshift-gitops").Get(context.Background(), app.Name, metav1.GetOptions{})
-			if err != nil {
-				klog.Fatal(err)
-			}
-
-			if !(application.Status.Sync.Status == "Synced" && application.Status.Health.Status == "Healthy") {
-				klog.Infof("Application %s not ready", app.Name)
-				count++
-			} else if strings.Contains(application.String(), ("context deadline exceeded")) {
-				fmt.Printf("Refreshing Application %s\n", app.Name)
-				patchPayload := []patchStringValue{{
-					Op:    "replace",
-					Path:  "/metadata/annotations/argocd.argoproj.io~1refresh",
-					Value: "soft",
-				}}
-
-				patchPayloadBytes, err := json.Marshal(patchPayload)
-				if err != nil {
-					klog.Fatal(err)
-				}
-				for _, app := range appsListFor.Items {
-					_, err = i.KubernetesClient.KubeInterface().AppsV1().Deployments("openshift-gitops").Patch(context.Background(), app.Name, types.JSONPatchType, patchPayloadBytes, metav1.PatchOptions{})
-					if err != nil {
-						klog.Fatal(err)
-					}
-				}
-			}
-		}
-		if err != nil {
-			klog.Fatal(err)
-		}
-
-		if count == 0 {
-			klog.Info("All Application are ready\n")
-			break
+	report, err := NewReadinessChecker(appClientset).Check(context.Background())
+	if err != nil {
+		if report != nil && len(report.NotReady()) > 0 {
+			return fmt.Errorf("ArgoCD applications %v are not ready: %v", report.NotReady(), err)
 		}
-		time.Sleep(10 * time.Second)
+		return err
 	}
-	return err
+	klog.Info("All Applications are ready")
+	return nil
 }
 
 // Create secret in e2e-secrets which can be copied to testing namespaces
 func (i *InstallAppStudio) createE2EQuaySecret() error {
-	quayToken := os.Getenv("QUAY_TOKEN")
+	quayToken := e2eConfig.Get("QUAY_TOKEN")
 	if quayToken == "" {
 		return fmt.Errorf("failed to obtain quay token from 'QUAY_TOKEN' env; make sure the env exists")
 	}
@@ -322,7 +276,7 @@ func (i *InstallAppStudio) createE2EQuaySecret() error {
 
 // Update spi-oauth-service-environment-config to add OAUTH_REDIRECT_PROXY_URL property for oauth tests
 func (i *InstallAppStudio) addSPIOauthRedirectProxyUrl() {
-	OauthRedirectProxyUrl := os.Getenv("OAUTH_REDIRECT_PROXY_URL")
+	OauthRedirectProxyUrl := e2eConfig.Get("OAUTH_REDIRECT_PROXY_URL")
 	if OauthRedirectProxyUrl == "" {
 		klog.Error("OAUTH_REDIRECT_PROXY_URL not set: not updating spi configuration")
 		return
@@ -0,0 +1,325 @@
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argoclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	argoCDNamespace = "openshift-gitops"
+	// rootApplication generates the ApplicationSets of all RHTAP components
+	rootApplication = "all-application-sets"
+	// ReadinessReportName is the name of the readiness report files (with .json and .md extensions) in the artifact dir
+	ReadinessReportName = "argocd-readiness-report"
+)
+
+// ReadinessChecker waits until all ArgoCD Applications are synced and healthy. Applications which are not ready
+// for a while are refreshed, with a growing delay between refreshes of the same Application.
+type ReadinessChecker struct {
+	// Namespace of the ArgoCD Applications
+	Namespace string
+	// RootApplication is hard refreshed before the check, so it picks up the latest changes; no refresh if empty
+	RootApplication string
+	// Timeout of the whole check
+	Timeout time.Duration
+	// Interval between two checks of the Applications
+	Interval time.Duration
+	// RefreshAfter is how long an Application may be not ready before it's refreshed,
+	// it doubles after each refresh of the Application up to MaxRefreshAfter
+	RefreshAfter    time.Duration
+	MaxRefreshAfter time.Duration
+	// ArtifactDir is the directory the readiness report is written to, no report is written if empty
+	ArtifactDir string
+	// Out is the writer the progress is logged to
+	Out io.Writer
+
+	client    argoclientset.Interface
+	refreshes map[string]*refreshState
+	now       func() time.Time
+}
+
+// refreshState tracks refreshes of a not ready Application
+type refreshState struct {
+	count int
+	delay time.Duration
+	next  time.Time
+}
+
+// ReadinessReport describes the state of ArgoCD Applications at the end of a readiness check
+type ReadinessReport struct {
+	Ready        bool                   `json:"ready"`
+	Duration     string                 `json:"duration"`
+	Applications []ApplicationReadiness `json:"applications"`
+}
+
+// ApplicationReadiness describes the health and sync state of an ArgoCD Application
+type ApplicationReadiness struct {
+	Name              string              `json:"name"`
+	Ready             bool                `json:"ready"`
+	SyncStatus        string              `json:"syncStatus"`
+	HealthStatus      string              `json:"healthStatus"`
+	HealthMessage     string              `json:"healthMessage,omitempty"`
+	OperationPhase    string              `json:"operationPhase,omitempty"`
+	OperationMessage  string              `json:"operationMessage,omitempty"`
+	Conditions        []string            `json:"conditions,omitempty"`
+	DegradedResources []ResourceReadiness `json:"degradedResources,omitempty"`
+	Refreshes         int                 `json:"refreshes,omitempty"`
+}
+
+// ResourceReadiness describes a resource of an Application which is not synced or not healthy
+type ResourceReadiness struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace,omitempty"`
+	Name          string `json:"name"`
+	SyncStatus    string `json:"syncStatus"`
+	HealthStatus  string `json:"healthStatus,omitempty"`
+	HealthMessage string `json:"healthMessage,omitempty"`
+}
+
+// NewReadinessChecker returns a checker of Applications in openshift-gitops namespace with a 30 minutes timeout,
+// writing the report to the artifact dir of the CI job
+func NewReadinessChecker(client argoclientset.Interface) *ReadinessChecker {
+	return &ReadinessChecker{
+		Namespace:       argoCDNamespace,
+		RootApplication: rootApplication,
+		Timeout:         30 * time.Minute,
+		Interval:        10 * time.Second,
+		RefreshAfter:    2 * time.Minute,
+		MaxRefreshAfter: 10 * time.Minute,
+		ArtifactDir:     ci.Detect().ArtifactDir(),
+		Out:             os.Stdout,
+		client:          client,
+		now:             time.Now,
+	}
+}
+
+// Check waits until all Applications are ready. The report describes the last observed state of the Applications,
+// it's returned (and written to the artifact dir) even if they are not ready before the timeout.
+func (c *ReadinessChecker) Check(ctx context.Context) (*ReadinessReport, error) {
+	start := c.now()
+	c.refreshes = map[string]*refreshState{}
+	if c.RootApplication != "" {
+		if err := c.refresh(ctx, c.RootApplication, v1alpha1.RefreshTypeHard); err != nil && !k8sErrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	report := &ReadinessReport{}
+	poller := utils.NewPoller(fmt.Sprintf("ArgoCD applications in %s namespace to be synced and healthy", c.Namespace), c.Timeout).
+		WithInterval(c.Interval).
+		WithOutput(c.Out)
+	poller.Summary = func(state interface{}) string {
+		return state.(*ReadinessReport).summary()
+	}
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, interface{}, error) {
+		apps, err := c.client.ArgoprojV1alpha1().Applications(c.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, nil, fmt.Errorf("error when listing applications: %v", err)
+		}
+		report = c.newReport(apps.Items)
+		return report.Ready, report, c.refreshNotReady(ctx, apps.Items)
+	})
+	report.Duration = c.now().Sub(start).Round(time.Second).String()
+
+	if c.ArtifactDir != "" {
+		if writeErr := report.write(c.ArtifactDir); writeErr != nil {
+			fmt.Fprintf(c.Out, "failed to write ArgoCD readiness report: %v\n", writeErr)
+		}
+	}
+	return report, err
+}
+
+// refreshNotReady refreshes Applications which have not been ready for longer than their refresh delay.
+// Applications with a comparison error are hard refreshed, to regenerate their manifests.
+func (c *ReadinessChecker) refreshNotReady(ctx context.Context, apps []v1alpha1.Application) error {
+	now := c.now()
+	for _, app := range apps {
+		if isApplicationReady(&app) {
+			continue
+		}
+		state, ok := c.refreshes[app.Name]
+		if !ok {
+			state = &refreshState{delay: c.RefreshAfter, next: now.Add(c.RefreshAfter)}
+			c.refreshes[app.Name] = state
+		}
+		if now.Before(state.next) || isOperationRunning(&app) {
+			continue
+		}
+
+		refreshType := v1alpha1.RefreshTypeNormal
+		if hasCondition(&app, v1alpha1.ApplicationConditionComparisonError) {
+			refreshType = v1alpha1.RefreshTypeHard
+		}
+		if err := c.refresh(ctx, app.Name, refreshType); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "requested %s refresh of application %s\n", refreshType, app.Name)
+
+		state.count++
+		state.delay *= 2
+		if state.delay > c.MaxRefreshAfter {
+			state.delay = c.MaxRefreshAfter
+		}
+		state.next = now.Add(state.delay)
+	}
+	return nil
+}
+
+func (c *ReadinessChecker) refresh(ctx context.Context, name string, refreshType v1alpha1.RefreshType) error {
+	patch := []byte(fmt.Sprintf(`{"metadata": {"annotations": {%q: %q}}}`, v1alpha1.AnnotationKeyRefresh, refreshType))
+	if _, err := c.client.ArgoprojV1alpha1().Applications(c.Namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error when requesting %s refresh of application %s: %w", refreshType, name, err)
+	}
+	return nil
+}
+
+func (c *ReadinessChecker) newReport(apps []v1alpha1.Application) *ReadinessReport {
+	report := &ReadinessReport{Ready: len(apps) > 0}
+	for i := range apps {
+		app := &apps[i]
+		r := ApplicationReadiness{
+			Name:          app.Name,
+			Ready:         isApplicationReady(app),
+			SyncStatus:    string(app.Status.Sync.Status),
+			HealthStatus:  string(app.Status.Health.Status),
+			HealthMessage: app.Status.Health.Message,
+		}
+		if op := app.Status.OperationState; op != nil {
+			r.OperationPhase, r.OperationMessage = string(op.Phase), op.Message
+		}
+		for _, cond := range app.Status.Conditions {
+			r.Conditions = append(r.Conditions, fmt.Sprintf("%s: %s", cond.Type, cond.Message))
+		}
+		for _, res := range app.Status.Resources {
+			if res.Status == v1alpha1.SyncStatusCodeSynced && (res.Health == nil || res.Health.Status == health.HealthStatusHealthy) {
+				continue
+			}
+			rr := ResourceReadiness{Kind: res.Kind, Namespace: res.Namespace, Name: res.Name, SyncStatus: string(res.Status)}
+			if res.Health != nil {
+				rr.HealthStatus, rr.HealthMessage = string(res.Health.Status), res.Health.Message
+			}
+			r.DegradedResources = append(r.DegradedResources, rr)
+		}
+		if state, ok := c.refreshes[app.Name]; ok {
+			r.Refreshes = state.count
+		}
+		report.Ready = report.Ready && r.Ready
+		report.Applications = append(report.Applications, r)
+	}
+	// not ready applications first
+	sort.SliceStable(report.Applications, func(i, j int) bool {
+		a, b := report.Applications[i], report.Applications[j]
+		if a.Ready != b.Ready {
+			return !a.Ready
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+func isApplicationReady(app *v1alpha1.Application) bool {
+	return app.Status.Sync.Status == v1alpha1.SyncStatusCodeSynced && app.Status.Health.Status == health.HealthStatusHealthy
+}
+
+func isOperationRunning(app *v1alpha1.Application) bool {
+	return app.Status.OperationState != nil && app.Status.OperationState.Phase == synccommon.OperationRunning
+}
+
+func hasCondition(app *v1alpha1.Application, conditionType string) bool {
+	for _, cond := range app.Status.Conditions {
+		if cond.Type == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// NotReady returns names of the Applications which are not ready
+func (r *ReadinessReport) NotReady() []string {
+	names := []string{}
+	for _, app := range r.Applications {
+		if !app.Ready {
+			names = append(names, app.Name)
+		}
+	}
+	return names
+}
+
+func (r *ReadinessReport) summary() string {
+	if len(r.Applications) == 0 {
+		return "no applications found"
+	}
+	notReady := []string{}
+	for _, app := range r.Applications {
+		if !app.Ready {
+			notReady = append(notReady, fmt.Sprintf("%s (%s, %s)", app.Name, app.SyncStatus, app.HealthStatus))
+		}
+	}
+	if len(notReady) == 0 {
+		return fmt.Sprintf("all %d applications are ready", len(r.Applications))
+	}
+	return fmt.Sprintf("%d/%d applications are not ready: %s", len(notReady), len(r.Applications), strings.Join(notReady, ", "))
+}
+
+// Markdown renders the report as a Markdown document
+func (r *ReadinessReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# ArgoCD readiness report\n\n")
+	fmt.Fprintf(&b, "Ready: %t, duration: %s\n\n", r.Ready, r.Duration)
+	fmt.Fprintf(&b, "| Application | Sync | Health | Operation | Refreshes |\n|---|---|---|---|---|\n")
+	for _, app := range r.Applications {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %d |\n", app.Name, app.SyncStatus, app.HealthStatus, app.OperationPhase, app.Refreshes)
+	}
+	for _, app := range r.Applications {
+		if app.Ready {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", app.Name)
+		if app.HealthMessage != "" {
+			fmt.Fprintf(&b, "* health: %s\n", app.HealthMessage)
+		}
+		if app.OperationMessage != "" {
+			fmt.Fprintf(&b, "* operation %s: %s\n", app.OperationPhase, app.OperationMessage)
+		}
+		for _, cond := range app.Conditions {
+			fmt.Fprintf(&b, "* condition %s\n", cond)
+		}
+		for _, res := range app.DegradedResources {
+			fmt.Fprintf(&b, "* %s %s/%s: %s %s", res.Kind, res.Namespace, res.Name, res.SyncStatus, res.HealthStatus)
+			if res.HealthMessage != "" {
+				fmt.Fprintf(&b, " (%s)", res.HealthMessage)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// write writes the report as JSON and Markdown files to the directory
+func (r *ReadinessReport) write(dir string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ReadinessReportName+".json"), data, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ReadinessReportName+".md"), []byte(r.Markdown()), 0600)
+}
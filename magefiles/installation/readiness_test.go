@@ -0,0 +1,126 @@
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func application(name string, sync v1alpha1.SyncStatusCode, health health.HealthStatusCode) *v1alpha1.Application {
+	return &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: argoCDNamespace},
+		Status: v1alpha1.ApplicationStatus{
+			Sync:   v1alpha1.SyncStatus{Status: sync},
+			Health: v1alpha1.HealthStatus{Status: health},
+		},
+	}
+}
+
+func newTestReadinessChecker(t *testing.T, apps ...*v1alpha1.Application) (*ReadinessChecker, *fake.Clientset) {
+	client := fake.NewSimpleClientset()
+	for _, app := range apps {
+		assert.NoError(t, client.Tracker().Add(app))
+	}
+	c := NewReadinessChecker(client)
+	c.Timeout = 200 * time.Millisecond
+	c.Interval = 10 * time.Millisecond
+	c.RefreshAfter = 100 * time.Millisecond
+	c.MaxRefreshAfter = time.Hour
+	c.ArtifactDir = t.TempDir()
+	c.Out = io.Discard
+	return c, client
+}
+
+func refreshAnnotation(t *testing.T, client *fake.Clientset, name string) string {
+	app, err := client.ArgoprojV1alpha1().Applications(argoCDNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	return app.Annotations[v1alpha1.AnnotationKeyRefresh]
+}
+
+func TestReadinessCheckerReady(t *testing.T) {
+	c, client := newTestReadinessChecker(t,
+		application(rootApplication, v1alpha1.SyncStatusCodeSynced, health.HealthStatusHealthy),
+		application("build-service", v1alpha1.SyncStatusCodeSynced, health.HealthStatusHealthy),
+	)
+
+	report, err := c.Check(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, report.Ready)
+	assert.Empty(t, report.NotReady())
+	assert.Equal(t, "hard", refreshAnnotation(t, client, rootApplication))
+	assert.Empty(t, refreshAnnotation(t, client, "build-service"))
+	assert.FileExists(t, filepath.Join(c.ArtifactDir, ReadinessReportName+".json"))
+	assert.FileExists(t, filepath.Join(c.ArtifactDir, ReadinessReportName+".md"))
+}
+
+func TestReadinessCheckerTimeout(t *testing.T) {
+	degraded := application("build-service", v1alpha1.SyncStatusCodeSynced, health.HealthStatusDegraded)
+	degraded.Status.Health.Message = "Deployment is not available"
+	degraded.Status.Resources = []v1alpha1.ResourceStatus{
+		{Kind: "Deployment", Namespace: "build-service", Name: "controller", Status: v1alpha1.SyncStatusCodeSynced, Health: &v1alpha1.HealthStatus{Status: health.HealthStatusDegraded, Message: "ImagePullBackOff"}},
+		{Kind: "Service", Namespace: "build-service", Name: "metrics", Status: v1alpha1.SyncStatusCodeSynced, Health: &v1alpha1.HealthStatus{Status: health.HealthStatusHealthy}},
+	}
+	comparisonError := application("integration", v1alpha1.SyncStatusCodeUnknown, health.HealthStatusHealthy)
+	comparisonError.Status.Conditions = []v1alpha1.ApplicationCondition{{Type: v1alpha1.ApplicationConditionComparisonError, Message: "context deadline exceeded"}}
+	syncing := application("release", v1alpha1.SyncStatusCodeOutOfSync, health.HealthStatusProgressing)
+	syncing.Status.OperationState = &v1alpha1.OperationState{Phase: synccommon.OperationRunning, Message: "waiting for healthy state of apps/Deployment/release-controller"}
+
+	c, client := newTestReadinessChecker(t,
+		application("application-api", v1alpha1.SyncStatusCodeSynced, health.HealthStatusHealthy),
+		degraded, comparisonError, syncing,
+	)
+	c.RootApplication = ""
+	c.Timeout = 250 * time.Millisecond
+
+	report, err := c.Check(context.Background())
+	var timeoutErr *utils.PollTimeoutError
+	assert.ErrorAs(t, err, &timeoutErr)
+	assert.False(t, report.Ready)
+	assert.Equal(t, []string{"build-service", "integration", "release"}, report.NotReady())
+
+	// targeted refreshes, no refresh of ready applications and applications being synced
+	assert.Empty(t, refreshAnnotation(t, client, "application-api"))
+	assert.Equal(t, "normal", refreshAnnotation(t, client, "build-service"))
+	assert.Equal(t, "hard", refreshAnnotation(t, client, "integration"))
+	assert.Empty(t, refreshAnnotation(t, client, "release"))
+
+	// refreshes are backed off
+	assert.Equal(t, 1, report.Applications[0].Refreshes)
+	assert.Equal(t, []ResourceReadiness{{Kind: "Deployment", Namespace: "build-service", Name: "controller", SyncStatus: "Synced", HealthStatus: "Degraded", HealthMessage: "ImagePullBackOff"}}, report.Applications[0].DegradedResources)
+	assert.Equal(t, []string{"ComparisonError: context deadline exceeded"}, report.Applications[1].Conditions)
+	assert.Equal(t, "Running", report.Applications[2].OperationPhase)
+
+	data, err := os.ReadFile(filepath.Join(c.ArtifactDir, ReadinessReportName+".json"))
+	assert.NoError(t, err)
+	written := &ReadinessReport{}
+	assert.NoError(t, json.Unmarshal(data, written))
+	assert.Equal(t, report, written)
+
+	md, err := os.ReadFile(filepath.Join(c.ArtifactDir, ReadinessReportName+".md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(md), "| build-service | Synced | Degraded |  | 1 |")
+	assert.Contains(t, string(md), "* Deployment build-service/controller: Synced Degraded (ImagePullBackOff)")
+	assert.Contains(t, string(md), "* operation Running: waiting for healthy state of apps/Deployment/release-controller")
+}
+
+func TestReadinessCheckerNoApplications(t *testing.T) {
+	c, _ := newTestReadinessChecker(t)
+	c.RootApplication = ""
+
+	report, err := c.Check(context.Background())
+	assert.Error(t, err)
+	assert.False(t, report.Ready)
+	assert.Equal(t, "no applications found", report.summary())
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/clients/common"
+	"github.com/redhat-appstudio/e2e-tests/pkg/clients/github"
+	"github.com/redhat-appstudio/e2e-tests/pkg/clients/gitops"
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/janitor"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox"
+	"github.com/redhat-appstudio/image-controller/pkg/quay"
+)
+
+//go:embed janitor_policy.yaml
+var janitorPolicyYaml []byte
+
+// janitorBackends create backends of the janitor, they are created only if the policy has rules for their resources
+var janitorBackends = []struct {
+	types []janitor.ResourceType
+	new   func() (janitor.Backend, error)
+}{
+	{janitor.GithubResourceTypes, newGithubJanitorBackend},
+	{janitor.QuayResourceTypes, newQuayJanitorBackend},
+	{[]janitor.ResourceType{janitor.SprayProxyBackend}, newSprayProxyJanitorBackend},
+	{janitor.ClusterResourceTypes, newClusterJanitorBackend},
+}
+
+// loadJanitorPolicy loads the policy from JANITOR_POLICY, or the embedded janitor_policy.yaml if it's not set.
+// With rules given, the policy contains only these rules.
+func loadJanitorPolicy(rules ...string) (*janitor.Policy, error) {
+	data := janitorPolicyYaml
+	if path := config.Get(config.JanitorPolicyEnv); path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read janitor policy %s: %v", path, err)
+		}
+	}
+	policy, err := janitor.LoadPolicy(data)
+	if err != nil || len(rules) == 0 {
+		return policy, err
+	}
+	return policy.Select(rules...)
+}
+
+func newJanitor(policy *janitor.Policy, dryRun bool) (*janitor.Janitor, error) {
+	j := janitor.New(policy, dryRun)
+	for _, b := range janitorBackends {
+		if !policyUsesResourceTypes(policy, b.types) {
+			continue
+		}
+		backend, err := b.new()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create janitor backend for %v: %v", b.types, err)
+		}
+		j.Register(backend, b.types...)
+	}
+	return j, nil
+}
+
+func policyUsesResourceTypes(policy *janitor.Policy, types []janitor.ResourceType) bool {
+	for _, r := range policy.Rules {
+		for _, t := range types {
+			if r.Resource == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newGithubJanitorBackend() (janitor.Backend, error) {
+	token := config.Get(constants.GITHUB_TOKEN_ENV)
+	if token == "" {
+		return nil, fmt.Errorf("env var %s is not set", constants.GITHUB_TOKEN_ENV)
+	}
+	client, err := github.NewGithubClient(token, config.Get(constants.GITHUB_E2E_ORGANIZATION_ENV))
+	if err != nil {
+		return nil, err
+	}
+	return &janitor.GithubBackend{Client: client}, nil
+}
+
+func newQuayJanitorBackend() (janitor.Backend, error) {
+	quayOrgToken := config.Get("DEFAULT_QUAY_ORG_TOKEN")
+	if quayOrgToken == "" {
+		return nil, fmt.Errorf(quayTokenNotFoundError)
+	}
+	quayClient := quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayOrgToken, quayApiUrl)
+	return &janitor.QuayBackend{Client: quayClient, Organization: config.Get(constants.DEFAULT_QUAY_ORG_ENV)}, nil
+}
+
+func newSprayProxyJanitorBackend() (janitor.Backend, error) {
+	sprayProxy, err := newSprayProxy()
+	if err != nil {
+		return nil, err
+	}
+	return &janitor.SprayProxyServersBackend{Client: sprayProxy}, nil
+}
+
+func newClusterJanitorBackend() (janitor.Backend, error) {
+	client, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	return &janitor.ClusterBackend{Client: client.KubeRest()}, nil
+}
+
+// runJanitor applies the policy, prints the report and stores it in the artifact dir
+func runJanitor(ctx context.Context, policy *janitor.Policy, dryRun bool) error {
+	j, err := newJanitor(policy, dryRun)
+	if err != nil {
+		return err
+	}
+	report, runErr := j.Run(ctx)
+	if err := report.Print(os.Stdout); err != nil {
+		klog.Errorf("failed to print janitor report: %v", err)
+	}
+	if err := report.Write(artifactDir, janitor.ReportFile); err != nil {
+		klog.Errorf("failed to store janitor report: %v", err)
+	}
+	return runErr
+}
+
+// runJanitorRules applies the named rules of the janitor policy
+func runJanitorRules(ctx context.Context, dryRun bool, rules ...string) error {
+	policy, err := loadJanitorPolicy(rules...)
+	if err != nil {
+		return err
+	}
+	return runJanitor(ctx, policy, dryRun)
+}
+
+// Deletes resources left behind by the e2e tests according to the janitor policy (see docs/Janitor.md).
+// Nothing is deleted unless JANITOR_DRY_RUN=false, JANITOR_RULES selects rules to run.
+func (Local) Janitor() error {
+	var rules []string
+	if r := config.Get(config.JanitorRulesEnv); r != "" {
+		for _, rule := range strings.Split(r, ",") {
+			rules = append(rules, strings.TrimSpace(rule))
+		}
+	}
+	dryRun, err := strconv.ParseBool(config.Get(config.JanitorDryRunEnv))
+	if err != nil {
+		return fmt.Errorf("unable to parse %s env var: %v", config.JanitorDryRunEnv, err)
+	}
+	return runJanitorRules(context.Background(), dryRun, rules...)
+}
+
+// Deletes UserSignups, namespaces, Environments and DeploymentTargetClaims left behind by aborted test runs.
+// Only resources labelled by the tests and older than REAPER_TTL are deleted, nothing unless REAPER_DRY_RUN=false.
+func (Local) ReapStaleClusterResources() error {
+	dryRun, err := strconv.ParseBool(config.Get(config.ReaperDryRunEnv))
+	if err != nil {
+		return fmt.Errorf("unable to parse %s env var: %v", config.ReaperDryRunEnv, err)
+	}
+	ttl, err := time.ParseDuration(config.Get(config.ReaperTTLEnv))
+	if err != nil {
+		return fmt.Errorf("unable to parse %s env var: %v", config.ReaperTTLEnv, err)
+	}
+	finalizerTimeout, err := time.ParseDuration(config.Get(config.ReaperFinalizerTimeoutEnv))
+	if err != nil {
+		return fmt.Errorf("unable to parse %s env var: %v", config.ReaperFinalizerTimeoutEnv, err)
+	}
+
+	cc, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return err
+	}
+	commonCtrl, err := common.NewSuiteController(cc)
+	if err != nil {
+		return err
+	}
+	sandboxCtrl, err := sandbox.NewDevSandboxController(cc.KubeInterface(), cc.KubeRest())
+	if err != nil {
+		return err
+	}
+	gitopsCtrl, err := gitops.NewSuiteController(cc)
+	if err != nil {
+		return err
+	}
+
+	reaper := janitor.NewReaper(cc.KubeRest(), commonCtrl, sandboxCtrl, gitopsCtrl, ttl, dryRun)
+	reaper.FinalizerTimeout = finalizerTimeout
+	report, runErr := reaper.Run(context.Background())
+	if err := report.Print(os.Stdout); err != nil {
+		klog.Errorf("failed to print reaper report: %v", err)
+	}
+	if err := report.Write(artifactDir, janitor.ReaperReportFile); err != nil {
+		klog.Errorf("failed to store reaper report: %v", err)
+	}
+	return runErr
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/janitor"
+)
+
+func TestJanitorPolicy(t *testing.T) {
+	policy, err := loadJanitorPolicy()
+	assert.NoError(t, err)
+	// rules run by the cleanup targets
+	for _, rule := range []string{"github-e2e-repositories", "github-webhooks", "quay-e2e-repositories", "quay-e2e-robots", "quay-test-images-tags", "quay-private-repositories", "sprayproxy-stale-backends"} {
+		assert.NotNil(t, policy.Rule(rule), "janitor policy has no rule %s", rule)
+	}
+	assert.False(t, policyUsesResourceTypes(policy, janitor.ClusterResourceTypes))
+
+	selected, err := loadJanitorPolicy("quay-test-images-tags")
+	assert.NoError(t, err)
+	assert.True(t, policyUsesResourceTypes(selected, janitor.QuayResourceTypes))
+	assert.False(t, policyUsesResourceTypes(selected, janitor.GithubResourceTypes))
+
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(policyFile, []byte("rules: [{name: e2e-namespaces, resource: namespace, olderThan: 24h}]"), 0644))
+	t.Setenv(config.JanitorPolicyEnv, policyFile)
+	custom, err := loadJanitorPolicy()
+	assert.NoError(t, err)
+	assert.Len(t, custom.Rules, 1)
+	assert.True(t, policyUsesResourceTypes(custom, janitor.ClusterResourceTypes))
+	_, err = loadJanitorPolicy("github-webhooks")
+	assert.EqualError(t, err, "janitor policy has no rule github-webhooks")
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devfile/library/v2/pkg/util"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+)
+
+//go:embed job_routes.yaml
+var jobRoutesYaml []byte
+
+// JobRoutes routes CI jobs to the e2e tests they run, see job_routes.yaml
+type JobRoutes struct {
+	Routes []JobRoute `json:"routes"`
+}
+
+// JobRoute configures the e2e tests run by CI jobs matching its conditions
+type JobRoute struct {
+	Name           string            `json:"name"`
+	JobNameMatches []string          `json:"jobNameMatches,omitempty"`
+	Repos          []string          `json:"repos,omitempty"`
+	ExcludeRepos   []string          `json:"excludeRepos,omitempty"`
+	ComponentImage *ComponentImage   `json:"componentImage,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	LabelFilter    string            `json:"labelFilter,omitempty"`
+	Setup          []string          `json:"setup,omitempty"`
+	PairedRepos    []string          `json:"pairedRepos,omitempty"`
+
+	jobNameRegexps []*regexp.Regexp
+}
+
+// ComponentImage describes env vars of the component image built by a service repository job
+type ComponentImage struct {
+	EnvPrefix string `json:"envPrefix"`
+	TagSuffix string `json:"tagSuffix"`
+}
+
+// jobSetupHooks are setup hooks which can be referenced in the setup of a job route
+var jobSetupHooks = map[string]func() error{
+	"sprayproxy": func() error {
+		requiresSprayProxyRegistering = true
+		return nil
+	},
+	"multi-platform": func() error {
+		requiresMultiPlatformTests = true
+		return nil
+	},
+	"jvm-custom-bundle":             buildCustomJavaBundle,
+	"spi-oauth-image":               setSPIOAuthImage,
+	"infra-deployments-pr":          setInfraDeploymentsPR,
+	"release-service-catalog-pr":    setReleaseServiceCatalogPR,
+	"release-image-controller-quay": setReleaseImageControllerQuay,
+	"test-impact":                   setTestImpactLabelFilter,
+}
+
+// pairedRepoHooks configure tests to use a PR of the repository paired to the tested PR
+var pairedRepoHooks = map[string]func(paired *ci.PullRequest) error{
+	"infra-deployments": setPairedInfraDeploymentsPR,
+	"release-service":   setPairedReleaseServicePR,
+}
+
+// pairedPRLookup returns the PR of the repository paired to the tested PR, or nil if there is none
+var pairedPRLookup = getPairedPR
+
+// loadJobRoutes parses job routes and checks all referenced hooks exist
+func loadJobRoutes(data []byte) (*JobRoutes, error) {
+	routes := &JobRoutes{}
+	if err := yaml.UnmarshalStrict(data, routes); err != nil {
+		return nil, fmt.Errorf("error when parsing job routes: %v", err)
+	}
+	for i := range routes.Routes {
+		r := &routes.Routes[i]
+		for _, p := range r.JobNameMatches {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid job name pattern %q of job route %s: %v", p, r.Name, err)
+			}
+			r.jobNameRegexps = append(r.jobNameRegexps, re)
+		}
+		for _, h := range r.Setup {
+			if _, ok := jobSetupHooks[h]; !ok {
+				return nil, fmt.Errorf("unknown setup hook %q of job route %s", h, r.Name)
+			}
+		}
+		for _, repo := range r.PairedRepos {
+			if _, ok := pairedRepoHooks[repo]; !ok {
+				return nil, fmt.Errorf("pairing with repository %q of job route %s is not supported", repo, r.Name)
+			}
+		}
+	}
+	return routes, nil
+}
+
+// Route returns the first route matching the job, or nil if there is none
+func (jr *JobRoutes) Route(jobName, repo string) *JobRoute {
+	for i := range jr.Routes {
+		if jr.Routes[i].matches(jobName, repo) {
+			return &jr.Routes[i]
+		}
+	}
+	return nil
+}
+
+func (r *JobRoute) matches(jobName, repo string) bool {
+	for _, re := range r.jobNameRegexps {
+		if !re.MatchString(jobName) {
+			return false
+		}
+	}
+	if len(r.Repos) > 0 && !utils.Contains(r.Repos, repo) {
+		return false
+	}
+	return !utils.Contains(r.ExcludeRepos, repo)
+}
+
+// apply sets env vars of the route and runs its setup hooks
+func (r *JobRoute) apply() error {
+	isRehearsal := strings.Contains(jobName, "rehearse")
+
+	if r.ComponentImage != nil {
+		sp := strings.Split(config.Get("COMPONENT_IMAGE"), "@")
+		tag := fmt.Sprintf("redhat-appstudio-%s", r.ComponentImage.TagSuffix)
+		// "rehearse" jobs metadata are not relevant for testing
+		if isRehearsal {
+			r.ComponentImage.setEnv(sp[0], tag, nil)
+		} else {
+			r.ComponentImage.setEnv(sp[0], tag, pr)
+		}
+	}
+	for k, v := range r.Env {
+		os.Setenv(k, os.ExpandEnv(v))
+	}
+	if r.LabelFilter != "" {
+		os.Setenv("E2E_TEST_SUITE_LABEL", r.LabelFilter)
+	}
+	for _, h := range r.Setup {
+		if err := jobSetupHooks[h](); err != nil {
+			return fmt.Errorf("setup %s of job route %s failed: %v", h, r.Name, err)
+		}
+	}
+	// "rehearse" jobs metadata are not relevant for testing
+	if isRehearsal {
+		return nil
+	}
+	for _, repo := range r.PairedRepos {
+		paired := pairedPRLookup(repo)
+		if paired == nil {
+			continue
+		}
+		if err := pairedRepoHooks[repo](paired); err != nil {
+			return fmt.Errorf("pairing with %s repository failed: %v", repo, err)
+		}
+	}
+	return nil
+}
+
+// setEnv sets env vars of the component image built from the PR, the PR is nil if it's not relevant for testing
+func (c *ComponentImage) setEnv(imageRepo, tag string, pull *ci.PullRequest) {
+	os.Setenv(fmt.Sprintf("%s_IMAGE_REPO", c.EnvPrefix), imageRepo)
+	os.Setenv(fmt.Sprintf("%s_IMAGE_TAG", c.EnvPrefix), tag)
+	if pull != nil {
+		os.Setenv(fmt.Sprintf("%s_PR_OWNER", c.EnvPrefix), pull.RemoteName)
+		os.Setenv(fmt.Sprintf("%s_PR_SHA", c.EnvPrefix), pull.CommitSHA)
+	}
+}
+
+// componentImage returns the component image of the service repository, or nil if there is no route named after the repository
+func (jr *JobRoutes) componentImage(repo string) *ComponentImage {
+	for _, r := range jr.Routes {
+		if r.Name == repo && r.ComponentImage != nil {
+			return r.ComponentImage
+		}
+	}
+	return nil
+}
+
+func setRequiredEnvVars() error {
+	routes, err := loadJobRoutes(jobRoutesYaml)
+	if err != nil {
+		return err
+	}
+	route := routes.Route(jobName, pr.RepoName)
+	if route == nil {
+		klog.Infof("job %s of repository %s doesn't match any job route", jobName, pr.RepoName)
+		return nil
+	}
+	klog.Infof("job %s is routed as %s", jobName, route.Name)
+	if err := route.apply(); err != nil {
+		return err
+	}
+	return applyPairingManifest(routes, route)
+}
+
+func setSPIOAuthImage() error {
+	im := strings.Split(config.Get("CI_SPI_OAUTH_IMAGE"), "@")
+	os.Setenv("SPI_OAUTH_IMAGE_REPO", im[0])
+	os.Setenv("SPI_OAUTH_IMAGE_TAG", fmt.Sprintf("redhat-appstudio-%s", "spi-oauth-image"))
+	return nil
+}
+
+func setInfraDeploymentsPR() error {
+	os.Setenv("INFRA_DEPLOYMENTS_ORG", pr.RemoteName)
+	os.Setenv("INFRA_DEPLOYMENTS_BRANCH", pr.BranchName)
+	return nil
+}
+
+func setReleaseServiceCatalogPR() error {
+	// "rehearse" jobs metadata are not relevant for testing
+	if strings.Contains(jobName, "rehearse") {
+		return nil
+	}
+	os.Setenv("RELEASE_SERVICE_CATALOG_URL", fmt.Sprintf("https://github.com/%s/%s", pr.RemoteName, pr.RepoName))
+	os.Setenv("RELEASE_SERVICE_CATALOG_REVISION", pr.CommitSHA)
+	return nil
+}
+
+func setPairedInfraDeploymentsPR(paired *ci.PullRequest) error {
+	os.Setenv("INFRA_DEPLOYMENTS_ORG", paired.RemoteName)
+	os.Setenv("INFRA_DEPLOYMENTS_BRANCH", paired.BranchName)
+	return nil
+}
+
+func setPairedReleaseServicePR(paired *ci.PullRequest) error {
+	os.Setenv("RELEASE_SERVICE_IMAGE_REPO", "quay.io/redhat-user-workloads/rhtap-release-2-tenant/release-service/release-service")
+	os.Setenv("RELEASE_SERVICE_IMAGE_TAG", fmt.Sprintf("on-pr-%s", paired.CommitSHA))
+	os.Setenv("RELEASE_SERVICE_PR_OWNER", paired.RemoteName)
+	os.Setenv("RELEASE_SERVICE_PR_SHA", paired.CommitSHA)
+	return nil
+}
+
+func setReleaseImageControllerQuay() error {
+	if config.Get("REL_IMAGE_CONTROLLER_QUAY_ORG") != "" {
+		os.Setenv("IMAGE_CONTROLLER_QUAY_ORG", config.Get("REL_IMAGE_CONTROLLER_QUAY_ORG"))
+	}
+	if config.Get("REL_IMAGE_CONTROLLER_QUAY_TOKEN") != "" {
+		os.Setenv("IMAGE_CONTROLLER_QUAY_TOKEN", config.Get("REL_IMAGE_CONTROLLER_QUAY_TOKEN"))
+	}
+	return nil
+}
+
+// buildCustomJavaBundle overrides the default Tekton bundle s2i-java task for the purpose of testing jvm-build-service PR
+func buildCustomJavaBundle() error {
+	klog.Infof("going to override default Tekton bundle s2i-java task for the purpose of testing jvm-build-service PR")
+	var err error
+	var defaultBundleRef string
+	var tektonObj runtime.Object
+
+	tag := fmt.Sprintf("%d-%s", time.Now().Unix(), util.GenerateRandomString(4))
+	quayOrg := config.Get(constants.DEFAULT_QUAY_ORG_ENV)
+	newS2iJavaTaskImg := strings.ReplaceAll(constants.DefaultImagePushRepo, constants.DefaultQuayOrg, quayOrg)
+	var newS2iJavaTaskRef, _ = name.ParseReference(fmt.Sprintf("%s:task-bundle-%s", newS2iJavaTaskImg, tag))
+	newJavaBuilderPipelineImg := strings.ReplaceAll(constants.DefaultImagePushRepo, constants.DefaultQuayOrg, quayOrg)
+	var newJavaBuilderPipelineRef, _ = name.ParseReference(fmt.Sprintf("%s:pipeline-bundle-%s", newJavaBuilderPipelineImg, tag))
+	var newReqprocessorImage = config.Get("JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE")
+	var newTaskYaml, newPipelineYaml []byte
+
+	if err = utils.CreateDockerConfigFile(config.Get("QUAY_TOKEN")); err != nil {
+		return fmt.Errorf("failed to create docker config file: %+v", err)
+	}
+	if defaultBundleRef, err = tekton.GetDefaultPipelineBundleRef(constants.BuildPipelineSelectorYamlURL, "Java"); err != nil {
+		return fmt.Errorf("failed to get the pipeline bundle ref: %+v", err)
+	}
+	if tektonObj, err = tekton.ExtractTektonObjectFromBundle(defaultBundleRef, "pipeline", "java-builder"); err != nil {
+		return fmt.Errorf("failed to extract the Tekton Pipeline from bundle: %+v", err)
+	}
+	javaPipelineObj := tektonObj.(*tektonapi.Pipeline)
+
+	var currentS2iJavaTaskRef string
+	for _, t := range javaPipelineObj.PipelineSpec().Tasks {
+		params := t.TaskRef.Params
+		var lastBundle *tektonapi.Param
+		s2iTask := false
+		for i, param := range params {
+			if param.Name == "bundle" {
+				lastBundle = &t.TaskRef.Params[i]
+			} else if param.Name == "name" && param.Value.StringVal == "s2i-java" {
+				s2iTask = true
+			}
+		}
+		if s2iTask {
+			currentS2iJavaTaskRef = lastBundle.Value.StringVal
+			klog.Infof("Found current task ref %s", currentS2iJavaTaskRef)
+			lastBundle.Value = *tektonapi.NewStructuredValues(newS2iJavaTaskRef.String())
+			break
+		}
+	}
+	if tektonObj, err = tekton.ExtractTektonObjectFromBundle(currentS2iJavaTaskRef, "task", "s2i-java"); err != nil {
+		return fmt.Errorf("failed to extract the Tekton Task from bundle: %+v", err)
+	}
+	taskObj := tektonObj.(*tektonapi.Task)
+
+	for i, s := range taskObj.Spec.Steps {
+		if s.Name == "analyse-dependencies-java-sbom" {
+			taskObj.Spec.Steps[i].Image = newReqprocessorImage
+		}
+	}
+
+	if newTaskYaml, err = yaml.Marshal(taskObj); err != nil {
+		return fmt.Errorf("error when marshalling a new task to YAML: %v", err)
+	}
+	if newPipelineYaml, err = yaml.Marshal(javaPipelineObj); err != nil {
+		return fmt.Errorf("error when marshalling a new pipeline to YAML: %v", err)
+	}
+
+	keychain := authn.NewMultiKeychain(authn.DefaultKeychain)
+	authOption := remoteimg.WithAuthFromKeychain(keychain)
+
+	if err = tekton.BuildAndPushTektonBundle(newTaskYaml, newS2iJavaTaskRef, authOption); err != nil {
+		return fmt.Errorf("error when building/pushing a tekton task bundle: %v", err)
+	}
+	if err = tekton.BuildAndPushTektonBundle(newPipelineYaml, newJavaBuilderPipelineRef, authOption); err != nil {
+		return fmt.Errorf("error when building/pushing a tekton pipeline bundle: %v", err)
+	}
+	os.Setenv(constants.CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE_ENV, newJavaBuilderPipelineRef.String())
+	return nil
+}
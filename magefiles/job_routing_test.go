@@ -0,0 +1,218 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRouting(t *testing.T) {
+	routes, err := loadJobRoutes(jobRoutesYaml)
+	assert.NoError(t, err)
+
+	customJavaBundleBuilt := false
+	defer func(hook func() error) { jobSetupHooks["jvm-custom-bundle"] = hook }(jobSetupHooks["jvm-custom-bundle"])
+	jobSetupHooks["jvm-custom-bundle"] = func() error {
+		customJavaBundleBuilt = true
+		return nil
+	}
+	defer func(lookup func(string) *ci.PullRequest) { pairedPRLookup = lookup }(pairedPRLookup)
+	pairedPRLookup = func(repo string) *ci.PullRequest {
+		if repo == "infra-deployments" {
+			return &ci.PullRequest{RemoteName: "author", BranchName: "feature"}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		jobName          string
+		repo             string
+		route            string
+		env              map[string]string
+		sprayProxy       bool
+		multiPlatform    bool
+		customJavaBundle bool
+		unsetEnv         []string
+	}{
+		{
+			jobName:       "periodic-ci-redhat-appstudio-infra-deployments-main-appstudio-e2e-tests-periodic",
+			route:         "nightly",
+			sprayProxy:    true,
+			multiPlatform: true,
+		},
+		{
+			jobName:    "pull-ci-redhat-appstudio-application-service-main-application-service-e2e",
+			repo:       "application-service",
+			route:      "application-service",
+			sprayProxy: true,
+			env: map[string]string{
+				"HAS_IMAGE_REPO":       "quay.io/org/component",
+				"HAS_IMAGE_TAG":        "redhat-appstudio-has-image",
+				"HAS_PR_OWNER":         "author",
+				"HAS_PR_SHA":           "abcd",
+				"E2E_TEST_SUITE_LABEL": "e2e-demo,byoc",
+			},
+		},
+		{
+			jobName: "pull-ci-redhat-appstudio-release-service-main-release-service-e2e",
+			repo:    "release-service",
+			route:   "release-service",
+			env:     map[string]string{"RELEASE_SERVICE_IMAGE_TAG": "redhat-appstudio-release-service-image", "E2E_TEST_SUITE_LABEL": "release-service"},
+		},
+		{
+			jobName:    "pull-ci-redhat-appstudio-integration-service-main-integration-service-e2e",
+			repo:       "integration-service",
+			route:      "integration-service",
+			sprayProxy: true,
+			env:        map[string]string{"INTEGRATION_SERVICE_IMAGE_TAG": "redhat-appstudio-integration-service-image", "E2E_TEST_SUITE_LABEL": "integration-service"},
+		},
+		{
+			jobName:          "pull-ci-redhat-appstudio-jvm-build-service-main-jvm-build-service-e2e",
+			repo:             "jvm-build-service",
+			route:            "jvm-build-service",
+			customJavaBundle: true,
+			env: map[string]string{
+				"JVM_BUILD_SERVICE_IMAGE_TAG":          "redhat-appstudio-jvm-build-service-image",
+				"JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE": "quay.io/org/reqprocessor",
+				"JVM_BUILD_SERVICE_CACHE_IMAGE":        "quay.io/org/cache",
+				"E2E_TEST_SUITE_LABEL":                 "jvm-build",
+			},
+		},
+		{
+			jobName:    "pull-ci-redhat-appstudio-build-service-main-build-service-e2e",
+			repo:       "build-service",
+			route:      "build-service",
+			sprayProxy: true,
+			env:        map[string]string{"BUILD_SERVICE_IMAGE_TAG": "redhat-appstudio-build-service-image", "E2E_TEST_SUITE_LABEL": "build"},
+		},
+		{
+			jobName:    "pull-ci-redhat-appstudio-image-controller-main-image-controller-e2e",
+			repo:       "image-controller",
+			route:      "image-controller",
+			sprayProxy: true,
+			env:        map[string]string{"IMAGE_CONTROLLER_IMAGE_TAG": "redhat-appstudio-image-controller-image", "E2E_TEST_SUITE_LABEL": "image-controller"},
+		},
+		{
+			jobName: "pull-ci-redhat-appstudio-remote-secret-main-remote-secret-service-e2e",
+			repo:    "remote-secret",
+			route:   "remote-secret-service",
+			env:     map[string]string{"REMOTE_SECRET_IMAGE_TAG": "redhat-appstudio-remote-secret-image", "E2E_TEST_SUITE_LABEL": "remote-secret"},
+		},
+		{
+			jobName: "pull-ci-redhat-appstudio-service-provider-integration-operator-main-spi-service-e2e",
+			repo:    "service-provider-integration-operator",
+			route:   "spi-service",
+			env: map[string]string{
+				"SPI_OPERATOR_IMAGE_TAG": "redhat-appstudio-spi-image",
+				"SPI_OAUTH_IMAGE_REPO":   "quay.io/org/spi-oauth",
+				"SPI_OAUTH_IMAGE_TAG":    "redhat-appstudio-spi-oauth-image",
+				"E2E_TEST_SUITE_LABEL":   "spi-suite",
+			},
+		},
+		{
+			jobName:       "pull-ci-redhat-appstudio-multi-platform-controller-main-multi-platform-controller-service-e2e",
+			repo:          "multi-platform-controller",
+			route:         "multi-platform-controller",
+			multiPlatform: true,
+			env:           map[string]string{"MULTI_PLATFORM_CONTROLLER_IMAGE_TAG": "redhat-appstudio-multi-platform-controller", "E2E_TEST_SUITE_LABEL": "multi-platform"},
+		},
+		{
+			jobName:    "rehearse-12345-pull-ci-redhat-appstudio-build-service-main-build-service-e2e",
+			route:      "build-service",
+			sprayProxy: true,
+			env:        map[string]string{"BUILD_SERVICE_IMAGE_REPO": "quay.io/org/component"},
+			unsetEnv:   []string{"BUILD_SERVICE_PR_OWNER", "BUILD_SERVICE_PR_SHA"},
+		},
+		{
+			jobName:       "pull-ci-redhat-appstudio-infra-deployments-main-appstudio-e2e-tests",
+			repo:          "infra-deployments",
+			route:         "infra-deployments",
+			sprayProxy:    true,
+			multiPlatform: true,
+			env: map[string]string{
+				"INFRA_DEPLOYMENTS_ORG":    "author",
+				"INFRA_DEPLOYMENTS_BRANCH": "feature",
+				"E2E_TEST_SUITE_LABEL":     "e2e-demo,rhtap-demo,spi-suite,remote-secret,integration-service,ec,byoc,build-templates,multi-platform",
+			},
+		},
+		{
+			jobName: "pull-ci-redhat-appstudio-release-service-catalog-main-release-service-catalog-e2e",
+			repo:    "release-service-catalog",
+			route:   "release-service-catalog",
+			env: map[string]string{
+				"RELEASE_SERVICE_CATALOG_URL":      "https://github.com/author/release-service-catalog",
+				"RELEASE_SERVICE_CATALOG_REVISION": "abcd",
+				"IMAGE_CONTROLLER_QUAY_ORG":        "release-org",
+				"E2E_TEST_SUITE_LABEL":             "release-pipelines",
+			},
+		},
+		{
+			jobName:       "rehearse-12345-pull-ci-redhat-appstudio-infra-deployments-main-appstudio-e2e-tests",
+			route:         "rehearsal",
+			sprayProxy:    true,
+			multiPlatform: true,
+		},
+		{
+			jobName:       "pull-ci-redhat-appstudio-e2e-tests-main-redhat-appstudio-e2e",
+			repo:          "e2e-tests",
+			route:         "e2e-tests",
+			sprayProxy:    true,
+			multiPlatform: true,
+			env:           map[string]string{"INFRA_DEPLOYMENTS_ORG": "author", "INFRA_DEPLOYMENTS_BRANCH": "feature"},
+		},
+		{
+			jobName:       "pull-ci-redhat-appstudio-e2e-tests-main-application-service-e2e",
+			repo:          "e2e-tests",
+			route:         "e2e-tests",
+			sprayProxy:    true,
+			multiPlatform: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.jobName, func(t *testing.T) {
+			t.Setenv("COMPONENT_IMAGE", "quay.io/org/component@sha256:1234")
+			t.Setenv("CI_JBS_REQPROCESSOR_IMAGE", "quay.io/org/reqprocessor")
+			t.Setenv("CI_JBS_CACHE_IMAGE", "quay.io/org/cache")
+			t.Setenv("CI_SPI_OAUTH_IMAGE", "quay.io/org/spi-oauth@sha256:5678")
+			t.Setenv("REL_IMAGE_CONTROLLER_QUAY_ORG", "release-org")
+			for _, env := range []string{"E2E_TEST_SUITE_LABEL", "INFRA_DEPLOYMENTS_ORG", "INFRA_DEPLOYMENTS_BRANCH", "BUILD_SERVICE_PR_OWNER", "BUILD_SERVICE_PR_SHA"} {
+				t.Setenv(env, "")
+				os.Unsetenv(env)
+			}
+			jobName = tc.jobName
+			pr = &ci.PullRequest{RemoteName: "author", BranchName: "feature", CommitSHA: "abcd", RepoName: tc.repo}
+			requiresSprayProxyRegistering, requiresMultiPlatformTests, customJavaBundleBuilt = false, false, false
+
+			route := routes.Route(tc.jobName, tc.repo)
+			if !assert.NotNil(t, route) {
+				return
+			}
+			assert.Equal(t, tc.route, route.Name)
+			assert.NoError(t, route.apply())
+			for k, v := range tc.env {
+				assert.Equal(t, v, os.Getenv(k), k)
+			}
+			for _, env := range tc.unsetEnv {
+				_, isSet := os.LookupEnv(env)
+				assert.False(t, isSet, env)
+			}
+			assert.Equal(t, tc.sprayProxy, requiresSprayProxyRegistering, "SprayProxy registration")
+			assert.Equal(t, tc.multiPlatform, requiresMultiPlatformTests, "multi-platform tests")
+			assert.Equal(t, tc.customJavaBundle, customJavaBundleBuilt, "custom Java bundle")
+		})
+	}
+}
+
+func TestLoadJobRoutesValidation(t *testing.T) {
+	_, err := loadJobRoutes([]byte("routes:\n- name: broken\n  setup: [unknown]\n"))
+	assert.ErrorContains(t, err, `unknown setup hook "unknown" of job route broken`)
+
+	_, err = loadJobRoutes([]byte("routes:\n- name: broken\n  pairedRepos: [unknown]\n"))
+	assert.ErrorContains(t, err, `pairing with repository "unknown" of job route broken is not supported`)
+
+	_, err = loadJobRoutes([]byte("routes:\n- name: broken\n  jobNameMatch: [e2e]\n"))
+	assert.ErrorContains(t, err, "error when parsing job routes")
+}
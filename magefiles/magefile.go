@@ -1,12 +1,11 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,35 +20,33 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
-	gh "github.com/google/go-github/v44/github"
 	"github.com/magefile/mage/sh"
 	"github.com/redhat-appstudio/e2e-tests/magefiles/installation"
 	"github.com/redhat-appstudio/e2e-tests/magefiles/testspecs"
-	"github.com/redhat-appstudio/e2e-tests/pkg/clients/github"
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/slack"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/sprayproxy"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
-	"github.com/redhat-appstudio/image-controller/pkg/quay"
 	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 )
 
 const (
-	quayApiUrl       = "https://quay.io/api/v1"
-	gitopsRepository = "GitOps Repository"
+	quayApiUrl = "https://quay.io/api/v1"
 )
 
 var (
-	requiredBinaries = []string{"jq", "kubectl", "oc", "yq", "git", "helm"}
-	artifactDir      = utils.GetEnv("ARTIFACT_DIR", ".")
-	openshiftJobSpec = &OpenshiftJobSpec{}
-	pr               = &PullRequestMetadata{}
-	jobName          = utils.GetEnv("JOB_NAME", "")
-	// can be periodic, presubmit or postsubmit
-	jobType                    = utils.GetEnv("JOB_TYPE", "")
-	reposToDeleteDefaultRegexp = "jvm-build|e2e-dotnet|build-suite|e2e|pet-clinic-e2e|test-app|e2e-quayio|petclinic|test-app|integ-app|^dockerfile-|new-|^python|my-app|^test-|^multi-component"
-	repositoriesWithWebhooks   = []string{"devfile-sample-hello-world", "hacbs-test-project"}
+	// binaries used by infra-deployments scripts installing RHTAP
+	bootstrapBinaries = []string{"jq", "kubectl", "oc", "yq", "git", "helm"}
+	ciProvider        = ci.Detect()
+	artifactDir       = ciProvider.ArtifactDir()
+	pr                = &ci.PullRequest{}
+	jobName           = ciProvider.JobName()
+	// can be periodic, presubmit, postsubmit, manual or local
+	jobType = ciProvider.JobType()
 	// determine whether CI will run tests that require to register SprayProxy
 	// in order to run tests that require PaC application
 	requiresSprayProxyRegistering bool
@@ -60,42 +57,33 @@ var (
 	quayTokenNotFoundError = "DEFAULT_QUAY_ORG_TOKEN env var was not found"
 )
 
-func (CI) parseJobSpec() error {
-	jobSpecEnvVarData := os.Getenv("JOB_SPEC")
-
-	if err := json.Unmarshal([]byte(jobSpecEnvVarData), openshiftJobSpec); err != nil {
-		return fmt.Errorf("error when parsing openshift job spec data: %v", err)
-	}
-	return nil
+// isPeriodicOrRehearsalJob returns true for jobs whose metadata are not relevant for testing
+func isPeriodicOrRehearsalJob() bool {
+	return jobType == ci.Periodic || strings.Contains(jobName, "rehearse")
 }
 
 func (ci CI) init() error {
-	var err error
-
-	if jobType == "periodic" || strings.Contains(jobName, "rehearse") {
+	if isPeriodicOrRehearsalJob() {
 		return nil
 	}
 
-	if err = ci.parseJobSpec(); err != nil {
-		return err
+	testedPR, err := ciProvider.PullRequest()
+	if err != nil {
+		return fmt.Errorf("error when getting the tested PR from %s: %v", ciProvider.Name(), err)
+	}
+	if testedPR != nil {
+		pr = testedPR
 	}
 
-	pr.Organization = openshiftJobSpec.Refs.Organization
-	pr.RepoName = openshiftJobSpec.Refs.Repo
-	pr.CommitSHA = openshiftJobSpec.Refs.Pulls[0].SHA
-	pr.Number = openshiftJobSpec.Refs.Pulls[0].Number
-
-	prUrl := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", pr.Organization, pr.RepoName, pr.Number)
-	pr.RemoteName, pr.BranchName, err = getRemoteAndBranchNameFromPRLink(prUrl)
-	if err != nil {
-		return err
+	if err := loadPairingManifest(); err != nil {
+		return fmt.Errorf("error when loading pairing manifest: %v", err)
 	}
 
 	return nil
 }
 
 func (ci CI) PrepareE2EBranch() error {
-	if jobType == "periodic" || strings.Contains(jobName, "rehearse") {
+	if isPeriodicOrRehearsalJob() {
 		return nil
 	}
 
@@ -103,19 +91,19 @@ func (ci CI) PrepareE2EBranch() error {
 		return err
 	}
 
-	if openshiftJobSpec.Refs.Repo == "e2e-tests" {
+	if pr.RepoName == "e2e-tests" {
 		if err := gitCheckoutRemoteBranch(pr.RemoteName, pr.CommitSHA); err != nil {
 			return err
 		}
 	} else {
-		if isPRPairingRequired("e2e-tests") {
-			if err := gitCheckoutRemoteBranch(pr.RemoteName, pr.BranchName); err != nil {
+		if paired := getPairedPR("e2e-tests"); paired != nil {
+			if err := gitCheckoutRemoteBranch(paired.RemoteName, paired.BranchName); err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	return mergePairedPRs(".", "e2e-tests")
 }
 
 func (Local) PrepareCluster() error {
@@ -132,103 +120,46 @@ func (Local) TestE2E() error {
 	return RunE2ETests()
 }
 
-// Deletes autogenerated repositories from redhat-appstudio-qe Github org.
-// Env vars to configure this target: REPO_REGEX (optional), DRY_RUN (optional) - defaults to false
-// Remove all repos which with 1 day lifetime. By default will delete gitops repositories from redhat-appstudio-qe
+// Deletes autogenerated repositories older than 24 hours from redhat-appstudio-qe Github org
+// by the github-e2e-repositories rule of the janitor policy.
+// Env vars to configure this target: REPO_REGEX (optional) replacing name patterns of the rule, DRY_RUN (optional) - defaults to true
 func (Local) CleanupGithubOrg() error {
-	githubToken := os.Getenv("GITHUB_TOKEN")
-	if githubToken == "" {
-		return fmt.Errorf("env var GITHUB_TOKEN is not set")
-	}
-	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", "true"))
+	dryRun, err := strconv.ParseBool(config.Get("DRY_RUN"))
 	if err != nil {
 		return fmt.Errorf("unable to parse DRY_RUN env var\n\t%s", err)
 	}
-
-	// Get all repos
-	githubOrgName := utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
-	ghClient, err := github.NewGithubClient(githubToken, githubOrgName)
-	if err != nil {
-		return err
-	}
-	repos, err := ghClient.GetAllRepositories()
+	policy, err := loadJanitorPolicy("github-e2e-repositories")
 	if err != nil {
 		return err
 	}
-	var reposToDelete []*gh.Repository
-
-	// Filter repos by regex & time check
-	r, err := regexp.Compile(utils.GetEnv("REPO_REGEX", reposToDeleteDefaultRegexp))
-	if err != nil {
-		return fmt.Errorf("unable to compile regex: %s", err)
-	}
-	for _, repo := range repos {
-		// Add only repos older than 24 hours
-		dayDuration, _ := time.ParseDuration("24h")
-		if time.Since(repo.GetCreatedAt().Time) > dayDuration {
-			// Add only repos matching the regex
-			if r.MatchString(*repo.Name) || *repo.Description == gitopsRepository {
-				reposToDelete = append(reposToDelete, repo)
-			}
+	if repoRegex := config.Get("REPO_REGEX"); repoRegex != "" {
+		if err := policy.Rules[0].SetNamePatterns(repoRegex); err != nil {
+			return fmt.Errorf("unable to compile regex: %s", err)
 		}
 	}
-
-	if dryRun {
-		klog.Info("Dry run enabled. Listing repositories that would be deleted:")
-	}
-
-	// Delete repos
-	for _, repo := range reposToDelete {
-		if dryRun {
-			klog.Infof("\t%s", repo.GetName())
-		} else {
-			err := ghClient.DeleteRepository(repo)
-			if err != nil {
-				klog.Warningf("error deleting repository: %s\n", err)
-			}
-		}
+	if err := runJanitor(context.Background(), policy, dryRun); err != nil {
+		return err
 	}
 	if dryRun {
-		klog.Info("If you really want to delete these repositories, run `DRY_RUN=false [REGEXP=<regexp>] mage local:cleanupGithubOrg`")
+		klog.Info("If you really want to delete these repositories, run `DRY_RUN=false [REPO_REGEX=<regexp>] mage local:cleanupGithubOrg`")
 	}
 	return nil
 }
 
-// Deletes Quay repos and robot accounts older than 24 hours with prefixes `has-e2e` and `e2e-demos`, uses env vars DEFAULT_QUAY_ORG and DEFAULT_QUAY_ORG_TOKEN
+// Deletes Quay repos and robot accounts older than 24 hours with e2e prefixes by the quay-e2e-repositories and quay-e2e-robots rules
+// of the janitor policy, uses env vars DEFAULT_QUAY_ORG and DEFAULT_QUAY_ORG_TOKEN
 func (Local) CleanupQuayReposAndRobots() error {
-	quayOrgToken := os.Getenv("DEFAULT_QUAY_ORG_TOKEN")
-	if quayOrgToken == "" {
-		return fmt.Errorf(quayTokenNotFoundError)
-	}
-	quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
-
-	quayClient := quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayOrgToken, quayApiUrl)
-	return cleanupQuayReposAndRobots(quayClient, quayOrg)
+	return runJanitorRules(context.Background(), false, "quay-e2e-repositories", "quay-e2e-robots")
 }
 
-// Deletes Quay Tags older than 7 days in `test-images` repository
+// Deletes Quay Tags older than 7 days in `test-images` repository by the quay-test-images-tags rule of the janitor policy
 func (Local) CleanupQuayTags() error {
-	quayOrgToken := os.Getenv("DEFAULT_QUAY_ORG_TOKEN")
-	if quayOrgToken == "" {
-		return fmt.Errorf(quayTokenNotFoundError)
-	}
-	quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
-
-	quayClient := quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayOrgToken, quayApiUrl)
-	return cleanupQuayTags(quayClient, quayOrg, "test-images")
+	return runJanitorRules(context.Background(), false, "quay-test-images-tags")
 }
 
-// Deletes the private repos with prefix "build-e2e" or "rhtap-demo"
+// Deletes the private repos older than 7 days with prefixes of e2e tests by the quay-private-repositories rule of the janitor policy
 func (Local) CleanupPrivateRepos() error {
-	repoNamePrefixes := []string{"build-e2e", "rhtap-demo", "multi-platform", "jvm-build"}
-	quayOrgToken := os.Getenv("DEFAULT_QUAY_ORG_TOKEN")
-	if quayOrgToken == "" {
-		return fmt.Errorf(quayTokenNotFoundError)
-	}
-	quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
-
-	quayClient := quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayOrgToken, quayApiUrl)
-	return cleanupPrivateRepos(quayClient, quayOrg, repoNamePrefixes)
+	return runJanitorRules(context.Background(), false, "quay-private-repositories")
 }
 
 func (ci CI) Bootstrap() error {
@@ -294,31 +225,18 @@ func (ci CI) TestE2E() error {
 }
 
 func RunE2ETests() error {
-	labelFilter := utils.GetEnv("E2E_TEST_SUITE_LABEL", "!upgrade-create && !upgrade-verify && !upgrade-cleanup && !release-pipelines")
-	return runTests(labelFilter, "e2e-report.xml")
+	return runTests(config.Get(config.E2ETestSuiteLabelEnv), "e2e-report.xml")
 }
 
+// Checks configuration and prerequisites of the suites selected by the label filter
 func PreflightChecks() error {
-	requiredEnv := []string{
-		"GITHUB_TOKEN",
-		"QUAY_TOKEN",
-		"DEFAULT_QUAY_ORG",
-		"DEFAULT_QUAY_ORG_TOKEN",
-	}
-	missingEnv := []string{}
-	for _, env := range requiredEnv {
-		if os.Getenv(env) == "" {
-			missingEnv = append(missingEnv, env)
-		}
-	}
-	if len(missingEnv) != 0 {
-		return fmt.Errorf("required env vars containing secrets (%s) not defined or empty", strings.Join(missingEnv, ","))
+	labelFilter := activeLabelFilter()
+	if _, err := config.Load(labelFilter); err != nil {
+		return fmt.Errorf("%v - see docs/Configuration.md or run 'mage config:print'", err)
 	}
 
-	for _, binaryName := range requiredBinaries {
-		if err := sh.Run("which", binaryName); err != nil {
-			return fmt.Errorf("binary %s not found in PATH - please install it first", binaryName)
-		}
+	if err := checkSuitePrerequisites(labelFilter, "tests", suites.NewPrerequisiteChecker(nil)); err != nil {
+		return err
 	}
 
 	if err := sh.RunV("go", "install", "-mod=mod", "github.com/onsi/ginkgo/v2/ginkgo"); err != nil {
@@ -328,211 +246,6 @@ func PreflightChecks() error {
 	return nil
 }
 
-func setRequiredEnvVars() error {
-
-	// RHTAP Nightly E2E job
-	// The job name is taken from https://github.com/openshift/release/blob/f03153fa4ad36c0e10050d977e7f0f7619d2163a/ci-operator/config/redhat-appstudio/infra-deployments/redhat-appstudio-infra-deployments-main.yaml#L59C7-L59C35
-	if strings.Contains(jobName, "appstudio-e2e-tests-periodic") {
-		requiresMultiPlatformTests = true
-		requiresSprayProxyRegistering = true
-		return nil
-	}
-
-	if openshiftJobSpec.Refs.Repo != "e2e-tests" {
-
-		if strings.HasSuffix(jobName, "-service-e2e") || strings.Contains(jobName, "image-controller") {
-			var envVarPrefix, imageTagSuffix, testSuiteLabel string
-			sp := strings.Split(os.Getenv("COMPONENT_IMAGE"), "@")
-
-			switch {
-			case strings.Contains(jobName, "application-service"):
-				requiresSprayProxyRegistering = true
-				envVarPrefix = "HAS"
-				imageTagSuffix = "has-image"
-				testSuiteLabel = "e2e-demo,byoc"
-			case strings.Contains(jobName, "release-service"):
-				envVarPrefix = "RELEASE_SERVICE"
-				imageTagSuffix = "release-service-image"
-				testSuiteLabel = "release-service"
-			case strings.Contains(jobName, "integration-service"):
-				requiresSprayProxyRegistering = true
-				envVarPrefix = "INTEGRATION_SERVICE"
-				imageTagSuffix = "integration-service-image"
-				testSuiteLabel = "integration-service"
-			case strings.Contains(jobName, "jvm-build-service"):
-				envVarPrefix = "JVM_BUILD_SERVICE"
-				imageTagSuffix = "jvm-build-service-image"
-				testSuiteLabel = "jvm-build"
-				// Since CI requires to have default values for dependency images
-				// (https://github.com/openshift/release/blob/master/ci-operator/step-registry/redhat-appstudio/e2e/redhat-appstudio-e2e-ref.yaml#L15)
-				// we cannot let these env vars to have identical names in CI as those env vars used in tests
-				// e.g. JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE, otherwise those images they are referencing wouldn't
-				// be always relevant for tests and tests would be failing
-				os.Setenv(fmt.Sprintf("%s_REQPROCESSOR_IMAGE", envVarPrefix), os.Getenv("CI_JBS_REQPROCESSOR_IMAGE"))
-				os.Setenv(fmt.Sprintf("%s_CACHE_IMAGE", envVarPrefix), os.Getenv("CI_JBS_CACHE_IMAGE"))
-
-				klog.Infof("going to override default Tekton bundle s2i-java task for the purpose of testing jvm-build-service PR")
-				var err error
-				var defaultBundleRef string
-				var tektonObj runtime.Object
-
-				tag := fmt.Sprintf("%d-%s", time.Now().Unix(), util.GenerateRandomString(4))
-				quayOrg := utils.GetEnv(constants.DEFAULT_QUAY_ORG_ENV, constants.DefaultQuayOrg)
-				newS2iJavaTaskImg := strings.ReplaceAll(constants.DefaultImagePushRepo, constants.DefaultQuayOrg, quayOrg)
-				var newS2iJavaTaskRef, _ = name.ParseReference(fmt.Sprintf("%s:task-bundle-%s", newS2iJavaTaskImg, tag))
-				newJavaBuilderPipelineImg := strings.ReplaceAll(constants.DefaultImagePushRepo, constants.DefaultQuayOrg, quayOrg)
-				var newJavaBuilderPipelineRef, _ = name.ParseReference(fmt.Sprintf("%s:pipeline-bundle-%s", newJavaBuilderPipelineImg, tag))
-				var newReqprocessorImage = os.Getenv("JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE")
-				var newTaskYaml, newPipelineYaml []byte
-
-				if err = utils.CreateDockerConfigFile(os.Getenv("QUAY_TOKEN")); err != nil {
-					return fmt.Errorf("failed to create docker config file: %+v", err)
-				}
-				if defaultBundleRef, err = tekton.GetDefaultPipelineBundleRef(constants.BuildPipelineSelectorYamlURL, "Java"); err != nil {
-					return fmt.Errorf("failed to get the pipeline bundle ref: %+v", err)
-				}
-				if tektonObj, err = tekton.ExtractTektonObjectFromBundle(defaultBundleRef, "pipeline", "java-builder"); err != nil {
-					return fmt.Errorf("failed to extract the Tekton Pipeline from bundle: %+v", err)
-				}
-				javaPipelineObj := tektonObj.(*tektonapi.Pipeline)
-
-				var currentS2iJavaTaskRef string
-				for _, t := range javaPipelineObj.PipelineSpec().Tasks {
-					params := t.TaskRef.Params
-					var lastBundle *tektonapi.Param
-					s2iTask := false
-					for i, param := range params {
-						if param.Name == "bundle" {
-							lastBundle = &t.TaskRef.Params[i]
-						} else if param.Name == "name" && param.Value.StringVal == "s2i-java" {
-							s2iTask = true
-						}
-					}
-					if s2iTask {
-						currentS2iJavaTaskRef = lastBundle.Value.StringVal
-						klog.Infof("Found current task ref %s", currentS2iJavaTaskRef)
-						lastBundle.Value = *tektonapi.NewStructuredValues(newS2iJavaTaskRef.String())
-						break
-					}
-				}
-				if tektonObj, err = tekton.ExtractTektonObjectFromBundle(currentS2iJavaTaskRef, "task", "s2i-java"); err != nil {
-					return fmt.Errorf("failed to extract the Tekton Task from bundle: %+v", err)
-				}
-				taskObj := tektonObj.(*tektonapi.Task)
-
-				for i, s := range taskObj.Spec.Steps {
-					if s.Name == "analyse-dependencies-java-sbom" {
-						taskObj.Spec.Steps[i].Image = newReqprocessorImage
-					}
-				}
-
-				if newTaskYaml, err = yaml.Marshal(taskObj); err != nil {
-					return fmt.Errorf("error when marshalling a new task to YAML: %v", err)
-				}
-				if newPipelineYaml, err = yaml.Marshal(javaPipelineObj); err != nil {
-					return fmt.Errorf("error when marshalling a new pipeline to YAML: %v", err)
-				}
-
-				keychain := authn.NewMultiKeychain(authn.DefaultKeychain)
-				authOption := remoteimg.WithAuthFromKeychain(keychain)
-
-				if err = tekton.BuildAndPushTektonBundle(newTaskYaml, newS2iJavaTaskRef, authOption); err != nil {
-					return fmt.Errorf("error when building/pushing a tekton task bundle: %v", err)
-				}
-				if err = tekton.BuildAndPushTektonBundle(newPipelineYaml, newJavaBuilderPipelineRef, authOption); err != nil {
-					return fmt.Errorf("error when building/pushing a tekton pipeline bundle: %v", err)
-				}
-				os.Setenv(constants.CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE_ENV, newJavaBuilderPipelineRef.String())
-			case strings.Contains(jobName, "build-service"):
-				requiresSprayProxyRegistering = true
-				envVarPrefix = "BUILD_SERVICE"
-				imageTagSuffix = "build-service-image"
-				testSuiteLabel = "build"
-			case strings.Contains(jobName, "image-controller"):
-				requiresSprayProxyRegistering = true
-				envVarPrefix = "IMAGE_CONTROLLER"
-				imageTagSuffix = "image-controller-image"
-				testSuiteLabel = "image-controller"
-			case strings.Contains(jobName, "remote-secret-service"):
-				envVarPrefix = "REMOTE_SECRET"
-				imageTagSuffix = "remote-secret-image"
-				testSuiteLabel = "remote-secret"
-			case strings.Contains(jobName, "spi-service"):
-				envVarPrefix = "SPI_OPERATOR"
-				imageTagSuffix = "spi-image"
-				testSuiteLabel = "spi-suite"
-				// spi also requires service-provider-integration-oauth image
-				im := strings.Split(os.Getenv("CI_SPI_OAUTH_IMAGE"), "@")
-				os.Setenv("SPI_OAUTH_IMAGE_REPO", im[0])
-				os.Setenv("SPI_OAUTH_IMAGE_TAG", fmt.Sprintf("redhat-appstudio-%s", "spi-oauth-image"))
-			case strings.Contains(jobName, "multi-platform-controller"):
-				envVarPrefix = "MULTI_PLATFORM_CONTROLLER"
-				imageTagSuffix = "multi-platform-controller"
-				testSuiteLabel = "multi-platform"
-				requiresMultiPlatformTests = true
-			}
-
-			os.Setenv(fmt.Sprintf("%s_IMAGE_REPO", envVarPrefix), sp[0])
-			os.Setenv(fmt.Sprintf("%s_IMAGE_TAG", envVarPrefix), fmt.Sprintf("redhat-appstudio-%s", imageTagSuffix))
-			// "rehearse" jobs metadata are not relevant for testing
-			if !strings.Contains(jobName, "rehearse") {
-				os.Setenv(fmt.Sprintf("%s_PR_OWNER", envVarPrefix), pr.RemoteName)
-				os.Setenv(fmt.Sprintf("%s_PR_SHA", envVarPrefix), pr.CommitSHA)
-			}
-
-			os.Setenv("E2E_TEST_SUITE_LABEL", testSuiteLabel)
-
-		} else if openshiftJobSpec.Refs.Repo == "infra-deployments" {
-			requiresMultiPlatformTests = true
-			requiresSprayProxyRegistering = true
-			os.Setenv("INFRA_DEPLOYMENTS_ORG", pr.RemoteName)
-			os.Setenv("INFRA_DEPLOYMENTS_BRANCH", pr.BranchName)
-			/* Disabling "build tests" temporary due:
-			TODO: Enable when issues are done:
-			https://issues.redhat.com/browse/RHTAPBUGS-992, https://issues.redhat.com/browse/RHTAPBUGS-991, https://issues.redhat.com/browse/RHTAPBUGS-989,
-			https://issues.redhat.com/browse/RHTAPBUGS-978,https://issues.redhat.com/browse/RHTAPBUGS-956
-			*/
-			os.Setenv("E2E_TEST_SUITE_LABEL", "e2e-demo,rhtap-demo,spi-suite,remote-secret,integration-service,ec,byoc,build-templates,multi-platform")
-		} else if strings.Contains(jobName, "release-service-catalog") { // release-service-catalog jobs (pull, rehearsal)
-			envVarPrefix := "RELEASE_SERVICE"
-			// "rehearse" jobs metadata are not relevant for testing
-			if !strings.Contains(jobName, "rehearse") {
-				os.Setenv(fmt.Sprintf("%s_CATALOG_URL", envVarPrefix), fmt.Sprintf("https://github.com/%s/%s", pr.RemoteName, pr.RepoName))
-				os.Setenv(fmt.Sprintf("%s_CATALOG_REVISION", envVarPrefix), pr.CommitSHA)
-				if isPRPairingRequired("release-service") {
-					os.Setenv(fmt.Sprintf("%s_IMAGE_REPO", envVarPrefix),
-						"quay.io/redhat-user-workloads/rhtap-release-2-tenant/release-service/release-service")
-					pairedSha := getPairedCommitSha("release-service")
-					if pairedSha != "" {
-						os.Setenv(fmt.Sprintf("%s_IMAGE_TAG", envVarPrefix), fmt.Sprintf("on-pr-%s", pairedSha))
-					}
-					os.Setenv(fmt.Sprintf("%s_PR_OWNER", envVarPrefix), pr.RemoteName)
-					os.Setenv(fmt.Sprintf("%s_PR_SHA", envVarPrefix), pairedSha)
-				}
-			}
-			if os.Getenv("REL_IMAGE_CONTROLLER_QUAY_ORG") != "" {
-				os.Setenv("IMAGE_CONTROLLER_QUAY_ORG", os.Getenv("REL_IMAGE_CONTROLLER_QUAY_ORG"))
-			}
-			if os.Getenv("REL_IMAGE_CONTROLLER_QUAY_TOKEN") != "" {
-				os.Setenv("IMAGE_CONTROLLER_QUAY_TOKEN", os.Getenv("REL_IMAGE_CONTROLLER_QUAY_TOKEN"))
-			}
-			os.Setenv("E2E_TEST_SUITE_LABEL", "release-pipelines")
-		} else { // openshift/release rehearse job for e2e-tests/infra-deployments repos
-			requiresMultiPlatformTests = true
-			requiresSprayProxyRegistering = true
-		}
-	} else { // e2e-tests repository PR
-		requiresMultiPlatformTests = true
-		requiresSprayProxyRegistering = true
-		if isPRPairingRequired("infra-deployments") {
-			os.Setenv("INFRA_DEPLOYMENTS_ORG", pr.RemoteName)
-			os.Setenv("INFRA_DEPLOYMENTS_BRANCH", pr.BranchName)
-		}
-	}
-
-	return nil
-}
-
 func setupMultiPlatformTests() error {
 	klog.Infof("going to create new Tekton bundle remote-build for the purpose of testing multi-platform-controller PR")
 	var err error
@@ -540,12 +253,12 @@ func setupMultiPlatformTests() error {
 	var tektonObj runtime.Object
 
 	tag := fmt.Sprintf("%d-%s", time.Now().Unix(), util.GenerateRandomString(4))
-	quayOrg := utils.GetEnv(constants.DEFAULT_QUAY_ORG_ENV, constants.DefaultQuayOrg)
+	quayOrg := config.Get(constants.DEFAULT_QUAY_ORG_ENV)
 	newMultiPlatformBuilderPipelineImg := strings.ReplaceAll(constants.DefaultImagePushRepo, constants.DefaultQuayOrg, quayOrg)
 	var newRemotePipeline, _ = name.ParseReference(fmt.Sprintf("%s:pipeline-bundle-%s", newMultiPlatformBuilderPipelineImg, tag))
 	var newPipelineYaml []byte
 
-	if err = utils.CreateDockerConfigFile(os.Getenv("QUAY_TOKEN")); err != nil {
+	if err = utils.CreateDockerConfigFile(config.Get("QUAY_TOKEN")); err != nil {
 		return fmt.Errorf("failed to create docker config file: %+v", err)
 	}
 	if defaultBundleRef, err = tekton.GetDefaultPipelineBundleRef(constants.BuildPipelineSelectorYamlURL, "Docker build"); err != nil {
@@ -603,14 +316,18 @@ func setupMultiPlatformTests() error {
 func BootstrapCluster() error {
 	envVars := map[string]string{}
 
-	if os.Getenv("CI") == "true" {
+	if err := checkBootstrapBinaries(); err != nil {
+		return err
+	}
+
+	if config.Get("CI") == "true" {
 		if err := setRequiredEnvVars(); err != nil {
 			return fmt.Errorf("error when setting up required env vars: %v", err)
 		}
-		if os.Getenv("REPO_NAME") == "e2e-tests" {
+		if pr.RepoName == "e2e-tests" {
 			// Some scripts in infra-deployments repo are referencing scripts/utils in e2e-tests repo
 			// This env var allows to test changes introduced in "e2e-tests" repo PRs in CI
-			envVars["E2E_TESTS_COMMIT_SHA"] = os.Getenv("PULL_PULL_SHA")
+			envVars["E2E_TESTS_COMMIT_SHA"] = pr.CommitSHA
 		}
 	}
 
@@ -618,47 +335,11 @@ func BootstrapCluster() error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize installation controller: %+v", err)
 	}
+	ic.InfraDeploymentsPullRequests = pairedPRsToMerge("infra-deployments")
 
 	return ic.InstallAppStudioPreviewMode()
 }
 
-func isPRPairingRequired(repoForPairing string) bool {
-	var pullRequests []gh.PullRequest
-
-	url := fmt.Sprintf("https://api.github.com/repos/redhat-appstudio/%s/pulls?per_page=100", repoForPairing)
-	if err := sendHttpRequestAndParseResponse(url, "GET", &pullRequests); err != nil {
-		klog.Infof("cannot determine %s Github branches for author %s: %v. will stick with the redhat-appstudio/%s main branch for running tests", repoForPairing, pr.RemoteName, err, repoForPairing)
-		return false
-	}
-
-	for _, pull := range pullRequests {
-		if pull.GetHead().GetRef() == pr.BranchName && pull.GetUser().GetLogin() == pr.RemoteName {
-			return true
-		}
-	}
-
-	return false
-}
-
-func getPairedCommitSha(repoForPairing string) string {
-	var pullRequests []gh.PullRequest
-
-	url := fmt.Sprintf("https://api.github.com/repos/redhat-appstudio/%s/pulls?per_page=100", repoForPairing)
-	if err := sendHttpRequestAndParseResponse(url, "GET", &pullRequests); err != nil {
-		klog.Infof("cannot determine %s Github branches for author %s: %v. will stick with the redhat-appstudio/%s main branch for running tests", repoForPairing, pr.RemoteName, err, repoForPairing)
-		return ""
-	}
-
-	for _, pull := range pullRequests {
-		if pull.GetHead().GetRef() == pr.BranchName && pull.GetUser().GetLogin() == pr.RemoteName {
-			return pull.GetHead().GetSHA()
-		}
-	}
-
-	klog.Infof("cannot determine %s commit sha for author %s", repoForPairing, pr.RemoteName)
-	return ""
-}
-
 // Generates ginkgo test suite files under the cmd/ directory.
 func GenerateTestSuiteFile(packageName string) error {
 
@@ -687,34 +368,10 @@ func GenerateTestSuiteFile(packageName string) error {
 	return nil
 }
 
-// Remove all webhooks which with 1 day lifetime. By default will delete webooks from redhat-appstudio-qe
+// Remove all webhooks which with 1 day lifetime by the github-webhooks rule of the janitor policy.
+// By default will delete webooks from redhat-appstudio-qe
 func CleanWebHooks() error {
-	token := utils.GetEnv(constants.GITHUB_TOKEN_ENV, "")
-	if token == "" {
-		return fmt.Errorf("empty GITHUB_TOKEN env. Please provide a valid github token")
-	}
-
-	githubOrg := utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
-	gh, err := github.NewGithubClient(token, githubOrg)
-	if err != nil {
-		return err
-	}
-	for _, repo := range repositoriesWithWebhooks {
-		webhookList, err := gh.ListRepoWebhooks(repo)
-		if err != nil {
-			return err
-		}
-		for _, wh := range webhookList {
-			dayDuration, _ := time.ParseDuration("24h")
-			if time.Since(wh.GetCreatedAt()) > dayDuration {
-				klog.Infof("removing webhook: %s, git_organization: %s, git_repository: %s", wh.GetName(), githubOrg, repo)
-				if err := gh.DeleteWebhook(repo, wh.GetID()); err != nil {
-					return fmt.Errorf("failed to delete webhook: %v, repo: %s", wh.Name, repo)
-				}
-			}
-		}
-	}
-	return nil
+	return runJanitorRules(context.Background(), false, "github-webhooks")
 }
 
 // Generate a Text Outline file from a Ginkgo Spec
@@ -854,13 +511,18 @@ func AppendFrameworkDescribeGoFile(specFile string) error {
 
 func newSprayProxy() (*sprayproxy.SprayProxyConfig, error) {
 	var sprayProxyUrl, sprayProxyToken string
-	if sprayProxyUrl = os.Getenv("QE_SPRAYPROXY_HOST"); sprayProxyUrl == "" {
+	if sprayProxyUrl = config.Get("QE_SPRAYPROXY_HOST"); sprayProxyUrl == "" {
 		return nil, fmt.Errorf("env var QE_SPRAYPROXY_HOST is not set")
 	}
-	if sprayProxyToken = os.Getenv("QE_SPRAYPROXY_TOKEN"); sprayProxyToken == "" {
+	if sprayProxyToken = config.Get("QE_SPRAYPROXY_TOKEN"); sprayProxyToken == "" {
 		return nil, fmt.Errorf("env var QE_SPRAYPROXY_TOKEN is not set")
 	}
-	return sprayproxy.NewSprayProxyConfig(sprayProxyUrl, sprayProxyToken)
+	// SprayProxy exposed via a route with a self-signed certificate needs QE_SPRAYPROXY_SKIP_TLS_VERIFY=true
+	skipTLSVerify, err := strconv.ParseBool(config.Get("QE_SPRAYPROXY_SKIP_TLS_VERIFY"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse QE_SPRAYPROXY_SKIP_TLS_VERIFY env var: %v", err)
+	}
+	return sprayproxy.NewSprayProxyConfig(sprayProxyUrl, sprayProxyToken, sprayproxy.WithInsecureSkipVerify(skipTLSVerify))
 }
 
 func registerPacServer() error {
@@ -875,7 +537,9 @@ func registerPacServer() error {
 	if err != nil {
 		return fmt.Errorf("failed to get PaC host: %+v", err)
 	}
-	_, err = sprayProxyConfig.RegisterServer(pacHost)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	_, err = sprayProxyConfig.RegisterServer(ctx, pacHost)
 	if err != nil {
 		return fmt.Errorf("error when registering PaC server %s to SprayProxy server %s: %+v", pacHost, sprayProxyConfig.BaseURL, err)
 	}
@@ -903,7 +567,9 @@ func unregisterPacServer() error {
 	if err != nil {
 		return fmt.Errorf("failed to get PaC host: %+v", err)
 	}
-	_, err = sprayProxyConfig.UnregisterServer(pacHost)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	_, err = sprayProxyConfig.UnregisterServer(ctx, pacHost)
 	if err != nil {
 		return fmt.Errorf("error when unregistering PaC server %s from SprayProxy server %s: %+v", pacHost, sprayProxyConfig.BaseURL, err)
 	}
@@ -918,7 +584,9 @@ func unregisterPacServer() error {
 }
 
 func printRegisteredPacServers() error {
-	servers, err := sprayProxyConfig.GetServers()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	servers, err := sprayProxyConfig.GetServers(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get registered PaC servers from SprayProxy: %+v", err)
 	}
@@ -1027,6 +695,9 @@ func UpgradeTestsWorkflow() error {
 }
 
 func BootstrapClusterForUpgrade() (*installation.InstallAppStudio, error) {
+	if err := checkBootstrapBinaries(); err != nil {
+		return nil, err
+	}
 	//Use main branch of infra-deployments in redhat-appstudio org as default version for upgrade
 	ic, err := installation.NewAppStudioInstallControllerUpgrade("redhat-appstudio", "main")
 	if err != nil {
@@ -1037,7 +708,7 @@ func BootstrapClusterForUpgrade() (*installation.InstallAppStudio, error) {
 }
 
 func UpgradeCluster() error {
-	return MergePRInRemote(utils.GetEnv("UPGRADE_BRANCH", ""), utils.GetEnv("UPGRADE_FORK_ORGANIZATION", "redhat-appstudio"), "./tmp/infra-deployments")
+	return MergePRInRemote(config.Get("UPGRADE_BRANCH"), config.Get("UPGRADE_FORK_ORGANIZATION"), "./tmp/infra-deployments", pairingManifest.PullRequests("infra-deployments")...)
 }
 
 func CheckClusterAfterUpgrade(ic *installation.InstallAppStudio) error {
@@ -1058,9 +729,12 @@ func CleanWorkload() error {
 
 func runTests(labelsToRun string, junitReportFile string) error {
 	// added --output-interceptor-mode=none to mitigate RHTAPBUGS-34
-	return sh.RunV("ginkgo", "-p", "--output-interceptor-mode=none", "--timeout=90m", fmt.Sprintf("--output-dir=%s", artifactDir), "--junit-report="+junitReportFile, "--label-filter="+labelsToRun, "./cmd", "--", "--generate-rppreproc-report=true", fmt.Sprintf("--rp-preproc-dir=%s", artifactDir))
+	return sh.RunV("ginkgo", "-p", "--output-interceptor-mode=none", "--timeout=90m", fmt.Sprintf("--output-dir=%s", artifactDir), "--junit-report="+junitReportFile, "--label-filter="+labelsToRun, "./cmd", "--", "--generate-rppreproc-report=true", fmt.Sprintf("--rp-preproc-dir=%s", artifactDir), fmt.Sprintf("--timing-report=%s/step-timings.json", artifactDir), fmt.Sprintf("--html-report=%s/e2e-report.html", artifactDir))
 }
 
+// CleanupRegisteredPacServers unregisters PaC servers whose route is not reachable anymore or whose cluster is gone
+// by the sprayproxy-stale-backends rule of the janitor policy.
+// Set SPRAYPROXY_REAP_DRY_RUN=true to only list the servers that would be unregistered.
 func CleanupRegisteredPacServers() error {
 	var err error
 	sprayProxyConfig, err = newSprayProxy()
@@ -1068,22 +742,17 @@ func CleanupRegisteredPacServers() error {
 		return fmt.Errorf("failed to initialize SprayProxy config: %+v", err)
 	}
 
-	servers, err := sprayProxyConfig.GetServers()
-	if err != nil {
-		return fmt.Errorf("failed to get registered PaC servers from SprayProxy: %+v", err)
+	klog.Infof("Before cleaningup Pac servers...")
+	if err = printRegisteredPacServers(); err != nil {
+		klog.Error(err)
 	}
-	klog.Infof("Before cleaningup Pac servers, the registered PaC servers: %v", servers)
 
-	for _, server := range strings.Split(servers, ",") {
-		// Verify if the server is a valid host, if not, unregister it
-		if !isValidPacHost(server) {
-			_, err := sprayProxyConfig.UnregisterServer(strings.TrimSpace(server))
-			if err != nil {
-				return fmt.Errorf("error when unregistering PaC server %s from SprayProxy server %s: %+v", server, sprayProxyConfig.BaseURL, err)
-			}
-			klog.Infof("Cleanup invalid PaC server: %s", server)
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	if err := runJanitorRules(ctx, config.Get("SPRAYPROXY_REAP_DRY_RUN") == "true", "sprayproxy-stale-backends"); err != nil {
+		return fmt.Errorf("error when unregistering PaC servers from SprayProxy server %s: %+v", sprayProxyConfig.BaseURL, err)
 	}
+
 	klog.Infof("After cleaningup Pac servers...")
 	err = printRegisteredPacServers()
 	if err != nil {
@@ -1091,8 +760,3 @@ func CleanupRegisteredPacServers() error {
 	}
 	return nil
 }
-
-func isValidPacHost(server string) bool {
-	_, err := http.Get(strings.TrimSpace(server))
-	return err == nil
-}
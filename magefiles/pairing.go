@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// pairingManifest lists PRs to test together with the tested PR, see docs/OpenShiftCI.md
+var pairingManifest ci.PairingManifest
+
+// mergedRepos are repositories checked out by the tests, so several of their PRs can be paired by merging them
+var mergedRepos = []string{"e2e-tests", "infra-deployments"}
+
+// getPairedPR returns the PR of the repository paired with the tested PR, or nil if there is none.
+// The first PR of the repository in the pairing manifest takes precedence over a PR with the same branch and author.
+func getPairedPR(repoForPairing string) *ci.PullRequest {
+	if prs := pairingManifest.PullRequests(repoForPairing); len(prs) > 0 {
+		return prs[0]
+	}
+	paired, err := ciProvider.PairedPullRequest(repoForPairing)
+	if err != nil {
+		klog.Infof("cannot determine %s Github branches for author %s: %v. will stick with the redhat-appstudio/%s main branch for running tests", repoForPairing, pr.RemoteName, err, repoForPairing)
+		return nil
+	}
+	return paired
+}
+
+// pairedPRsToMerge returns PRs of the repository from the pairing manifest, which have to be merged on top of the tested PR,
+// or on top of the first paired PR if the repository is not the tested one
+func pairedPRsToMerge(repo string) []*ci.PullRequest {
+	prs := pairingManifest.PullRequests(repo)
+	if pr.RepoName != repo {
+		if len(prs) == 0 {
+			return nil
+		}
+		return prs[1:]
+	}
+	toMerge := []*ci.PullRequest{}
+	for _, p := range prs {
+		if p.Organization != pr.Organization || p.Number != pr.Number {
+			toMerge = append(toMerge, p)
+		}
+	}
+	return toMerge
+}
+
+// mergePairedPRs merges PRs of the repository from the pairing manifest into its checkout in repoPath
+func mergePairedPRs(repoPath, repo string) error {
+	return ci.MergePullRequests(repoPath, pairedPRsToMerge(repo))
+}
+
+// loadPairingManifest loads the pairing manifest, resolves its PRs and records them in the artifacts
+func loadPairingManifest() error {
+	manifest, err := ci.LoadPairingManifest(ciProvider)
+	if err != nil || manifest == nil {
+		return err
+	}
+	routes, err := loadJobRoutes(jobRoutesYaml)
+	if err != nil {
+		return err
+	}
+	if err := validatePairingManifest(manifest, routes); err != nil {
+		return err
+	}
+	if err := manifest.Resolve(pr.Author); err != nil {
+		return fmt.Errorf("error when resolving pairing manifest: %v", err)
+	}
+	for _, e := range manifest {
+		klog.Infof("pairing with %s at %s", e, e.Resolved.CommitSHA)
+	}
+	pairingManifest = manifest
+	return manifest.WriteArtifact(artifactDir)
+}
+
+// validatePairingManifest checks that all PRs in the manifest can be paired, before anything is deployed
+func validatePairingManifest(manifest ci.PairingManifest, routes *JobRoutes) error {
+	for _, repo := range manifest.Repos() {
+		entries := manifest.Entries(repo)
+		switch {
+		case utils.Contains(mergedRepos, repo):
+			continue
+		case len(entries) > 1:
+			return fmt.Errorf("%s and %s cannot be paired at the same time, only PRs of %v repositories can be merged", entries[0], entries[1], mergedRepos)
+		case pairedRepoHooks[repo] != nil:
+			continue
+		case entries[0].Image == "":
+			return fmt.Errorf("%s cannot be paired without the image built from it", entries[0])
+		case strings.Contains(entries[0].Image, "@"):
+			return fmt.Errorf("image %s of %s has to be referenced by a tag", entries[0].Image, entries[0])
+		case routes.componentImage(repo) == nil:
+			return fmt.Errorf("%s cannot be paired, there is no job route with the component image of %s repository", entries[0], repo)
+		}
+	}
+	return nil
+}
+
+// applyPairingManifest configures tests to use PRs from the pairing manifest, which are not paired by the route
+func applyPairingManifest(routes *JobRoutes, route *JobRoute) error {
+	for _, repo := range pairingManifest.Repos() {
+		if repo == pr.RepoName || repo == "e2e-tests" || utils.Contains(route.PairedRepos, repo) {
+			continue
+		}
+		paired := getPairedPR(repo)
+		if hook, ok := pairedRepoHooks[repo]; ok {
+			if err := hook(paired); err != nil {
+				return fmt.Errorf("pairing with %s repository failed: %v", repo, err)
+			}
+			continue
+		}
+		image := pairingManifest.Entries(repo)[0].Image
+		imageRepo, tag := splitImageTag(image)
+		routes.componentImage(repo).setEnv(imageRepo, tag, paired)
+		klog.Infof("using %s image %s", repo, image)
+	}
+	return nil
+}
+
+// splitImageTag splits the image reference to the repository and the tag
+func splitImageTag(image string) (string, string) {
+	for i := len(image) - 1; i >= 0 && image[i] != '/'; i-- {
+		if image[i] == ':' {
+			return image[:i], image[i+1:]
+		}
+	}
+	return image, "latest"
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePairingManifest(t *testing.T) {
+	routes, err := loadJobRoutes(jobRoutesYaml)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		manifest    string
+		expectedErr string
+	}{
+		{"- {repo: e2e-tests, pr: 1}\n- {repo: infra-deployments, pr: 2}\n- {repo: infra-deployments, pr: 3}\n- {repo: release-service, pr: 4}", ""},
+		{"- {repo: build-service, pr: 1, image: 'quay.io/redhat-user-workloads/rhtap-build-tenant/build-service:on-pr-abcd'}", ""},
+		{"- {repo: release-service, pr: 1}\n- {repo: release-service, pr: 2}", "redhat-appstudio/release-service#1 and redhat-appstudio/release-service#2 cannot be paired at the same time"},
+		{"- {repo: build-service, pr: 1}", "redhat-appstudio/build-service#1 cannot be paired without the image built from it"},
+		{"- {repo: build-service, pr: 1, image: 'quay.io/redhat-user-workloads/rhtap-build-tenant/build-service@sha256:1234'}", "has to be referenced by a tag"},
+		{"- {repo: unknown, pr: 1, image: 'quay.io/redhat-user-workloads/rhtap-build-tenant/unknown:latest'}", "there is no job route with the component image of unknown repository"},
+	}
+
+	for _, tc := range testCases {
+		m, err := ci.ParsePairingManifest([]byte(tc.manifest))
+		assert.NoError(t, err)
+		if tc.expectedErr == "" {
+			assert.NoError(t, validatePairingManifest(m, routes), tc.manifest)
+		} else {
+			assert.ErrorContains(t, validatePairingManifest(m, routes), tc.expectedErr, tc.manifest)
+		}
+	}
+}
+
+func TestApplyPairingManifest(t *testing.T) {
+	routes, err := loadJobRoutes(jobRoutesYaml)
+	assert.NoError(t, err)
+	defer func() { pairingManifest = nil }()
+	pairingManifest = ci.PairingManifest{
+		{Repo: "infra-deployments", PR: 2, Resolved: &ci.PullRequest{RepoName: "infra-deployments", Number: 2, RemoteName: "other", BranchName: "infra", CommitSHA: "2222"}},
+		{Repo: "build-service", PR: 3, Image: "quay.io/org/build-service:on-pr-3333", Resolved: &ci.PullRequest{RepoName: "build-service", Number: 3, RemoteName: "author", CommitSHA: "3333"}},
+		{Repo: "infra-deployments", PR: 4, Resolved: &ci.PullRequest{RepoName: "infra-deployments", Number: 4, RemoteName: "author", CommitSHA: "4444"}},
+		{Repo: "e2e-tests", PR: 5, Resolved: &ci.PullRequest{Organization: "redhat-appstudio", RepoName: "e2e-tests", Number: 5, RemoteName: "author", CommitSHA: "5555"}},
+	}
+	for _, env := range []string{"INFRA_DEPLOYMENTS_ORG", "INFRA_DEPLOYMENTS_BRANCH", "BUILD_SERVICE_IMAGE_REPO", "BUILD_SERVICE_IMAGE_TAG", "BUILD_SERVICE_PR_OWNER", "BUILD_SERVICE_PR_SHA"} {
+		t.Setenv(env, "")
+	}
+	pr = &ci.PullRequest{Organization: "redhat-appstudio", RepoName: "e2e-tests", Number: 1}
+
+	// the first infra-deployments PR is cloned, the others are merged into it
+	route := routes.Route("pull-ci-redhat-appstudio-e2e-tests-main-redhat-appstudio-e2e", "e2e-tests")
+	assert.NoError(t, route.apply())
+	assert.NoError(t, applyPairingManifest(routes, route))
+	assert.Equal(t, "other", os.Getenv("INFRA_DEPLOYMENTS_ORG"))
+	assert.Equal(t, "infra", os.Getenv("INFRA_DEPLOYMENTS_BRANCH"))
+	assert.Equal(t, []*ci.PullRequest{pairingManifest[2].Resolved}, pairedPRsToMerge("infra-deployments"))
+
+	assert.Equal(t, "quay.io/org/build-service", os.Getenv("BUILD_SERVICE_IMAGE_REPO"))
+	assert.Equal(t, "on-pr-3333", os.Getenv("BUILD_SERVICE_IMAGE_TAG"))
+	assert.Equal(t, "author", os.Getenv("BUILD_SERVICE_PR_OWNER"))
+	assert.Equal(t, "3333", os.Getenv("BUILD_SERVICE_PR_SHA"))
+
+	// all e2e-tests PRs except the tested one are merged into it
+	assert.Equal(t, []*ci.PullRequest{pairingManifest[3].Resolved}, pairedPRsToMerge("e2e-tests"))
+	pr.Number = 5
+	assert.Empty(t, pairedPRsToMerge("e2e-tests"))
+}
+
+func TestSplitImageTag(t *testing.T) {
+	repo, tag := splitImageTag("quay.io/org/build-service:on-pr-abcd")
+	assert.Equal(t, "quay.io/org/build-service", repo)
+	assert.Equal(t, "on-pr-abcd", tag)
+
+	repo, tag = splitImageTag("localhost:5000/build-service")
+	assert.Equal(t, "localhost:5000/build-service", repo)
+	assert.Equal(t, "latest", tag)
+}
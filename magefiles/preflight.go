@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redhat-appstudio/e2e-tests/magefiles/testspecs"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
+	"k8s.io/klog/v2"
+)
+
+// activeLabelFilter returns the label filter of the tests to run. In CI it's set by the job route,
+// which is applied only when the cluster is bootstrapped.
+func activeLabelFilter() string {
+	if config.Get("CI") == "true" {
+		if routes, err := loadJobRoutes(jobRoutesYaml); err == nil {
+			if route := routes.Route(jobName, pr.RepoName); route != nil && route.LabelFilter != "" {
+				return route.LabelFilter
+			}
+		}
+	}
+	return config.Get(config.E2ETestSuiteLabelEnv)
+}
+
+// selectedSuites returns suites with specs selected by the label filter, labels of the specs are read
+// from the sources in the tests directory
+func selectedSuites(labelFilter, testsDir string) ([]*suites.Suite, error) {
+	specLabels, err := testspecs.SpecLabels(testsDir)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading labels of specs in %s: %v", testsDir, err)
+	}
+	return suites.Selected(labelFilter, specLabels)
+}
+
+// checkSuitePrerequisites checks env vars and binaries needed by the suites selected by the label filter.
+// CRDs and cluster capabilities are checked before each spec, when the cluster is bootstrapped.
+// Specs of suites with unmet optional prerequisites are skipped, which is only logged.
+func checkSuitePrerequisites(labelFilter, testsDir string, checker *suites.PrerequisiteChecker) error {
+	selected, err := selectedSuites(labelFilter, testsDir)
+	if err != nil {
+		return err
+	}
+	missing := []string{}
+	for _, s := range selected {
+		for _, u := range checker.Unmet(s.Prerequisites, false) {
+			if u.IsOptional {
+				klog.Warningf("specs of %s will be skipped: %s", s.Name, u.Reason)
+				continue
+			}
+			missing = append(missing, fmt.Sprintf("%s (needed by %s)", u.Reason, s.Name))
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("prerequisites of suites selected by label filter %q are not met: %s", labelFilter, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkBootstrapBinaries checks binaries needed for installing RHTAP on the cluster
+func checkBootstrapBinaries() error {
+	checker := suites.NewPrerequisiteChecker(nil)
+	prerequisites := []suites.Prerequisite{}
+	for _, b := range bootstrapBinaries {
+		prerequisites = append(prerequisites, suites.Binary(b))
+	}
+	if unmet := checker.Unmet(prerequisites, false); len(unmet) != 0 {
+		return fmt.Errorf("%s - please install it first", unmet[0].Reason)
+	}
+	return nil
+}
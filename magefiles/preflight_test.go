@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
+	"github.com/stretchr/testify/assert"
+)
+
+// testsDir is the directory of the e2e tests relative to magefiles
+const testsDir = "../tests"
+
+func suiteNames(selected []*suites.Suite) []string {
+	names := []string{}
+	for _, s := range selected {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestSelectedSuites(t *testing.T) {
+	selected, err := selectedSuites("release-service", testsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"release-service-suite"}, suiteNames(selected))
+
+	selected, err = selectedSuites("e2e-demo,byoc,spi-suite", testsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"byoc-suite", "spi-suite"}, suiteNames(selected))
+
+	// labels of nested containers and specs select their suite
+	selected, err = selectedSuites("build-templates", testsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"build-service-suite"}, suiteNames(selected))
+
+	selected, err = selectedSuites("verify-stage", testsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rhtap-demo-suite"}, suiteNames(selected))
+
+	// the filter is tested against all labels of a spec
+	selected, err = selectedSuites("release-pipelines && fbc-tests", testsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"release-pipelines-suite"}, suiteNames(selected))
+
+	selected, err = selectedSuites("!upgrade-create && !upgrade-verify && !upgrade-cleanup && !release-pipelines", testsDir)
+	assert.NoError(t, err)
+	assert.NotContains(t, suiteNames(selected), "upgrade-suite")
+	assert.NotContains(t, suiteNames(selected), "release-pipelines-suite")
+	assert.Contains(t, suiteNames(selected), "build-service-suite")
+
+	_, err = selectedSuites("build &&", testsDir)
+	assert.ErrorContains(t, err, "error when parsing label filter")
+}
+
+func TestCheckSuitePrerequisites(t *testing.T) {
+	for _, env := range []string{"QUAY_TOKEN", "MULTI_PLATFORM_AWS_ACCESS_KEY", "MULTI_PLATFORM_AWS_SECRET_ACCESS_KEY", "MULTI_PLATFORM_AWS_SSH_KEY"} {
+		t.Setenv(env, "")
+	}
+
+	// specs missing optional prerequisites are skipped
+	assert.NoError(t, checkSuitePrerequisites("multi-platform", testsDir, suites.NewPrerequisiteChecker(nil)))
+	// only prerequisites of the selected suites are checked
+	assert.NoError(t, checkSuitePrerequisites("release-service", testsDir, suites.NewPrerequisiteChecker(nil)))
+	assert.EqualError(t, checkSuitePrerequisites("build || release-service", testsDir, suites.NewPrerequisiteChecker(nil)),
+		`prerequisites of suites selected by label filter "build || release-service" are not met: env var QUAY_TOKEN is not set (needed by build-service-suite)`)
+}
+
+func TestActiveLabelFilter(t *testing.T) {
+	defer func(name string, tested *ci.PullRequest) { jobName, pr = name, tested }(jobName, pr)
+	t.Setenv(config.E2ETestSuiteLabelEnv, "")
+	t.Setenv("CI", "")
+	assert.Equal(t, config.DefaultLabelFilter, activeLabelFilter())
+
+	// in CI the filter comes from the job route
+	t.Setenv("CI", "true")
+	jobName = "pull-ci-redhat-appstudio-release-service-main-release-service-e2e"
+	pr = &ci.PullRequest{RepoName: "release-service"}
+	assert.Equal(t, "release-service", activeLabelFilter())
+}
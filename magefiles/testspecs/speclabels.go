@@ -0,0 +1,165 @@
+package testspecs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SpecLabels returns label sets of specs in the Go packages under the directory, keyed by the name of the
+// framework describe function of their suite. Each set contains labels of a container or spec node together
+// with labels of all its parent nodes, which is the set Ginkgo evaluates label filters against.
+func SpecLabels(dir string) (map[string][][]string, error) {
+	specLabels := map[string][][]string{}
+	err := inspectSuiteDescribes(dir, func(_, describe string, ce *ast.CallExpr, constants map[string]string) {
+		specLabels[describe] = append(specLabels[describe], collectLabelSets(ce, nil, constants)...)
+	})
+	return specLabels, err
+}
+
+// PackageLabels returns labels selecting specs of the Go packages under the directory, keyed by the slash-separated
+// path of the package directory. Each framework describe call contributes its first label, or the first labels
+// of its labeled nested nodes if the call has no labels. Packages without describe calls have no entry.
+func PackageLabels(dir string) (map[string][]string, error) {
+	packageLabels := map[string][]string{}
+	err := inspectSuiteDescribes(dir, func(pkgDir, _ string, ce *ast.CallExpr, constants map[string]string) {
+		sets := collectLabelSets(ce, nil, constants)
+		if len(sets[0]) != 0 {
+			sets = sets[:1]
+		} else {
+			sets = sets[1:]
+		}
+		for _, labels := range sets {
+			if len(labels) != 0 && !contains(packageLabels[pkgDir], labels[0]) {
+				packageLabels[pkgDir] = append(packageLabels[pkgDir], labels[0])
+			}
+		}
+	})
+	for _, labels := range packageLabels {
+		sort.Strings(labels)
+	}
+	return packageLabels, err
+}
+
+// inspectSuiteDescribes calls the function for each call of a framework *SuiteDescribe function in the Go packages
+// under the directory, with the directory of the package and string constants declared in it
+func inspectSuiteDescribes(dir string, fn func(pkgDir, describe string, ce *ast.CallExpr, constants map[string]string)) error {
+	fset := token.NewFileSet()
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		pkgs, err := parser.ParseDir(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			constants := stringConstants(pkg)
+			for _, f := range pkg.Files {
+				ast.Inspect(f, func(n ast.Node) bool {
+					ce, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					if len(ce.Args) == 0 {
+						return true
+					}
+					describe := findFrameworkDescribeAstNode(ce)
+					if !strings.HasSuffix(describe.Name, "SuiteDescribe") {
+						return true
+					}
+					fn(filepath.ToSlash(path), describe.Name, ce, constants)
+					return false
+				})
+			}
+		}
+		return nil
+	})
+}
+
+// collectLabelSets returns the label set of the call and label sets of labeled calls nested in it
+func collectLabelSets(ce *ast.CallExpr, parentLabels []string, constants map[string]string) [][]string {
+	labels := append(append([]string{}, parentLabels...), callLabels(ce, constants)...)
+	sets := [][]string{labels}
+	for _, arg := range ce.Args {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			nested, ok := n.(*ast.CallExpr)
+			if !ok || len(callLabels(nested, constants)) == 0 {
+				return true
+			}
+			sets = append(sets, collectLabelSets(nested, labels, constants)...)
+			return false
+		})
+	}
+	return sets
+}
+
+// callLabels returns values of Label arguments of the call, resolving names of string constants of the package
+func callLabels(ce *ast.CallExpr, constants map[string]string) []string {
+	labels := []string{}
+	for _, arg := range ce.Args {
+		label, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if id, ok := label.Fun.(*ast.Ident); !ok || id.Name != "Label" {
+			continue
+		}
+		for _, l := range label.Args {
+			switch expr := l.(type) {
+			case *ast.BasicLit:
+				if expr.Kind == token.STRING {
+					if unquoted, err := strconv.Unquote(expr.Value); err == nil {
+						labels = append(labels, unquoted)
+					}
+				}
+			case *ast.Ident:
+				if v, ok := constants[expr.Name]; ok {
+					labels = append(labels, v)
+				}
+			}
+		}
+	}
+	return labels
+}
+
+// stringConstants returns values of string constants declared in the package
+func stringConstants(pkg *ast.Package) map[string]string {
+	constants := map[string]string{}
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i >= len(vs.Values) {
+						continue
+					}
+					if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if v, err := strconv.Unquote(lit.Value); err == nil {
+							constants[name.Name] = v
+						}
+					}
+				}
+			}
+		}
+	}
+	return constants
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
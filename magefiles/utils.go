@@ -1,16 +1,10 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
-	"regexp"
 	"strings"
-	"sync"
 	"text/template"
 	"time"
 
@@ -22,29 +16,12 @@ import (
 	plumbingHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	sprig "github.com/go-task/slim-sprig"
 	"github.com/magefile/mage/sh"
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/slack"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
-	"github.com/redhat-appstudio/image-controller/pkg/quay"
+	e2eConfig "github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 )
 
-const quayPrefixesToDeleteRegexp = "e2e-demos|has-e2e|multi-comp|build-e2e"
-
-func getRemoteAndBranchNameFromPRLink(url string) (remote, branchName string, err error) {
-	ghRes := &GithubPRInfo{}
-	if err := sendHttpRequestAndParseResponse(url, "GET", ghRes); err != nil {
-		return "", "", err
-	}
-
-	if ghRes.Head.Label == "" {
-		return "", "", fmt.Errorf("failed to get an information about the remote and branch name from PR %s", url)
-	}
-
-	split := strings.Split(ghRes.Head.Label, ":")
-	remote, branchName = split[0], split[1]
-
-	return remote, branchName, nil
-}
-
 func gitCheckoutRemoteBranch(remoteName, branchName string) error {
 	var git = sh.RunCmd("git")
 	for _, arg := range [][]string{
@@ -59,32 +36,6 @@ func gitCheckoutRemoteBranch(remoteName, branchName string) error {
 	return nil
 }
 
-func sendHttpRequestAndParseResponse(url, method string, v interface{}) error {
-	req, err := http.NewRequestWithContext(context.Background(), method, url, nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", os.Getenv("GITHUB_TOKEN")))
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error when sending request to '%s': %+v", url, err)
-	}
-	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error when reading the response body from URL '%s': %+v", url, err)
-	}
-	if res.StatusCode > 299 {
-		return fmt.Errorf("unexpected status code: %d, response body: %s", res.StatusCode, string(body))
-	}
-
-	if err := json.Unmarshal(body, v); err != nil {
-		return fmt.Errorf("error when unmarshalling the response body from URL '%s': %+v", url, err)
-	}
-
-	return nil
-}
-
 func retry(f func() error, attempts int, delay time.Duration) error {
 	var err error
 	for i := 0; i < attempts; i++ {
@@ -167,185 +118,14 @@ func renderTemplate(destination, templatePath string, templateData interface{},
 	return nil
 }
 
-func cleanupQuayReposAndRobots(quayService quay.QuayService, quayOrg string) error {
-	r, err := regexp.Compile(fmt.Sprintf(`^(%s)`, quayPrefixesToDeleteRegexp))
-	if err != nil {
-		return err
-	}
-
-	repos, err := quayService.GetAllRepositories(quayOrg)
-	if err != nil {
-		return err
-	}
-
-	// Key is the repo name without slashes which is the same as robot name
-	// Value is the repo name with slashes
-	reposMap := make(map[string]string)
-
-	for _, repo := range repos {
-		if r.MatchString(repo.Name) {
-			sanitizedRepoName := strings.ReplaceAll(repo.Name, "/", "") // repo name without slashes
-			reposMap[sanitizedRepoName] = repo.Name
-		}
-	}
-
-	robots, err := quayService.GetAllRobotAccounts(quayOrg)
-	if err != nil {
-		return err
-	}
-
-	r, err = regexp.Compile(fmt.Sprintf(`^%s\+(%s)`, quayOrg, quayPrefixesToDeleteRegexp))
-	if err != nil {
-		return err
-	}
-
-	const timeFormat = "Mon, 02 Jan 2006 15:04:05 -0700"
-
-	// Deletes robots and their repos with correct prefix if created more than 24 hours ago
-	for _, robot := range robots {
-		parsed, err := time.Parse(timeFormat, robot.Created)
-		if err != nil {
-			return err
-		}
-
-		// If robot.Name has correct prefix and was created more than 24 hours ago
-		if r.MatchString(robot.Name) && time.Since(parsed) > 24*time.Hour {
-			// Robot name without the name of org which is the same as previous sanitizedRepoName
-			// redhat-appstudio-qe+e2e-demos turns to e2e-demos
-			splitRobotName := strings.Split(robot.Name, "+")
-			if len(splitRobotName) != 2 {
-				return fmt.Errorf("failed to split robot name into 2 parts, got %d parts", len(splitRobotName))
-			}
-			sanitizedRepoName := splitRobotName[1] // Same as robot shortname
-			if repo, exists := reposMap[sanitizedRepoName]; exists {
-				deleted, err := quayService.DeleteRepository(quayOrg, repo)
-				if err != nil {
-					return fmt.Errorf("failed to delete repository %s, error: %s", repo, err)
-				}
-				if !deleted {
-					fmt.Printf("repository %s has already been deleted, skipping\n", repo)
-				}
-			}
-			// DeleteRobotAccount uses robot shortname, so e2e-demos instead of redhat-appstudio-qe+e2e-demos
-			deleted, err := quayService.DeleteRobotAccount(quayOrg, splitRobotName[1])
-			if err != nil {
-				return fmt.Errorf("failed to delete robot account %s, error: %s", robot.Name, err)
-			}
-			if !deleted {
-				fmt.Printf("robot account %s has already been deleted, skipping\n", robot.Name)
-			}
-		}
-	}
-	return nil
-}
-
-func cleanupQuayTags(quayService quay.QuayService, organization, repository string) error {
-	workerCount := 10
-	var wg sync.WaitGroup
-
-	var allTags []quay.Tag
-	var errors []error
-
-	page := 1
-	for {
-		tags, hasAdditional, err := quayService.GetTagsFromPage(organization, repository, page)
-		page++
-		if err != nil {
-			errors = append(errors, fmt.Errorf("error getting tags of `%s` repository of `%s` organization on page `%d`, error: %s", repository, organization, page, err))
-			continue
-		}
-		allTags = append(allTags, tags...)
-		if !hasAdditional {
-			break
-		}
-	}
-
-	wg.Add(workerCount)
-
-	var errorsMutex sync.Mutex
-	for i := 0; i < workerCount; i++ {
-		go func(startIdx int, allTags []quay.Tag, errors []error, errorsMutex *sync.Mutex, wg *sync.WaitGroup) {
-			defer wg.Done()
-			for idx := startIdx; idx < len(allTags); idx += workerCount {
-				tag := allTags[idx]
-				if time.Unix(tag.StartTS, 0).Before(time.Now().AddDate(0, 0, -7)) {
-					deleted, err := quayService.DeleteTag(organization, repository, tag.Name)
-					if err != nil {
-						errorsMutex.Lock()
-						errors = append(errors, fmt.Errorf("error during deletion of tag `%s` in repository `%s` of organization `%s`, error: `%s`", tag.Name, repository, organization, err))
-						errorsMutex.Unlock()
-					} else if !deleted {
-						fmt.Printf("tag `%s` in repository `%s` of organization `%s` was not deleted\n", tag.Name, repository, organization)
-					}
-				}
-			}
-		}(i, allTags, errors, &errorsMutex, &wg)
-	}
-
-	wg.Wait()
-
-	if len(errors) == 0 {
-		return nil
-	}
-
-	var errBuilder strings.Builder
-	for _, err := range errors {
-		errBuilder.WriteString(fmt.Sprintf("%s\n", err))
-	}
-	return fmt.Errorf("encountered errors during CleanupQuayTags: %s", errBuilder.String())
-}
-
-func repoNameStartsWithPrefix(prefixes []string, repoName string) bool {
-	for _, prefix := range prefixes {
-		hasPrefix := strings.HasPrefix(repoName, prefix)
-		if hasPrefix {
-			return true
-		}
-	}
-	return false
-}
-
-// Deletes the private repos older than 7 days
-func cleanupPrivateRepos(quayService quay.QuayService, quayOrg string, repoNamePrefixes []string) error {
-	repos, err := quayService.GetAllRepositories(quayOrg)
-	if err != nil {
-		return err
-	}
-	foundError := false
-	var errors []error
-	for _, repo := range repos {
-		if !repo.IsPublic && repoNameStartsWithPrefix(repoNamePrefixes, repo.Name) {
-			lastModifiedTime := int64(repo.LastModified)
-			currentTime := time.Now().Unix()
-			timeDiff := int64(7 * 24 * 3600)
-			if currentTime-lastModifiedTime > timeDiff {
-				_, err := quayService.DeleteRepository(quayOrg, repo.Name)
-				if err != nil {
-					foundError = true
-					errors = append(errors, fmt.Errorf("failed to delete repository %s with error: %v", repo.Name, err))
-					continue
-				}
-				fmt.Printf("Deleted repo %s successfully\n", repo.Name)
-			}
-		}
-	}
-	if foundError {
-		var errBuilder strings.Builder
-		for _, err := range errors {
-			errBuilder.WriteString(fmt.Sprintf("%s\n", err))
-		}
-		return fmt.Errorf("encountered errors during cleanup of private repos: %s", errBuilder.String())
-	}
-	return nil
-}
-
-func MergePRInRemote(branch string, forkOrganization string, repoPath string) error {
+// MergePRInRemote merges the branch and then the paired PRs into the preview branch of the repository and pushes it
+func MergePRInRemote(branch string, forkOrganization string, repoPath string, pairedPRs ...*ci.PullRequest) error {
 	if branch == "" {
 		klog.Fatal("The branch for upgrade is empty!")
 	}
 	var auth = &plumbingHttp.BasicAuth{
 		Username: "123",
-		Password: utils.GetEnv("GITHUB_TOKEN", ""),
+		Password: e2eConfig.Get(constants.GITHUB_TOKEN_ENV),
 	}
 
 	repo, err := git.PlainOpen(repoPath)
@@ -406,6 +186,9 @@ func MergePRInRemote(branch string, forkOrganization string, repoPath string) er
 	if err != nil {
 		klog.Fatal(err)
 	}
+	if err = ci.MergePullRequests(repoPath, pairedPRs); err != nil {
+		return err
+	}
 
 	err = repo.Push(&git.PushOptions{
 		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", previewBranchRef.Name().String(), previewBranchRef.Name().String()))},
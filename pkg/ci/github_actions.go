@@ -0,0 +1,84 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// githubActions is the GitHub Actions provider, see https://docs.github.com/en/actions/learn-github-actions/variables#default-environment-variables
+type githubActions struct{}
+
+func (g *githubActions) Name() string {
+	return "github-actions"
+}
+
+func (g *githubActions) JobName() string {
+	return fmt.Sprintf("%s/%s", os.Getenv("GITHUB_WORKFLOW"), os.Getenv("GITHUB_JOB"))
+}
+
+func (g *githubActions) JobType() JobType {
+	switch os.Getenv("GITHUB_EVENT_NAME") {
+	case "pull_request", "pull_request_target":
+		return Presubmit
+	case "push":
+		return Postsubmit
+	case "schedule":
+		return Periodic
+	default:
+		return Manual
+	}
+}
+
+func (g *githubActions) JobID() string {
+	return os.Getenv("GITHUB_RUN_ID")
+}
+
+func (g *githubActions) JobURL() string {
+	return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"))
+}
+
+func (g *githubActions) ArtifactDir() string {
+	return artifactDir(".")
+}
+
+// PullRequest returns the PR from the payload of the event which triggered the workflow
+func (g *githubActions) PullRequest() (*PullRequest, error) {
+	if g.JobType() != Presubmit {
+		return nil, nil
+	}
+	data, err := os.ReadFile(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading GitHub event payload: %v", err)
+	}
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+			User   struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			Head githubRef `json:"head"`
+			Base githubRef `json:"base"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error when parsing GitHub event payload: %v", err)
+	}
+
+	org, repo, _ := strings.Cut(os.Getenv("GITHUB_REPOSITORY"), "/")
+	return &PullRequest{
+		Author:       event.PullRequest.User.Login,
+		Organization: org,
+		RepoName:     repo,
+		BranchName:   event.PullRequest.Head.Ref,
+		CommitSHA:    event.PullRequest.Head.SHA,
+		BaseSHA:      event.PullRequest.Base.SHA,
+		Number:       event.PullRequest.Number,
+		RemoteName:   event.PullRequest.Head.owner(),
+	}, nil
+}
+
+func (g *githubActions) PairedPullRequest(repo string) (*PullRequest, error) {
+	return pairedPullRequest(g, repo)
+}
@@ -0,0 +1,40 @@
+package ci
+
+import (
+	"os"
+)
+
+// local is the provider of runs outside of a CI system, they don't test any PR
+type local struct{}
+
+func (l *local) Name() string {
+	return "local"
+}
+
+func (l *local) JobName() string {
+	return os.Getenv("JOB_NAME")
+}
+
+func (l *local) JobType() JobType {
+	return Local
+}
+
+func (l *local) JobID() string {
+	return ""
+}
+
+func (l *local) JobURL() string {
+	return ""
+}
+
+func (l *local) ArtifactDir() string {
+	return artifactDir(".")
+}
+
+func (l *local) PullRequest() (*PullRequest, error) {
+	return nil, nil
+}
+
+func (l *local) PairedPullRequest(repo string) (*PullRequest, error) {
+	return nil, nil
+}
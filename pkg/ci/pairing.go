@@ -0,0 +1,282 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+)
+
+// PairingManifestEnv is the env var with a path of a pairing manifest file. The file takes precedence over
+// a pairing manifest in the tested PR description.
+const PairingManifestEnv = "PAIRING_MANIFEST"
+
+// PairingManifestArtifact is the name of the artifact with the resolved pairing manifest
+const PairingManifestArtifact = "pairing-manifest.json"
+
+// pairingBlock matches a pairing manifest in a PR description, e.g.
+//
+//	```pairing
+//	- repo: infra-deployments
+//	  pr: 1234
+//	- repo: build-service
+//	  pr: 567
+//	  image: quay.io/redhat-user-workloads/rhtap-build-tenant/build-service/build-service:on-pr-<sha>
+//	```
+var pairingBlock = regexp.MustCompile("(?s)```pairing[ \t]*\r?\n(.*?)```")
+
+// pairingOrganizations are GitHub organizations whose PRs can be listed in a pairing manifest
+var pairingOrganizations = []string{pairingOrganization, "redhat-appstudio-qe"}
+
+// pairingImageRepositories are prefixes of images which can be listed in a pairing manifest
+var pairingImageRepositories = []string{"quay.io/redhat-user-workloads/", "quay.io/redhat-appstudio/"}
+
+// PairingEntry is a PR listed in a pairing manifest
+type PairingEntry struct {
+	// Repo is a repository in the redhat-appstudio organization, or "<org>/<repo>"
+	Repo string `json:"repo"`
+	PR   int    `json:"pr"`
+	// Image is the component image built from the PR, needed for pairing with service repositories
+	Image string `json:"image,omitempty"`
+	// Resolved is the PR as it was when the manifest was resolved
+	Resolved *PullRequest `json:"resolved,omitempty"`
+}
+
+// PairingManifest lists PRs across repositories that have to be tested together, in the order they are applied
+type PairingManifest []*PairingEntry
+
+// ParsePairingManifest parses and validates a pairing manifest
+func ParsePairingManifest(data []byte) (PairingManifest, error) {
+	manifest := PairingManifest{}
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error when parsing pairing manifest: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, e := range manifest {
+		if e == nil || e.Repo == "" || e.PR <= 0 {
+			return nil, fmt.Errorf("each pairing manifest entry has to specify repo and pr")
+		}
+		if !utils.Contains(pairingOrganizations, e.Organization()) {
+			return nil, fmt.Errorf("%s cannot be paired, only PRs of %v organizations can be listed in the pairing manifest", e, pairingOrganizations)
+		}
+		if e.Image != "" && !hasAnyPrefix(e.Image, pairingImageRepositories) {
+			return nil, fmt.Errorf("image %s of %s cannot be paired, only images of %v repositories can be listed in the pairing manifest", e.Image, e, pairingImageRepositories)
+		}
+		if seen[e.String()] {
+			return nil, fmt.Errorf("%s is listed in the pairing manifest more than once", e)
+		}
+		seen[e.String()] = true
+	}
+	return manifest, nil
+}
+
+// LoadPairingManifest loads the pairing manifest from the file given by PAIRING_MANIFEST env var,
+// or from the description of the PR tested by the provider. It returns nil if there is no manifest.
+func LoadPairingManifest(p Provider) (PairingManifest, error) {
+	if path := config.Get(PairingManifestEnv); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading pairing manifest: %v", err)
+		}
+		return ParsePairingManifest(data)
+	}
+
+	tested, err := p.PullRequest()
+	if err != nil || tested == nil {
+		return nil, err
+	}
+	var ghPR struct {
+		Body string `json:"body"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", githubAPIURL, tested.Organization, tested.RepoName, tested.Number)
+	if err := getGithubJSON(url, &ghPR); err != nil {
+		return nil, err
+	}
+	match := pairingBlock.FindStringSubmatch(ghPR.Body)
+	if match == nil {
+		return nil, nil
+	}
+	return ParsePairingManifest([]byte(match[1]))
+}
+
+// Organization returns the GitHub organization of the entry repository
+func (e *PairingEntry) Organization() string {
+	if org, _, found := strings.Cut(e.Repo, "/"); found {
+		return org
+	}
+	return pairingOrganization
+}
+
+// RepoName returns the entry repository name without the organization
+func (e *PairingEntry) RepoName() string {
+	return e.Repo[strings.LastIndex(e.Repo, "/")+1:]
+}
+
+func (e *PairingEntry) String() string {
+	return fmt.Sprintf("%s/%s#%d", e.Organization(), e.RepoName(), e.PR)
+}
+
+// Resolve looks up the current state of all PRs in the manifest. It fails if any of them is not open,
+// or GitHub reports it has conflicts with its base branch, or it was opened by someone else than the author
+// of the tested PR, who is not a member of the redhat-appstudio organization. The author check is skipped
+// if the author is empty, i.e. no PR is tested.
+func (m PairingManifest) Resolve(author string) error {
+	for _, e := range m {
+		var ghPR struct {
+			State     string `json:"state"`
+			Mergeable *bool  `json:"mergeable"`
+			User      struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			Head githubRef `json:"head"`
+			Base githubRef `json:"base"`
+		}
+		url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", githubAPIURL, e.Organization(), e.RepoName(), e.PR)
+		if err := getGithubJSON(url, &ghPR); err != nil {
+			return fmt.Errorf("cannot resolve %s: %v", e, err)
+		}
+		if ghPR.State != "open" {
+			return fmt.Errorf("%s is %s", e, ghPR.State)
+		}
+		// GitHub computes mergeability in background, so it's unknown (nil) for a while after the PR changes
+		if ghPR.Mergeable != nil && !*ghPR.Mergeable {
+			return fmt.Errorf("%s has conflicts with its base branch %s", e, ghPR.Base.Ref)
+		}
+		if err := e.checkAuthor(ghPR.User.Login, author); err != nil {
+			return err
+		}
+		e.Resolved = &PullRequest{
+			Author:       ghPR.User.Login,
+			Organization: e.Organization(),
+			RepoName:     e.RepoName(),
+			BranchName:   ghPR.Head.Ref,
+			CommitSHA:    ghPR.Head.SHA,
+			Number:       e.PR,
+			RemoteName:   ghPR.Head.owner(),
+		}
+	}
+	return nil
+}
+
+// checkAuthor checks the PR can be paired with a PR of the author, so that nobody can get changes
+// of an untrusted user deployed by listing them in the pairing manifest
+func (e *PairingEntry) checkAuthor(prAuthor, author string) error {
+	if author == "" || prAuthor == author {
+		return nil
+	}
+	member, err := isOrganizationMember(pairingOrganization, prAuthor)
+	if err != nil {
+		return fmt.Errorf("cannot check if %s author %s is a member of %s organization: %v", e, prAuthor, pairingOrganization, err)
+	}
+	if !member {
+		return fmt.Errorf("%s cannot be paired, its author %s is neither the author of the tested PR nor a member of %s organization", e, prAuthor, pairingOrganization)
+	}
+	return nil
+}
+
+// isOrganizationMember returns true if the GitHub user is a member of the organization
+func isOrganizationMember(org, user string) (bool, error) {
+	// GitHub redirects requests of non-members to public members of the organization
+	status, body, err := getGithub(fmt.Sprintf("%s/orgs/%s/members/%s", githubAPIURL, org, user))
+	switch {
+	case err != nil:
+		return false, err
+	case status == http.StatusNoContent:
+		return true, nil
+	case status == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code: %d, response body: %s", status, string(body))
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Repos returns repositories of the manifest in the order of their first occurrence
+func (m PairingManifest) Repos() []string {
+	repos := []string{}
+	seen := map[string]bool{}
+	for _, e := range m {
+		if !seen[e.RepoName()] {
+			seen[e.RepoName()] = true
+			repos = append(repos, e.RepoName())
+		}
+	}
+	return repos
+}
+
+// Entries returns the manifest entries of the repository in the manifest order
+func (m PairingManifest) Entries(repo string) []*PairingEntry {
+	entries := []*PairingEntry{}
+	for _, e := range m {
+		if e.RepoName() == repo {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// PullRequests returns resolved PRs of the repository in the manifest order
+func (m PairingManifest) PullRequests(repo string) []*PullRequest {
+	prs := []*PullRequest{}
+	for _, e := range m.Entries(repo) {
+		if e.Resolved != nil {
+			prs = append(prs, e.Resolved)
+		}
+	}
+	return prs
+}
+
+// WriteArtifact records the manifest with the resolved PRs in the artifact directory
+func (m PairingManifest) WriteArtifact(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error when marshalling pairing manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, PairingManifestArtifact), data, 0600); err != nil {
+		return fmt.Errorf("error when writing pairing manifest artifact: %v", err)
+	}
+	return nil
+}
+
+// MergePullRequests merges the PRs into the git repository checked out in repoPath, in the given order.
+// If a PR conflicts with the already merged changes, the merge is aborted and the conflicting files are reported.
+func MergePullRequests(repoPath string, prs []*PullRequest) error {
+	for _, pr := range prs {
+		repoURL := fmt.Sprintf("%s/%s/%s.git", githubURL, pr.RemoteName, pr.RepoName)
+		if out, err := git(repoPath, "fetch", repoURL, pr.CommitSHA); err != nil {
+			return fmt.Errorf("error when fetching %s from %s: %v\n%s", pr.CommitSHA, repoURL, err, out)
+		}
+		msg := fmt.Sprintf("Merge %s/%s#%d (%s)", pr.Organization, pr.RepoName, pr.Number, pr.CommitSHA)
+		if out, err := git(repoPath, "merge", "--no-ff", "-m", msg, "FETCH_HEAD"); err != nil {
+			conflicts, _ := git(repoPath, "diff", "--name-only", "--diff-filter=U")
+			_, _ = git(repoPath, "merge", "--abort")
+			if conflicts != "" {
+				return fmt.Errorf("%s/%s#%d conflicts with previously merged changes in: %s", pr.Organization, pr.RepoName, pr.Number, strings.Join(strings.Fields(conflicts), ", "))
+			}
+			return fmt.Errorf("error when merging %s/%s#%d: %v\n%s", pr.Organization, pr.RepoName, pr.Number, err, out)
+		}
+	}
+	return nil
+}
+
+func git(repoPath string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", repoPath}, args...)...).CombinedOutput() // #nosec G204
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,133 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePairingManifest(t *testing.T) {
+	manifest, err := ParsePairingManifest([]byte("- repo: infra-deployments\n  pr: 2\n- repo: redhat-appstudio-qe/build-service\n  pr: 5\n  image: quay.io/redhat-user-workloads/rhtap-build-tenant/build-service:on-pr-abcd\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"infra-deployments", "build-service"}, manifest.Repos())
+	assert.Equal(t, "redhat-appstudio/infra-deployments#2", manifest[0].String())
+	assert.Equal(t, "redhat-appstudio-qe/build-service#5", manifest.Entries("build-service")[0].String())
+
+	_, err = ParsePairingManifest([]byte("- repo: infra-deployments\n  pr: 2\n- repo: redhat-appstudio/infra-deployments\n  pr: 2\n"))
+	assert.ErrorContains(t, err, "redhat-appstudio/infra-deployments#2 is listed in the pairing manifest more than once")
+	_, err = ParsePairingManifest([]byte("- repo: infra-deployments\n"))
+	assert.ErrorContains(t, err, "each pairing manifest entry has to specify repo and pr")
+	_, err = ParsePairingManifest([]byte("- repo: infra-deployments\n  pull: 2\n"))
+	assert.ErrorContains(t, err, "error when parsing pairing manifest")
+	_, err = ParsePairingManifest([]byte("- repo: my-org/build-service\n  pr: 5\n"))
+	assert.ErrorContains(t, err, "my-org/build-service#5 cannot be paired, only PRs of [redhat-appstudio redhat-appstudio-qe] organizations can be listed")
+	_, err = ParsePairingManifest([]byte("- repo: build-service\n  pr: 5\n  image: quay.io/my-org/build-service:on-pr-abcd\n"))
+	assert.ErrorContains(t, err, "image quay.io/my-org/build-service:on-pr-abcd of redhat-appstudio/build-service#5 cannot be paired")
+}
+
+func TestLoadPairingManifest(t *testing.T) {
+	unsetCIEnv(t)
+	fakeGithubAPI(t)
+	t.Setenv(PairingManifestEnv, "")
+	t.Setenv("PROW_JOB_ID", "123")
+	t.Setenv("JOB_SPEC", `{"type": "presubmit", "refs": {"org": "redhat-appstudio", "repo": "application-service", "pulls": [{"number": 42, "author": "author", "sha": "abcd"}]}}`)
+
+	// from the tested PR description
+	manifest, err := LoadPairingManifest(Detect())
+	assert.NoError(t, err)
+	assert.NoError(t, manifest.Resolve("author"))
+	assert.Equal(t, []*PullRequest{{Author: "author", Organization: "redhat-appstudio", RepoName: "infra-deployments", BranchName: "feature", CommitSHA: "2222", Number: 2, RemoteName: "author"}}, manifest.PullRequests("infra-deployments"))
+
+	dir := t.TempDir()
+	assert.NoError(t, manifest.WriteArtifact(dir))
+	artifact, err := os.ReadFile(filepath.Join(dir, PairingManifestArtifact))
+	assert.NoError(t, err)
+	assert.Contains(t, string(artifact), `"CommitSHA": "2222"`)
+
+	// from a file
+	path := filepath.Join(dir, "pairing.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("- repo: infra-deployments\n  pr: 3\n"), 0600))
+	t.Setenv(PairingManifestEnv, path)
+	manifest, err = LoadPairingManifest(Detect())
+	assert.NoError(t, err)
+	assert.ErrorContains(t, manifest.Resolve("author"), "redhat-appstudio/infra-deployments#3 has conflicts with its base branch main")
+
+	manifest, err = ParsePairingManifest([]byte("- repo: infra-deployments\n  pr: 4\n"))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, manifest.Resolve("author"), "redhat-appstudio/infra-deployments#4 is closed")
+
+	// PRs of other users can be paired only if they are members of the organization
+	manifest, err = ParsePairingManifest([]byte("- repo: infra-deployments\n  pr: 5\n"))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, manifest.Resolve("author"), "redhat-appstudio/infra-deployments#5 cannot be paired, its author stranger is neither the author of the tested PR nor a member of redhat-appstudio organization")
+	assert.NoError(t, manifest.Resolve(""))
+	manifest, err = ParsePairingManifest([]byte("- repo: infra-deployments\n  pr: 6\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, manifest.Resolve("author"))
+	assert.Equal(t, "member", manifest[0].Resolved.Author)
+
+	// local runs don't test any PR
+	t.Setenv(PairingManifestEnv, "")
+	t.Setenv("PROW_JOB_ID", "")
+	manifest, err = LoadPairingManifest(Detect())
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+}
+
+func TestMergePullRequests(t *testing.T) {
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "e2e")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "e2e@example.com")
+	}
+	defaultURL := githubURL
+	githubURL = t.TempDir()
+	t.Cleanup(func() { githubURL = defaultURL })
+
+	// the "author" fork of infra-deployments with PRs changing README.md and config.yaml
+	fork := filepath.Join(githubURL, "author", "infra-deployments.git")
+	commit := func(file, content string) string {
+		assert.NoError(t, os.WriteFile(filepath.Join(fork, file), []byte(content), 0600))
+		_, err := git(fork, "add", file)
+		assert.NoError(t, err)
+		_, err = git(fork, "commit", "-m", "update "+file)
+		assert.NoError(t, err)
+		sha, err := git(fork, "rev-parse", "HEAD")
+		assert.NoError(t, err)
+		return sha
+	}
+	assert.NoError(t, os.MkdirAll(fork, 0750))
+	_, err := git(fork, "init", "-b", "main")
+	assert.NoError(t, err)
+	commit("README.md", "readme\n")
+	readme := commit("README.md", "readme\nchanged\n")
+	_, err = git(fork, "checkout", "-b", "config", "main~1")
+	assert.NoError(t, err)
+	config := commit("config.yaml", "config\n")
+	_, err = git(fork, "checkout", "-b", "conflict", "main~1")
+	assert.NoError(t, err)
+	conflict := commit("README.md", "readme\nconflict\n")
+
+	clone := filepath.Join(t.TempDir(), "infra-deployments")
+	_, err = git(filepath.Dir(clone), "clone", "--branch", "main", fork, clone)
+	assert.NoError(t, err)
+	_, err = git(clone, "reset", "--hard", "HEAD~1")
+	assert.NoError(t, err)
+
+	pullRequest := func(number int, sha string) *PullRequest {
+		return &PullRequest{Organization: "redhat-appstudio", RepoName: "infra-deployments", RemoteName: "author", Number: number, CommitSHA: sha}
+	}
+	assert.NoError(t, MergePullRequests(clone, []*PullRequest{pullRequest(1, readme), pullRequest(2, config)}))
+	log, err := git(clone, "log", "--format=%s", "-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Merge redhat-appstudio/infra-deployments#2 ("+config+")\nMerge redhat-appstudio/infra-deployments#1 ("+readme+")", log)
+
+	err = MergePullRequests(clone, []*PullRequest{pullRequest(3, conflict)})
+	assert.ErrorContains(t, err, "redhat-appstudio/infra-deployments#3 conflicts with previously merged changes in: README.md")
+	status, err := git(clone, "status", "--porcelain")
+	assert.NoError(t, err)
+	assert.Empty(t, status)
+}
@@ -0,0 +1,192 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+)
+
+// JobType is a type of CI job
+type JobType string
+
+const (
+	// Presubmit jobs test a pull request
+	Presubmit JobType = "presubmit"
+	// Postsubmit jobs test a pushed commit
+	Postsubmit JobType = "postsubmit"
+	// Periodic jobs are triggered by a schedule
+	Periodic JobType = "periodic"
+	// Manual jobs are triggered by hand, e.g. by a workflow dispatch
+	Manual JobType = "manual"
+	// Local runs are not started by a CI system
+	Local JobType = "local"
+)
+
+// pairingOrganization is the GitHub organization searched for PRs paired with the tested PR
+const pairingOrganization = "redhat-appstudio"
+
+// githubURL and githubAPIURL can be overridden in tests
+var (
+	githubURL    = "https://github.com"
+	githubAPIURL = "https://api.github.com"
+)
+
+// PullRequest describes a GitHub pull request tested by a CI job
+type PullRequest struct {
+	// Author is the GitHub login of the PR author
+	Author string
+	// Organization and RepoName identify the repository the PR is opened against
+	Organization string
+	RepoName     string
+	// BranchName is the PR source branch
+	BranchName string
+	// CommitSHA is the PR head commit
+	CommitSHA string
+	// BaseSHA is the commit of the base branch the PR is tested against, it's empty if the CI system doesn't provide it
+	BaseSHA string
+	Number  int
+	// RemoteName is the owner of the repository the PR is opened from (the fork)
+	RemoteName string
+}
+
+// Provider exposes information about the CI system the tests run in
+type Provider interface {
+	// Name returns the name of the CI system, e.g. "prow"
+	Name() string
+	// JobName returns the name of the job, which is the same for all its runs
+	JobName() string
+	// JobType returns the type of the job
+	JobType() JobType
+	// JobID returns an identifier of the job run, or an empty string if there is none
+	JobID() string
+	// JobURL returns a link to the job run logs, or an empty string if there is none
+	JobURL() string
+	// ArtifactDir returns a directory for storing job artifacts (ARTIFACT_DIR env var overrides it)
+	ArtifactDir() string
+	// PullRequest returns the PR tested by the job, or nil if the job doesn't test a PR
+	PullRequest() (*PullRequest, error)
+	// PairedPullRequest returns a PR of the repository with the same branch and author as the tested PR,
+	// or nil if there is no such PR
+	PairedPullRequest(repo string) (*PullRequest, error)
+}
+
+// Detect returns the provider of the CI system the tests run in, the local provider is returned if no CI system is detected
+func Detect() Provider {
+	switch {
+	case os.Getenv("PROW_JOB_ID") != "":
+		return &prow{}
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return &githubActions{}
+	case os.Getenv("PAC_EVENT_TYPE") != "":
+		return &tektonPaC{}
+	default:
+		return &local{}
+	}
+}
+
+// artifactDir returns ARTIFACT_DIR env var value, or defaultDir if it's not set
+func artifactDir(defaultDir string) string {
+	if dir := config.Get("ARTIFACT_DIR"); dir != "" {
+		return dir
+	}
+	return defaultDir
+}
+
+// pairedPullRequest looks up an open PR in the repository with the same source branch and author as the PR tested by the provider
+func pairedPullRequest(p Provider, repo string) (*PullRequest, error) {
+	tested, err := p.PullRequest()
+	if err != nil || tested == nil {
+		return nil, err
+	}
+
+	var pulls []struct {
+		Number int `json:"number"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Head githubRef `json:"head"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls?per_page=100", githubAPIURL, pairingOrganization, repo)
+	if err := getGithubJSON(url, &pulls); err != nil {
+		return nil, fmt.Errorf("cannot list %s/%s PRs: %v", pairingOrganization, repo, err)
+	}
+
+	for _, pull := range pulls {
+		if pull.Head.Ref == tested.BranchName && pull.User.Login == tested.RemoteName {
+			return &PullRequest{
+				Author:       pull.User.Login,
+				Organization: pairingOrganization,
+				RepoName:     repo,
+				BranchName:   pull.Head.Ref,
+				CommitSHA:    pull.Head.SHA,
+				Number:       pull.Number,
+				RemoteName:   pull.Head.owner(),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// githubRef is the head or base of a PR as returned by GitHub API and in GitHub event payloads
+type githubRef struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	SHA   string `json:"sha"`
+	Repo  struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repo"`
+}
+
+// owner returns the owner of the repository the ref belongs to
+func (r githubRef) owner() string {
+	if r.Repo.Owner.Login != "" {
+		return r.Repo.Owner.Login
+	}
+	return strings.Split(r.Label, ":")[0]
+}
+
+// getGithubJSON sends a GET request to GitHub API and parses the JSON response, GITHUB_TOKEN env var is used for authentication
+func getGithubJSON(url string, v interface{}) error {
+	status, body, err := getGithub(url)
+	if err != nil {
+		return err
+	}
+	if status > 299 {
+		return fmt.Errorf("unexpected status code: %d, response body: %s", status, string(body))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error when unmarshalling the response body from URL '%s': %+v", url, err)
+	}
+	return nil
+}
+
+// getGithub sends a GET request to the GitHub API and returns the status code and the body of the response
+func getGithub(url string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if token := config.Get(constants.GITHUB_TOKEN_ENV); token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error when sending request to '%s': %+v", url, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error when reading the response body from URL '%s': %+v", url, err)
+	}
+	return res.StatusCode, body, nil
+}
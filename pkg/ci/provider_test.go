@@ -0,0 +1,160 @@
+package ci
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unsetCIEnv clears env vars used for detecting the CI provider
+func unsetCIEnv(t *testing.T) {
+	for _, env := range []string{"PROW_JOB_ID", "GITHUB_ACTIONS", "PAC_EVENT_TYPE", "ARTIFACT_DIR", "JOB_NAME"} {
+		t.Setenv(env, "")
+	}
+}
+
+// fakeGithubAPI serves the PR opened from the "author:feature" branch in redhat-appstudio/application-service
+// with a pairing manifest in its description, and infra-deployments PRs paired with it
+func fakeGithubAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/redhat-appstudio/application-service/pulls/42":
+			fmt.Fprint(w, `{"number": 42, "head": {"label": "author:feature", "ref": "feature", "sha": "abcd"},
+				"body": "Needs infra-deployments changes\r\n\r\n`+"```pairing\\r\\n- repo: infra-deployments\\r\\n  pr: 2\\r\\n```"+`"}`)
+		case "/repos/redhat-appstudio/infra-deployments/pulls/2":
+			fmt.Fprint(w, `{"state": "open", "mergeable": true, "user": {"login": "author"}, "head": {"ref": "feature", "sha": "2222", "repo": {"owner": {"login": "author"}}}}`)
+		case "/repos/redhat-appstudio/infra-deployments/pulls/3":
+			fmt.Fprint(w, `{"state": "open", "mergeable": false, "base": {"ref": "main"}}`)
+		case "/repos/redhat-appstudio/infra-deployments/pulls/4":
+			fmt.Fprint(w, `{"state": "closed"}`)
+		case "/repos/redhat-appstudio/infra-deployments/pulls/5":
+			fmt.Fprint(w, `{"state": "open", "user": {"login": "stranger"}, "head": {"ref": "feature", "sha": "5555", "repo": {"owner": {"login": "stranger"}}}}`)
+		case "/repos/redhat-appstudio/infra-deployments/pulls/6":
+			fmt.Fprint(w, `{"state": "open", "user": {"login": "member"}, "head": {"ref": "feature", "sha": "6666", "repo": {"owner": {"login": "member"}}}}`)
+		case "/orgs/redhat-appstudio/members/member":
+			w.WriteHeader(http.StatusNoContent)
+		case "/repos/redhat-appstudio/infra-deployments/pulls":
+			fmt.Fprint(w, `[
+				{"number": 1, "user": {"login": "other"}, "head": {"ref": "feature", "sha": "1111"}},
+				{"number": 2, "user": {"login": "author"}, "head": {"ref": "feature", "sha": "2222", "repo": {"owner": {"login": "author"}}}}
+			]`)
+		case "/repos/redhat-appstudio/e2e-tests/pulls":
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	defaultURL := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = defaultURL })
+}
+
+func TestDetect(t *testing.T) {
+	unsetCIEnv(t)
+	assert.Equal(t, "local", Detect().Name())
+	assert.Equal(t, Local, Detect().JobType())
+
+	t.Setenv("PAC_EVENT_TYPE", "push")
+	assert.Equal(t, "tekton-pac", Detect().Name())
+	t.Setenv("GITHUB_ACTIONS", "true")
+	assert.Equal(t, "github-actions", Detect().Name())
+	t.Setenv("PROW_JOB_ID", "123")
+	assert.Equal(t, "prow", Detect().Name())
+
+	t.Setenv("ARTIFACT_DIR", "/tmp/artifacts")
+	assert.Equal(t, "/tmp/artifacts", Detect().ArtifactDir())
+}
+
+func TestProw(t *testing.T) {
+	unsetCIEnv(t)
+	fakeGithubAPI(t)
+	t.Setenv("PROW_JOB_ID", "123")
+	t.Setenv("JOB_NAME", "pull-ci-redhat-appstudio-application-service-main-application-service-e2e")
+	t.Setenv("JOB_TYPE", "presubmit")
+	t.Setenv("BUILD_ID", "1700000000")
+	t.Setenv("JOB_SPEC", `{"type": "presubmit", "refs": {"org": "redhat-appstudio", "repo": "application-service", "base_sha": "base", "pulls": [{"number": 42, "author": "author", "sha": "abcd"}]}}`)
+
+	p := Detect()
+	assert.Equal(t, "pull-ci-redhat-appstudio-application-service-main-application-service-e2e", p.JobName())
+	assert.Equal(t, Presubmit, p.JobType())
+	assert.Equal(t, "1700000000", p.JobID())
+
+	pr, err := p.PullRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, &PullRequest{Author: "author", Organization: "redhat-appstudio", RepoName: "application-service", BranchName: "feature", CommitSHA: "abcd", BaseSHA: "base", Number: 42, RemoteName: "author"}, pr)
+
+	paired, err := p.PairedPullRequest("infra-deployments")
+	assert.NoError(t, err)
+	assert.Equal(t, &PullRequest{Author: "author", Organization: "redhat-appstudio", RepoName: "infra-deployments", BranchName: "feature", CommitSHA: "2222", Number: 2, RemoteName: "author"}, paired)
+
+	paired, err = p.PairedPullRequest("e2e-tests")
+	assert.NoError(t, err)
+	assert.Nil(t, paired)
+
+	// periodic jobs don't test any PR
+	t.Setenv("JOB_SPEC", `{"type": "periodic"}`)
+	pr, err = Detect().PullRequest()
+	assert.NoError(t, err)
+	assert.Nil(t, pr)
+}
+
+func TestGithubActions(t *testing.T) {
+	unsetCIEnv(t)
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	assert.NoError(t, os.WriteFile(eventPath, []byte(`{"pull_request": {"number": 7, "user": {"login": "author"}, "head": {"ref": "feature", "sha": "abcd", "repo": {"name": "e2e-tests", "owner": {"login": "fork-owner"}}}, "base": {"ref": "main", "sha": "base"}}}`), 0600))
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+	t.Setenv("GITHUB_REPOSITORY", "redhat-appstudio/e2e-tests")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_WORKFLOW", "Load tests")
+	t.Setenv("GITHUB_JOB", "run")
+	t.Setenv("GITHUB_RUN_ID", "99")
+
+	p := Detect()
+	assert.Equal(t, "Load tests/run", p.JobName())
+	assert.Equal(t, Presubmit, p.JobType())
+	assert.Equal(t, "99", p.JobID())
+	assert.Equal(t, "https://github.com/redhat-appstudio/e2e-tests/actions/runs/99", p.JobURL())
+
+	pr, err := p.PullRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, &PullRequest{Author: "author", Organization: "redhat-appstudio", RepoName: "e2e-tests", BranchName: "feature", CommitSHA: "abcd", BaseSHA: "base", Number: 7, RemoteName: "fork-owner"}, pr)
+
+	t.Setenv("GITHUB_EVENT_NAME", "schedule")
+	assert.Equal(t, Periodic, p.JobType())
+	pr, err = p.PullRequest()
+	assert.NoError(t, err)
+	assert.Nil(t, pr)
+}
+
+func TestTektonPaC(t *testing.T) {
+	unsetCIEnv(t)
+	t.Setenv("PAC_EVENT_TYPE", "pull_request")
+	t.Setenv("PAC_REPO_OWNER", "redhat-appstudio")
+	t.Setenv("PAC_REPO_NAME", "e2e-tests")
+	t.Setenv("PAC_REVISION", "abcd")
+	t.Setenv("PAC_PULL_REQUEST_NUMBER", "5")
+	t.Setenv("PAC_SOURCE_BRANCH", "feature")
+	t.Setenv("PAC_SOURCE_URL", "https://github.com/fork-owner/e2e-tests")
+	t.Setenv("PAC_SENDER", "author")
+	t.Setenv("PIPELINE_RUN_NAME", "e2e-tests-on-pull-request-x7kq")
+
+	p := Detect()
+	assert.Equal(t, "e2e-tests-on-pull-request-x7kq", p.JobName())
+	assert.Equal(t, Presubmit, p.JobType())
+
+	pr, err := p.PullRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, &PullRequest{Author: "author", Organization: "redhat-appstudio", RepoName: "e2e-tests", BranchName: "feature", CommitSHA: "abcd", Number: 5, RemoteName: "fork-owner"}, pr)
+
+	t.Setenv("PAC_PULL_REQUEST_NUMBER", "")
+	t.Setenv("PAC_EVENT_TYPE", "push")
+	assert.Equal(t, Postsubmit, p.JobType())
+}
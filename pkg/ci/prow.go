@@ -0,0 +1,125 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	v1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// prowURL can be overridden in tests
+var prowURL = "https://prow.ci.openshift.org"
+
+// OpenshiftJobSpec is the job specification OpenShift CI (Prow) passes to jobs in JOB_SPEC env var
+type OpenshiftJobSpec struct {
+	Type string `json:"type"`
+	Job  string `json:"job"`
+	Refs Refs   `json:"refs"`
+}
+
+type Refs struct {
+	RepoLink     string `json:"repo_link"`
+	Repo         string `json:"repo"`
+	Organization string `json:"org"`
+	BaseSHA      string `json:"base_sha"`
+	Pulls        []Pull `json:"pulls"`
+}
+
+type Pull struct {
+	Number     int    `json:"number"`
+	Author     string `json:"author"`
+	SHA        string `json:"sha"`
+	PRLink     string `json:"link"`
+	AuthorLink string `json:"author_link"`
+}
+
+// prow is the OpenShift CI provider, see https://docs.prow.k8s.io/docs/jobs/#job-environment-variables
+type prow struct {
+	pr *PullRequest
+}
+
+func (p *prow) Name() string {
+	return "prow"
+}
+
+func (p *prow) JobName() string {
+	return os.Getenv("JOB_NAME")
+}
+
+func (p *prow) JobType() JobType {
+	return JobType(os.Getenv("JOB_TYPE"))
+}
+
+func (p *prow) JobID() string {
+	return os.Getenv("BUILD_ID")
+}
+
+// JobURL returns the URL of the job run logs stored in the Prow job status
+func (p *prow) JobURL() string {
+	r, err := http.Get(fmt.Sprintf("%s/prowjob?prowjob=%s", prowURL, os.Getenv("PROW_JOB_ID")))
+	if err != nil {
+		return ""
+	}
+	defer r.Body.Close()
+	if r.StatusCode > 299 {
+		return ""
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return ""
+	}
+	var pj v1.ProwJob
+	if err := yaml.Unmarshal(body, &pj); err != nil {
+		return ""
+	}
+	return pj.Status.URL
+}
+
+func (p *prow) ArtifactDir() string {
+	return artifactDir(".")
+}
+
+// PullRequest returns the PR from JOB_SPEC, its source branch and fork are looked up using GitHub API
+func (p *prow) PullRequest() (*PullRequest, error) {
+	if p.pr != nil {
+		return p.pr, nil
+	}
+	jobSpec := &OpenshiftJobSpec{}
+	if err := json.Unmarshal([]byte(os.Getenv("JOB_SPEC")), jobSpec); err != nil {
+		return nil, fmt.Errorf("error when parsing openshift job spec data: %v", err)
+	}
+	if len(jobSpec.Refs.Pulls) == 0 {
+		return nil, nil
+	}
+
+	pr := &PullRequest{
+		Author:       jobSpec.Refs.Pulls[0].Author,
+		Organization: jobSpec.Refs.Organization,
+		RepoName:     jobSpec.Refs.Repo,
+		CommitSHA:    jobSpec.Refs.Pulls[0].SHA,
+		BaseSHA:      jobSpec.Refs.BaseSHA,
+		Number:       jobSpec.Refs.Pulls[0].Number,
+	}
+	var ghPR struct {
+		Head githubRef `json:"head"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", githubAPIURL, pr.Organization, pr.RepoName, pr.Number)
+	if err := getGithubJSON(url, &ghPR); err != nil {
+		return nil, err
+	}
+	if ghPR.Head.Label == "" {
+		return nil, fmt.Errorf("failed to get an information about the remote and branch name from PR %s", url)
+	}
+	pr.RemoteName, pr.BranchName = ghPR.Head.owner(), ghPR.Head.Ref
+
+	p.pr = pr
+	return pr, nil
+}
+
+func (p *prow) PairedPullRequest(repo string) (*PullRequest, error) {
+	return pairedPullRequest(p, repo)
+}
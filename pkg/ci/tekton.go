@@ -0,0 +1,83 @@
+package ci
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+)
+
+// tektonPaC is the Pipelines as Code provider. PaC doesn't expose the event to task steps, so the PipelineRun
+// running the tests has to pass its dynamic variables (https://pipelinesascode.com/docs/guide/authoringprs/#dynamic-variables)
+// as env vars:
+//
+//	PAC_EVENT_TYPE={{event_type}}, PAC_REPO_OWNER={{repo_owner}}, PAC_REPO_NAME={{repo_name}}, PAC_REVISION={{revision}},
+//	PAC_PULL_REQUEST_NUMBER={{pull_request_number}}, PAC_SOURCE_BRANCH={{source_branch}}, PAC_SOURCE_URL={{source_url}},
+//	PAC_SENDER={{sender}}, PIPELINE_RUN_NAME=$(context.pipelineRun.name)
+//
+// and optionally JOB_NAME (defaults to the PipelineRun name) and PIPELINE_RUN_URL (a link to the PipelineRun logs)
+type tektonPaC struct{}
+
+func (t *tektonPaC) Name() string {
+	return "tekton-pac"
+}
+
+func (t *tektonPaC) JobName() string {
+	return utils.GetEnv("JOB_NAME", os.Getenv("PIPELINE_RUN_NAME"))
+}
+
+func (t *tektonPaC) JobType() JobType {
+	switch {
+	case os.Getenv("PAC_PULL_REQUEST_NUMBER") != "":
+		return Presubmit
+	case os.Getenv("PAC_EVENT_TYPE") == "push":
+		return Postsubmit
+	case os.Getenv("PAC_EVENT_TYPE") == "incoming":
+		return Manual
+	default:
+		return Periodic
+	}
+}
+
+func (t *tektonPaC) JobID() string {
+	return os.Getenv("PIPELINE_RUN_NAME")
+}
+
+func (t *tektonPaC) JobURL() string {
+	return os.Getenv("PIPELINE_RUN_URL")
+}
+
+func (t *tektonPaC) ArtifactDir() string {
+	return artifactDir(".")
+}
+
+// PullRequest returns the PR from PaC dynamic variables
+func (t *tektonPaC) PullRequest() (*PullRequest, error) {
+	if t.JobType() != Presubmit {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(os.Getenv("PAC_PULL_REQUEST_NUMBER"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAC_PULL_REQUEST_NUMBER: %v", err)
+	}
+	remoteName := os.Getenv("PAC_REPO_OWNER")
+	if sourceURL, err := url.Parse(os.Getenv("PAC_SOURCE_URL")); err == nil && sourceURL.Path != "" {
+		remoteName = strings.Split(strings.TrimPrefix(sourceURL.Path, "/"), "/")[0]
+	}
+	return &PullRequest{
+		Author:       os.Getenv("PAC_SENDER"),
+		Organization: os.Getenv("PAC_REPO_OWNER"),
+		RepoName:     os.Getenv("PAC_REPO_NAME"),
+		BranchName:   os.Getenv("PAC_SOURCE_BRANCH"),
+		CommitSHA:    os.Getenv("PAC_REVISION"),
+		Number:       number,
+		RemoteName:   remoteName,
+	}, nil
+}
+
+func (t *tektonPaC) PairedPullRequest(repo string) (*PullRequest, error) {
+	return pairedPullRequest(t, repo)
+}
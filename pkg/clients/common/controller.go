@@ -3,8 +3,8 @@ package common
 import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/github"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 )
 
 // Create the struct for kubernetes and github clients.
@@ -21,7 +21,7 @@ Create controller for the common kubernetes API crud operations. This controller
 Check if a github organization env var is set, if not use by default the redhat-appstudio-qe org. See: https://github.com/redhat-appstudio-qe
 */
 func NewSuiteController(kubeC *kubeCl.CustomClient) (*SuiteController, error) {
-	gh, err := github.NewGithubClient(utils.GetEnv(constants.GITHUB_TOKEN_ENV, ""), utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe"))
+	gh, err := github.NewGithubClient(config.Get(constants.GITHUB_TOKEN_ENV), config.Get(constants.GITHUB_E2E_ORGANIZATION_ENV))
 	if err != nil {
 		return nil, err
 	}
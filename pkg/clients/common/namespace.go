@@ -12,7 +12,6 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
@@ -32,7 +31,17 @@ func (s *SuiteController) DeleteNamespace(namespace string) error {
 	// Wait for the namespace to no longer exist. The namespace may remain stuck in 'Terminating' state
 	// if it contains with finalizers that are not handled. We detect this case here, and report any resources still
 	// in the Namespace.
-	if err := utils.WaitUntil(s.namespaceDoesNotExist(namespace), time.Minute*10); err != nil {
+	err = utils.NewPoller(fmt.Sprintf("namespace %s to be deleted", namespace), time.Minute*10).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			ns, err := s.KubeInterface().CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+			if k8sErrors.IsNotFound(err) {
+				return true, nil, nil
+			} else if err != nil {
+				return false, nil, err
+			}
+			return false, ns.Status, nil
+		})
+	if err != nil {
 
 		// On failure to delete, list all namespace-scoped resources still in the namespace.
 		resourcesInNamespace := s.ListNamespaceScopedResourcesAsString(namespace, s.KubeInterface(), s.DynamicClient())
@@ -109,8 +118,19 @@ func (s *SuiteController) ListNamespaceScopedResourcesAsString(namespace string,
 	return resourceList
 }
 
-// CreateTestNamespace creates a namespace where Application and Component CR will be created
+// CreateTestNamespace creates a namespace where Application and Component CR will be created. The namespace is labelled
+// as created by the e2e framework, so it's deleted by the reaper if the tests don't delete it.
 func (s *SuiteController) CreateTestNamespace(name string) (*corev1.Namespace, error) {
+	return s.createTestNamespace(name, utils.WithE2ETestLabel(map[string]string{constants.ArgoCDLabelKey: constants.ArgoCDLabelValue}))
+}
+
+// CreateProvidedTestNamespace prepares a namespace provided by the user, e.g. by E2E_APPLICATIONS_NAMESPACE, like
+// CreateTestNamespace, but it doesn't label the namespace for the reaper, the namespace outlives the test run.
+func (s *SuiteController) CreateProvidedTestNamespace(name string) (*corev1.Namespace, error) {
+	return s.createTestNamespace(name, map[string]string{constants.ArgoCDLabelKey: constants.ArgoCDLabelValue})
+}
+
+func (s *SuiteController) createTestNamespace(name string, labels map[string]string) (*corev1.Namespace, error) {
 	// Check if the E2E test namespace already exists
 	ns, err := s.KubeInterface().CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
 
@@ -120,7 +140,7 @@ func (s *SuiteController) CreateTestNamespace(name string) (*corev1.Namespace, e
 			nsTemplate := corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:   name,
-					Labels: map[string]string{constants.ArgoCDLabelKey: constants.ArgoCDLabelValue},
+					Labels: labels,
 				}}
 			ns, err = s.KubeInterface().CoreV1().Namespaces().Create(context.Background(), &nsTemplate, metav1.CreateOptions{})
 			if err != nil {
@@ -189,23 +209,13 @@ func (s *SuiteController) CreateTestNamespace(name string) (*corev1.Namespace, e
 
 	// Argo CD role/rolebinding need to be present in the namespace before we create GitOpsDeployments.
 	// - These role bindings are created in namespaces labeled with 'argocd.argoproj.io/managed-by' (see above)
-	if err := utils.WaitUntil(s.argoCDNamespaceRBACPresent(name), time.Second*120); err != nil {
+	if err := utils.NewPoller(fmt.Sprintf("Argo CD role and role binding in %s namespace", name), time.Second*120).Poll(context.Background(), s.argoCDNamespaceRBACPresent(name)); err != nil {
 		return nil, fmt.Errorf("argo CD Namespace RBAC was never present in '%s': %v", name, err)
 	}
 
 	return ns, nil
 }
 
-// namespaceDoesNotExist returns a condition that can be used to wait for the namespace to not exist
-func (s *SuiteController) namespaceDoesNotExist(namespace string) wait.ConditionFunc {
-	return func() (bool, error) {
-
-		_, err := s.KubeInterface().CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
-
-		return err != nil && k8sErrors.IsNotFound(err), nil
-	}
-}
-
 // GetNamespace returns the requested Namespace object
 func (s *SuiteController) GetNamespace(namespace string) (*corev1.Namespace, error) {
 	return s.KubeInterface().CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
@@ -75,7 +75,8 @@ func (s *SuiteController) WaitForPodSelector(
 	}
 
 	for i := range podList.Items {
-		if err := utils.WaitUntil(fn(podList.Items[i].Name, namespace), time.Duration(timeout)*time.Second); err != nil {
+		poller := utils.NewPoller(fmt.Sprintf("pod %s in %s namespace", podList.Items[i].Name, namespace), time.Duration(timeout)*time.Second)
+		if err := poller.PollCondition(context.Background(), fn(podList.Items[i].Name, namespace)); err != nil {
 			return err
 		}
 	}
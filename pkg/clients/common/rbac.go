@@ -5,9 +5,9 @@ import (
 	"strings"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 func (s *SuiteController) ListRoles(namespace string) (*rbacv1.RoleList, error) {
@@ -31,11 +31,14 @@ func (s *SuiteController) GetRoleBinding(rolebindingName, namespace string) (*rb
 // argoCDNamespaceRBACPresent returns a condition which waits for the Argo CD role/rolebindings to be set on the namespace.
 //   - This Role/RoleBinding allows Argo cd to deploy into the namespace (which is referred to as 'managing the namespace'), and
 //     is created by the GitOps Operator.
-func (s *SuiteController) argoCDNamespaceRBACPresent(namespace string) wait.ConditionFunc {
-	return func() (bool, error) {
+//
+// The observed state is the list of Argo CD roles and role bindings found in the namespace.
+func (s *SuiteController) argoCDNamespaceRBACPresent(namespace string) utils.PollFunc {
+	return func(ctx context.Context) (bool, interface{}, error) {
+		found := []string{}
 		roles, err := s.ListRoles(namespace)
-		if err != nil || roles == nil {
-			return false, nil
+		if err != nil {
+			return false, nil, err
 		}
 
 		// The namespace should contain a 'gitops-service-argocd-' Role
@@ -43,25 +46,24 @@ func (s *SuiteController) argoCDNamespaceRBACPresent(namespace string) wait.Cond
 		for _, role := range roles.Items {
 			if strings.HasPrefix(role.Name, constants.ArgoCDLabelValue+"-") {
 				roleFound = true
+				found = append(found, "Role "+role.Name)
 			}
 		}
-		if !roleFound {
-			return false, nil
-		}
 
 		// The namespace should contain a 'gitops-service-argocd-' RoleBinding
 		roleBindingFound := false
 		roleBindings, err := s.ListRoleBindings(namespace)
-		if err != nil || roleBindings == nil {
-			return false, nil
+		if err != nil {
+			return false, found, err
 		}
 		for _, roleBinding := range roleBindings.Items {
 			if strings.HasPrefix(roleBinding.Name, constants.ArgoCDLabelValue+"-") {
 				roleBindingFound = true
+				found = append(found, "RoleBinding "+roleBinding.Name)
 			}
 		}
 
-		return roleBindingFound, nil
+		return roleFound && roleBindingFound, found, nil
 	}
 }
 
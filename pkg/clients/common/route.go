@@ -5,8 +5,11 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -93,3 +96,42 @@ func (h *SuiteController) RouteEndpointIsAccessible(route *routev1.Route, endpoi
 
 	return nil
 }
+
+// CreateRoute creates an edge terminated route exposing the given service.
+func (h *SuiteController) CreateRoute(routeName, namespace, serviceName string) (*routev1.Route, error) {
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      routeName,
+			Namespace: namespace,
+		},
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{Kind: "Service", Name: serviceName},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			},
+		},
+	}
+	if err := h.KubeRest().Create(context.Background(), route); err != nil {
+		return nil, fmt.Errorf("failed to create route %s/%s: %v", namespace, routeName, err)
+	}
+
+	return route, utils.NewPoller(fmt.Sprintf("host of route %s in %s namespace to be set", routeName, namespace), time.Minute).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			r, err := h.GetOpenshiftRoute(routeName, namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			route = r
+			return route.Spec.Host != "", route.Status, nil
+		})
+}
+
+// DeleteRoute deletes a route with a given name in a given namespace.
+func (h *SuiteController) DeleteRoute(routeName, namespace string) error {
+	route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: routeName, Namespace: namespace}}
+	if err := h.KubeRest().Delete(context.Background(), route); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -7,8 +7,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	. "github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -123,7 +123,7 @@ func (s *SuiteController) CreateRegistryJsonSecret(name, namespace, authKey, key
 
 // AddRegistryAuthSecretToSA adds registry auth secret to service account
 func (s *SuiteController) AddRegistryAuthSecretToSA(registryAuth, namespace string) error {
-	quayToken := utils.GetEnv(registryAuth, "")
+	quayToken := config.Get(registryAuth)
 	if quayToken == "" {
 		return errors.New("failed to get registry auth secret")
 	}
@@ -2,9 +2,13 @@ package common
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // GetServiceByName returns the service for a given component name
@@ -21,3 +25,48 @@ func (h *SuiteController) GetServiceByName(serviceName string, serviceNamespace
 	}
 	return service, nil
 }
+
+// CreateExternalService creates a Service without a selector together with Endpoints pointing to the given IP and port.
+// It is used to make a server running outside of the cluster (e.g. in the test process) reachable from the cluster.
+func (h *SuiteController) CreateExternalService(serviceName, namespace, ip string, port int32) (*corev1.Service, error) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: port, TargetPort: intstr.FromInt(int(port))}},
+		},
+	}
+	if err := h.KubeRest().Create(context.Background(), service); err != nil {
+		return nil, fmt.Errorf("failed to create service %s/%s: %v", namespace, serviceName, err)
+	}
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: namespace,
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: ip}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Port: port}},
+		}},
+	}
+	if err := h.KubeRest().Create(context.Background(), endpoints); err != nil {
+		if delErr := h.DeleteService(serviceName, namespace); delErr != nil {
+			return nil, fmt.Errorf("failed to create endpoints %s/%s: %v, failed to delete the service: %v", namespace, serviceName, err, delErr)
+		}
+		return nil, fmt.Errorf("failed to create endpoints %s/%s: %v", namespace, serviceName, err)
+	}
+
+	return service, nil
+}
+
+// DeleteService deletes a service with a given name in a given namespace.
+func (h *SuiteController) DeleteService(serviceName, namespace string) error {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace}}
+	if err := h.KubeRest().Delete(context.Background(), service); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
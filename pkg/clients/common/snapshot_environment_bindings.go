@@ -38,13 +38,18 @@ func (s *SuiteController) DeleteAllSnapshotEnvBindingsInASpecificNamespace(names
 		return fmt.Errorf("error deleting snapshotEnvironmentBindings from the namespace %s: %+v", namespace, err)
 	}
 
-	snapshotEnvironmentBindingList := &appservice.SnapshotEnvironmentBindingList{}
-	return utils.WaitUntil(func() (done bool, err error) {
-		if err := s.KubeRest().List(context.Background(), snapshotEnvironmentBindingList, &rclient.ListOptions{Namespace: namespace}); err != nil {
-			return false, nil
-		}
-		return len(snapshotEnvironmentBindingList.Items) == 0, nil
-	}, timeout)
+	return utils.NewPoller(fmt.Sprintf("all snapshot environment bindings to be deleted from %s namespace", namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			snapshotEnvironmentBindingList := &appservice.SnapshotEnvironmentBindingList{}
+			if err := s.KubeRest().List(ctx, snapshotEnvironmentBindingList, &rclient.ListOptions{Namespace: namespace}); err != nil {
+				return false, nil, err
+			}
+			remaining := []string{}
+			for _, binding := range snapshotEnvironmentBindingList.Items {
+				remaining = append(remaining, binding.Name)
+			}
+			return len(remaining) == 0, remaining, nil
+		})
 }
 
 // ListAllSnapshotEnvBindings returns a list of all SnapshotEnvBindings in a given namespace.
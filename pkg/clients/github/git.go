@@ -59,3 +59,30 @@ func (g *Github) ExistsRef(repository, branchName string) (bool, error) {
 	}
 	return true, nil
 }
+
+// ListBranches returns all branches of the repository, including their last commits with dates
+func (g *Github) ListBranches(repository string) ([]*github.Branch, error) {
+	ctx := context.Background()
+	opt := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var allBranches []*github.Branch
+	for {
+		branches, resp, err := g.client.Repositories.ListBranches(ctx, g.organization, repository, opt)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing branches of the repo %s: %v", repository, err)
+		}
+		allBranches = append(allBranches, branches...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	// Listed branches contain only SHAs of their last commits
+	for i, b := range allBranches {
+		branch, _, err := g.client.Repositories.GetBranch(ctx, g.organization, repository, b.GetName(), true)
+		if err != nil {
+			return nil, fmt.Errorf("error when getting the branch '%s' for the repo '%s': %v", b.GetName(), repository, err)
+		}
+		allBranches[i] = branch
+	}
+	return allBranches, nil
+}
@@ -39,6 +39,15 @@ func (g *Github) ListPullRequests(repository string) ([]*github.PullRequest, err
 	return prs, nil
 }
 
+// ClosePullRequest closes the pull request without merging it
+func (g *Github) ClosePullRequest(repository string, prNumber int) error {
+	_, _, err := g.client.PullRequests.Edit(context.Background(), g.organization, repository, prNumber, &github.PullRequest{State: github.String("closed")})
+	if err != nil {
+		return fmt.Errorf("error when closing the pull request #%d in the repo %s: %v", prNumber, repository, err)
+	}
+	return nil
+}
+
 func (g *Github) ListPullRequestCommentsSince(repository string, prNumber int, since time.Time) ([]*github.IssueComment, error) {
 	comments, _, err := g.client.Issues.ListComments(context.Background(), g.organization, repository, prNumber, &github.IssueListCommentsOptions{
 		Since:     &since,
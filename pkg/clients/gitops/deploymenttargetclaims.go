@@ -26,6 +26,14 @@ func (g *GitopsController) GetDeploymentTargetClaimsList(namespace string) (*app
 	return deploymentTargetClaimList, nil
 }
 
+// DeleteDeploymentTargetClaim deletes the DeploymentTargetClaim, it's not an error if it doesn't exist
+func (g *GitopsController) DeleteDeploymentTargetClaim(deploymentTargetClaim *appservice.DeploymentTargetClaim) error {
+	if err := g.KubeRest().Delete(context.Background(), deploymentTargetClaim); err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting DeploymentTargetClaim %s from the namespace %s: %+v", deploymentTargetClaim.Name, deploymentTargetClaim.Namespace, err)
+	}
+	return nil
+}
+
 // StoreDeploymentTargetClaim stores a given DeploymentTargetClaim as an artifact.
 func (g *GitopsController) StoreDeploymentTargetClaim(deploymentTargetClaim *appservice.DeploymentTargetClaim) error {
 	return logs.StoreResourceYaml(deploymentTargetClaim, "deploymentTargetClaim-"+deploymentTargetClaim.Name)
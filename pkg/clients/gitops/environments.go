@@ -70,6 +70,7 @@ func (g *GitopsController) CreateEphemeralEnvironment(name string, namespace str
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
+			Labels:    utils.WithE2ETestLabel(nil),
 		},
 		Spec: appservice.EnvironmentSpec{
 			DeploymentStrategy: appservice.DeploymentStrategy_AppStudioAutomated,
@@ -123,6 +124,7 @@ func (g *GitopsController) CreatePocEnvironment(name string, namespace string) (
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
+			Labels:    utils.WithE2ETestLabel(nil),
 		},
 		Spec: appservice.EnvironmentSpec{
 			Type:               "POC",
@@ -166,13 +168,26 @@ func (g *GitopsController) DeleteAllEnvironmentsInASpecificNamespace(namespace s
 		return fmt.Errorf("error deleting environments from the namespace %s: %+v", namespace, err)
 	}
 
-	environmentList := &appservice.EnvironmentList{}
-	return utils.WaitUntil(func() (done bool, err error) {
-		if err := g.KubeRest().List(context.Background(), environmentList, &client.ListOptions{Namespace: namespace}); err != nil {
-			return false, nil
-		}
-		return len(environmentList.Items) == 0, nil
-	}, timeout)
+	return utils.NewPoller(fmt.Sprintf("all environments to be deleted from %s namespace", namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			environmentList := &appservice.EnvironmentList{}
+			if err := g.KubeRest().List(ctx, environmentList, &client.ListOptions{Namespace: namespace}); err != nil {
+				return false, nil, err
+			}
+			remaining := []string{}
+			for _, environment := range environmentList.Items {
+				remaining = append(remaining, environment.Name)
+			}
+			return len(remaining) == 0, remaining, nil
+		})
+}
+
+// DeleteEnvironment deletes the environment, it's not an error if it doesn't exist
+func (g *GitopsController) DeleteEnvironment(environment *appservice.Environment) error {
+	if err := g.KubeRest().Delete(context.Background(), environment); err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting environment %s from the namespace %s: %+v", environment.Name, environment.Namespace, err)
+	}
+	return nil
 }
 
 // ListAllEnvironments returns a list of all Environments in a given namespace.
@@ -71,9 +71,17 @@ func (h *HasController) CreateApplicationWithTimeout(name string, namespace stri
 		return nil, err
 	}
 
-	if err := utils.WaitUntil(h.ApplicationDevfilePresent(application), timeout); err != nil {
-		application = h.refreshApplicationForErrorDebug(application)
-		return nil, fmt.Errorf("timed out when waiting for devfile content creation for application %s in %s namespace: %+v. applicattion: %s", name, namespace, err, utils.ToPrettyJSONString(application))
+	err := utils.NewPoller(fmt.Sprintf("devfile content creation for application %s in %s namespace", name, namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			app, err := h.GetApplication(name, namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			application.Status = app.Status
+			return application.Status.Devfile != "", app, nil
+		})
+	if err != nil {
+		return nil, err
 	}
 
 	return application, nil
@@ -94,7 +102,16 @@ func (h *HasController) DeleteApplication(name string, namespace string, reportE
 			return fmt.Errorf("error deleting an application: %+v", err)
 		}
 	}
-	return utils.WaitUntil(h.ApplicationDeleted(&application), 1*time.Minute)
+	return utils.NewPoller(fmt.Sprintf("application %s to be deleted from %s namespace", name, namespace), 1*time.Minute).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			app, err := h.GetApplication(name, namespace)
+			if k8sErrors.IsNotFound(err) {
+				return true, nil, nil
+			} else if err != nil {
+				return false, nil, err
+			}
+			return false, app, nil
+		})
 }
 
 // ApplicationDeleted check if a given application object was deleted successfully from the kubernetes cluster.
@@ -111,24 +128,18 @@ func (h *HasController) DeleteAllApplicationsInASpecificNamespace(namespace stri
 		return fmt.Errorf("error deleting applications from the namespace %s: %+v", namespace, err)
 	}
 
-	return utils.WaitUntil(func() (done bool, err error) {
-		applicationList, err := h.ListAllApplications(namespace)
-		if err != nil {
-			return false, nil
-		}
-		return len(applicationList.Items) == 0, nil
-	}, timeout)
-}
-
-// refreshApplicationForErrorDebug return the latest application object from the kubernetes cluster.
-func (h *HasController) refreshApplicationForErrorDebug(application *appservice.Application) *appservice.Application {
-	retApp := &appservice.Application{}
-
-	if err := h.KubeRest().Get(context.Background(), rclient.ObjectKeyFromObject(application), retApp); err != nil {
-		return application
-	}
-
-	return retApp
+	return utils.NewPoller(fmt.Sprintf("all applications to be deleted from %s namespace", namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			applicationList, err := h.ListAllApplications(namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			remaining := []string{}
+			for _, a := range applicationList.Items {
+				remaining = append(remaining, a.Name)
+			}
+			return len(remaining) == 0, remaining, nil
+		})
 }
 
 // ListAllApplications returns a list of all Applications in a given namespace.
@@ -55,18 +55,20 @@ func (h *HasController) CreateComponentDetectionQueryWithTimeout(name string, na
 		return nil, err
 	}
 
-	err := utils.WaitUntil(func() (done bool, err error) {
-		componentDetectionQuery, err = h.GetComponentDetectionQuery(componentDetectionQuery.Name, componentDetectionQuery.Namespace)
-		if err != nil {
-			return false, err
-		}
-		for _, condition := range componentDetectionQuery.Status.Conditions {
-			if condition.Type == "Completed" && len(componentDetectionQuery.Status.ComponentDetected) > 0 {
-				return true, nil
+	err := utils.NewPoller(fmt.Sprintf("component detection query %s to complete in %s namespace", name, namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			cdq, err := h.GetComponentDetectionQuery(componentDetectionQuery.Name, componentDetectionQuery.Namespace)
+			if err != nil {
+				return false, nil, utils.StopPolling(err)
 			}
-		}
-		return false, nil
-	}, timeout)
+			componentDetectionQuery = cdq
+			for _, condition := range componentDetectionQuery.Status.Conditions {
+				if condition.Type == "Completed" && len(componentDetectionQuery.Status.ComponentDetected) > 0 {
+					return true, componentDetectionQuery, nil
+				}
+			}
+			return false, componentDetectionQuery, nil
+		})
 
 	if err != nil {
 		return nil, fmt.Errorf("error waiting for cdq to be ready: %v", err)
@@ -81,13 +83,18 @@ func (h *HasController) DeleteAllComponentDetectionQueriesInASpecificNamespace(n
 		return fmt.Errorf("error deleting component detection queries from the namespace %s: %+v", namespace, err)
 	}
 
-	return utils.WaitUntil(func() (done bool, err error) {
-		componentDetectionQueriesList, err := h.ListAllComponentDetectionQueries(namespace)
-		if err != nil {
-			return false, nil
-		}
-		return len(componentDetectionQueriesList.Items) == 0, nil
-	}, timeout)
+	return utils.NewPoller(fmt.Sprintf("all component detection queries to be deleted from %s namespace", namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			componentDetectionQueriesList, err := h.ListAllComponentDetectionQueries(namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			remaining := []string{}
+			for _, cdq := range componentDetectionQueriesList.Items {
+				remaining = append(remaining, cdq.Name)
+			}
+			return len(remaining) == 0, remaining, nil
+		})
 }
 
 // ListAllComponentDetectionQueries returns a list of all ComponentDetectionQueries in a given namespace.
@@ -13,6 +13,7 @@ import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/tekton"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
+	"github.com/redhat-appstudio/e2e-tests/pkg/matchers"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/build"
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -128,6 +129,7 @@ type RetryOptions struct {
 
 // Waits for a given component to be finished and in case of hitting issue: https://issues.redhat.com/browse/SRVKP-2749 do a given retries.
 func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservice.Component, sha string, t *tekton.TektonController, r *RetryOptions) error {
+	defer logs.TrackTiming("WaitForComponentPipelineToBeFinished")()
 	attempts := 1
 	app := component.Spec.Application
 	var pr *pipeline.PipelineRun
@@ -219,14 +221,12 @@ func (h *HasController) CreateComponent(componentSpec appservice.ComponentSpec,
 	if err := h.KubeRest().Create(ctx, componentObject); err != nil {
 		return nil, err
 	}
-	if err := utils.WaitUntil(h.ComponentReady(componentObject), time.Minute*10); err != nil {
-		componentObject = h.refreshComponentForErrorDebug(componentObject)
-		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", componentSpec.ComponentName, namespace, utils.ToPrettyJSONString(componentObject))
+	if err := h.waitForComponentReady(componentObject, time.Minute*10); err != nil {
+		return nil, err
 	}
 
-	if utils.WaitUntil(h.CheckForImageAnnotation(componentObject), time.Minute*5) != nil {
-		componentObject = h.refreshComponentForErrorDebug(componentObject)
-		return nil, fmt.Errorf("timed out when waiting for image-controller annotations to be updated on component %s in namespace %s. component: %s", componentSpec.ComponentName, namespace, utils.ToPrettyJSONString(componentObject))
+	if err := h.waitForImageAnnotation(componentObject, time.Minute*5); err != nil {
+		return nil, err
 	}
 	return componentObject, nil
 }
@@ -293,7 +293,16 @@ func (h *HasController) DeleteComponent(name string, namespace string, reportErr
 	}
 
 	// RHTAPBUGS-978: temporary timeout to 15min
-	err := utils.WaitUntil(h.ComponentDeleted(&component), 15*time.Minute)
+	err := utils.NewPoller(fmt.Sprintf("component %s to be deleted from %s namespace", name, namespace), 15*time.Minute).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			c, err := h.GetComponent(name, namespace)
+			if k8sErrors.IsNotFound(err) {
+				return true, nil, nil
+			} else if err != nil {
+				return false, nil, err
+			}
+			return false, c, nil
+		})
 
 	// temporary logs
 	deletionTime := time.Since(start).Minutes()
@@ -312,14 +321,18 @@ func (h *HasController) DeleteAllComponentsInASpecificNamespace(namespace string
 		return fmt.Errorf("error deleting components from the namespace %s: %+v", namespace, err)
 	}
 
-	componentList := &appservice.ComponentList{}
-
-	err := utils.WaitUntil(func() (done bool, err error) {
-		if err := h.KubeRest().List(context.Background(), componentList, &rclient.ListOptions{Namespace: namespace}); err != nil {
-			return false, nil
-		}
-		return len(componentList.Items) == 0, nil
-	}, timeout)
+	err := utils.NewPoller(fmt.Sprintf("all components to be deleted from %s namespace", namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			componentList := &appservice.ComponentList{}
+			if err := h.KubeRest().List(ctx, componentList, &rclient.ListOptions{Namespace: namespace}); err != nil {
+				return false, nil, err
+			}
+			remaining := []string{}
+			for _, c := range componentList.Items {
+				remaining = append(remaining, c.Name)
+			}
+			return len(remaining) == 0, remaining, nil
+		})
 
 	// temporary logs
 	deletionTime := time.Since(start).Minutes()
@@ -331,16 +344,11 @@ func (h *HasController) DeleteAllComponentsInASpecificNamespace(namespace string
 // Waits for a component to be reconciled in the application service.
 func (h *HasController) ComponentReady(component *appservice.Component) wait.ConditionFunc {
 	return func() (bool, error) {
-		messages, err := h.GetComponentConditionStatusMessages(component.Name, component.Namespace)
+		c, err := h.GetComponent(component.Name, component.Namespace)
 		if err != nil {
 			return false, nil
 		}
-		for _, m := range messages {
-			if strings.Contains(m, "success") {
-				return true, nil
-			}
-		}
-		return false, nil
+		return matchers.BeReady().Match(c)
 	}
 }
 
@@ -453,16 +461,32 @@ func (h *HasController) RetriggerComponentPipelineRun(component *appservice.Comp
 	return sha, nil
 }
 
-// refreshComponentForErrorDebug returns the latest component object from the kubernetes cluster.
-func (h *HasController) refreshComponentForErrorDebug(component *appservice.Component) *appservice.Component {
-	retComp := &appservice.Component{}
-	key := rclient.ObjectKeyFromObject(component)
-	err := h.KubeRest().Get(context.Background(), key, retComp)
-	if err != nil {
-		//TODO let's log this somehow, but return the original component obj, as that is better than nothing
-		return component
-	}
-	return retComp
+// waitForComponentReady waits for the component to be reconciled in the application service.
+// On timeout, the returned error describes the last observed component.
+func (h *HasController) waitForComponentReady(component *appservice.Component, timeout time.Duration) error {
+	return utils.NewPoller(fmt.Sprintf("component %s to be ready in %s namespace", component.Name, component.Namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			c, err := h.GetComponent(component.Name, component.Namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			ready, err := matchers.BeReady().Match(c)
+			return ready, c, err
+		})
+}
+
+// waitForImageAnnotation waits for image-controller to create the image repository of the component.
+// On timeout, the returned error describes the last observed annotations of the component.
+func (h *HasController) waitForImageAnnotation(component *appservice.Component, timeout time.Duration) error {
+	return utils.NewPoller(fmt.Sprintf("image-controller annotations to be updated on component %s in %s namespace", component.Name, component.Namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			c, err := h.GetComponent(component.Name, component.Namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			annotations := c.GetAnnotations()
+			return build.IsImageAnnotationPresent(annotations) && build.ImageRepoCreationSucceeded(annotations), annotations, nil
+		})
 }
 
 func (h *HasController) CheckForImageAnnotation(component *appservice.Component) wait.ConditionFunc {
@@ -565,9 +589,8 @@ func (h *HasController) CreateComponentWithoutGenerateAnnotation(componentSpec a
 		return nil, err
 	}
 
-	if err := utils.WaitUntil(h.ComponentReady(componentObject), time.Minute*10); err != nil {
-		componentObject = h.refreshComponentForErrorDebug(componentObject)
-		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", componentSpec.ComponentName, namespace, utils.ToPrettyJSONString(componentObject))
+	if err := h.waitForComponentReady(componentObject, time.Minute*10); err != nil {
+		return nil, err
 	}
 
 	return componentObject, nil
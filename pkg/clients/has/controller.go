@@ -1,8 +1,8 @@
 package has
 
 import (
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/github"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
@@ -19,8 +19,8 @@ type HasController struct {
 
 // Initializes all the clients and return interface to operate with application-service controller.
 func NewSuiteController(kube *kubeCl.CustomClient) (*HasController, error) {
-	gh, err := github.NewGithubClient(utils.GetEnv(constants.GITHUB_TOKEN_ENV, ""),
-		utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe"))
+	gh, err := github.NewGithubClient(config.Get(constants.GITHUB_TOKEN_ENV),
+		config.Get(constants.GITHUB_E2E_ORGANIZATION_ENV))
 	if err != nil {
 		return nil, err
 	}
@@ -131,17 +131,23 @@ func (i *IntegrationController) DeleteAllSnapshotsInASpecificNamespace(namespace
 		return fmt.Errorf("error deleting snapshots from the namespace %s: %+v", namespace, err)
 	}
 
-	return utils.WaitUntil(func() (done bool, err error) {
-		snapshotList, err := i.ListAllSnapshots(namespace)
-		if err != nil {
-			return false, nil
-		}
-		return len(snapshotList.Items) == 0, nil
-	}, timeout)
+	return utils.NewPoller(fmt.Sprintf("all snapshots to be deleted from %s namespace", namespace), timeout).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			snapshotList, err := i.ListAllSnapshots(namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			remaining := []string{}
+			for _, snapshot := range snapshotList.Items {
+				remaining = append(remaining, snapshot.Name)
+			}
+			return len(remaining) == 0, remaining, nil
+		})
 }
 
 // WaitForSnapshotToGetCreated wait for the Snapshot to get created successfully.
 func (i *IntegrationController) WaitForSnapshotToGetCreated(snapshotName, pipelinerunName, componentName, testNamespace string) (*appstudioApi.Snapshot, error) {
+	defer logs.TrackTiming("WaitForSnapshotToGetCreated")()
 	var snapshot *appstudioApi.Snapshot
 
 	err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 10*time.Minute, true, func(ctx context.Context) (done bool, err error) {
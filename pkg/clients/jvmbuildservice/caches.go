@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/common"
+	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
 	"github.com/redhat-appstudio/jvm-build-service/pkg/apis/jvmbuildservice/v1alpha1"
 
 	v1 "k8s.io/api/apps/v1"
@@ -16,6 +17,7 @@ import (
 
 // WaitForCache waits for cache to exist.
 func (j *JvmbuildserviceController) WaitForCache(commonctrl *common.SuiteController, testNamespace string) error {
+	defer logs.TrackTiming("WaitForCache")()
 	return wait.PollUntilContextTimeout(context.Background(), 5*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
 		cache, err := commonctrl.GetDeployment(v1alpha1.CacheDeploymentName, testNamespace)
 		if err != nil {
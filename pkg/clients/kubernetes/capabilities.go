@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ComponentNamespaces maps AppStudio components (and the operators they depend on) to the namespaces of their
+// deployments. A component is installed if it has a ready deployment in its namespace.
+var ComponentNamespaces = map[string]string{
+	"application-service":       "application-service",
+	"build-service":             "build-service",
+	"enterprise-contract":       "enterprise-contract-service",
+	"image-controller":          "image-controller",
+	"integration-service":       "integration-service",
+	"jvm-build-service":         "jvm-build-service",
+	"multi-platform-controller": "multi-platform-controller",
+	"openshift-gitops":          "openshift-gitops",
+	"openshift-pipelines":       "openshift-pipelines",
+	"release-service":           "release-service",
+	"remote-secret":             "remotesecret",
+	"spi":                       "spi-system",
+}
+
+var (
+	crdGVR            = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	clusterVersionGVR = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+)
+
+// Operator is a deployment of a component
+type Operator struct {
+	Component string `json:"component"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+	// Images of the deployment's containers, with their SHAs if the deployment has running pods
+	Images []string `json:"images"`
+}
+
+// Capabilities describes what is installed in the cluster. Specs query it to skip or adapt to the cluster
+// they run against, and it's recorded with each run as the cluster inventory.
+type Capabilities struct {
+	KubernetesVersion string `json:"kubernetesVersion"`
+	// OpenShiftVersion is empty if the cluster is not an OpenShift cluster
+	OpenShiftVersion string `json:"openShiftVersion,omitempty"`
+	// APIVersions served by the cluster, e.g. "route.openshift.io/v1"
+	APIVersions []string `json:"apiVersions"`
+	// CRDs installed in the cluster, e.g. "components.appstudio.redhat.com"
+	CRDs         []string   `json:"crds"`
+	Operators    []Operator `json:"operators"`
+	DiscoveredAt time.Time  `json:"discoveredAt"`
+}
+
+// HasAPIVersion returns true if the cluster serves the group version, e.g. "appstudio.redhat.com/v1alpha1"
+func (c *Capabilities) HasAPIVersion(groupVersion string) bool {
+	return contains(c.APIVersions, groupVersion)
+}
+
+// HasAPIGroup returns true if the cluster serves any version of the API group, e.g. "route.openshift.io"
+func (c *Capabilities) HasAPIGroup(group string) bool {
+	for _, gv := range c.APIVersions {
+		if g, _, _ := strings.Cut(gv, "/"); g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCRD returns true if the custom resource is installed, e.g. "components.appstudio.redhat.com"
+func (c *Capabilities) HasCRD(name string) bool {
+	return contains(c.CRDs, name)
+}
+
+// IsInstalled returns true if the component has a deployment in its namespace
+func (c *Capabilities) IsInstalled(component string) bool {
+	for _, o := range c.Operators {
+		if o.Component == component {
+			return true
+		}
+	}
+	return false
+}
+
+// HasComponent returns true if the component is installed and all its deployments are ready
+func (c *Capabilities) HasComponent(component string) bool {
+	found := false
+	for _, o := range c.Operators {
+		if o.Component != component {
+			continue
+		}
+		if !o.Ready {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// IsOpenShift returns true if the cluster is an OpenShift cluster
+func (c *Capabilities) IsOpenShift() bool {
+	return c.OpenShiftVersion != ""
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscoverCapabilities inspects the API versions, CRDs and component deployments of the cluster
+func (c *CustomClient) DiscoverCapabilities(ctx context.Context) (*Capabilities, error) {
+	return discoverCapabilities(ctx, c.KubeInterface(), c.DynamicClient())
+}
+
+func discoverCapabilities(ctx context.Context, kube kubernetes.Interface, dyn dynamic.Interface) (*Capabilities, error) {
+	caps := &Capabilities{APIVersions: []string{}, CRDs: []string{}, Operators: []Operator{}, DiscoveredAt: time.Now().UTC()}
+
+	version, err := kube.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error when getting version of the cluster: %v", err)
+	}
+	caps.KubernetesVersion = version.GitVersion
+
+	groups, err := kube.Discovery().ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("error when discovering API groups of the cluster: %v", err)
+	}
+	for _, g := range groups.Groups {
+		for _, v := range g.Versions {
+			caps.APIVersions = append(caps.APIVersions, v.GroupVersion)
+		}
+	}
+	sort.Strings(caps.APIVersions)
+
+	crds, err := dyn.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing CRDs: %v", err)
+	}
+	for _, crd := range crds.Items {
+		caps.CRDs = append(caps.CRDs, crd.GetName())
+	}
+	sort.Strings(caps.CRDs)
+
+	if caps.HasAPIVersion(clusterVersionGVR.GroupVersion().String()) {
+		clusterVersion, err := dyn.Resource(clusterVersionGVR).Get(ctx, "version", metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("error when getting OpenShift cluster version: %v", err)
+		} else if err == nil {
+			caps.OpenShiftVersion, _, _ = unstructured.NestedString(clusterVersion.Object, "status", "desired", "version")
+		}
+	}
+
+	components := make([]string, 0, len(ComponentNamespaces))
+	for component := range ComponentNamespaces {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		operators, err := discoverOperators(ctx, kube, component, ComponentNamespaces[component])
+		if err != nil {
+			return nil, err
+		}
+		caps.Operators = append(caps.Operators, operators...)
+	}
+	return caps, nil
+}
+
+// discoverOperators returns deployments in the namespace of the component with images of their pods
+func discoverOperators(ctx context.Context, kube kubernetes.Interface, component, namespace string) ([]Operator, error) {
+	deployments, err := kube.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing deployments of %s in namespace %s: %v", component, namespace, err)
+	}
+	operators := []Operator{}
+	for _, d := range deployments.Items {
+		images, err := deploymentImages(ctx, kube, d)
+		if err != nil {
+			return nil, err
+		}
+		operators = append(operators, Operator{
+			Component: component,
+			Namespace: namespace,
+			Name:      d.Name,
+			Ready:     d.Spec.Replicas == nil || d.Status.AvailableReplicas >= *d.Spec.Replicas,
+			Images:    images,
+		})
+	}
+	return operators, nil
+}
+
+// deploymentImages returns image IDs of the deployment's running containers, or the images of its pod template
+// if none of its pods are running
+func deploymentImages(ctx context.Context, kube kubernetes.Interface, d appsv1.Deployment) ([]string, error) {
+	images := []string{}
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of deployment %s/%s: %v", d.Namespace, d.Name, err)
+	}
+	pods, err := kube.CoreV1().Pods(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing pods of deployment %s/%s: %v", d.Namespace, d.Name, err)
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.ImageID != "" && !contains(images, status.ImageID) {
+				images = append(images, status.ImageID)
+			}
+		}
+	}
+	if len(images) == 0 {
+		for _, container := range d.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+	}
+	sort.Strings(images)
+	return images, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	pointer "k8s.io/utils/ptr"
+)
+
+func deployment(namespace, name string, replicas, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.To(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "quay.io/redhat-appstudio/" + name + ":latest"}}}},
+		},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+func unstructuredObject(apiVersion, kind, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	return obj
+}
+
+func TestDiscoverCapabilities(t *testing.T) {
+	kube := fake.NewSimpleClientset(
+		deployment("build-service", "build-service-controller-manager", 1, 1),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "build-service", Name: "build-service-controller-manager-1", Labels: map[string]string{"app": "build-service-controller-manager"}},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{ImageID: "quay.io/redhat-appstudio/build-service@sha256:1234"}}},
+		},
+		deployment("jvm-build-service", "jvm-build-service-operator", 1, 0),
+		deployment("image-controller", "image-controller-controller-manager", 1, 1),
+		deployment("image-controller", "image-controller-webhook", 2, 1),
+	)
+	kube.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.27.6+f67aeb3"}
+	kube.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "appstudio.redhat.com/v1alpha1"},
+		{GroupVersion: "config.openshift.io/v1"},
+		{GroupVersion: "route.openshift.io/v1"},
+	}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"},
+		unstructuredObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "components.appstudio.redhat.com", map[string]interface{}{}),
+		unstructuredObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "applications.appstudio.redhat.com", map[string]interface{}{}),
+		unstructuredObject("config.openshift.io/v1", "ClusterVersion", "version", map[string]interface{}{
+			"status": map[string]interface{}{"desired": map[string]interface{}{"version": "4.14.1"}},
+		}),
+	)
+
+	caps, err := discoverCapabilities(context.Background(), kube, dyn)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.27.6+f67aeb3", caps.KubernetesVersion)
+	assert.Equal(t, "4.14.1", caps.OpenShiftVersion)
+	assert.True(t, caps.IsOpenShift())
+	assert.Equal(t, []string{"applications.appstudio.redhat.com", "components.appstudio.redhat.com"}, caps.CRDs)
+	assert.True(t, caps.HasCRD("components.appstudio.redhat.com"))
+	assert.False(t, caps.HasCRD("repositories.pipelinesascode.tekton.dev"))
+	assert.True(t, caps.HasAPIVersion("route.openshift.io/v1"))
+	assert.False(t, caps.HasAPIVersion("route.openshift.io/v2"))
+	assert.True(t, caps.HasAPIGroup("appstudio.redhat.com"))
+	assert.False(t, caps.HasAPIGroup("toolchain.dev.openshift.com"))
+
+	assert.Equal(t, []Operator{
+		{Component: "build-service", Namespace: "build-service", Name: "build-service-controller-manager", Ready: true, Images: []string{"quay.io/redhat-appstudio/build-service@sha256:1234"}},
+		{Component: "image-controller", Namespace: "image-controller", Name: "image-controller-controller-manager", Ready: true, Images: []string{"quay.io/redhat-appstudio/image-controller-controller-manager:latest"}},
+		{Component: "image-controller", Namespace: "image-controller", Name: "image-controller-webhook", Ready: false, Images: []string{"quay.io/redhat-appstudio/image-controller-webhook:latest"}},
+		{Component: "jvm-build-service", Namespace: "jvm-build-service", Name: "jvm-build-service-operator", Ready: false, Images: []string{"quay.io/redhat-appstudio/jvm-build-service-operator:latest"}},
+	}, caps.Operators)
+	assert.True(t, caps.HasComponent("build-service"))
+	assert.True(t, caps.IsInstalled("image-controller"))
+	assert.False(t, caps.HasComponent("image-controller"))
+	assert.False(t, caps.HasComponent("jvm-build-service"))
+	assert.False(t, caps.HasComponent("release-service"))
+	assert.False(t, caps.IsInstalled("release-service"))
+}
+
+func TestDiscoverCapabilitiesKubernetes(t *testing.T) {
+	kube := fake.NewSimpleClientset()
+	kube.Resources = []*metav1.APIResourceList{{GroupVersion: "apps/v1"}}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"})
+
+	caps, err := discoverCapabilities(context.Background(), kube, dyn)
+	assert.NoError(t, err)
+	assert.False(t, caps.IsOpenShift())
+	assert.Empty(t, caps.CRDs)
+	assert.Empty(t, caps.Operators)
+}
@@ -0,0 +1,109 @@
+package sprayproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// FakeServer is an in-memory stand-in for SprayProxy backends API, meant to be used in unit tests.
+type FakeServer struct {
+	*httptest.Server
+	Token string
+
+	mu       sync.Mutex
+	backends []string
+	// failures holds status codes returned (in order) instead of handling the following requests
+	failures []int
+	requests int
+}
+
+// NewFakeServer starts a fake SprayProxy server requiring the given bearer token, with the given backends registered.
+// The caller is responsible for calling Close.
+func NewFakeServer(token string, backends ...string) *FakeServer {
+	f := &FakeServer{Token: token, backends: append([]string{}, backends...)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+// NewClient returns a SprayProxy client configured for the fake server without retry delays.
+func (f *FakeServer) NewClient() *SprayProxyConfig {
+	s, _ := NewSprayProxyConfig(f.URL, f.Token, WithHTTPClient(f.Client()), WithRetries(defaultRetryAttempts, 0))
+	return s
+}
+
+// Backends returns currently registered backends.
+func (f *FakeServer) Backends() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.backends...)
+}
+
+// FailNext makes the server respond to the following requests with the given status codes.
+func (f *FakeServer) FailNext(statusCodes ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, statusCodes...)
+}
+
+// Requests returns the number of requests received by the server.
+func (f *FakeServer) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func (f *FakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	if len(f.failures) > 0 {
+		code := f.failures[0]
+		f.failures = f.failures[1:]
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+	if r.URL.Path != backendsPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+f.Token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		fmt.Fprintf(w, "%s %s", backendsPrefix, strings.Join(f.backends, ","))
+		return
+	}
+
+	body := map[string]string{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["url"] == "" {
+		http.Error(w, "request body must contain the backend url", http.StatusBadRequest)
+		return
+	}
+	backend := body["url"]
+
+	switch r.Method {
+	case http.MethodPost:
+		if !containsBackend(f.backends, backend) {
+			f.backends = append(f.backends, backend)
+		}
+		fmt.Fprintf(w, "registered backend %s", backend)
+	case http.MethodDelete:
+		for i, b := range f.backends {
+			if b == backend {
+				f.backends = append(f.backends[:i], f.backends[i+1:]...)
+				fmt.Fprintf(w, "unregistered backend %s", backend)
+				return
+			}
+		}
+		http.Error(w, fmt.Sprintf("backend %s is not registered", backend), http.StatusNotFound)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -5,88 +5,222 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"github.com/avast/retry-go/v4"
 	routev1 "github.com/openshift/api/route/v1"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 )
 
-func NewSprayProxyConfig(url string, token string) (*SprayProxyConfig, error) {
-	return &SprayProxyConfig{
-		BaseURL: url,
+func NewSprayProxyConfig(url string, token string, opts ...Option) (*SprayProxyConfig, error) {
+	if url == "" {
+		return nil, fmt.Errorf("SprayProxy URL cannot be empty")
+	}
+	s := &SprayProxyConfig{
+		BaseURL: strings.TrimSuffix(url, "/"),
 		HTTPClient: &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					// #nosec G402
-					InsecureSkipVerify: true,
-				},
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
 			},
 		},
-		Token: token,
-	}, nil
+		Token:          token,
+		RequestTimeout: defaultRequestTimeout,
+		RetryAttempts:  defaultRetryAttempts,
+		RetryDelay:     defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
-func (s *SprayProxyConfig) RegisterServer(pacHost string) (string, error) {
+// RegisterServer registers the given PaC host as a SprayProxy backend.
+func (s *SprayProxyConfig) RegisterServer(ctx context.Context, pacHost string) (*Response, error) {
 	bytesData, err := buildBodyData(pacHost)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	result, err := s.sendRequest(http.MethodPost, bytes.NewReader(bytesData))
+	return s.sendRequest(ctx, http.MethodPost, bytesData)
+}
+
+// UnregisterServer removes the given PaC host from SprayProxy backends.
+func (s *SprayProxyConfig) UnregisterServer(ctx context.Context, pacHost string) (*Response, error) {
+	bytesData, err := buildBodyData(pacHost)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return result, nil
+
+	return s.sendRequest(ctx, http.MethodDelete, bytesData)
 }
 
-func (s *SprayProxyConfig) UnregisterServer(pacHost string) (string, error) {
-	bytesData, err := buildBodyData(pacHost)
+// GetServers returns URLs of all backends registered in SprayProxy.
+func (s *SprayProxyConfig) GetServers(ctx context.Context) ([]string, error) {
+	result, err := s.sendRequest(ctx, http.MethodGet, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return parseBackends(result.Message), nil
+}
 
-	result, err := s.sendRequest(http.MethodDelete, bytes.NewReader(bytesData))
+// Reap unregisters backends which are considered stale by the given check function (CheckBackend if nil),
+// e.g. because their PaC route is unreachable or the cluster they belong to does not exist anymore.
+// Backends listed in keep are never unregistered. With dryRun set, no backend is unregistered,
+// the result just lists what would be removed.
+func (s *SprayProxyConfig) Reap(ctx context.Context, check BackendCheckFunc, dryRun bool, keep ...string) (*ReapResult, error) {
+	if check == nil {
+		check = CheckBackend
+	}
+	servers, err := s.GetServers(ctx)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to get registered PaC servers from SprayProxy: %+v", err)
+	}
+
+	result := &ReapResult{DryRun: dryRun, Errors: map[string]error{}}
+	for _, server := range servers {
+		if containsBackend(keep, server) {
+			result.Kept = append(result.Kept, BackendStatus{URL: server, Alive: true, Reason: "excluded from reaping"})
+			continue
+		}
+		status := check(ctx, server)
+		if status.Alive {
+			result.Kept = append(result.Kept, status)
+			continue
+		}
+		if !dryRun {
+			if _, err := s.UnregisterServer(ctx, server); err != nil {
+				result.Errors[server] = err
+				continue
+			}
+			klog.Infof("Unregistered stale PaC server %s: %s", server, status.Reason)
+		}
+		result.Removed = append(result.Removed, status)
+	}
+
+	if len(result.Errors) > 0 {
+		return result, fmt.Errorf("failed to unregister %d stale PaC server(s) from SprayProxy server %s", len(result.Errors), s.BaseURL)
 	}
 	return result, nil
 }
 
-func (s *SprayProxyConfig) GetServers() (string, error) {
-	result, err := s.sendRequest(http.MethodGet, nil)
+// BackendCheckFunc decides whether a registered backend is still alive.
+type BackendCheckFunc func(ctx context.Context, backend string) BackendStatus
+
+// CheckBackend considers a backend stale when its host cannot be resolved anymore (the owning cluster is gone)
+// or when its PaC route does not respond at all. Any HTTP response means the backend is alive.
+func CheckBackend(ctx context.Context, backend string) BackendStatus {
+	status := BackendStatus{URL: backend}
+	u, err := url.Parse(backend)
+	if err != nil || u.Hostname() == "" {
+		status.Reason = fmt.Sprintf("invalid backend URL: %v", err)
+		return status
+	}
+
+	if _, err := net.DefaultResolver.LookupHost(ctx, u.Hostname()); err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			status.Reason = fmt.Sprintf("cluster is gone, host %s cannot be resolved", u.Hostname())
+			return status
+		}
+	}
+
+	client := &http.Client{
+		Timeout: defaultRequestTimeout,
+		Transport: &http.Transport{
+			// #nosec G402 -- PaC routes of test clusters use self-signed certificates, we only check reachability
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backend, nil)
+	if err != nil {
+		status.Reason = err.Error()
+		return status
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		status.Reason = fmt.Sprintf("PaC route is unreachable: %v", err)
+		return status
+	}
+	defer res.Body.Close()
+
+	status.Alive = true
+	return status
+}
+
+func (s *SprayProxyConfig) sendRequest(ctx context.Context, httpMethod string, data []byte) (*Response, error) {
+	requestURL := s.BaseURL + backendsPath
+
+	var response *Response
+	err := retry.Do(
+		func() error {
+			var err error
+			response, err = s.doRequest(ctx, httpMethod, requestURL, data)
+			return err
+		},
+		retry.Context(ctx),
+		retry.Attempts(s.RetryAttempts),
+		retry.Delay(s.RetryDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(isRetryable),
+	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return strings.TrimPrefix(result, "Backend urls:"), nil
+	return response, nil
 }
 
-func (s *SprayProxyConfig) sendRequest(httpMethod string, data io.Reader) (string, error) {
-	requestURL := s.BaseURL + "/backends"
+func (s *SprayProxyConfig) doRequest(ctx context.Context, httpMethod, requestURL string, data []byte) (*Response, error) {
+	if s.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.RequestTimeout)
+		defer cancel()
+	}
 
-	req, err := http.NewRequest(httpMethod, requestURL, data)
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, httpMethod, requestURL, body)
 	if err != nil {
-		return "", err
+		return nil, retry.Unrecoverable(err)
 	}
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.Token))
 
 	res, err := s.HTTPClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to access SprayProxy server with status code: %d and body: %s", res.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to read response body of SprayProxy server with status code %d: %v", res.StatusCode, err)
 	}
 
-	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &ResponseError{Method: httpMethod, StatusCode: res.StatusCode, Body: strings.TrimSpace(string(resBody))}
+	}
+
+	return &Response{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(resBody))}, nil
+}
 
-	return string(body), err
+// isRetryable returns false for client errors (4xx), which won't succeed on retry
+func isRetryable(err error) bool {
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode >= 500
+	}
+	return true
 }
 
 func GetPaCHost() (string, error) {
@@ -117,3 +251,23 @@ func buildBodyData(pacHost string) ([]byte, error) {
 	}
 	return bytesData, nil
 }
+
+// parseBackends parses the "Backend urls: <url1>,<url2>" response of SprayProxy
+func parseBackends(message string) []string {
+	backends := []string{}
+	for _, backend := range strings.Split(strings.TrimPrefix(message, backendsPrefix), ",") {
+		if backend = strings.TrimSpace(backend); backend != "" {
+			backends = append(backends, backend)
+		}
+	}
+	return backends
+}
+
+func containsBackend(backends []string, backend string) bool {
+	for _, b := range backends {
+		if strings.TrimSuffix(b, "/") == strings.TrimSuffix(backend, "/") {
+			return true
+		}
+	}
+	return false
+}
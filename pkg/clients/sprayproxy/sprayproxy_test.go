@@ -0,0 +1,89 @@
+package sprayproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterAndUnregisterServer(t *testing.T) {
+	server := NewFakeServer("token")
+	defer server.Close()
+	client := server.NewClient()
+	ctx := context.Background()
+
+	res, err := client.RegisterServer(ctx, "https://pac.cluster-a.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	servers, err := client.GetServers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://pac.cluster-a.example.com"}, servers)
+
+	_, err = client.UnregisterServer(ctx, "https://pac.cluster-a.example.com")
+	assert.NoError(t, err)
+
+	servers, err = client.GetServers(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, servers)
+}
+
+func TestSendRequestErrors(t *testing.T) {
+	server := NewFakeServer("token")
+	defer server.Close()
+	ctx := context.Background()
+
+	client := server.NewClient()
+	client.Token = "wrong"
+	_, err := client.GetServers(ctx)
+	var responseErr *ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusUnauthorized, responseErr.StatusCode)
+	assert.Equal(t, 1, server.Requests(), "client errors should not be retried")
+
+	client = server.NewClient()
+	server.FailNext(http.StatusBadGateway, http.StatusServiceUnavailable)
+	_, err = client.RegisterServer(ctx, "https://pac.cluster-a.example.com")
+	assert.NoError(t, err, "server errors should be retried")
+	assert.Equal(t, []string{"https://pac.cluster-a.example.com"}, server.Backends())
+
+	_, err = client.UnregisterServer(ctx, "https://pac.cluster-b.example.com")
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusNotFound, responseErr.StatusCode)
+}
+
+func TestReap(t *testing.T) {
+	alive := "https://pac.alive.example.com"
+	stale := "https://pac.stale.example.com"
+	own := "https://pac.own.example.com"
+	server := NewFakeServer("token", alive, stale, own)
+	defer server.Close()
+	client := server.NewClient()
+
+	check := func(ctx context.Context, backend string) BackendStatus {
+		if backend == alive {
+			return BackendStatus{URL: backend, Alive: true}
+		}
+		return BackendStatus{URL: backend, Reason: "PaC route is unreachable"}
+	}
+
+	result, err := client.Reap(context.Background(), check, true, own)
+	assert.NoError(t, err)
+	assert.Len(t, result.Removed, 1)
+	assert.Equal(t, stale, result.Removed[0].URL)
+	assert.Len(t, server.Backends(), 3, "dry run should not unregister any backend")
+
+	result, err = client.Reap(context.Background(), check, false, own)
+	assert.NoError(t, err)
+	assert.Len(t, result.Removed, 1)
+	assert.Len(t, result.Kept, 2)
+	assert.ElementsMatch(t, []string{alive, own}, server.Backends())
+}
+
+func TestParseBackends(t *testing.T) {
+	assert.Empty(t, parseBackends("Backend urls: "))
+	assert.Equal(t, []string{"https://a", "https://b"}, parseBackends("Backend urls: https://a, https://b"))
+}
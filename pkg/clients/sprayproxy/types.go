@@ -1,16 +1,98 @@
 package sprayproxy
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
 
 const (
 	sprayProxyNamespace = "sprayproxy"
 	sprayProxyName      = "sprayproxy-route"
 	pacNamespace        = "openshift-pipelines"
 	pacRouteName        = "pipelines-as-code-controller"
+
+	backendsPath          = "/backends"
+	backendsPrefix        = "Backend urls:"
+	defaultRequestTimeout = 30 * time.Second
+	defaultRetryAttempts  = 3
+	defaultRetryDelay     = 2 * time.Second
 )
 
 type SprayProxyConfig struct {
 	BaseURL    string
 	Token      string
 	HTTPClient *http.Client
+	// RequestTimeout limits a single attempt of a request to SprayProxy
+	RequestTimeout time.Duration
+	// RetryAttempts is the number of attempts made for requests failing with a network error or 5xx status code
+	RetryAttempts uint
+	RetryDelay    time.Duration
+}
+
+// Option configures the SprayProxy client.
+type Option func(*SprayProxyConfig)
+
+// WithInsecureSkipVerify disables verification of the SprayProxy server certificate.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(s *SprayProxyConfig) {
+		if transport, ok := s.HTTPClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.InsecureSkipVerify = skip // #nosec G402
+		}
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for talking to SprayProxy.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *SprayProxyConfig) {
+		s.HTTPClient = client
+	}
+}
+
+// WithRequestTimeout sets the timeout of a single request attempt.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *SprayProxyConfig) {
+		s.RequestTimeout = timeout
+	}
+}
+
+// WithRetries sets how many times and how often a failed request is attempted.
+func WithRetries(attempts uint, delay time.Duration) Option {
+	return func(s *SprayProxyConfig) {
+		s.RetryAttempts = attempts
+		s.RetryDelay = delay
+	}
+}
+
+// Response is a successful response of the SprayProxy backends API.
+type Response struct {
+	StatusCode int
+	Message    string
+}
+
+// ResponseError is returned when SprayProxy responds with a non-2xx status code.
+type ResponseError struct {
+	Method     string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("SprayProxy %s request failed with status code %d: %s", e.Method, e.StatusCode, e.Body)
+}
+
+// BackendStatus describes the result of checking a single registered backend.
+type BackendStatus struct {
+	URL   string
+	Alive bool
+	// Reason explains why the backend is considered stale
+	Reason string
+}
+
+// ReapResult lists backends that were (or in dry-run mode would be) unregistered by Reap.
+type ReapResult struct {
+	DryRun  bool
+	Removed []BackendStatus
+	Kept    []BackendStatus
+	Errors  map[string]error
 }
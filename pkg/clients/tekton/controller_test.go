@@ -1,10 +1,17 @@
 package tekton
 
 import (
-	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/h2non/gock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 	"github.com/stretchr/testify/assert"
 )
@@ -43,73 +50,58 @@ func TestCosignResultMissingFormat(t *testing.T) {
 	}.Missing("prefix"))
 }
 
-func createHttpMock(urlPath, tag string, response any) {
-	s := gock.New("https://quay.io/api/v1")
-	if len(tag) > 0 {
-		s.MatchParam("specificTag", tag)
-	}
-	s.Get(urlPath).
-		Reply(200).
-		JSON(response)
+// pushImage pushes an image with the given number of layers to the in-memory registry
+func pushImage(t *testing.T, ref name.Reference, layers int64) v1.Hash {
+	img, err := random.Image(10, layers)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Write(ref, img))
+	digest, err := img.Digest()
+	assert.NoError(t, err)
+	return digest
 }
 
 func TestFindingCosignResults(t *testing.T) {
-	const imageRegistryName = "quay.io"
-	const imageRepo = "test/repo"
-	const imageTag = "123"
-	const imageDigest = "sha256:abc"
-	const cosignImageTag = "sha256-abc"
-	const imageRef = imageRegistryName + "/" + imageRepo + ":" + imageTag + "@" + imageDigest
-	const signatureImageDigest = "sha256:signature"
-	const attestationImageDigest = "sha256:attestation"
-	const SignatureImageRef = imageRegistryName + "/" + imageRepo + "@" + signatureImageDigest
-	const AttestationImageRef = imageRegistryName + "/" + imageRepo + "@" + attestationImageDigest
-
 	cases := []struct {
 		Name                    string
 		SignatureImagePresent   bool
 		AttestationImagePresent bool
-		AttestationImageLayers  []any
+		AttestationImageLayers  int64
 		ExpectedErrors          []string
-		Result                  *tekton.CosignResult
+		SignatureFound          bool
+		AttestationFound        bool
 	}{
-		{"happy day", true, true, []any{""}, []string{}, &tekton.CosignResult{
-			SignatureImageRef:   SignatureImageRef,
-			AttestationImageRef: AttestationImageRef,
-		}},
-		{"happy day multiple attestations", true, true, []any{"", ""}, []string{}, &tekton.CosignResult{
-			SignatureImageRef:   SignatureImageRef,
-			AttestationImageRef: AttestationImageRef,
-		}},
-		{"missing signature", false, true, []any{""}, []string{"error when getting signature"}, &tekton.CosignResult{
-			SignatureImageRef:   "",
-			AttestationImageRef: AttestationImageRef,
-		}},
-		{"missing attestation", true, false, []any{""}, []string{"error when getting attestation"}, &tekton.CosignResult{
-			SignatureImageRef:   SignatureImageRef,
-			AttestationImageRef: "",
-		}},
-		{"missing signature and attestation", false, false, []any{""}, []string{"error when getting attestation", "error when getting signature"}, &tekton.CosignResult{
-			SignatureImageRef:   "",
-			AttestationImageRef: "",
-		}},
-		{"missing layers in attestation", true, true, []any{}, []string{"cannot get layers from"}, &tekton.CosignResult{
-			SignatureImageRef:   SignatureImageRef,
-			AttestationImageRef: "",
-		}},
+		{"happy day", true, true, 1, []string{}, true, true},
+		{"happy day multiple attestations", true, true, 2, []string{}, true, true},
+		{"missing signature", false, true, 1, []string{"error when getting signature"}, false, true},
+		{"missing attestation", true, false, 1, []string{"error when getting attestation"}, true, false},
+		{"missing signature and attestation", false, false, 1, []string{"error when getting attestation", "error when getting signature"}, false, false},
+		{"missing layers in attestation", true, true, 0, []string{"cannot get layers from"}, true, false},
 	}
 
 	for _, cse := range cases {
 		t.Run(cse.Name, func(t *testing.T) {
-			defer gock.Off()
+			server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+			defer server.Close()
+
+			repo, err := name.NewRepository(strings.TrimPrefix(server.URL, "http://") + "/test/repo")
+			assert.NoError(t, err)
+			imageDigest := pushImage(t, repo.Tag("123"), 1)
+			imageRef := repo.Tag("123").String() + "@" + imageDigest.String()
+			cosignImageTag := strings.Replace(imageDigest.String(), ":", "-", 1)
 
+			expected := &tekton.CosignResult{}
 			if cse.SignatureImagePresent {
-				createHttpMock(fmt.Sprintf("/repository/%s/tag", imageRepo), cosignImageTag+".sig", &tekton.TagResponse{Tags: []tekton.Tag{{Digest: signatureImageDigest}}})
+				signatureImageDigest := pushImage(t, repo.Tag(cosignImageTag+".sig"), 1)
+				if cse.SignatureFound {
+					expected.SignatureImageRef = repo.Digest(signatureImageDigest.String()).String()
+				}
 			}
 			if cse.AttestationImagePresent {
-				createHttpMock(fmt.Sprintf("/repository/%s/tag", imageRepo), cosignImageTag+".att", &tekton.TagResponse{Tags: []tekton.Tag{{Digest: attestationImageDigest}}})
+				attestationImageDigest := pushImage(t, repo.Tag(cosignImageTag+".att"), cse.AttestationImageLayers)
+				if cse.AttestationFound {
+					expected.AttestationImageRef = repo.Digest(attestationImageDigest.String()).String()
+				}
 			}
-			createHttpMock(fmt.Sprintf("/repository/%s/manifest/%s", imageRepo, attestationImageDigest), "", &tekton.ManifestResponse{Layers: cse.AttestationImageLayers})
 
 			result, err := tekton.FindCosignResultsForImage(imageRef)
 
@@ -121,7 +113,7 @@ func TestFindingCosignResults(t *testing.T) {
 			} else {
 				assert.Empty(t, cse.ExpectedErrors)
 			}
-			assert.Equal(t, cse.Result, result)
+			assert.Equal(t, expected, result)
 		})
 	}
 
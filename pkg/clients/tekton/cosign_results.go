@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	g "github.com/onsi/ginkgo/v2"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/attestation"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 )
 
@@ -21,3 +22,26 @@ func (t *TektonController) AwaitAttestationAndSignature(image string, timeout ti
 		return true, nil
 	})
 }
+
+// VerifyAttestationAndSignature verifies the signature and the attestation of the image locally
+// against the Tekton Chains public key.
+func (t *TektonController) VerifyAttestationAndSignature(image string) error {
+	publicKey, err := t.GetTektonChainsPublicKey()
+	if err != nil {
+		return err
+	}
+	if _, err := tekton.VerifyImageSignatures(image, publicKey); err != nil {
+		return err
+	}
+	_, err = tekton.VerifyImageAttestations(image, publicKey)
+	return err
+}
+
+// GetImageProvenance returns the SLSA provenance of the image attested by Tekton Chains.
+func (t *TektonController) GetImageProvenance(image string) (*attestation.Provenance, error) {
+	publicKey, err := t.GetTektonChainsPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return attestation.GetProvenance(image, publicKey)
+}
@@ -0,0 +1,300 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"knative.dev/pkg/apis"
+)
+
+const pipelineRunLabel = "tekton.dev/pipelineRun"
+
+const (
+	taskRunPending   = "pending"
+	taskRunRunning   = "running"
+	taskRunSucceeded = "succeeded"
+	taskRunFailed    = "failed"
+)
+
+// PipelineRunCondition decides whether waiting for a PipelineRun is over. Returning an error stops the wait.
+type PipelineRunCondition func(pr *pipeline.PipelineRun) (bool, error)
+
+// PipelineRunFinished is satisfied once the PipelineRun finishes, regardless of its result.
+func PipelineRunFinished(pr *pipeline.PipelineRun) (bool, error) {
+	return pr.Status.CompletionTime != nil, nil
+}
+
+// PipelineRunSucceeded is satisfied once the PipelineRun succeeds. It returns an error if the PipelineRun fails.
+func PipelineRunSucceeded(pr *pipeline.PipelineRun) (bool, error) {
+	condition := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if condition == nil || condition.IsUnknown() {
+		return false, nil
+	}
+	if condition.IsFalse() {
+		return false, fmt.Errorf("PipelineRun %s/%s failed: %s: %s", pr.Namespace, pr.Name, condition.Reason, condition.Message)
+	}
+	return true, nil
+}
+
+// PipelineRunWaitOptions selects the PipelineRun to wait for. Either Name or LabelSelector has to be set.
+type PipelineRunWaitOptions struct {
+	Name string
+	// LabelSelector, e.g. "appstudio.openshift.io/component=my-component". If more PipelineRuns match,
+	// the first one satisfying the condition is returned.
+	LabelSelector string
+	Timeout       time.Duration
+	// Condition defaults to PipelineRunFinished
+	Condition PipelineRunCondition
+	// Out receives TaskRun progress, defaults to GinkgoWriter
+	Out io.Writer
+}
+
+// PipelineRunTimeoutError is returned when the PipelineRun doesn't satisfy the condition in time.
+// It lists pipeline tasks which were still pending or running at that moment.
+type PipelineRunTimeoutError struct {
+	Namespace string
+	// Name is empty if no PipelineRun matching the label selector was found
+	Name          string
+	LabelSelector string
+	Timeout       time.Duration
+	Running       []string
+	Pending       []string
+}
+
+func (e *PipelineRunTimeoutError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("timed out after %s waiting for a PipelineRun matching %q in namespace %s: no such PipelineRun was found", e.Timeout, e.LabelSelector, e.Namespace)
+	}
+	msg := fmt.Sprintf("timed out after %s waiting for PipelineRun %s/%s", e.Timeout, e.Namespace, e.Name)
+	if len(e.Running) > 0 {
+		msg += fmt.Sprintf("; tasks still running: %s", strings.Join(e.Running, ", "))
+	}
+	if len(e.Pending) > 0 {
+		msg += fmt.Sprintf("; tasks still pending: %s", strings.Join(e.Pending, ", "))
+	}
+	return msg
+}
+
+// WaitForPipelineRun waits until the PipelineRun selected by opts satisfies the condition and returns its final state.
+// See the package level WaitForPipelineRun for details.
+func (t *TektonController) WaitForPipelineRun(ctx context.Context, namespace string, opts PipelineRunWaitOptions) (*pipeline.PipelineRun, error) {
+	return WaitForPipelineRun(ctx, t.PipelineClient(), namespace, opts)
+}
+
+// WaitForPipelineRun waits until the PipelineRun selected by opts satisfies the condition and returns its final state.
+// The PipelineRun is observed through an informer, so interrupted watches are resumed from the last seen resourceVersion.
+// TaskRun state changes (started, succeeded, failed, retried) are written to opts.Out while waiting.
+func WaitForPipelineRun(ctx context.Context, client versioned.Interface, namespace string, opts PipelineRunWaitOptions) (*pipeline.PipelineRun, error) {
+	if opts.Name == "" && opts.LabelSelector == "" {
+		return nil, fmt.Errorf("either name or label selector of the PipelineRun has to be set")
+	}
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", opts.LabelSelector, err)
+	}
+	if opts.Condition == nil {
+		opts.Condition = PipelineRunFinished
+	}
+	if opts.Out == nil {
+		opts.Out = g.GinkgoWriter
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// TaskRuns inherit labels of their PipelineRun, so the same selector can be used for watching them
+	taskRunSelector := opts.LabelSelector
+	if opts.Name != "" {
+		taskRunSelector = strings.Trim(strings.Join([]string{opts.LabelSelector, pipelineRunLabel + "=" + opts.Name}, ","), ",")
+	}
+	progress := newTaskRunProgress(opts.Out)
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		progress.watch(progressCtx, client, namespace, taskRunSelector)
+	}()
+	defer wg.Wait()
+	defer stopProgress()
+
+	prs := client.TektonV1().PipelineRuns(namespace)
+	lw := newListWatch(ctx, opts.Name, opts.LabelSelector,
+		func(ctx context.Context, o metav1.ListOptions) (runtime.Object, error) { return prs.List(ctx, o) },
+		prs.Watch)
+
+	var last *pipeline.PipelineRun
+	_, err = watchtools.UntilWithSync(ctx, lw, &pipeline.PipelineRun{}, nil, func(event watch.Event) (bool, error) {
+		pr, ok := event.Object.(*pipeline.PipelineRun)
+		if !ok || (opts.Name != "" && pr.Name != opts.Name) || !selector.Matches(labels.Set(pr.Labels)) {
+			return false, nil
+		}
+		if event.Type == watch.Deleted {
+			return false, fmt.Errorf("PipelineRun %s/%s was deleted while waiting for it", pr.Namespace, pr.Name)
+		}
+		last = pr.DeepCopy()
+		return opts.Condition(pr)
+	})
+	if err != nil && ctx.Err() != nil {
+		timeoutErr := &PipelineRunTimeoutError{Namespace: namespace, Name: opts.Name, LabelSelector: opts.LabelSelector, Timeout: opts.Timeout}
+		if last != nil {
+			timeoutErr.Name = last.Name
+			timeoutErr.Running, timeoutErr.Pending = progress.unfinishedTasks(last)
+		}
+		return last, timeoutErr
+	}
+	return last, err
+}
+
+// newListWatch returns a ListerWatcher limited to objects with the given name and labels.
+func newListWatch(ctx context.Context, name, labelSelector string, list func(context.Context, metav1.ListOptions) (runtime.Object, error), watchFunc func(context.Context, metav1.ListOptions) (watch.Interface, error)) cache.ListerWatcher {
+	filter := func(o *metav1.ListOptions) {
+		o.LabelSelector = labelSelector
+		if name != "" {
+			o.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}
+	}
+	return &cache.ListWatch{
+		ListFunc: func(o metav1.ListOptions) (runtime.Object, error) {
+			filter(&o)
+			return list(ctx, o)
+		},
+		WatchFunc: func(o metav1.ListOptions) (watch.Interface, error) {
+			filter(&o)
+			return watchFunc(ctx, o)
+		},
+	}
+}
+
+type taskRunState struct {
+	pipelineRun  string
+	pipelineTask string
+	state        string
+	retries      int
+	startTime    *metav1.Time
+}
+
+// taskRunProgress reports TaskRun state changes of the watched PipelineRun(s)
+type taskRunProgress struct {
+	out io.Writer
+
+	mu       sync.Mutex
+	taskRuns map[string]*taskRunState
+}
+
+func newTaskRunProgress(out io.Writer) *taskRunProgress {
+	return &taskRunProgress{out: out, taskRuns: map[string]*taskRunState{}}
+}
+
+func (p *taskRunProgress) watch(ctx context.Context, client versioned.Interface, namespace, labelSelector string) {
+	trs := client.TektonV1().TaskRuns(namespace)
+	lw := newListWatch(ctx, "", labelSelector,
+		func(ctx context.Context, o metav1.ListOptions) (runtime.Object, error) { return trs.List(ctx, o) },
+		trs.Watch)
+	// the condition never succeeds, the watch runs until the context is cancelled
+	_, _ = watchtools.UntilWithSync(ctx, lw, &pipeline.TaskRun{}, nil, func(event watch.Event) (bool, error) {
+		if tr, ok := event.Object.(*pipeline.TaskRun); ok && event.Type != watch.Deleted {
+			p.update(tr)
+		}
+		return false, nil
+	})
+}
+
+func (p *taskRunProgress) update(tr *pipeline.TaskRun) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := &taskRunState{
+		pipelineRun:  tr.Labels[pipelineRunLabel],
+		pipelineTask: tr.Labels["tekton.dev/pipelineTask"],
+		state:        getTaskRunState(tr),
+		retries:      len(tr.Status.RetriesStatus),
+		startTime:    tr.Status.StartTime,
+	}
+	previous, seen := p.taskRuns[tr.Name]
+	p.taskRuns[tr.Name] = current
+	if !seen {
+		previous = &taskRunState{state: taskRunPending}
+	}
+
+	prefix := fmt.Sprintf("[%s] TaskRun %s (task %q)", current.pipelineRun, tr.Name, current.pipelineTask)
+	if current.retries > previous.retries {
+		fmt.Fprintf(p.out, "%s failed, retrying (retry %d of %d)\n", prefix, current.retries, tr.Spec.Retries)
+	}
+	if current.state == previous.state {
+		return
+	}
+	switch current.state {
+	case taskRunRunning:
+		fmt.Fprintf(p.out, "%s started\n", prefix)
+	case taskRunSucceeded:
+		fmt.Fprintf(p.out, "%s succeeded after %s\n", prefix, taskRunDuration(tr))
+	case taskRunFailed:
+		condition := tr.Status.GetCondition(apis.ConditionSucceeded)
+		fmt.Fprintf(p.out, "%s failed after %s: %s: %s\n", prefix, taskRunDuration(tr), condition.Reason, condition.Message)
+	}
+}
+
+// unfinishedTasks returns pipeline tasks of the PipelineRun which are running and which haven't started yet.
+func (p *taskRunProgress) unfinishedTasks(pr *pipeline.PipelineRun) (running, pending []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	started := map[string]bool{}
+	for name, tr := range p.taskRuns {
+		if tr.pipelineRun != pr.Name || tr.state == taskRunPending {
+			continue
+		}
+		started[tr.pipelineTask] = true
+		if tr.state == taskRunRunning {
+			running = append(running, fmt.Sprintf("%s (TaskRun %s, running for %s)", tr.pipelineTask, name, time.Since(tr.startTime.Time).Round(time.Second)))
+		}
+	}
+	if pr.Status.PipelineSpec != nil {
+		for _, task := range append(pr.Status.PipelineSpec.Tasks, pr.Status.PipelineSpec.Finally...) {
+			if !started[task.Name] {
+				pending = append(pending, task.Name)
+			}
+		}
+	}
+	sort.Strings(running)
+	return running, pending
+}
+
+func getTaskRunState(tr *pipeline.TaskRun) string {
+	condition := tr.Status.GetCondition(apis.ConditionSucceeded)
+	switch {
+	case tr.Status.StartTime == nil:
+		return taskRunPending
+	case condition == nil || condition.IsUnknown():
+		return taskRunRunning
+	case condition.IsTrue():
+		return taskRunSucceeded
+	default:
+		return taskRunFailed
+	}
+}
+
+func taskRunDuration(tr *pipeline.TaskRun) time.Duration {
+	if tr.Status.StartTime == nil || tr.Status.CompletionTime == nil {
+		return 0
+	}
+	return tr.Status.CompletionTime.Sub(tr.Status.StartTime.Time).Round(time.Second)
+}
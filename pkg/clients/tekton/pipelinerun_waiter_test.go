@@ -0,0 +1,109 @@
+package tekton
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func withSucceededCondition(status corev1.ConditionStatus, reason string) duckv1.Status {
+	return duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status, Reason: reason}}}
+}
+
+func newTestPipelineRun(name string, tasks ...string) *pipeline.PipelineRun {
+	spec := &pipeline.PipelineSpec{}
+	for _, task := range tasks {
+		spec.Tasks = append(spec.Tasks, pipeline.PipelineTask{Name: task})
+	}
+	return &pipeline.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{"app": "test"}},
+		Status: pipeline.PipelineRunStatus{
+			Status:                  withSucceededCondition(corev1.ConditionUnknown, "Running"),
+			PipelineRunStatusFields: pipeline.PipelineRunStatusFields{StartTime: &metav1.Time{Time: time.Now()}, PipelineSpec: spec},
+		},
+	}
+}
+
+func newTestTaskRun(pipelineRun, task string) *pipeline.TaskRun {
+	return &pipeline.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: pipelineRun + "-" + task, Namespace: "ns", Labels: map[string]string{
+			"app": "test", pipelineRunLabel: pipelineRun, "tekton.dev/pipelineTask": task,
+		}},
+		Status: pipeline.TaskRunStatus{
+			Status:              withSucceededCondition(corev1.ConditionUnknown, "Running"),
+			TaskRunStatusFields: pipeline.TaskRunStatusFields{StartTime: &metav1.Time{Time: time.Now()}},
+		},
+	}
+}
+
+func TestWaitForPipelineRunReportsTaskRunProgress(t *testing.T) {
+	pr := newTestPipelineRun("build", "clone", "build")
+	clone := newTestTaskRun("build", "clone")
+	client := fake.NewSimpleClientset(pr, clone)
+	out := &syncBuffer{}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		clone.Status.Status = withSucceededCondition(corev1.ConditionTrue, "Succeeded")
+		clone.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		_, _ = client.TektonV1().TaskRuns("ns").UpdateStatus(context.Background(), clone, metav1.UpdateOptions{})
+		time.Sleep(100 * time.Millisecond)
+		pr.Status.Status = withSucceededCondition(corev1.ConditionFalse, "Failed")
+		pr.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		_, _ = client.TektonV1().PipelineRuns("ns").UpdateStatus(context.Background(), pr, metav1.UpdateOptions{})
+	}()
+
+	final, err := WaitForPipelineRun(context.Background(), client, "ns", PipelineRunWaitOptions{LabelSelector: "app=test", Timeout: 10 * time.Second, Out: out})
+	assert.NoError(t, err)
+	assert.Equal(t, "build", final.Name)
+	assert.NotNil(t, final.Status.CompletionTime)
+	assert.Contains(t, out.String(), `TaskRun build-clone (task "clone") started`)
+	assert.Contains(t, out.String(), `TaskRun build-clone (task "clone") succeeded`)
+
+	_, err = WaitForPipelineRun(context.Background(), client, "ns", PipelineRunWaitOptions{Name: "build", Condition: PipelineRunSucceeded, Out: out})
+	assert.ErrorContains(t, err, "PipelineRun ns/build failed: Failed")
+}
+
+func TestWaitForPipelineRunTimeout(t *testing.T) {
+	client := fake.NewSimpleClientset(newTestPipelineRun("build", "clone", "build", "push"), newTestTaskRun("build", "clone"))
+
+	final, err := WaitForPipelineRun(context.Background(), client, "ns", PipelineRunWaitOptions{Name: "build", Timeout: 500 * time.Millisecond, Out: &syncBuffer{}})
+	assert.NotNil(t, final)
+	timeoutErr, ok := err.(*PipelineRunTimeoutError)
+	assert.True(t, ok, "expected PipelineRunTimeoutError, got %v", err)
+	assert.Len(t, timeoutErr.Running, 1)
+	assert.Contains(t, timeoutErr.Running[0], "clone (TaskRun build-clone")
+	assert.Equal(t, []string{"build", "push"}, timeoutErr.Pending)
+	assert.Contains(t, err.Error(), "timed out after 500ms waiting for PipelineRun ns/build")
+
+	_, err = WaitForPipelineRun(context.Background(), client, "ns", PipelineRunWaitOptions{LabelSelector: "app=missing", Timeout: 200 * time.Millisecond, Out: &syncBuffer{}})
+	assert.ErrorContains(t, err, "no such PipelineRun was found")
+}
@@ -35,7 +35,14 @@ func (t *TektonController) createAndWait(pr *pipeline.PipelineRun, namespace str
 		return nil, err
 	}
 	g.GinkgoWriter.Printf("Creating Pipeline %q\n", pipelineRun.Name)
-	return pipelineRun, utils.WaitUntil(t.CheckPipelineRunStarted(pipelineRun.Name, namespace), time.Duration(taskTimeout)*time.Second)
+	return pipelineRun, utils.NewPoller(fmt.Sprintf("PipelineRun %s to start in %s namespace", pipelineRun.Name, namespace), time.Duration(taskTimeout)*time.Second).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			pr, err := t.GetPipelineRun(pipelineRun.Name, namespace)
+			if err != nil {
+				return false, nil, err
+			}
+			return pr.Status.StartTime != nil, pr.Status, nil
+		})
 }
 
 // RunPipeline creates a pipelineRun and waits for it to start.
@@ -103,21 +110,27 @@ func (t *TektonController) GetPipelineRunLogs(pipelineRunName, namespace string)
 	return podLog, nil
 }
 
-// GetPipelineRunWatch returns pipelineRun watch interface.
+// GetPipelineRunWatch returns a watch of all pipelineRuns in the namespace. Use WaitForPipelineRun for waiting
+// for a particular pipelineRun.
 func (t *TektonController) GetPipelineRunWatch(ctx context.Context, namespace string) (watch.Interface, error) {
 	return t.PipelineClient().TektonV1().PipelineRuns(namespace).Watch(ctx, metav1.ListOptions{})
 }
 
-// WatchPipelineRun waits until pipelineRun finishes.
+// WatchPipelineRun waits until pipelineRun finishes, reporting progress of its TaskRuns to GinkgoWriter.
 func (t *TektonController) WatchPipelineRun(pipelineRunName, namespace string, taskTimeout int) error {
+	defer logs.TrackTiming("WatchPipelineRun")()
 	g.GinkgoWriter.Printf("Waiting for pipeline %q to finish\n", pipelineRunName)
-	return utils.WaitUntil(t.CheckPipelineRunFinished(pipelineRunName, namespace), time.Duration(taskTimeout)*time.Second)
+	_, err := t.WaitForPipelineRun(context.Background(), namespace, PipelineRunWaitOptions{Name: pipelineRunName, Timeout: time.Duration(taskTimeout) * time.Second})
+	return err
 }
 
-// WatchPipelineRunSucceeded waits until the pipelineRun succeeds.
+// WatchPipelineRunSucceeded waits until the pipelineRun succeeds, reporting progress of its TaskRuns to GinkgoWriter.
+// It returns an error as soon as the pipelineRun fails.
 func (t *TektonController) WatchPipelineRunSucceeded(pipelineRunName, namespace string, taskTimeout int) error {
+	defer logs.TrackTiming("WatchPipelineRunSucceeded")()
 	g.GinkgoWriter.Printf("Waiting for pipeline %q to finish\n", pipelineRunName)
-	return utils.WaitUntil(t.CheckPipelineRunSucceeded(pipelineRunName, namespace), time.Duration(taskTimeout)*time.Second)
+	_, err := t.WaitForPipelineRun(context.Background(), namespace, PipelineRunWaitOptions{Name: pipelineRunName, Timeout: time.Duration(taskTimeout) * time.Second, Condition: PipelineRunSucceeded})
+	return err
 }
 
 // CheckPipelineRunStarted checks if pipelineRUn started.
@@ -239,6 +252,13 @@ func (t *TektonController) StorePipelineRun(pipelineRun *pipeline.PipelineRun) e
 	}
 	artifacts["pipelineRun-"+pipelineRun.Name+".yaml"] = pipelineRunYaml
 
+	if tekton.HasPipelineRunFailed(pipelineRun) {
+		// the log and YAML of a failed PipelineRun are stored even if it can't be diagnosed
+		if err := t.addDiagnosisArtifacts(pipelineRun, artifacts); err != nil {
+			g.GinkgoWriter.Printf("failed to diagnose PipelineRun %s/%s: %+v\n", pipelineRun.Namespace, pipelineRun.Name, err)
+		}
+	}
+
 	if err := logs.StoreArtifacts(artifacts); err != nil {
 		return err
 	}
@@ -246,6 +266,44 @@ func (t *TektonController) StorePipelineRun(pipelineRun *pipeline.PipelineRun) e
 	return nil
 }
 
+func (t *TektonController) addDiagnosisArtifacts(pipelineRun *pipeline.PipelineRun, artifacts map[string][]byte) error {
+	diagnosis, err := tekton.DiagnosePipelineRun(t.KubeRest(), t.KubeInterface(), pipelineRun, tekton.DiagnoseOptions{})
+	if err != nil {
+		return err
+	}
+	diagnosisJSON, err := diagnosis.JSON()
+	if err != nil {
+		return err
+	}
+	artifacts["pipelineRun-"+pipelineRun.Name+"-diagnosis.txt"] = []byte(diagnosis.String())
+	artifacts["pipelineRun-"+pipelineRun.Name+"-diagnosis.json"] = diagnosisJSON
+	return nil
+}
+
+// GetPipelineRunTrace returns a timeline of the PipelineRun covering queueing, pod scheduling, volume binding,
+// init containers and steps of its TaskRuns.
+func (t *TektonController) GetPipelineRunTrace(pipelineRun *pipeline.PipelineRun) (*tekton.PipelineRunTrace, error) {
+	return tekton.CollectPipelineRunTrace(t.KubeRest(), t.KubeInterface(), pipelineRun)
+}
+
+// StorePipelineRunTrace stores the PipelineRun timeline as an artifact, both in Chrome Trace Event format
+// (to be opened in chrome://tracing or Perfetto) and as a text Gantt chart.
+func (t *TektonController) StorePipelineRunTrace(pipelineRun *pipeline.PipelineRun) (*tekton.PipelineRunTrace, error) {
+	trace, err := t.GetPipelineRunTrace(pipelineRun)
+	if err != nil {
+		return nil, err
+	}
+	chromeTrace, err := tekton.ChromeTrace(trace)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := map[string][]byte{
+		"pipelineRun-" + pipelineRun.Name + "-trace.json": chromeTrace,
+		"pipelineRun-" + pipelineRun.Name + "-gantt.txt":  []byte(trace.Gantt(100)),
+	}
+	return trace, logs.StoreArtifacts(artifacts)
+}
+
 // StoreAllPipelineRuns stores all PipelineRuns in a given namespace.
 func (t *TektonController) StoreAllPipelineRuns(namespace string) error {
 	pipelineRuns, err := t.ListAllPipelineRuns(namespace)
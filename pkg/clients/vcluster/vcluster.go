@@ -9,9 +9,11 @@ import (
 
 	routev1 "github.com/openshift/api/route/v1"
 	client "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/clientcmd"
@@ -50,6 +52,11 @@ func (c *vclusterFactory) InitializeVCluster(clusterName string, targetNamespace
 		return "", err
 	}
 
+	// vcluster creates the namespace if it doesn't exist, mark it for the stale cluster resource reaper
+	if err := c.labelNamespace(targetNamespace); err != nil {
+		return "", err
+	}
+
 	if err := c.CreateKubeconfig(clusterName, targetNamespace, kubeconfigPath); err != nil {
 		return "", err
 	}
@@ -74,6 +81,14 @@ func (c *vclusterFactory) InitializeVCluster(clusterName string, targetNamespace
 	return kubeconfigPath, nil
 }
 
+func (c *vclusterFactory) labelNamespace(namespace string) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:%q}}}`, constants.E2ETestLabelKey, constants.E2ETestLabelValue))
+	if _, err := c.KubeClient.KubeInterface().CoreV1().Namespaces().Patch(context.Background(), namespace, types.MergePatchType, patch, v1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to label vcluster namespace %s: %v", namespace, err)
+	}
+	return nil
+}
+
 func (vc *vclusterFactory) CreateRoute(serviceName string, namespace string) (route *routev1.Route, err error) {
 	routeSpec := routev1.Route{
 		ObjectMeta: v1.ObjectMeta{
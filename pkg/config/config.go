@@ -0,0 +1,230 @@
+// Package config declares settings of the e2e tests, which are read from environment variables,
+// and validates that settings required by the selected test suites are set before any test runs.
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// Type is the type of a setting value
+type Type string
+
+const (
+	String   Type = "string"
+	Bool     Type = "bool"
+	Int      Type = "int"
+	Duration Type = "duration"
+)
+
+// Labels are Ginkgo labels of specs
+type Labels []string
+
+// Setting is a configuration option of the e2e tests read from the env var of the same name
+type Setting struct {
+	Name        string
+	Type        Type
+	Default     string
+	Secret      bool
+	Description string
+	// Required is true if the setting is needed by all test suites
+	Required bool
+	// RequiredBy lists labels of specs which fail without the setting. The setting is required
+	// if the label filter of the test run selects specs with any of the labels.
+	RequiredBy []Labels
+}
+
+// Config holds effective values of the settings
+type Config struct {
+	values map[string]string
+}
+
+// Lookup returns the declared setting with the name
+func Lookup(name string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Get returns the value of the setting with the name from env, or its default value if it's not set
+func Get(name string) string {
+	s, ok := Lookup(name)
+	if !ok {
+		panic(fmt.Sprintf("setting %s is not declared in pkg/config", name))
+	}
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return s.Default
+}
+
+// Load reads all settings from env and validates their values. Settings required by specs
+// selected by the label filter have to be set.
+func Load(labelFilter string) (*Config, error) {
+	required, err := RequiredSettings(labelFilter)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{values: map[string]string{}}
+	invalid, missing := []string{}, []string{}
+	for _, s := range Settings {
+		v := Get(s.Name)
+		c.values[s.Name] = v
+		if v == "" {
+			if _, ok := required[s.Name]; ok {
+				missing = append(missing, s.Name)
+			}
+			continue
+		}
+		if err := s.validate(v); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+	}
+	if len(missing) != 0 {
+		invalid = append(invalid, fmt.Sprintf("required env vars (%s) not defined or empty", strings.Join(missing, ",")))
+	}
+	if len(invalid) != 0 {
+		return c, fmt.Errorf("invalid configuration: %s", strings.Join(invalid, "; "))
+	}
+	return c, nil
+}
+
+// RequiredSettings returns names of the settings required by specs selected by the label filter.
+// Filters which select specs without labels, like the default one excluding just a few suites,
+// don't select specs by their labels, so they require only the settings needed by all suites.
+func RequiredSettings(labelFilter string) (map[string]struct{}, error) {
+	filter, err := types.ParseLabelFilter(labelFilter)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing label filter %q: %v", labelFilter, err)
+	}
+	selectsByLabels := !filter(Labels{})
+	required := map[string]struct{}{}
+	for _, s := range Settings {
+		if s.Required {
+			required[s.Name] = struct{}{}
+		}
+		for _, labels := range s.RequiredBy {
+			if selectsByLabels && filter(labels) {
+				required[s.Name] = struct{}{}
+			}
+		}
+	}
+	return required, nil
+}
+
+func (s Setting) validate(v string) error {
+	var err error
+	switch s.Type {
+	case Bool:
+		_, err = strconv.ParseBool(v)
+	case Int:
+		_, err = strconv.Atoi(v)
+	case Duration:
+		_, err = time.ParseDuration(v)
+	}
+	if err != nil {
+		return fmt.Errorf("%s=%q is not a valid %s", s.Name, s.mask(v), s.Type)
+	}
+	return nil
+}
+
+// mask hides values of secrets
+func (s Setting) mask(v string) string {
+	if s.Secret && v != "" {
+		return "*****"
+	}
+	return v
+}
+
+func (s Setting) requiredBy() string {
+	if s.Required {
+		return "all suites"
+	}
+	sets := []string{}
+	for _, labels := range s.RequiredBy {
+		sets = append(sets, strings.Join(labels, " && "))
+	}
+	return strings.Join(sets, ", ")
+}
+
+// String returns the value of the setting
+func (c *Config) String(name string) string {
+	if _, ok := Lookup(name); !ok {
+		panic(fmt.Sprintf("setting %s is not declared in pkg/config", name))
+	}
+	return c.values[name]
+}
+
+// Bool returns the value of the bool setting, false if it's not set
+func (c *Config) Bool(name string) bool {
+	v, _ := strconv.ParseBool(c.String(name))
+	return v
+}
+
+// Int returns the value of the int setting, 0 if it's not set
+func (c *Config) Int(name string) int {
+	v, _ := strconv.Atoi(c.String(name))
+	return v
+}
+
+// Duration returns the value of the duration setting, 0 if it's not set
+func (c *Config) Duration(name string) time.Duration {
+	v, _ := time.ParseDuration(c.String(name))
+	return v
+}
+
+// Print writes effective values of all settings with masked secrets, sorted by name
+func (c *Config) Print(w io.Writer) error {
+	settings := append([]Setting{}, Settings...)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tSOURCE")
+	for _, s := range settings {
+		source := "unset"
+		if os.Getenv(s.Name) != "" {
+			source = "env"
+		} else if s.Default != "" {
+			source = "default"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, s.mask(c.values[s.Name]), source)
+	}
+	return tw.Flush()
+}
+
+// Markdown renders documentation of all settings
+func Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Configuration\n\n")
+	b.WriteString("<!-- Generated by `mage config:docs` from pkg/config/settings.go, do not edit. -->\n\n")
+	b.WriteString("The tests are configured by environment variables. A setting is validated by `mage local:prepareCluster` and other targets running the tests, ")
+	b.WriteString("if it's required by specs selected by `E2E_TEST_SUITE_LABEL`. Filters excluding just some specs, like the default one, require only settings needed by all suites. ")
+	b.WriteString("Run `mage config:print` to show the effective configuration.\n\n")
+	b.WriteString("| Name | Type | Default | Secret | Required by | Description |\n|---|---|---|---|---|---|\n")
+	for _, s := range Settings {
+		secret := ""
+		if s.Secret {
+			secret = "yes"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n", s.Name, s.Type, codeOrEmpty(s.Default), secret, s.requiredBy(), s.Description)
+	}
+	return b.String()
+}
+
+func codeOrEmpty(v string) string {
+	if v == "" {
+		return ""
+	}
+	return "`" + v + "`"
+}
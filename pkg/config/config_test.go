@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setRequiredEnv sets settings required by all suites and unsets the others
+func setRequiredEnv(t *testing.T) {
+	for _, s := range Settings {
+		value := ""
+		if s.Required {
+			value = "value"
+		}
+		t.Setenv(s.Name, value)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	setRequiredEnv(t)
+	cfg, err := Load(DefaultLabelFilter)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultLabelFilter, cfg.String(E2ETestSuiteLabelEnv))
+	assert.Equal(t, "redhat-appstudio-qe", cfg.String("MY_GITHUB_ORG"))
+	assert.Equal(t, 6*60*60.0, cfg.Duration("IMAGE_TAG_EXPIRATION").Seconds())
+	assert.False(t, cfg.Bool("E2E_SKIP_CLEANUP"))
+
+	t.Setenv("E2E_SKIP_CLEANUP", "true")
+	t.Setenv("KLOG_VERBOSITY", "5")
+	cfg, err = Load(DefaultLabelFilter)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Bool("E2E_SKIP_CLEANUP"))
+	assert.Equal(t, 5, cfg.Int("KLOG_VERBOSITY"))
+
+	t.Setenv("KLOG_VERBOSITY", "high")
+	t.Setenv("GITHUB_TOKEN", "")
+	_, err = Load(DefaultLabelFilter)
+	assert.EqualError(t, err, `invalid configuration: KLOG_VERBOSITY="high" is not a valid int; required env vars (GITHUB_TOKEN) not defined or empty`)
+
+	_, err = Load("spi-suite &&")
+	assert.ErrorContains(t, err, "error when parsing label filter")
+}
+
+func TestRequiredSettings(t *testing.T) {
+	testCases := []struct {
+		labelFilter string
+		required    []string
+		notRequired []string
+	}{
+		// the default filter doesn't select suites by labels
+		{DefaultLabelFilter, []string{"GITHUB_TOKEN"}, []string{"BYOC_KUBECONFIG", "CYPRESS_GH_USER", "PYXIS_STAGE_KEY"}},
+		{"spi-suite", []string{"GITHUB_TOKEN", "CYPRESS_GH_USER", "QUAY_OAUTH_USER"}, []string{"BYOC_KUBECONFIG"}},
+		{"spi-suite && !gh-oauth-flow", []string{"QUAY_OAUTH_USER"}, []string{"CYPRESS_GH_USER"}},
+		{"e2e-demo,byoc", []string{"BYOC_KUBECONFIG"}, []string{"CYPRESS_GH_USER"}},
+		{"release-pipelines && fbc-tests", []string{"OFFLINE_TOKEN"}, []string{"PYXIS_STAGE_KEY"}},
+	}
+
+	for _, tc := range testCases {
+		required, err := RequiredSettings(tc.labelFilter)
+		assert.NoError(t, err)
+		for _, name := range tc.required {
+			assert.Contains(t, required, name, tc.labelFilter)
+		}
+		for _, name := range tc.notRequired {
+			assert.NotContains(t, required, name, tc.labelFilter)
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	t.Setenv("MY_GITHUB_ORG", "my-org")
+	cfg, err := Load(DefaultLabelFilter)
+	assert.NoError(t, err)
+
+	var out strings.Builder
+	assert.NoError(t, cfg.Print(&out))
+	assert.NotContains(t, out.String(), "ghp_secret")
+	assert.Regexp(t, `GITHUB_TOKEN +\*\*\*\*\* +env`, out.String())
+	assert.Regexp(t, `MY_GITHUB_ORG +my-org +env`, out.String())
+	assert.Regexp(t, `QUAY_E2E_ORGANIZATION +redhat-appstudio-qe +default`, out.String())
+	assert.Regexp(t, `BYOC_KUBECONFIG +unset`, out.String())
+}
+
+func TestSettingsDocs(t *testing.T) {
+	names := map[string]bool{}
+	for _, s := range Settings {
+		assert.False(t, names[s.Name], "%s is declared more than once", s.Name)
+		names[s.Name] = true
+		assert.NotEmpty(t, s.Description, s.Name)
+		if s.Default != "" {
+			assert.NoError(t, s.validate(s.Default), s.Name)
+		}
+	}
+
+	docs, err := os.ReadFile("../../docs/Configuration.md")
+	assert.NoError(t, err)
+	assert.Equal(t, Markdown(), string(docs), "docs/Configuration.md is outdated, run 'mage config:docs'")
+}
@@ -0,0 +1,139 @@
+package config
+
+import "github.com/redhat-appstudio/e2e-tests/pkg/constants"
+
+const (
+	// E2ETestSuiteLabelEnv is the Ginkgo label filter selecting specs to run
+	E2ETestSuiteLabelEnv = "E2E_TEST_SUITE_LABEL"
+	// DefaultLabelFilter selects all specs except upgrade and release pipelines tests, which need a dedicated setup
+	DefaultLabelFilter = "!upgrade-create && !upgrade-verify && !upgrade-cleanup && !release-pipelines"
+	// TestImpactBaseRefEnv is the git ref which local changes are compared to when selecting suites affected by them
+	TestImpactBaseRefEnv = "E2E_TEST_IMPACT_BASE_REF"
+	// JanitorPolicyEnv is the path of a policy file replacing the default policy of the janitor
+	JanitorPolicyEnv = "JANITOR_POLICY"
+	// JanitorDryRunEnv makes the janitor only report resources it would delete
+	JanitorDryRunEnv = "JANITOR_DRY_RUN"
+	// JanitorRulesEnv selects rules of the janitor policy to run
+	JanitorRulesEnv = "JANITOR_RULES"
+	// ReaperTTLEnv is the age of labelled cluster resources deleted by the reaper
+	ReaperTTLEnv = "REAPER_TTL"
+	// ReaperDryRunEnv makes the reaper only report resources it would delete
+	ReaperDryRunEnv = "REAPER_DRY_RUN"
+	// ReaperFinalizerTimeoutEnv is how long the reaper waits before removing finalizers of deleted resources
+	ReaperFinalizerTimeoutEnv = "REAPER_FINALIZER_TIMEOUT"
+)
+
+// Settings declares all settings of the e2e tests. docs/Configuration.md is generated from them by `mage config:docs`.
+var Settings = []Setting{
+	// test run
+	{Name: E2ETestSuiteLabelEnv, Type: String, Default: DefaultLabelFilter, Description: "Ginkgo label filter selecting specs to run"},
+	{Name: TestImpactBaseRefEnv, Type: String, Default: "main", Description: "Git ref which changes of the current branch are compared to by `mage local:testImpact`, CI jobs compare changes of the tested PR to its base commit"},
+	{Name: "PAIRING_MANIFEST", Type: String, Description: "Pairing manifest file listing PRs tested together with the tested PR, see [OpenShiftCI.md](OpenShiftCI.md#pairing-prs-across-repositories)"},
+	{Name: "E2E_SKIP_CLEANUP", Type: Bool, Description: "Keep resources created by the tests for debugging"},
+	{Name: "KLOG_VERBOSITY", Type: Int, Description: "Verbosity of klog logs"},
+	{Name: "CI", Type: Bool, Description: "Set to `true` in CI jobs, which bootstrap the cluster according to the job route"},
+	{Name: "ARTIFACT_DIR", Type: String, Description: "Directory for test reports and logs. CI artifacts are stored in the current directory by default, logs and reports of specs in `./tmp`"},
+	{Name: constants.SLACK_BOT_TOKEN_ENV, Type: String, Secret: true, Description: "Slack bot token for notifying about critical CI failures"},
+
+	// Github and Quay accounts
+	{Name: constants.GITHUB_TOKEN_ENV, Type: String, Secret: true, Required: true, Description: "Github token with permissions to the Github organization of the tests"},
+	{Name: constants.GITHUB_E2E_ORGANIZATION_ENV, Type: String, Default: "redhat-appstudio-qe", Description: "Github organization for repositories created by the tests"},
+	{Name: "QUAY_TOKEN", Type: String, Secret: true, Required: true, Description: "Quay.io docker config JSON used for pulling and pushing images"},
+	{Name: constants.DEFAULT_QUAY_ORG_ENV, Type: String, Default: constants.DefaultQuayOrg, Description: "Quay organization for repositories of component images"},
+	{Name: "DEFAULT_QUAY_ORG_TOKEN", Type: String, Secret: true, Required: true, Description: "Quay API token of `DEFAULT_QUAY_ORG`"},
+	{Name: constants.QUAY_E2E_ORGANIZATION_ENV, Type: String, Default: "redhat-appstudio-qe", Description: "Quay organization for images pushed by the pipelines"},
+	{Name: constants.QUAY_OAUTH_USER_ENV, Type: String, Description: "Quay.io username for uploading SPI tokens",
+		RequiredBy: []Labels{{"spi-suite", "quay-imagepullsecret-usage"}}},
+	{Name: constants.QUAY_OAUTH_TOKEN_ENV, Type: String, Secret: true, Description: "Quay.io token of `QUAY_OAUTH_USER`",
+		RequiredBy: []Labels{{"spi-suite", "quay-imagepullsecret-usage"}}},
+	{Name: constants.IMAGE_TAG_EXPIRATION_ENV, Type: Duration, Default: constants.DefaultImageTagExpiration, Description: "Expiration of image tags built by the tests"},
+
+	// cluster installation
+	{Name: "INFRA_DEPLOYMENTS_ORG", Type: String, Default: "redhat-appstudio", Description: "Github organization of the infra-deployments repository to install RHTAP from"},
+	{Name: "INFRA_DEPLOYMENTS_BRANCH", Type: String, Default: "main", Description: "Branch of the infra-deployments repository to install RHTAP from"},
+	{Name: "E2E_PAC_GITHUB_APP_ID", Type: String, Description: "ID of the Github app used by Pipelines as Code"},
+	{Name: "E2E_PAC_GITHUB_APP_PRIVATE_KEY", Type: String, Secret: true, Description: "Base64 encoded private key of `E2E_PAC_GITHUB_APP_ID`"},
+	{Name: "QE_SPRAYPROXY_HOST", Type: String, Description: "URL of the SprayProxy server forwarding Github webhooks to the cluster"},
+	{Name: "QE_SPRAYPROXY_TOKEN", Type: String, Secret: true, Description: "Token of `QE_SPRAYPROXY_HOST`"},
+	{Name: "QE_SPRAYPROXY_SKIP_TLS_VERIFY", Type: Bool, Default: "false", Description: "Skip verification of the SprayProxy server certificate, e.g. of a route with a self-signed certificate"},
+	{Name: "COMPONENT_IMAGE", Type: String, Description: "Image built from the tested PR of a service repository, set by CI"},
+	{Name: "CI_SPI_OAUTH_IMAGE", Type: String, Description: "SPI OAuth image built from the tested PR of the SPI repository, set by CI"},
+	{Name: "JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE", Type: String, Description: "Request processor image built from the tested PR of jvm-build-service, set by CI"},
+	{Name: "UPGRADE_BRANCH", Type: String, Description: "Branch of infra-deployments the cluster is upgraded to"},
+	{Name: "UPGRADE_FORK_ORGANIZATION", Type: String, Default: "redhat-appstudio", Description: "Github organization of infra-deployments the cluster is upgraded from"},
+
+	// test namespaces and users
+	{Name: constants.E2E_APPLICATIONS_NAMESPACE_ENV, Type: String, Description: "Existing namespace for tests which don't create their own one"},
+	{Name: constants.USER_KUBE_CONFIG_PATH_ENV, Type: String, Description: "Kubeconfig of the sandbox user, `./tmp/<user>.kubeconfig` by default"},
+	{Name: constants.WEBHOOK_RECEIVER_IP_ENV, Type: String, Description: "IP of the test process reachable from the cluster, for the test webhook receiver"},
+	{Name: "COMPONENT_REPO_URLS", Type: String, Default: "https://github.com/redhat-appstudio-qe/devfile-sample-python-basic.git", Description: "Comma separated Git repositories of components built by build templates tests"},
+	{Name: "APP_SUFFIX", Type: String, Description: "Suffix of application names in build templates tests"},
+
+	// build service
+	{Name: constants.EC_PIPELINES_REPO_URL_ENV, Type: String, Default: "https://github.com/redhat-appstudio/build-definitions", Description: "Git repository of the Enterprise Contract pipelines"},
+	{Name: constants.EC_PIPELINES_REPO_REVISION_ENV, Type: String, Default: "main", Description: "Revision of `EC_PIPELINES_REPO_URL`"},
+	{Name: constants.CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE_ENV, Type: String, Description: "Bundle overriding the default Java build pipeline"},
+	{Name: constants.CUSTOM_BUILDAH_REMOTE_PIPELINE_BUILD_BUNDLE_ENV, Type: String, Description: "Bundle of the buildah-remote build pipeline"},
+	{Name: "JVM_BUILD_SERVICE_TEST_REPO_URL", Type: String, Default: "https://github.com/redhat-appstudio-qe/hacbs-test-project", Description: "Git repository built by JVM build service tests"},
+	{Name: "JVM_BUILD_SERVICE_TEST_REPO_REVISION", Type: String, Default: "34da5a8f51fba6a8b7ec75a727d3c72ebb5e1274", Description: "Revision of `JVM_BUILD_SERVICE_TEST_REPO_URL`"},
+	{Name: "MULTI_PLATFORM_TEST_REPO_URL", Type: String, Default: "https://github.com/devfile-samples/devfile-sample-go-basic", Description: "Git repository built by multi platform tests"},
+	{Name: "MULTI_PLATFORM_TEST_REPO_REVISION", Type: String, Default: "c713067b0e65fb3de50d1f7c457eb51c2ab0dbb0", Description: "Revision of `MULTI_PLATFORM_TEST_REPO_URL`"},
+	{Name: "MULTI_PLATFORM_AWS_ACCESS_KEY", Type: String, Secret: true, Description: "AWS access key of the multi platform controller",
+		RequiredBy: []Labels{{"multi-platform"}}},
+	{Name: "MULTI_PLATFORM_AWS_SECRET_ACCESS_KEY", Type: String, Secret: true, Description: "AWS secret access key of the multi platform controller",
+		RequiredBy: []Labels{{"multi-platform"}}},
+	{Name: "MULTI_PLATFORM_AWS_SSH_KEY", Type: String, Secret: true, Description: "SSH key of AWS hosts of the multi platform controller",
+		RequiredBy: []Labels{{"multi-platform"}}},
+	{Name: constants.SKIP_PAC_TESTS_ENV, Type: Bool, Description: "Skip Pipelines as Code tests, set by CI if SprayProxy registration fails"},
+
+	// SPI
+	{Name: "OAUTH_REDIRECT_PROXY_URL", Type: String, Description: "Public URL of the SPI OAuth redirect proxy",
+		RequiredBy: []Labels{{"spi-suite", "gh-oauth-flow"}}},
+	{Name: "CYPRESS_GH_USER", Type: String, Description: "Github user logging in by Cypress in the SPI OAuth flow",
+		RequiredBy: []Labels{{"spi-suite", "gh-oauth-flow"}}},
+	{Name: "CYPRESS_GH_PASSWORD", Type: String, Secret: true, Description: "Password of `CYPRESS_GH_USER`",
+		RequiredBy: []Labels{{"spi-suite", "gh-oauth-flow"}}},
+	{Name: "CYPRESS_GH_2FA_CODE", Type: String, Secret: true, Description: "2FA code of `CYPRESS_GH_USER`",
+		RequiredBy: []Labels{{"spi-suite", "gh-oauth-flow"}}},
+
+	// BYOC
+	{Name: "BYOC_KUBECONFIG", Type: String, Description: "Kubeconfig of the OpenShift cluster used as a BYOC environment",
+		RequiredBy: []Labels{{"byoc"}}},
+
+	// release
+	{Name: "RELEASE_SERVICE_CATALOG_URL", Type: String, Default: "https://github.com/redhat-appstudio/release-service-catalog", Description: "Git repository of release pipelines"},
+	{Name: "RELEASE_SERVICE_CATALOG_REVISION", Type: String, Default: "staging", Description: "Revision of `RELEASE_SERVICE_CATALOG_URL`"},
+	{Name: constants.PYXIS_STAGE_KEY_ENV, Type: String, Secret: true, Description: "Key for accessing Pyxis stage",
+		RequiredBy: []Labels{{"release-pipelines", "pushPyxis"}, {"release-pipelines", "push-to-external-registry"}}},
+	{Name: constants.PYXIS_STAGE_CERT_ENV, Type: String, Secret: true, Description: "Certificate for accessing Pyxis stage",
+		RequiredBy: []Labels{{"release-pipelines", "pushPyxis"}, {"release-pipelines", "push-to-external-registry"}}},
+	{Name: constants.OFFLINE_TOKEN_ENV, Type: String, Secret: true, Description: "Offline token for getting a Keycloak token of the stage cluster",
+		RequiredBy: []Labels{{"release-pipelines", "fbc-tests"}}},
+	{Name: constants.KEYLOAK_URL_ENV, Type: String, Description: "Keycloak URL of the stage cluster",
+		RequiredBy: []Labels{{"release-pipelines", "fbc-tests"}}},
+	{Name: constants.TOOLCHAIN_API_URL_ENV, Type: String, Description: "Toolchain API URL of the stage cluster",
+		RequiredBy: []Labels{{"release-pipelines", "fbc-tests"}}},
+	{Name: constants.RELEASE_DEV_WORKSPACE_ENV, Type: String, Default: constants.DevReleaseTeam, Description: "Dev workspace of release pipelines tests"},
+	{Name: "IMAGE_CONTROLLER_QUAY_ORG", Type: String, Default: "hacbs-release-tests", Description: "Quay organization of image repositories created by the image controller in release tests"},
+	{Name: "IMAGE_CONTROLLER_QUAY_ORG_TOKEN", Type: String, Secret: true, Description: "Quay API token of `IMAGE_CONTROLLER_QUAY_ORG`"},
+	{Name: "REL_IMAGE_CONTROLLER_QUAY_ORG", Type: String, Description: "Overrides `IMAGE_CONTROLLER_QUAY_ORG` of the cluster installed for release-service PRs"},
+	{Name: "REL_IMAGE_CONTROLLER_QUAY_TOKEN", Type: String, Secret: true, Description: "Quay API token of `REL_IMAGE_CONTROLLER_QUAY_ORG`"},
+	{Name: constants.RELEASE_MANAGED_WORKSPACE_ENV, Type: String, Default: constants.ManagedReleaseTeam, Description: "Managed workspace of release pipelines tests"},
+
+	// cleanup
+	{Name: JanitorPolicyEnv, Type: String, Description: "Policy of `mage local:janitor`, the embedded `magefiles/janitor_policy.yaml` by default"},
+	{Name: JanitorDryRunEnv, Type: Bool, Default: "true", Description: "Only report resources `mage local:janitor` would delete"},
+	{Name: JanitorRulesEnv, Type: String, Description: "Comma separated rules of the janitor policy to run, all rules by default"},
+	{Name: ReaperTTLEnv, Type: Duration, Default: "24h", Description: "Age of cluster resources labelled by the tests which `mage local:reapStaleClusterResources` deletes"},
+	{Name: ReaperDryRunEnv, Type: Bool, Default: "true", Description: "Only report resources `mage local:reapStaleClusterResources` would delete"},
+	{Name: "SPRAYPROXY_REAP_DRY_RUN", Type: Bool, Default: "false", Description: "Only report PaC servers `mage cleanupRegisteredPacServers` would unregister from SprayProxy"},
+	{Name: "DRY_RUN", Type: Bool, Default: "true", Description: "Only report Github repositories `mage local:cleanupGithubOrg` would delete"},
+	{Name: "REPO_REGEX", Type: String, Description: "Regex of Github repositories `mage local:cleanupGithubOrg` deletes, replacing name patterns of the github-e2e-repositories janitor rule"},
+	{Name: ReaperFinalizerTimeoutEnv, Type: Duration, Default: "5m", Description: "Time to wait for deleted Environments and DeploymentTargetClaims to be gone before their finalizers are removed"},
+
+	// stage cluster
+	{Name: "STAGEUSER_TOKEN", Type: String, Secret: true, Description: "Offline token of the stage user", RequiredBy: []Labels{{"verify-stage"}}},
+	{Name: "STAGE_SSOURL", Type: String, Description: "Keycloak URL of the stage cluster", RequiredBy: []Labels{{"verify-stage"}}},
+	{Name: "STAGE_APIURL", Type: String, Description: "Toolchain API URL of the stage cluster", RequiredBy: []Labels{{"verify-stage"}}},
+	{Name: "STAGE_USERNAME", Type: String, Description: "Username of the stage user", RequiredBy: []Labels{{"verify-stage"}}},
+}
@@ -91,10 +91,17 @@ const (
 	// This variable is set by an automation in case Spray Proxy configuration fails in CI
 	SKIP_PAC_TESTS_ENV = "SKIP_PAC_TESTS"
 
+	// IP address of the test process reachable from the cluster (e.g. IP of the pod running the tests). Used for exposing the test webhook receiver in the cluster
+	WEBHOOK_RECEIVER_IP_ENV = "WEBHOOK_RECEIVER_IP"
+
 	// Test namespace's required labels
 	ArgoCDLabelKey   string = "argocd.argoproj.io/managed-by"
 	ArgoCDLabelValue string = "gitops-service-argocd"
 
+	// Label stamped on cluster resources created by the e2e framework, the stale cluster resource reaper deletes only these
+	E2ETestLabelKey   string = "appstudio.redhat.com/e2e-test"
+	E2ETestLabelValue string = "true"
+
 	BuildPipelinesConfigMapDefaultNamespace = "build-templates"
 
 	HostOperatorNamespace   string = "toolchain-host-operator"
@@ -0,0 +1,88 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
+)
+
+const (
+	// ClusterInventorySetup is the name of the suite setup recording capabilities of the cluster to ClusterInventoryFile
+	ClusterInventorySetup = "cluster-inventory"
+	// ClusterInventoryFile is the file in the artifact dir with capabilities of the cluster the suite ran against
+	ClusterInventoryFile = "cluster-inventory.json"
+	// PipelinesAsCodeCRD is the CRD installed with Pipelines as Code
+	PipelinesAsCodeCRD = "repositories.pipelinesascode.tekton.dev"
+)
+
+var (
+	capabilitiesMu sync.Mutex
+	capabilities   *kubeCl.Capabilities
+)
+
+func init() {
+	RegisterSuiteSetup(ClusterInventorySetup, recordClusterInventory)
+}
+
+// ClusterCapabilities returns capabilities of the cluster, they are discovered once per parallel process
+func ClusterCapabilities() (*kubeCl.Capabilities, error) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	if capabilities != nil {
+		return capabilities, nil
+	}
+	client, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	caps, err := client.DiscoverCapabilities(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	capabilities = caps
+	return capabilities, nil
+}
+
+// recordClusterInventory stores capabilities of the cluster in the artifact dir, so the run can be reproduced
+// against a cluster with the same components
+func recordClusterInventory() error {
+	caps, err := ClusterCapabilities()
+	if err != nil {
+		return fmt.Errorf("failed to discover capabilities of the cluster: %v", err)
+	}
+	return writeClusterInventory(caps, ci.Detect().ArtifactDir())
+}
+
+func writeClusterInventory(caps *kubeCl.Capabilities, dir string) error {
+	data, err := json.MarshalIndent(caps, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster inventory: %v", err)
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create artifact dir %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ClusterInventoryFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cluster inventory: %v", err)
+	}
+	return nil
+}
+
+// SkipUnlessPipelinesAsCode skips the current spec (or all specs of the Ordered container, if called from BeforeAll)
+// unless Pipelines as Code is installed and CI could register the cluster to SprayProxy (see SKIP_PAC_TESTS).
+func SkipUnlessPipelinesAsCode() {
+	if config.Get(constants.SKIP_PAC_TESTS_ENV) == "true" {
+		Skip("Skipping this test due to configuration issue with Spray proxy")
+	}
+	SkipUnlessPrerequisites(suites.CRD(PipelinesAsCodeCRD))
+}
@@ -0,0 +1,29 @@
+package framework
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+)
+
+func TestWriteClusterInventory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	caps := &kubeCl.Capabilities{
+		KubernetesVersion: "v1.27.6+f67aeb3",
+		OpenShiftVersion:  "4.14.1",
+		CRDs:              []string{PipelinesAsCodeCRD},
+		Operators:         []kubeCl.Operator{{Component: "build-service", Namespace: "build-service", Name: "build-service-controller-manager", Ready: true, Images: []string{"quay.io/redhat-appstudio/build-service@sha256:1234"}}},
+	}
+	assert.NoError(t, writeClusterInventory(caps, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, ClusterInventoryFile))
+	assert.NoError(t, err)
+	recorded := &kubeCl.Capabilities{}
+	assert.NoError(t, json.Unmarshal(data, recorded))
+	assert.Equal(t, caps, recorded)
+}
@@ -39,7 +39,6 @@ import (
 	. "github.com/onsi/ginkgo/v2/reporters"
 	types "github.com/onsi/ginkgo/v2/types"
 	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"k8s.io/klog/v2"
 )
 
@@ -98,6 +97,8 @@ type CustomJUnitTestCase struct {
 	Classname string `xml:"classname,attr"`
 	// Time is the time in seconds to execute the spec - maps onto SpecReport.RunTime
 	Time float64 `xml:"time,attr"`
+	// Properties captures the duration (in seconds) of each By step and framework wait helper of the spec
+	Properties *JUnitProperties `xml:"properties,omitempty"`
 	//Skipped is populated with a message if the test was skipped or pending
 	Skipped *JUnitSkipped `xml:"skipped,omitempty"`
 	//Error is populated if the test panicked or was interrupted
@@ -152,6 +153,9 @@ func GenerateCustomJUnitReportWithConfig(report types.Report, dst string, config
 			Classname: logs.GetClassnameFromReport(spec),
 			Time:      spec.RunTime.Seconds(),
 		}
+		if timings := logs.GetStepTimings(spec); len(timings) > 0 {
+			test.Properties = stepTimingProperties(timings)
+		}
 		if !spec.State.Is(config.OmitTimelinesForSpecState) {
 			test.SystemErr = systemErrForUnstructuredReporters(spec)
 		}
@@ -242,8 +246,7 @@ func GenerateRPPreprocReport(report types.Report, rpPreprocParentDir string) {
 		}
 	}
 
-	wd, _ := os.Getwd()
-	artifactDir := utils.GetEnv("ARTIFACT_DIR", fmt.Sprintf("%s/tmp", wd))
+	artifactDir := logs.ArtifactDir()
 
 	// Generate folder structure for RPPreproc with logs
 	for i := range report.SpecReports {
@@ -297,6 +300,18 @@ func GenerateRPPreprocReport(report types.Report, rpPreprocParentDir string) {
 	}
 }
 
+// stepTimingProperties converts step timings of a spec to JUnit properties
+func stepTimingProperties(timings []logs.StepTiming) *JUnitProperties {
+	properties := &JUnitProperties{}
+	for i, t := range timings {
+		properties.Properties = append(properties.Properties, JUnitProperty{
+			Name:  fmt.Sprintf("step-%03d [%s] %s", i+1, t.Kind, t.Name),
+			Value: fmt.Sprintf("%.3f", t.Duration.Seconds()),
+		})
+	}
+	return properties
+}
+
 func writeLogInFile(filePath string, log string) {
 	// Do not create empty files
 	if len(log) != 0 {
@@ -2,58 +2,64 @@ package framework
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
 )
 
+// Suites and their prerequisites are declared in pkg/suites, a new suite needs a Describe function below
+// and a declaration there.
+
 // ByocSuiteDescribe annotates the byoc scenarios.
 func ByocSuiteDescribe(args ...interface{}) bool {
-	return Describe("[byoc-suite]", args)
+	return Describe(suites.Byoc.DescribeText(""), args)
 }
 
 // CommonSuiteDescribe annotates the common tests with the application label.
 func CommonSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[common-suite "+text+"]", args, Ordered)
+	return Describe(suites.Common.DescribeText(text), args, Ordered)
 }
 
 func BuildSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[build-service-suite "+text+"]", args)
+	return Describe(suites.Build.DescribeText(text), args)
 }
 
 func JVMBuildSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[jvm-build-service-suite "+text+"]", args, Ordered)
+	return Describe(suites.JVMBuild.DescribeText(text), args, Ordered)
 }
 
 func MultiPlatformBuildSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[multi-platform-build-service-suite "+text+"]", args, Ordered)
+	return Describe(suites.MultiPlatform.DescribeText(text), args, Ordered)
 }
 
 func IntegrationServiceSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[integration-service-suite "+text+"]", args, Ordered)
+	return Describe(suites.IntegrationService.DescribeText(text), args, Ordered)
 }
 
 func RhtapDemoSuiteDescribe(args ...interface{}) bool {
-	return Describe("[rhtap-demo-suite]", args)
+	return Describe(suites.RhtapDemo.DescribeText(""), args)
 }
 
 func SPISuiteDescribe(args ...interface{}) bool {
-	return Describe("[spi-suite]", args, Ordered)
+	return Describe(suites.SPI.DescribeText(""), args, Ordered)
 }
 
 func RemoteSecretSuiteDescribe(args ...interface{}) bool {
-	return Describe("[remotesecret-suite]", args, Ordered)
+	return Describe(suites.RemoteSecret.DescribeText(""), args, Ordered)
 }
 
 func EnterpriseContractSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[enterprise-contract-suite "+text+"]", args, Ordered)
+	return Describe(suites.EnterpriseContract.DescribeText(text), args, Ordered)
 }
 
+// UpgradeSuiteDescribe annotates the upgrade tests. They upgrade the whole cluster, so they never run in parallel with other specs.
 func UpgradeSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[upgrade-suite "+text+"]", args, Ordered)
+	return Describe(suites.Upgrade.DescribeText(text), args, Ordered, SerialOnly)
 }
 
 func ReleasePipelinesSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[release-pipelines-suite "+text+"]", args, Ordered)
+	return Describe(suites.ReleasePipelines.DescribeText(text), args, Ordered)
 }
 
 func ReleaseServiceSuiteDescribe(text string, args ...interface{}) bool {
-	return Describe("[release-service-suite "+text+"]", args, Ordered)
+	return Describe(suites.ReleaseService.DescribeText(text), args, Ordered)
 }
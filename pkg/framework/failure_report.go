@@ -5,17 +5,16 @@ import (
 	"strings"
 	"time"
 
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
 
 	. "github.com/onsi/ginkgo/v2"
 )
 
+// ReportFailure stores logs of the components' pods if the current spec failed. Components which are not installed
+// in the cluster are skipped.
 func ReportFailure(f **Framework) func() {
-	namespaces := map[string]string{
-		"Build Service":       "build-service",
-		"JVM Build Service":   "jvm-build-service",
-		"Application Service": "application-service",
-		"Image Controller":    "image-controller"}
+	components := []string{"build-service", "jvm-build-service", "application-service", "image-controller"}
 
 	return func() {
 		if !CurrentSpecReport().Failed() {
@@ -31,12 +30,21 @@ func ReportFailure(f **Framework) func() {
 			GinkgoWriter.Printf("failed to store test timing: %v\n", err)
 		}
 
+		caps, err := ClusterCapabilities()
+		if err != nil {
+			GinkgoWriter.Printf("failed to discover capabilities of the cluster, storing logs of all components: %v\n", err)
+		}
+
 		allPodLogs := make(map[string][]byte)
-		for _, namespace := range namespaces {
+		for _, component := range components {
+			if caps != nil && !caps.IsInstalled(component) {
+				continue
+			}
+			namespace := kubeCl.ComponentNamespaces[component]
 			podList, err := fwk.AsKubeAdmin.CommonController.ListAllPods(namespace)
 			if err != nil {
 				GinkgoWriter.Printf("failed to list pods in namespace %s: %v\n", namespace, err)
-				return
+				continue
 			}
 
 			for _, pod := range podList.Items {
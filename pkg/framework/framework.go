@@ -94,7 +94,10 @@ func NewFrameworkWithTimeout(userName string, timeout time.Duration, options ...
 		if err != nil {
 			return nil, fmt.Errorf("error when initializing appstudio hub controllers for admin user: %v", err)
 		}
-		if err = asAdmin.CommonController.AddRegistryAuthSecretToSA("QUAY_TOKEN", k.UserNamespace); err != nil {
+		// the registry auth is validated once per suite run, don't try to add an invalid one to every namespace
+		if err = SuiteSetupError(RegistryAuthSetup); err != nil {
+			GinkgoWriter.Printf("Skipping adding registry auth secret to service account: %v\n", err)
+		} else if err = asAdmin.CommonController.AddRegistryAuthSecretToSA("QUAY_TOKEN", k.UserNamespace); err != nil {
 			GinkgoWriter.Println(fmt.Sprintf("Failed to add registry auth secret to service account: %v\n", err))
 		}
 	}
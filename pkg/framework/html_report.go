@@ -0,0 +1,166 @@
+package framework
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
+)
+
+//go:embed html_report.tmpl
+var htmlReportTemplate string
+
+// HTMLReport is the data rendered into the static HTML report.
+type HTMLReport struct {
+	Title     string
+	StartTime time.Time
+	RunTime   time.Duration
+	Succeeded bool
+	Labels    []string
+	Counts    map[string]int
+	Suites    []HTMLReportSuite
+}
+
+// HTMLReportSuite groups specs belonging to the same e2e suite (e.g. "build-service-suite").
+type HTMLReportSuite struct {
+	Name     string
+	Failures int
+	Specs    []HTMLReportSpec
+}
+
+// HTMLReportSpec holds everything displayed for a single spec.
+type HTMLReportSpec struct {
+	Name            string
+	State           string
+	Labels          []string
+	RunTime         time.Duration
+	FailureMessage  string
+	FailureLocation string
+	GinkgoWriter    string
+	Steps           []logs.StepTiming
+	// ArtifactDir is a path to the spec artifact directory, relative to the report file
+	ArtifactDir   string
+	ArtifactFiles []string
+}
+
+// Failed returns true if the spec did not pass and was not skipped.
+func (s HTMLReportSpec) Failed() bool {
+	return s.State != types.SpecStatePassed.String() && s.State != types.SpecStateSkipped.String() && s.State != types.SpecStatePending.String()
+}
+
+// GenerateHTMLReport writes a self-contained HTML report of the given Ginkgo report into dst.
+// Failed specs are linked to their artifact directory (named by logs.ShortenStringAddHash) under artifactsDir, if it exists.
+func GenerateHTMLReport(report types.Report, dst, artifactsDir string) error {
+	htmlReport := NewHTMLReport(report, filepath.Dir(dst), artifactsDir)
+
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"join":     strings.Join,
+		"duration": func(d time.Duration) string { return d.Round(time.Second).String() },
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse html report template: %v", err)
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, htmlReport); err != nil {
+		return fmt.Errorf("failed to render html report: %v", err)
+	}
+	return nil
+}
+
+// NewHTMLReport converts the Ginkgo report into the HTML report data. Artifact links are made relative to reportDir.
+func NewHTMLReport(report types.Report, reportDir, artifactsDir string) HTMLReport {
+	htmlReport := HTMLReport{
+		Title:     report.SuiteDescription,
+		StartTime: report.StartTime,
+		RunTime:   report.RunTime,
+		Succeeded: report.SuiteSucceeded,
+		Counts:    map[string]int{},
+	}
+	suites := map[string]*HTMLReportSuite{}
+	labels := map[string]bool{}
+
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType != types.NodeTypeIt {
+			continue
+		}
+		suiteName := logs.GetClassnameFromReport(spec)
+		suite, ok := suites[suiteName]
+		if !ok {
+			suite = &HTMLReportSuite{Name: suiteName}
+			suites[suiteName] = suite
+		}
+
+		s := HTMLReportSpec{
+			Name:    spec.FullText(),
+			State:   spec.State.String(),
+			Labels:  spec.Labels(),
+			RunTime: spec.RunTime,
+			Steps:   logs.GetStepTimings(spec),
+		}
+		for _, l := range s.Labels {
+			labels[l] = true
+		}
+		htmlReport.Counts[s.State]++
+
+		if s.Failed() {
+			suite.Failures++
+			s.FailureMessage = spec.FailureMessage()
+			if spec.State == types.SpecStatePanicked {
+				s.FailureMessage += "\n" + spec.Failure.ForwardedPanic
+			}
+			s.FailureLocation = fmt.Sprintf("%s\n%s", spec.FailureLocation().String(), spec.FailureLocation().FullStackTrace)
+			s.GinkgoWriter = spec.CapturedGinkgoWriterOutput
+			s.ArtifactDir, s.ArtifactFiles = findSpecArtifacts(spec, reportDir, artifactsDir)
+		}
+
+		suite.Specs = append(suite.Specs, s)
+	}
+
+	for _, suite := range suites {
+		htmlReport.Suites = append(htmlReport.Suites, *suite)
+	}
+	sort.Slice(htmlReport.Suites, func(i, j int) bool {
+		return htmlReport.Suites[i].Name < htmlReport.Suites[j].Name
+	})
+	for l := range labels {
+		htmlReport.Labels = append(htmlReport.Labels, l)
+	}
+	sort.Strings(htmlReport.Labels)
+
+	return htmlReport
+}
+
+// findSpecArtifacts returns the path of the spec artifact directory relative to reportDir together with the files it contains
+func findSpecArtifacts(spec types.SpecReport, reportDir, artifactsDir string) (string, []string) {
+	specDir := filepath.Join(artifactsDir, logs.ShortenStringAddHash(spec))
+	entries, err := os.ReadDir(specDir)
+	if err != nil {
+		return "", nil
+	}
+
+	relDir, err := filepath.Rel(reportDir, specDir)
+	if err != nil {
+		relDir = specDir
+	}
+
+	files := []string{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			files = append(files, e.Name())
+		}
+	}
+	return filepath.ToSlash(relDir), files
+}
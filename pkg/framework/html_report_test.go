@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHTMLReport(t *testing.T) {
+	failed := types.SpecReport{
+		ContainerHierarchyTexts: []string{"[build-service-suite Build service E2E tests]"},
+		LeafNodeText:            "should trigger a PipelineRun",
+		LeafNodeType:            types.NodeTypeIt,
+		LeafNodeLabels:          []string{"build"},
+		State:                   types.SpecStateFailed,
+		Failure: types.Failure{
+			Message:  "PipelineRun failed",
+			Location: types.CodeLocation{FileName: "build.go", LineNumber: 42},
+		},
+		CapturedGinkgoWriterOutput: "waiting for PipelineRun",
+	}
+	passed := types.SpecReport{
+		ContainerHierarchyTexts: []string{"[release-service-suite Release tests]"},
+		LeafNodeText:            "creates a Release",
+		LeafNodeType:            types.NodeTypeIt,
+		LeafNodeLabels:          []string{"release"},
+		State:                   types.SpecStatePassed,
+	}
+	report := types.Report{
+		SuiteDescription: "Red Hat App Studio E2E tests",
+		SpecReports:      types.SpecReports{passed, failed},
+	}
+
+	dir := t.TempDir()
+	artifactsDir := filepath.Join(dir, "artifacts")
+	specDir := filepath.Join(artifactsDir, logs.ShortenStringAddHash(failed))
+	assert.NoError(t, os.MkdirAll(specDir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(specDir, "pipelinerun.yaml"), []byte("kind: PipelineRun"), 0644))
+
+	htmlReport := NewHTMLReport(report, dir, artifactsDir)
+	assert.Equal(t, []string{"build", "release"}, htmlReport.Labels)
+	assert.Len(t, htmlReport.Suites, 2)
+	assert.Equal(t, "build-service-suite", htmlReport.Suites[0].Name)
+	assert.Equal(t, 1, htmlReport.Suites[0].Failures)
+	assert.Equal(t, []string{"pipelinerun.yaml"}, htmlReport.Suites[0].Specs[0].ArtifactFiles)
+	assert.True(t, strings.HasPrefix(htmlReport.Suites[0].Specs[0].ArtifactDir, "artifacts/"))
+	assert.Empty(t, htmlReport.Suites[1].Specs[0].ArtifactDir)
+
+	dst := filepath.Join(dir, "report.html")
+	assert.NoError(t, GenerateHTMLReport(report, dst, artifactsDir))
+	content, err := os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "PipelineRun failed")
+	assert.Contains(t, string(content), "waiting for PipelineRun")
+	assert.Contains(t, string(content), "pipelinerun.yaml")
+}
@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devfile/library/v2/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// maxNameLength is the maximum length of a Kubernetes resource name, Quay repository or GitHub branch generated by UniqueName
+const maxNameLength = 63
+
+// SerialLabel labels specs that modify cluster-scoped state (e.g. cluster-wide configuration, operators
+// or shared external resources). Such specs have to run serially when the suite runs with `ginkgo -p`.
+const SerialLabel = "serial"
+
+// SerialOnly decorates a container whose specs modify cluster-scoped state, e.g.
+//
+//	framework.UpgradeSuiteDescribe("Upgrade tests", framework.SerialOnly, func() { ... })
+var SerialOnly = []interface{}{Label(SerialLabel), Serial}
+
+// Specs labeled as serial but not decorated as Serial fail before running, as they might break specs running in parallel
+var _ = BeforeEach(func() {
+	if err := checkSerialOnly(CurrentSpecReport()); err != nil {
+		Fail(err.Error())
+	}
+})
+
+// checkSerialOnly returns an error if the spec is labeled as serial, but it is not decorated as Serial
+func checkSerialOnly(report types.SpecReport) error {
+	for _, l := range report.Labels() {
+		if l == SerialLabel && !report.IsSerial {
+			return fmt.Errorf("spec %q is labeled %q, but it's not Serial: decorate its container with framework.SerialOnly", report.FullText(), SerialLabel)
+		}
+	}
+	return nil
+}
+
+// ProcessName returns a name unique for the current Ginkgo parallel process, e.g. "build-p2". It's stable
+// during the suite run, so it can be used for resources shared by specs of the process.
+func ProcessName(prefix string) string {
+	return truncateName(fmt.Sprintf("%s-p%d", prefix, GinkgoParallelProcess()), "")
+}
+
+// UniqueName returns a name of an external resource (e.g. a Quay repository or a GitHub branch) which does not
+// collide with resources created by other specs or other parallel processes, e.g. "base-p2-x7kq".
+func UniqueName(prefix string) string {
+	suffix := fmt.Sprintf("-p%d-%s", GinkgoParallelProcess(), util.GenerateRandomString(4))
+	return truncateName(prefix, suffix)
+}
+
+// truncateName shortens the prefix to keep the name with the suffix within the maximum name length
+func truncateName(prefix, suffix string) string {
+	prefix = strings.ToLower(prefix)
+	if len(prefix)+len(suffix) > maxNameLength {
+		prefix = strings.TrimRight(prefix[:maxNameLength-len(suffix)], "-")
+	}
+	return prefix + suffix
+}
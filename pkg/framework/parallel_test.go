@@ -0,0 +1,62 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaming(t *testing.T) {
+	assert.Equal(t, "build-p1", ProcessName("Build"))
+
+	name := UniqueName("base")
+	assert.Regexp(t, `^base-p1-[a-z]{4}$`, name)
+	assert.NotEqual(t, name, UniqueName("base"))
+
+	long := UniqueName(strings.Repeat("component-", 10))
+	assert.Len(t, long, maxNameLength)
+	assert.Regexp(t, `^component-.*-compo-p1-[a-z]{4}$`, long)
+}
+
+func TestCheckSerialOnly(t *testing.T) {
+	report := types.SpecReport{ContainerHierarchyTexts: []string{"[upgrade-suite Upgrade]"}, LeafNodeText: "upgrades the cluster", LeafNodeLabels: []string{SerialLabel}}
+	assert.ErrorContains(t, checkSerialOnly(report), `spec "[upgrade-suite Upgrade] upgrades the cluster" is labeled "serial", but it's not Serial`)
+
+	report.IsSerial = true
+	assert.NoError(t, checkSerialOnly(report))
+	assert.NoError(t, checkSerialOnly(types.SpecReport{LeafNodeLabels: []string{"build"}}))
+}
+
+func TestUserPool(t *testing.T) {
+	provisioned := []string{}
+	pool := NewUserPool("build")
+	pool.newFramework = func(userName string, options ...utils.Options) (*Framework, error) {
+		provisioned = append(provisioned, userName)
+		return &Framework{UserName: userName, UserNamespace: userName + "-tenant"}, nil
+	}
+	cleanupErr := error(nil)
+	pool.cleanup = func(f *Framework) error { return cleanupErr }
+
+	first, err := pool.Acquire()
+	assert.NoError(t, err)
+	second, err := pool.Acquire()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"build-p1-" + pool.id + "-1", "build-p1-" + pool.id + "-2"}, provisioned)
+
+	// a released user is reused
+	assert.NoError(t, pool.Release(first))
+	reused, err := pool.Acquire()
+	assert.NoError(t, err)
+	assert.Same(t, first, reused)
+
+	// a user whose namespace was not cleaned up is not reused
+	cleanupErr = fmt.Errorf("components were not deleted")
+	assert.ErrorContains(t, pool.Release(second), fmt.Sprintf("user %s is not reused, cleanup of namespace %s failed", second.UserName, second.UserNamespace))
+	third, err := pool.Acquire()
+	assert.NoError(t, err)
+	assert.Equal(t, "build-p1-"+pool.id+"-3", third.UserName)
+}
@@ -0,0 +1,44 @@
+package framework
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
+)
+
+// NewPrerequisiteChecker returns a checker of prerequisites discovering the cluster's capabilities (see ClusterCapabilities)
+func NewPrerequisiteChecker() *suites.PrerequisiteChecker {
+	return suites.NewPrerequisiteChecker(ClusterCapabilities)
+}
+
+// prerequisiteChecker checks prerequisites of specs in the current parallel process
+var prerequisiteChecker = NewPrerequisiteChecker()
+
+// Specs fail if prerequisites of their suite are not met, or they are skipped if the prerequisites are optional
+var _ = BeforeEach(func() {
+	suite := suites.Of(CurrentSpecReport())
+	if suite == nil {
+		return
+	}
+	unmet := prerequisiteChecker.Unmet(suite.Prerequisites, true)
+	if msg := suites.UnmetMessage(suite, unmet, false); msg != "" {
+		Fail(msg)
+	}
+	if msg := suites.UnmetMessage(suite, unmet, true); msg != "" {
+		Skip(msg)
+	}
+})
+
+// SkipUnlessPrerequisites skips the current spec (or all specs of the Ordered container, if called from BeforeAll)
+// unless the prerequisites are met. It's meant for prerequisites of only some specs of a suite.
+func SkipUnlessPrerequisites(prerequisites ...suites.Prerequisite) {
+	reasons := []string{}
+	for _, u := range prerequisiteChecker.Unmet(prerequisites, true) {
+		reasons = append(reasons, u.Reason)
+	}
+	if len(reasons) != 0 {
+		Skip("prerequisites are not met: " + strings.Join(reasons, ", "))
+	}
+}
@@ -0,0 +1,132 @@
+package framework
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	. "github.com/onsi/ginkgo/v2"
+	"k8s.io/klog/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+)
+
+// RegistryAuthSetup is the name of the suite setup validating the QUAY_TOKEN registry auth, which is linked
+// to the pipeline service account of every user namespace.
+const RegistryAuthSetup = "registry-auth"
+
+// registryLoginTimeout is the timeout of logging in to registries of the QUAY_TOKEN registry auth
+const registryLoginTimeout = time.Minute
+
+type suiteSetup struct {
+	name  string
+	setup func() error
+}
+
+var (
+	suiteSetupsMu sync.Mutex
+	suiteSetups   []suiteSetup
+	// suiteSetupErrors holds errors of the suite setups which failed, received from the first parallel process
+	suiteSetupErrors = map[string]string{}
+)
+
+func init() {
+	RegisterSuiteSetup(RegistryAuthSetup, validateRegistryAuth)
+}
+
+// RegisterSuiteSetup registers a one-time setup of the cluster. Registered setups run once per suite run
+// on the first parallel process before any spec starts (see RunSuiteSetup). Test packages register their
+// setups from an init function.
+func RegisterSuiteSetup(name string, setup func() error) {
+	suiteSetupsMu.Lock()
+	defer suiteSetupsMu.Unlock()
+	suiteSetups = append(suiteSetups, suiteSetup{name: name, setup: setup})
+}
+
+// RunSuiteSetup runs the registered setups and returns their errors serialized for ReceiveSuiteSetup.
+// It's meant to be the first function of SynchronizedBeforeSuite, e.g.
+//
+//	var _ = ginkgo.SynchronizedBeforeSuite(framework.RunSuiteSetup, framework.ReceiveSuiteSetup)
+func RunSuiteSetup() []byte {
+	suiteSetupsMu.Lock()
+	defer suiteSetupsMu.Unlock()
+	errs := map[string]string{}
+	for _, s := range suiteSetups {
+		GinkgoWriter.Printf("running suite setup %s\n", s.name)
+		if err := s.setup(); err != nil {
+			klog.Errorf("suite setup %s failed: %v", s.name, err)
+			errs[s.name] = err.Error()
+		}
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		Fail(fmt.Sprintf("failed to marshal results of suite setups: %v", err))
+	}
+	return data
+}
+
+// ReceiveSuiteSetup stores results of the suite setups in every parallel process.
+func ReceiveSuiteSetup(data []byte) {
+	errs := map[string]string{}
+	if err := json.Unmarshal(data, &errs); err != nil {
+		Fail(fmt.Sprintf("failed to unmarshal results of suite setups: %v", err))
+	}
+	suiteSetupsMu.Lock()
+	defer suiteSetupsMu.Unlock()
+	suiteSetupErrors = errs
+}
+
+// SuiteSetupError returns the error of the suite setup with the name, or nil if the setup succeeded or did not run.
+func SuiteSetupError(name string) error {
+	suiteSetupsMu.Lock()
+	defer suiteSetupsMu.Unlock()
+	if msg, ok := suiteSetupErrors[name]; ok {
+		return fmt.Errorf("suite setup %s failed: %s", name, msg)
+	}
+	return nil
+}
+
+// validateRegistryAuth logs in to the registries of the QUAY_TOKEN docker config, so that an invalid or expired token
+// fails once before any spec starts, instead of failing builds in every user namespace
+func validateRegistryAuth() error {
+	quayToken := config.Get("QUAY_TOKEN")
+	if quayToken == "" {
+		return fmt.Errorf("QUAY_TOKEN env var is not set")
+	}
+	dockerConfig, err := base64.StdEncoding.DecodeString(quayToken)
+	if err != nil {
+		return fmt.Errorf("QUAY_TOKEN is not base64 encoded: %v", err)
+	}
+	config := struct {
+		Auths map[string]authn.AuthConfig `json:"auths"`
+	}{}
+	if err := json.Unmarshal(dockerConfig, &config); err != nil {
+		return fmt.Errorf("QUAY_TOKEN is not a base64 encoded docker config JSON: %v", err)
+	}
+	if len(config.Auths) == 0 {
+		return fmt.Errorf("QUAY_TOKEN docker config has no auths")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryLoginTimeout)
+	defer cancel()
+	for host, auth := range config.Auths {
+		// auths might be scoped to a repository, e.g. quay.io/redhat-appstudio-qe
+		registry, err := name.NewRegistry(strings.SplitN(host, "/", 2)[0])
+		if err != nil {
+			return fmt.Errorf("QUAY_TOKEN contains auth of an invalid registry %s: %v", host, err)
+		}
+		scopes := []string{registry.Scope(transport.PullScope)}
+		if _, err := transport.NewWithContext(ctx, registry, authn.FromConfig(auth), http.DefaultTransport, scopes); err != nil {
+			return fmt.Errorf("failed to log in to %s with QUAY_TOKEN: %v", host, err)
+		}
+	}
+	return nil
+}
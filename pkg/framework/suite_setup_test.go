@@ -0,0 +1,56 @@
+package framework
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTokenRegistry returns a registry which issues bearer tokens only to the user with the password
+func newTokenRegistry(t *testing.T, user, password string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/token":
+			if u, p, ok := r.BasicAuth(); !ok || u != user || p != password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token": "token"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func quayToken(host, user, password string) string {
+	auth := base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"auths": {"%s": {"auth": "%s"}}}`, host, auth)))
+}
+
+func TestValidateRegistryAuth(t *testing.T) {
+	server := newTokenRegistry(t, "robot", "secret")
+	host := strings.Replace(strings.TrimPrefix(server.URL, "http://"), "127.0.0.1", "localhost", 1)
+
+	t.Setenv("QUAY_TOKEN", quayToken(host+"/redhat-appstudio-qe", "robot", "secret"))
+	assert.NoError(t, validateRegistryAuth())
+
+	t.Setenv("QUAY_TOKEN", quayToken(host, "robot", "expired"))
+	assert.ErrorContains(t, validateRegistryAuth(), fmt.Sprintf("failed to log in to %s with QUAY_TOKEN", host))
+
+	t.Setenv("QUAY_TOKEN", base64.StdEncoding.EncodeToString([]byte(`{"auths": {}}`)))
+	assert.EqualError(t, validateRegistryAuth(), "QUAY_TOKEN docker config has no auths")
+
+	t.Setenv("QUAY_TOKEN", "not base64")
+	assert.ErrorContains(t, validateRegistryAuth(), "QUAY_TOKEN is not base64 encoded")
+}
@@ -0,0 +1,124 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/redhat-appstudio/e2e-tests/pkg/logs"
+)
+
+// slowestStepsCount is the number of entries kept in the aggregated "slowest steps" part of the timing report
+const slowestStepsCount = 20
+
+// TimingReport is a per-suite summary of the time spent in By steps and framework wait helpers.
+type TimingReport struct {
+	Suite        string             `json:"suite"`
+	StartTime    time.Time          `json:"startTime"`
+	RunTime      time.Duration      `json:"runTime"`
+	Specs        []SpecTiming       `json:"specs"`
+	SlowestSteps []AggregatedTiming `json:"slowestSteps"`
+}
+
+// SpecTiming holds the step breakdown of a single spec.
+type SpecTiming struct {
+	Name    string            `json:"name"`
+	State   string            `json:"state"`
+	RunTime time.Duration     `json:"runTime"`
+	Steps   []logs.StepTiming `json:"steps"`
+}
+
+// AggregatedTiming summarizes all occurrences of a step with the same name and kind across the suite.
+type AggregatedTiming struct {
+	Name  string        `json:"name"`
+	Kind  string        `json:"kind"`
+	Count int           `json:"count"`
+	Total time.Duration `json:"total"`
+	Mean  time.Duration `json:"mean"`
+	Max   time.Duration `json:"max"`
+	// MaxSpec is the name of the spec in which the slowest occurrence was observed
+	MaxSpec string `json:"maxSpec"`
+}
+
+// NewTimingReport collects step timings from all specs in the given Ginkgo report.
+func NewTimingReport(report types.Report) TimingReport {
+	timingReport := TimingReport{
+		Suite:     report.SuiteDescription,
+		StartTime: report.StartTime,
+		RunTime:   report.RunTime,
+		Specs:     []SpecTiming{},
+	}
+	aggregated := map[string]*AggregatedTiming{}
+
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType != types.NodeTypeIt || spec.State.Is(types.SpecStateSkipped|types.SpecStatePending) {
+			continue
+		}
+		name := logs.ShortenStringAddHash(spec)
+		steps := logs.GetStepTimings(spec)
+		timingReport.Specs = append(timingReport.Specs, SpecTiming{
+			Name:    name,
+			State:   spec.State.String(),
+			RunTime: spec.RunTime,
+			Steps:   steps,
+		})
+
+		for _, step := range steps {
+			key := step.Kind + "/" + step.Name
+			a, ok := aggregated[key]
+			if !ok {
+				a = &AggregatedTiming{Name: step.Name, Kind: step.Kind}
+				aggregated[key] = a
+			}
+			a.Count++
+			a.Total += step.Duration
+			if step.Duration > a.Max {
+				a.Max = step.Duration
+				a.MaxSpec = name
+			}
+		}
+	}
+
+	for _, a := range aggregated {
+		a.Mean = a.Total / time.Duration(a.Count)
+		timingReport.SlowestSteps = append(timingReport.SlowestSteps, *a)
+	}
+	sort.Slice(timingReport.SlowestSteps, func(i, j int) bool {
+		return timingReport.SlowestSteps[i].Max > timingReport.SlowestSteps[j].Max
+	})
+	if len(timingReport.SlowestSteps) > slowestStepsCount {
+		timingReport.SlowestSteps = timingReport.SlowestSteps[:slowestStepsCount]
+	}
+
+	return timingReport
+}
+
+// SlowestStepsSummary returns a human readable table of the slowest steps in the report.
+func (r TimingReport) SlowestStepsSummary() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Slowest steps of %q:\n", r.Suite))
+	for i, a := range r.SlowestSteps {
+		sb.WriteString(fmt.Sprintf("%2d. [%s] %s - max: %s, mean: %s, count: %d (slowest in: %s)\n",
+			i+1, a.Kind, a.Name, a.Max.Round(time.Second), a.Mean.Round(time.Second), a.Count, a.MaxSpec))
+	}
+	return sb.String()
+}
+
+// GenerateTimingReport writes the per-step timing report of the given Ginkgo report as JSON into dst.
+func GenerateTimingReport(report types.Report, dst string) (TimingReport, error) {
+	timingReport := NewTimingReport(report)
+
+	data, err := json.MarshalIndent(timingReport, "", "  ")
+	if err != nil {
+		return timingReport, fmt.Errorf("failed to marshal timing report: %v", err)
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return timingReport, fmt.Errorf("failed to store timing report: %v", err)
+	}
+
+	return timingReport, nil
+}
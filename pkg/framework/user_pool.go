@@ -0,0 +1,164 @@
+package framework
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devfile/library/v2/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+)
+
+// userNamespaceCleanupTimeout is the timeout of deleting AppStudio resources from the namespace of a released user
+const userNamespaceCleanupTimeout = 5 * time.Minute
+
+// UserPool provisions sandbox users (and their tenant namespaces) for the current Ginkgo parallel process and
+// reuses them across spec containers, instead of provisioning a new user for every container. User names contain
+// the number of the process and a random ID of the pool, so users of parallel processes and of earlier runs never collide.
+type UserPool struct {
+	prefix  string
+	id      string
+	options []utils.Options
+
+	mu      sync.Mutex
+	idle    []*Framework
+	created int
+
+	// newFramework provisions a new user, NewFramework by default
+	newFramework func(userName string, options ...utils.Options) (*Framework, error)
+	// cleanup deletes resources created by specs from the namespace of a released user
+	cleanup func(f *Framework) error
+}
+
+var (
+	userPoolsMu sync.Mutex
+	userPools   = map[string]*UserPool{}
+)
+
+// NewUserPool returns a pool of users with names starting with the prefix.
+func NewUserPool(prefix string, options ...utils.Options) *UserPool {
+	return &UserPool{prefix: prefix, id: util.GenerateRandomString(4), options: options, newFramework: NewFramework, cleanup: CleanupUserNamespace}
+}
+
+// GetUserPool returns the pool of users of the current process with names starting with the prefix.
+func GetUserPool(prefix string, options ...utils.Options) *UserPool {
+	userPoolsMu.Lock()
+	defer userPoolsMu.Unlock()
+	if _, ok := userPools[prefix]; !ok {
+		userPools[prefix] = NewUserPool(prefix, options...)
+	}
+	return userPools[prefix]
+}
+
+// CloseUserPools deletes idle users of all pools of the current process. It's meant to be the first function
+// of SynchronizedAfterSuite, e.g.
+//
+//	var _ = ginkgo.SynchronizedAfterSuite(framework.CloseUserPools, func() {})
+func CloseUserPools() {
+	userPoolsMu.Lock()
+	defer userPoolsMu.Unlock()
+	for prefix, pool := range userPools {
+		if err := pool.Close(); err != nil {
+			GinkgoWriter.Printf("failed to delete users of the pool %s: %v\n", prefix, err)
+		}
+	}
+}
+
+// AcquireFramework returns a framework of an idle user from the pool with the prefix. The framework
+// is returned to the pool when the container finishes, a user of a failed container is kept for debugging, e.g.
+//
+//	BeforeAll(func() {
+//		f, err = framework.AcquireFramework("build")
+//		Expect(err).NotTo(HaveOccurred())
+//	})
+//
+//	AfterAll(func() {
+//		if !CurrentSpecReport().Failed() {
+//			Expect(framework.ReleaseFramework("build", f)).To(Succeed())
+//		}
+//	})
+func AcquireFramework(prefix string, options ...utils.Options) (*Framework, error) {
+	return GetUserPool(prefix, options...).Acquire()
+}
+
+// ReleaseFramework returns the framework to the pool with the prefix.
+func ReleaseFramework(prefix string, f *Framework) error {
+	return GetUserPool(prefix).Release(f)
+}
+
+// Acquire returns a framework of an idle user, or provisions a new user if there is none.
+func (p *UserPool) Acquire() (*Framework, error) {
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		f := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		GinkgoWriter.Printf("reusing user %s with namespace %s\n", f.UserName, f.UserNamespace)
+		return f, nil
+	}
+	p.created++
+	userName := p.userName(p.created)
+	p.mu.Unlock()
+
+	f, err := p.newFramework(userName, p.options...)
+	if err != nil {
+		return nil, fmt.Errorf("error when provisioning user %s: %v", userName, err)
+	}
+	return f, nil
+}
+
+// Release cleans up the namespace of the user and makes the user available for other containers.
+// A user whose namespace could not be cleaned up is not reused.
+func (p *UserPool) Release(f *Framework) error {
+	if f == nil {
+		return nil
+	}
+	if err := p.cleanup(f); err != nil {
+		return fmt.Errorf("user %s is not reused, cleanup of namespace %s failed: %v", f.UserName, f.UserNamespace, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle = append(p.idle, f)
+	return nil
+}
+
+// Close deletes idle users of the pool.
+func (p *UserPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var finalError string
+	for _, f := range p.idle {
+		if _, err := f.SandboxController.DeleteUserSignup(f.UserName); err != nil {
+			finalError = appendErrorToString(finalError, fmt.Errorf("error when deleting user %s: %v", f.UserName, err))
+		}
+	}
+	p.idle = nil
+	if len(finalError) > 0 {
+		return fmt.Errorf(finalError)
+	}
+	return nil
+}
+
+// userName returns the name of the n-th user of the pool in the current process, e.g. "build-p2-x7kq-1"
+func (p *UserPool) userName(n int) string {
+	return fmt.Sprintf("%s-%s-%d", ProcessName(p.prefix), p.id, n)
+}
+
+// CleanupUserNamespace deletes AppStudio resources created by specs from the namespace of the user.
+func CleanupUserNamespace(f *Framework) error {
+	c, namespace := f.AsKubeAdmin, f.UserNamespace
+	var finalError string
+	finalError = appendErrorToString(finalError, c.HasController.DeleteAllComponentsInASpecificNamespace(namespace, userNamespaceCleanupTimeout))
+	finalError = appendErrorToString(finalError, c.HasController.DeleteAllApplicationsInASpecificNamespace(namespace, userNamespaceCleanupTimeout))
+	finalError = appendErrorToString(finalError, c.HasController.DeleteAllComponentDetectionQueriesInASpecificNamespace(namespace, userNamespaceCleanupTimeout))
+	finalError = appendErrorToString(finalError, c.IntegrationController.DeleteAllSnapshotsInASpecificNamespace(namespace, userNamespaceCleanupTimeout))
+	finalError = appendErrorToString(finalError, c.CommonController.DeleteAllSnapshotEnvBindingsInASpecificNamespace(namespace, userNamespaceCleanupTimeout))
+	finalError = appendErrorToString(finalError, c.GitOpsController.DeleteAllEnvironmentsInASpecificNamespace(namespace, userNamespaceCleanupTimeout))
+	finalError = appendErrorToString(finalError, c.TektonController.DeleteAllPipelineRunsInASpecificNamespace(namespace))
+	if len(finalError) > 0 {
+		return fmt.Errorf(finalError)
+	}
+	return nil
+}
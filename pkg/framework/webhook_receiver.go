@@ -0,0 +1,275 @@
+package framework
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- GitHub still sends the legacy X-Hub-Signature header
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+)
+
+const (
+	GitHubSignatureHeader       = "X-Hub-Signature-256"
+	GitHubLegacySignatureHeader = "X-Hub-Signature"
+	GitHubEventHeader           = "X-GitHub-Event"
+	GitLabEventHeader           = "X-Gitlab-Event"
+	GitLabTokenHeader           = "X-Gitlab-Token"
+)
+
+// WebhookDelivery is a single request recorded by the WebhookReceiver.
+type WebhookDelivery struct {
+	ReceivedAt time.Time
+	Method     string
+	Path       string
+	Header     http.Header
+	Body       []byte
+	// Event is taken from the X-GitHub-Event/X-Gitlab-Event header or from the "resource" field of a GoWebHook payload
+	Event string
+	// SignatureValid is true when the delivery carried a signature (or token) matching the receiver secret
+	SignatureValid bool
+}
+
+// JSON decodes the delivery body into a generic JSON structure, returning nil if the body is not valid JSON.
+func (d WebhookDelivery) JSON() interface{} {
+	var payload interface{}
+	if err := json.Unmarshal(d.Body, &payload); err != nil {
+		return nil
+	}
+	return payload
+}
+
+// WebhookReceiver is a test-side HTTP server recording incoming webhook deliveries and verifying their signatures.
+type WebhookReceiver struct {
+	// Secret used for verifying HMAC signatures of deliveries. Signatures are not verified if empty.
+	Secret string
+	// RejectInvalidSignatures makes the receiver respond with 401 to deliveries without a valid signature.
+	// Such deliveries are recorded anyway.
+	RejectInvalidSignatures bool
+
+	mu         sync.Mutex
+	deliveries []WebhookDelivery
+	server     *http.Server
+	listener   net.Listener
+	cleanup    func() error
+}
+
+// NewWebhookReceiver returns a receiver verifying deliveries with the given secret. Use Start to start serving or
+// use the receiver directly as an http.Handler (e.g. with httptest.NewServer).
+func NewWebhookReceiver(secret string) *WebhookReceiver {
+	return &WebhookReceiver{Secret: secret}
+}
+
+// Start starts serving on the given address (e.g. ":8080"). Use Addr to get the actual address when using port 0.
+func (r *WebhookReceiver) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start webhook receiver on %s: %v", addr, err)
+	}
+	r.listener = listener
+	r.server = &http.Server{Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := r.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			GinkgoWriter.Printf("webhook receiver stopped with error: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the receiver listens on.
+func (r *WebhookReceiver) Addr() string {
+	if r.listener == nil {
+		return ""
+	}
+	return r.listener.Addr().String()
+}
+
+// Stop stops the server and removes cluster resources created for exposing it.
+func (r *WebhookReceiver) Stop() error {
+	var errs []string
+	if r.cleanup != nil {
+		if err := r.cleanup(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if r.server != nil {
+		if err := r.server.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to stop webhook receiver: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ServeHTTP records the delivery and verifies its signature.
+func (r *WebhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	delivery := WebhookDelivery{
+		ReceivedAt: time.Now(),
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Header:     req.Header.Clone(),
+		Body:       body,
+		Event:      webhookEvent(req.Header, body),
+	}
+	delivery.SignatureValid = r.verifySignature(req.Header, body)
+
+	r.mu.Lock()
+	r.deliveries = append(r.deliveries, delivery)
+	r.mu.Unlock()
+
+	if r.RejectInvalidSignatures && !delivery.SignatureValid {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Deliveries returns all recorded deliveries. It can be passed directly to Eventually:
+//
+//	Eventually(receiver.Deliveries).Should(HaveReceivedWebhook("push", MatchJSON(expected)))
+func (r *WebhookReceiver) Deliveries() []WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]WebhookDelivery{}, r.deliveries...)
+}
+
+// DeliveriesOf returns recorded deliveries of the given event.
+func (r *WebhookReceiver) DeliveriesOf(event string) []WebhookDelivery {
+	deliveries := []WebhookDelivery{}
+	for _, d := range r.Deliveries() {
+		if d.Event == event {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries
+}
+
+// Reset forgets all recorded deliveries.
+func (r *WebhookReceiver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = nil
+}
+
+func (r *WebhookReceiver) verifySignature(header http.Header, body []byte) bool {
+	if r.Secret == "" {
+		return true
+	}
+	if signature := header.Get(GitHubSignatureHeader); signature != "" {
+		return validHMAC(sha256.New, strings.TrimPrefix(signature, "sha256="), r.Secret, body)
+	}
+	if signature := header.Get(GitHubLegacySignatureHeader); signature != "" {
+		return validHMAC(sha1.New, strings.TrimPrefix(signature, "sha1="), r.Secret, body)
+	}
+	if signature := header.Get(DefaultSignatureHeader); signature != "" {
+		return validHMAC(sha256.New, signature, r.Secret, body)
+	}
+	if token := header.Get(GitLabTokenHeader); token != "" {
+		return hmac.Equal([]byte(token), []byte(r.Secret))
+	}
+	return false
+}
+
+func validHMAC(h func() hash.Hash, signature, secret string, body []byte) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func webhookEvent(header http.Header, body []byte) string {
+	for _, h := range []string{GitHubEventHeader, GitLabEventHeader} {
+		if event := header.Get(h); event != "" {
+			return event
+		}
+	}
+	payload := GoWebHookPayload{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		return payload.Resource
+	}
+	return ""
+}
+
+// HaveReceivedWebhook succeeds if a list of deliveries contains a validly signed delivery of the given event
+// whose body satisfies the payload matcher (e.g. MatchJSON or ContainSubstring). Pass nil to match any payload.
+func HaveReceivedWebhook(event string, payload types.GomegaMatcher) types.GomegaMatcher {
+	matchers := []types.GomegaMatcher{
+		WithTransform(func(d WebhookDelivery) string { return d.Event }, Equal(event)),
+		WithTransform(func(d WebhookDelivery) bool { return d.SignatureValid }, BeTrue()),
+	}
+	if payload != nil {
+		matchers = append(matchers, WithTransform(func(d WebhookDelivery) string { return string(d.Body) }, payload))
+	}
+	return ContainElement(SatisfyAll(matchers...))
+}
+
+// StartWebhookReceiver starts a webhook receiver in the test process and exposes it in the user namespace
+// via a Service and a Route. The test process has to be reachable from the cluster on the IP address
+// set in WEBHOOK_RECEIVER_IP env var (e.g. the IP of the pod running the tests). It returns the receiver
+// together with the URL services in the cluster should deliver webhooks to. Call Stop on the receiver to clean up.
+func (f *Framework) StartWebhookReceiver(name, secret string) (*WebhookReceiver, string, error) {
+	ip := config.Get(constants.WEBHOOK_RECEIVER_IP_ENV)
+	if ip == "" {
+		return nil, "", fmt.Errorf("env var %s has to be set to an IP address of the test process reachable from the cluster", constants.WEBHOOK_RECEIVER_IP_ENV)
+	}
+
+	// listen on a random port, receivers of parallel processes would collide on a fixed one
+	receiver := NewWebhookReceiver(secret)
+	if err := receiver.Start(":0"); err != nil {
+		return nil, "", err
+	}
+	_, portStr, err := net.SplitHostPort(receiver.Addr())
+	if err != nil {
+		_ = receiver.Stop()
+		return nil, "", fmt.Errorf("failed to get port of webhook receiver: %v", err)
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		_ = receiver.Stop()
+		return nil, "", fmt.Errorf("failed to get port of webhook receiver: %v", err)
+	}
+
+	ctrl := f.AsKubeAdmin.CommonController
+	if _, err := ctrl.CreateExternalService(name, f.UserNamespace, ip, int32(port)); err != nil {
+		_ = receiver.Stop()
+		return nil, "", err
+	}
+	route, err := ctrl.CreateRoute(name, f.UserNamespace, name)
+	receiver.cleanup = func() error {
+		if err := ctrl.DeleteRoute(name, f.UserNamespace); err != nil {
+			return err
+		}
+		return ctrl.DeleteService(name, f.UserNamespace)
+	}
+	if err != nil {
+		_ = receiver.Stop()
+		return nil, "", err
+	}
+
+	return receiver, "https://" + route.Spec.Host, nil
+}
@@ -0,0 +1,67 @@
+package framework
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWebhookReceiverWithGoWebHook(t *testing.T) {
+	g := NewWithT(t)
+	receiver := NewWebhookReceiver("secret")
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	hook := &GoWebHook{}
+	hook.Create(map[string]string{"status": "succeeded"}, "integration-test-status", "secret")
+	resp, err := hook.Send(server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	invalid := &GoWebHook{}
+	invalid.Create(map[string]string{"status": "failed"}, "integration-test-status", "wrong-secret")
+	_, err = invalid.Send(server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Eventually(receiver.Deliveries).Should(HaveLen(2))
+	g.Expect(receiver.Deliveries()).To(HaveReceivedWebhook("integration-test-status", MatchJSON(`{"resource":"integration-test-status","data":{"status":"succeeded"}}`)))
+	g.Expect(receiver.Deliveries()).NotTo(HaveReceivedWebhook("integration-test-status", ContainSubstring("failed")), "deliveries with invalid signatures should not match")
+	g.Expect(receiver.DeliveriesOf("integration-test-status")).To(HaveLen(2))
+
+	receiver.Reset()
+	g.Expect(receiver.Deliveries()).To(BeEmpty())
+}
+
+func TestWebhookReceiverWithGitHubSignature(t *testing.T) {
+	g := NewWithT(t)
+	receiver := NewWebhookReceiver("secret")
+	receiver.RejectInvalidSignatures = true
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	body := []byte(`{"action":"completed"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+
+	send := func(signature string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+		g.Expect(err).NotTo(HaveOccurred())
+		req.Header.Set(GitHubEventHeader, "check_run")
+		req.Header.Set(GitHubSignatureHeader, signature)
+		resp, err := http.DefaultClient.Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	g.Expect(send("sha256=" + hex.EncodeToString(mac.Sum(nil)))).To(Equal(http.StatusOK))
+	g.Expect(send("sha256=deadbeef")).To(Equal(http.StatusUnauthorized))
+	g.Expect(receiver.Deliveries()).To(HaveReceivedWebhook("check_run", nil))
+	g.Expect(receiver.Deliveries()).To(HaveLen(2))
+}
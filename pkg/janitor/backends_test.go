@@ -0,0 +1,331 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	gh "github.com/google/go-github/v44/github"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/clients/sprayproxy"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox"
+	"github.com/redhat-appstudio/image-controller/pkg/quay"
+)
+
+type githubClientMock struct {
+	repositories []*gh.Repository
+	branches     map[string][]*gh.Branch
+	hooks        map[string][]*gh.Hook
+	pullRequests map[string][]*gh.PullRequest
+	deleted      []string
+}
+
+var _ GithubClient = (*githubClientMock)(nil)
+
+func (m *githubClientMock) GetAllRepositories() ([]*gh.Repository, error) {
+	return m.repositories, nil
+}
+
+func (m *githubClientMock) DeleteRepository(repository *gh.Repository) error {
+	m.deleted = append(m.deleted, "repository "+repository.GetName())
+	return nil
+}
+
+func (m *githubClientMock) ListBranches(repository string) ([]*gh.Branch, error) {
+	return m.branches[repository], nil
+}
+
+func (m *githubClientMock) DeleteRef(repository, branchName string) error {
+	m.deleted = append(m.deleted, fmt.Sprintf("branch %s/%s", repository, branchName))
+	return nil
+}
+
+func (m *githubClientMock) ListRepoWebhooks(repository string) ([]*gh.Hook, error) {
+	return m.hooks[repository], nil
+}
+
+func (m *githubClientMock) DeleteWebhook(repository string, ID int64) error {
+	m.deleted = append(m.deleted, fmt.Sprintf("webhook %s/%d", repository, ID))
+	return nil
+}
+
+func (m *githubClientMock) ListPullRequests(repository string) ([]*gh.PullRequest, error) {
+	return m.pullRequests[repository], nil
+}
+
+func (m *githubClientMock) ClosePullRequest(repository string, prNumber int) error {
+	m.deleted = append(m.deleted, fmt.Sprintf("pull request %s#%d", repository, prNumber))
+	return nil
+}
+
+func branch(name string, date time.Time, protected bool) *gh.Branch {
+	return &gh.Branch{Name: gh.String(name), Protected: gh.Bool(protected), Commit: &gh.RepositoryCommit{Commit: &gh.Commit{Committer: &gh.CommitAuthor{Date: &date}}}}
+}
+
+func TestGithubBackend(t *testing.T) {
+	old, recent := testNow.Add(-48*time.Hour), testNow.Add(-time.Hour)
+	client := &githubClientMock{
+		repositories: []*gh.Repository{
+			{Name: gh.String("e2e-app"), CreatedAt: &gh.Timestamp{Time: old}},
+			{Name: gh.String("devfile-sample"), Description: gh.String("GitOps Repository"), CreatedAt: &gh.Timestamp{Time: old}},
+			{Name: gh.String("e2e-new"), CreatedAt: &gh.Timestamp{Time: recent}},
+			{Name: gh.String("e2e-tests"), CreatedAt: &gh.Timestamp{Time: old}},
+		},
+		branches: map[string][]*gh.Branch{
+			"devfile-sample": {branch("main", old, false), branch("release", old, true), branch("appstudio-abcd", old, false), branch("appstudio-efgh", recent, false)},
+		},
+		hooks: map[string][]*gh.Hook{
+			"devfile-sample": {{ID: gh.Int64(1), CreatedAt: &old, Config: map[string]interface{}{"url": "https://smee.io/abcd"}}, {ID: gh.Int64(2), CreatedAt: &recent}},
+		},
+		pullRequests: map[string][]*gh.PullRequest{
+			"devfile-sample": {
+				{Number: gh.Int(3), Title: gh.String("Red Hat Trusted App Test"), Head: &gh.PullRequestBranch{Ref: gh.String("appstudio-abcd")}, CreatedAt: &old},
+				{Number: gh.Int(4), Title: gh.String("Update README"), Head: &gh.PullRequestBranch{Ref: gh.String("readme")}, CreatedAt: &old},
+			},
+		},
+	}
+	policy, err := LoadPolicy([]byte(`
+rules:
+- name: pull-requests
+  resource: github-pull-request
+  scopes: [devfile-sample]
+  descriptionPatterns: ["^Red Hat Trusted App Test$"]
+  olderThan: 24h
+- name: branches
+  resource: github-branch
+  scopes: [devfile-sample]
+  exclude: ["^main$"]
+  olderThan: 24h
+- name: webhooks
+  resource: github-webhook
+  scopes: [devfile-sample]
+  olderThan: 24h
+- name: repositories
+  resource: github-repository
+  namePatterns: ["e2e"]
+  descriptionPatterns: ["^GitOps Repository$"]
+  exclude: ["^e2e-tests$"]
+  olderThan: 24h
+`))
+	assert.NoError(t, err)
+	j := New(policy, false)
+	j.now = func() time.Time { return testNow }
+	j.Workers = 1
+	j.Register(&GithubBackend{Client: client}, GithubResourceTypes...)
+
+	report, err := j.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"pull request devfile-sample#3",
+		"branch devfile-sample/appstudio-abcd",
+		"webhook devfile-sample/1",
+		"repository e2e-app",
+		"repository devfile-sample",
+	}, client.deleted)
+	assert.Equal(t, 5, report.Count(Deleted))
+	assert.Equal(t, 2, report.Count(Excluded))
+	assert.Equal(t, Resource{Type: GithubWebhook, Scope: "devfile-sample", Name: "1", Description: "https://smee.io/abcd", Timestamp: old, ID: 1}, report.Entries[3].Resource)
+}
+
+// quayClientMock serves repositories, robot accounts and tags of a single organization
+type quayClientMock struct {
+	quay.QuayService
+	mu           sync.Mutex
+	repositories []quay.Repository
+	robots       []quay.RobotAccount
+	tags         []quay.Tag
+	tagsOnPage   int
+	deleted      []string
+}
+
+func (m *quayClientMock) GetAllRepositories(string) ([]quay.Repository, error) {
+	return m.repositories, nil
+}
+
+func (m *quayClientMock) GetAllRobotAccounts(string) ([]quay.RobotAccount, error) {
+	return m.robots, nil
+}
+
+func (m *quayClientMock) GetTagsFromPage(_, _ string, page int) ([]quay.Tag, bool, error) {
+	end := page * m.tagsOnPage
+	if end >= len(m.tags) {
+		return m.tags[(page-1)*m.tagsOnPage:], false, nil
+	}
+	return m.tags[(page-1)*m.tagsOnPage : end], true, nil
+}
+
+func (m *quayClientMock) record(deleted string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, deleted)
+	return true, nil
+}
+
+func (m *quayClientMock) DeleteRepository(_, repository string) (bool, error) {
+	return m.record("repository " + repository)
+}
+
+func (m *quayClientMock) DeleteRobotAccount(_, robotName string) (bool, error) {
+	return m.record("robot " + robotName)
+}
+
+func (m *quayClientMock) DeleteTag(_, repository, tag string) (bool, error) {
+	return m.record(fmt.Sprintf("tag %s:%s", repository, tag))
+}
+
+func TestQuayBackend(t *testing.T) {
+	old, recent := testNow.Add(-25*time.Hour), testNow.Add(-time.Hour)
+	client := &quayClientMock{
+		repositories: []quay.Repository{
+			{Name: "e2e-demos/test-old", IsPublic: true, LastModified: int(old.Unix())},
+			{Name: "e2e-demos/test-new", IsPublic: true, LastModified: int(recent.Unix())},
+			{Name: "other/test-old", IsPublic: true, LastModified: int(old.Unix())},
+			{Name: "build-e2e/private-old", LastModified: int(testNow.AddDate(0, 0, -8).Unix())},
+			{Name: "build-e2e/public-old", IsPublic: true, LastModified: int(testNow.AddDate(0, 0, -8).Unix())},
+			{Name: "e2e-demos/test-unknown", IsPublic: true},
+			{Name: "e2e-demos/test-unpushed", IsPublic: true},
+		},
+		robots: []quay.RobotAccount{
+			{Name: "test-org+e2e-demostest-old", Created: old.Format(quayTimeFormat)},
+			{Name: "test-org+e2e-demostest-new", Created: recent.Format(quayTimeFormat)},
+			{Name: "test-org+othertest-old", Created: old.Format(quayTimeFormat)},
+			{Name: "test-org+e2e-demostest-unpushed", Created: old.Format(quayTimeFormat)},
+		},
+		tagsOnPage: 2,
+	}
+	for i := 0; i < 5; i++ {
+		client.tags = append(client.tags, quay.Tag{Name: fmt.Sprintf("tag-%d", i), StartTS: testNow.AddDate(0, 0, -2*i).Unix()})
+	}
+	policy, err := LoadPolicy([]byte(`
+rules:
+- name: e2e-repositories
+  resource: quay-repository
+  namePatterns: ["^(e2e-demos|has-e2e)"]
+  olderThan: 24h
+- name: e2e-robots
+  resource: quay-robot
+  namePatterns: ["^(e2e-demos|has-e2e)"]
+  olderThan: 24h
+- name: private-repositories
+  resource: quay-repository
+  namePatterns: ["^build-e2e"]
+  privateOnly: true
+  olderThan: 168h
+- name: test-images-tags
+  resource: quay-tag
+  scopes: [test-images]
+  olderThan: 168h
+`))
+	assert.NoError(t, err)
+	j := New(policy, false)
+	j.now = func() time.Time { return testNow }
+	j.Register(&QuayBackend{Client: client, Organization: "test-org"}, QuayResourceTypes...)
+
+	report, err := j.Run(context.Background())
+	assert.NoError(t, err)
+	sort.Strings(client.deleted)
+	assert.Equal(t, []string{
+		"repository build-e2e/private-old",
+		"repository e2e-demos/test-old",
+		"repository e2e-demos/test-unpushed",
+		"robot e2e-demostest-old",
+		"robot e2e-demostest-unpushed",
+		"tag test-images:tag-4",
+	}, client.deleted)
+	assert.Equal(t, 6, report.Count(Deleted))
+
+	// repositories without the last modification time have the creation time of their robot account,
+	// or they are kept if there is none
+	client.repositories = []quay.Repository{{Name: "e2e-demos/test-unknown", IsPublic: true}, {Name: "e2e-demos/test-unpushed", IsPublic: true}}
+	client.robots = []quay.RobotAccount{{Name: "test-org+e2e-demostest-unpushed", Created: old.Format(quayTimeFormat)}}
+	repos, err := (&QuayBackend{Client: client, Organization: "test-org"}).List(context.Background(), QuayRepository, nil)
+	assert.NoError(t, err)
+	assert.True(t, repos[0].Timestamp.IsZero())
+	assert.Equal(t, old.Unix(), repos[1].Timestamp.Unix())
+
+	client.robots = append(client.robots, quay.RobotAccount{Name: "test-org+invalid", Created: "yesterday"})
+	_, err = (&QuayBackend{Client: client, Organization: "test-org"}).List(context.Background(), QuayRobot, nil)
+	assert.ErrorContains(t, err, "failed to parse creation time of robot account test-org+invalid")
+}
+
+func TestSprayProxyServersBackend(t *testing.T) {
+	server := sprayproxy.NewFakeServer("token", "https://pac.alive.example.com", "https://pac.gone.example.com")
+	defer server.Close()
+	policy, err := LoadPolicy([]byte(`
+rules:
+- name: stale-backends
+  resource: sprayproxy-backend
+  staleOnly: true
+`))
+	assert.NoError(t, err)
+	backend := &SprayProxyServersBackend{Client: server.NewClient(), Check: func(_ context.Context, backend string) sprayproxy.BackendStatus {
+		if backend == "https://pac.gone.example.com" {
+			return sprayproxy.BackendStatus{URL: backend, Reason: "cluster is gone"}
+		}
+		return sprayproxy.BackendStatus{URL: backend, Alive: true}
+	}}
+
+	j := New(policy, true)
+	j.Register(backend, SprayProxyBackend)
+	report, err := j.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []ReportEntry{{Rule: "stale-backends", Resource: Resource{Type: SprayProxyBackend, Name: "https://pac.gone.example.com", StaleReason: "cluster is gone"}, Action: WouldDelete, Reason: "stale: cluster is gone"}}, report.Entries)
+	assert.Len(t, server.Backends(), 2)
+
+	j.DryRun = false
+	_, err = j.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://pac.alive.example.com"}, server.Backends())
+}
+
+func TestClusterBackend(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, toolchainApi.AddToScheme(scheme))
+	old := metav1.NewTime(testNow.Add(-48 * time.Hour))
+	c := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "build-e2e-abcd", CreationTimestamp: old}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-gitops", CreationTimestamp: old}},
+		&toolchainApi.UserSignup{ObjectMeta: metav1.ObjectMeta{Name: "e2e-user", Namespace: sandbox.DEFAULT_TOOLCHAIN_NAMESPACE, CreationTimestamp: old}},
+	).Build()
+	policy, err := LoadPolicy([]byte(`
+rules:
+- name: e2e-namespaces
+  resource: namespace
+  namePatterns: ["-e2e-"]
+  olderThan: 24h
+- name: e2e-usersignups
+  resource: usersignup
+  namePatterns: ["^e2e-"]
+  olderThan: 24h
+`))
+	assert.NoError(t, err)
+	j := New(policy, false)
+	j.now = func() time.Time { return testNow }
+	backend := &ClusterBackend{Client: c}
+	j.Register(backend, ClusterResourceTypes...)
+
+	report, err := j.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Count(Deleted))
+	namespaces := &corev1.NamespaceList{}
+	assert.NoError(t, c.List(context.Background(), namespaces))
+	assert.Len(t, namespaces.Items, 1)
+	assert.Equal(t, "openshift-gitops", namespaces.Items[0].Name)
+	userSignups := &toolchainApi.UserSignupList{}
+	assert.NoError(t, c.List(context.Background(), userSignups, crclient.InNamespace(sandbox.DEFAULT_TOOLCHAIN_NAMESPACE)))
+	assert.Empty(t, userSignups.Items)
+
+	// resources deleted in the meantime are not failures
+	assert.NoError(t, backend.Delete(context.Background(), Resource{Type: Namespace, Name: "build-e2e-abcd"}))
+}
@@ -0,0 +1,67 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox"
+)
+
+// ClusterBackend handles namespaces and UserSignups of a cluster. Namespaces which are already being deleted
+// are not listed, resources which are gone before their deletion are not reported as failures.
+type ClusterBackend struct {
+	Client crclient.Client
+}
+
+// ClusterResourceTypes are the types handled by ClusterBackend
+var ClusterResourceTypes = []ResourceType{Namespace, UserSignup}
+
+func (b *ClusterBackend) List(ctx context.Context, resourceType ResourceType, _ []string) ([]Resource, error) {
+	resources := []Resource{}
+	switch resourceType {
+	case Namespace:
+		namespaces := &corev1.NamespaceList{}
+		if err := b.Client.List(ctx, namespaces); err != nil {
+			return nil, err
+		}
+		for _, ns := range namespaces.Items {
+			if ns.DeletionTimestamp != nil {
+				continue
+			}
+			resources = append(resources, Resource{Type: resourceType, Name: ns.Name, Timestamp: ns.CreationTimestamp.Time})
+		}
+	case UserSignup:
+		userSignups := &toolchainApi.UserSignupList{}
+		if err := b.Client.List(ctx, userSignups, crclient.InNamespace(sandbox.DEFAULT_TOOLCHAIN_NAMESPACE)); err != nil {
+			return nil, err
+		}
+		for _, us := range userSignups.Items {
+			resources = append(resources, Resource{Type: resourceType, Name: us.Name, Timestamp: us.CreationTimestamp.Time})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+	return resources, nil
+}
+
+func (b *ClusterBackend) Delete(ctx context.Context, r Resource) error {
+	var obj crclient.Object
+	switch r.Type {
+	case Namespace:
+		obj = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: r.Name}}
+	case UserSignup:
+		obj = &toolchainApi.UserSignup{ObjectMeta: metav1.ObjectMeta{Name: r.Name, Namespace: sandbox.DEFAULT_TOOLCHAIN_NAMESPACE}}
+	default:
+		return fmt.Errorf("unsupported resource type %s", r.Type)
+	}
+	if err := b.Client.Delete(ctx, obj); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v44/github"
+)
+
+// GithubClient is the part of the Github client used by the janitor
+type GithubClient interface {
+	GetAllRepositories() ([]*gh.Repository, error)
+	DeleteRepository(repository *gh.Repository) error
+	ListBranches(repository string) ([]*gh.Branch, error)
+	DeleteRef(repository, branchName string) error
+	ListRepoWebhooks(repository string) ([]*gh.Hook, error)
+	DeleteWebhook(repository string, ID int64) error
+	ListPullRequests(repository string) ([]*gh.PullRequest, error)
+	ClosePullRequest(repository string, prNumber int) error
+}
+
+// GithubBackend handles repositories, branches, webhooks and pull requests of a Github organization.
+// Protected branches are never listed, pull requests are closed instead of deleted.
+type GithubBackend struct {
+	Client GithubClient
+}
+
+// GithubResourceTypes are the types handled by GithubBackend
+var GithubResourceTypes = []ResourceType{GithubRepository, GithubBranch, GithubWebhook, GithubPullRequest}
+
+func (b *GithubBackend) List(_ context.Context, resourceType ResourceType, scopes []string) ([]Resource, error) {
+	if resourceType == GithubRepository {
+		repos, err := b.Client.GetAllRepositories()
+		if err != nil {
+			return nil, err
+		}
+		resources := []Resource{}
+		for _, repo := range repos {
+			resources = append(resources, Resource{Type: resourceType, Name: repo.GetName(), Description: repo.GetDescription(), Private: repo.GetPrivate(), Timestamp: repo.GetCreatedAt().Time})
+		}
+		return resources, nil
+	}
+
+	resources := []Resource{}
+	for _, scope := range scopes {
+		scoped, err := b.listScoped(resourceType, scope)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, scoped...)
+	}
+	return resources, nil
+}
+
+func (b *GithubBackend) listScoped(resourceType ResourceType, repository string) ([]Resource, error) {
+	resources := []Resource{}
+	switch resourceType {
+	case GithubBranch:
+		branches, err := b.Client.ListBranches(repository)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			if branch.GetProtected() {
+				continue
+			}
+			date := branch.GetCommit().GetCommit().GetCommitter().GetDate()
+			resources = append(resources, Resource{Type: resourceType, Scope: repository, Name: branch.GetName(), Timestamp: date})
+		}
+	case GithubWebhook:
+		hooks, err := b.Client.ListRepoWebhooks(repository)
+		if err != nil {
+			return nil, err
+		}
+		for _, hook := range hooks {
+			url, _ := hook.Config["url"].(string)
+			resources = append(resources, Resource{Type: resourceType, Scope: repository, Name: fmt.Sprint(hook.GetID()), Description: url, Timestamp: hook.GetCreatedAt(), ID: hook.GetID()})
+		}
+	case GithubPullRequest:
+		prs, err := b.Client.ListPullRequests(repository)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			resources = append(resources, Resource{Type: resourceType, Scope: repository, Name: pr.GetHead().GetRef(), Description: pr.GetTitle(), Timestamp: pr.GetCreatedAt(), ID: int64(pr.GetNumber())})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+	return resources, nil
+}
+
+func (b *GithubBackend) Delete(_ context.Context, r Resource) error {
+	switch r.Type {
+	case GithubRepository:
+		return b.Client.DeleteRepository(&gh.Repository{Name: gh.String(r.Name)})
+	case GithubBranch:
+		return b.Client.DeleteRef(r.Scope, r.Name)
+	case GithubWebhook:
+		return b.Client.DeleteWebhook(r.Scope, r.ID)
+	case GithubPullRequest:
+		return b.Client.ClosePullRequest(r.Scope, int(r.ID))
+	}
+	return fmt.Errorf("unsupported resource type %s", r.Type)
+}
@@ -0,0 +1,272 @@
+package janitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ReportFile is the file in the artifact dir with the report of the last janitor run
+	ReportFile = "janitor-report.json"
+	// ReaperReportFile is the file in the artifact dir with the report of the last reaper run
+	ReaperReportFile = "reaper-report.json"
+
+	defaultWorkers = 10
+)
+
+// Action is what the janitor did with a resource selected by a rule
+type Action string
+
+const (
+	Deleted     Action = "deleted"
+	WouldDelete Action = "would-delete"
+	Excluded    Action = "excluded"
+	Failed      Action = "failed"
+)
+
+// Resource is a resource of any type known to the janitor
+type Resource struct {
+	Type ResourceType `json:"type"`
+	// Scope is the repository of branches, webhooks, pull requests and tags, or the namespace of namespaced resources
+	Scope       string `json:"scope,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Private     bool   `json:"private,omitempty"`
+	// Timestamp is the creation or last modification time, zero if unknown
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	// StaleReason is set by backends which can tell that the resource is not used anymore
+	StaleReason string `json:"staleReason,omitempty"`
+	// ID identifies resources which cannot be deleted by name, e.g. webhooks and pull requests
+	ID int64 `json:"id,omitempty"`
+}
+
+func (r Resource) String() string {
+	if r.Scope == "" {
+		return r.Name
+	}
+	return r.Scope + "/" + r.Name
+}
+
+func (r Resource) key() string {
+	return string(r.Type) + ":" + r.String()
+}
+
+// Backend lists and deletes resources of the types it is registered for
+type Backend interface {
+	List(ctx context.Context, resourceType ResourceType, scopes []string) ([]Resource, error)
+	Delete(ctx context.Context, resource Resource) error
+}
+
+// Janitor deletes resources selected by rules of its policy. With DryRun set, nothing is deleted,
+// the report just lists what would be deleted.
+type Janitor struct {
+	Policy *Policy
+	DryRun bool
+	// Workers is the number of resources deleted in parallel
+	Workers int
+
+	backends map[ResourceType]Backend
+	now      func() time.Time
+}
+
+// New returns a janitor for the policy, backends have to be registered before running it
+func New(policy *Policy, dryRun bool) *Janitor {
+	return &Janitor{Policy: policy, DryRun: dryRun, Workers: defaultWorkers, backends: map[ResourceType]Backend{}, now: time.Now}
+}
+
+// Register makes the backend responsible for resources of the given types
+func (j *Janitor) Register(backend Backend, types ...ResourceType) {
+	for _, t := range types {
+		j.backends[t] = backend
+	}
+}
+
+// Run evaluates the rules in order. A resource is handled by the first rule selecting it. Rules fail independently,
+// the returned error lists all failures while the report contains everything done before and after them.
+func (j *Janitor) Run(ctx context.Context) (*Report, error) {
+	report := &Report{DryRun: j.DryRun, Errors: map[string]string{}}
+	handled := map[string]bool{}
+	for _, rule := range j.Policy.Rules {
+		entries, err := j.runRule(ctx, rule, handled)
+		report.Entries = append(report.Entries, entries...)
+		if err != nil {
+			report.Errors[rule.Name] = err.Error()
+		}
+	}
+	return report, report.Err()
+}
+
+func (j *Janitor) runRule(ctx context.Context, rule *Rule, handled map[string]bool) ([]ReportEntry, error) {
+	backend, ok := j.backends[rule.Resource]
+	if !ok {
+		return nil, fmt.Errorf("no backend is registered for resources of type %s", rule.Resource)
+	}
+	resources, err := backend.List(ctx, rule.Resource, rule.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources of type %s: %v", rule.Resource, err)
+	}
+
+	now := j.now()
+	entries := []ReportEntry{}
+	for _, res := range resources {
+		if handled[res.key()] {
+			continue
+		}
+		action, reason, selected := rule.evaluate(res, now)
+		if !selected {
+			continue
+		}
+		handled[res.key()] = true
+		if action == Deleted && j.DryRun {
+			action = WouldDelete
+		}
+		entries = append(entries, ReportEntry{Rule: rule.Name, Resource: res, Action: action, Reason: reason})
+	}
+
+	if j.DryRun {
+		return entries, nil
+	}
+	return entries, j.delete(ctx, backend, entries)
+}
+
+// delete deletes resources of entries with the Deleted action in parallel and marks the failed ones
+func (j *Janitor) delete(ctx context.Context, backend Backend, entries []ReportEntry) error {
+	failed := deleteEntries(entries, j.Workers, func(entry *ReportEntry) error {
+		return backend.Delete(ctx, entry.Resource)
+	})
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d resource(s)", failed)
+	}
+	return nil
+}
+
+// deleteEntries calls del for entries with the Deleted action by workers in parallel. Entries of resources which
+// failed to be deleted are marked as failed, their number is returned.
+func deleteEntries(entries []ReportEntry, workers int, del func(entry *ReportEntry) error) int {
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				entry := &entries[i]
+				if err := del(entry); err != nil {
+					mu.Lock()
+					entry.Action, entry.Error = Failed, err.Error()
+					failed++
+					mu.Unlock()
+					continue
+				}
+				klog.Infof("deleted %s %s (%s)", entry.Resource.Type, entry.Resource, entry.Reason)
+			}
+		}()
+	}
+	for i := range entries {
+		if entries[i].Action == Deleted {
+			indexes <- i
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return failed
+}
+
+// ReportEntry records what the janitor did with a resource and why
+type ReportEntry struct {
+	// Rule is the rule of the janitor policy, or the stage of the reaper, which selected the resource
+	Rule     string   `json:"rule"`
+	Resource Resource `json:"resource"`
+	Action   Action   `json:"action"`
+	Reason   string   `json:"reason,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Report is the result of a janitor run
+type Report struct {
+	DryRun  bool          `json:"dryRun"`
+	Entries []ReportEntry `json:"entries"`
+	// Errors are errors of rules (or reaper stages) by their names
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Count returns the number of entries with the action
+func (r *Report) Count(action Action) int {
+	count := 0
+	for _, e := range r.Entries {
+		if e.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Err returns an error listing failed rules, or nil if all rules succeeded
+func (r *Report) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	rules := []string{}
+	for rule := range r.Errors {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	var errBuilder strings.Builder
+	for _, rule := range rules {
+		errBuilder.WriteString(fmt.Sprintf("\n%s: %s", rule, r.Errors[rule]))
+	}
+	return fmt.Errorf("cleanup failed:%s", errBuilder.String())
+}
+
+// Print writes the report as a table followed by a summary
+func (r *Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tTYPE\tRESOURCE\tACTION\tREASON")
+	for _, e := range r.Entries {
+		reason := e.Reason
+		if e.Error != "" {
+			reason = e.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Rule, e.Resource.Type, e.Resource, e.Action, reason)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("%d deleted, %d failed, %d excluded", r.Count(Deleted), r.Count(Failed), r.Count(Excluded))
+	if r.DryRun {
+		summary = fmt.Sprintf("dry run: %d would be deleted, %d excluded", r.Count(WouldDelete), r.Count(Excluded))
+	}
+	_, err := fmt.Fprintln(w, summary)
+	return err
+}
+
+// Write stores the report as the file in the dir
+func (r *Report) Write(dir, file string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %v", err)
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create dir %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), data, 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %v", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package janitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBackend keeps resources in memory, deleting resources listed in failing fails
+type fakeBackend struct {
+	mu        sync.Mutex
+	resources []Resource
+	deleted   []string
+	failing   map[string]bool
+	listErr   error
+}
+
+func (f *fakeBackend) List(_ context.Context, resourceType ResourceType, scopes []string) ([]Resource, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	resources := []Resource{}
+	for _, r := range f.resources {
+		if r.Type == resourceType && (len(scopes) == 0 || containsString(scopes, r.Scope)) {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+func (f *fakeBackend) Delete(_ context.Context, r Resource) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failing[r.Name] {
+		return fmt.Errorf("%s is locked", r.Name)
+	}
+	f.deleted = append(f.deleted, r.String())
+	return nil
+}
+
+func containsString(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
+
+const testPolicy = `
+rules:
+- name: keep-infra
+  resource: namespace
+  namePatterns: ["^openshift-", "^kube-"]
+  exclude: [".*"]
+  olderThan: 1h
+- name: e2e-namespaces
+  resource: namespace
+  olderThan: 24h
+- name: test-images-tags
+  resource: quay-tag
+  scopes: [test-images]
+  olderThan: 168h
+`
+
+var testNow = time.Date(2023, 11, 20, 12, 0, 0, 0, time.UTC)
+
+func newTestJanitor(t *testing.T, dryRun bool, backend *fakeBackend) *Janitor {
+	policy, err := LoadPolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+	j := New(policy, dryRun)
+	j.Register(backend, Namespace, QuayTag)
+	j.now = func() time.Time { return testNow }
+	return j
+}
+
+func testResources() []Resource {
+	return []Resource{
+		{Type: Namespace, Name: "openshift-gitops", Timestamp: testNow.AddDate(0, -1, 0)},
+		{Type: Namespace, Name: "build-e2e-abcd", Timestamp: testNow.Add(-48 * time.Hour)},
+		{Type: Namespace, Name: "has-e2e-efgh", Timestamp: testNow.Add(-25 * time.Hour)},
+		{Type: Namespace, Name: "spi-e2e-new", Timestamp: testNow.Add(-time.Hour)},
+		{Type: QuayTag, Scope: "test-images", Name: "old", Timestamp: testNow.AddDate(0, 0, -8)},
+		{Type: QuayTag, Scope: "test-images", Name: "new", Timestamp: testNow.AddDate(0, 0, -1)},
+		{Type: QuayTag, Scope: "other", Name: "old", Timestamp: testNow.AddDate(0, 0, -8)},
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	backend := &fakeBackend{resources: testResources()}
+	report, err := newTestJanitor(t, true, backend).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, backend.deleted)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []ReportEntry{
+		{Rule: "keep-infra", Resource: backend.resources[0], Action: Excluded, Reason: `excluded by ".*"`},
+		{Rule: "e2e-namespaces", Resource: backend.resources[1], Action: WouldDelete, Reason: "older than 24h0m0s"},
+		{Rule: "e2e-namespaces", Resource: backend.resources[2], Action: WouldDelete, Reason: "older than 24h0m0s"},
+		{Rule: "test-images-tags", Resource: backend.resources[4], Action: WouldDelete, Reason: "older than 168h0m0s"},
+	}, report.Entries)
+
+	out := &strings.Builder{}
+	assert.NoError(t, report.Print(out))
+	assert.Equal(t, `RULE              TYPE       RESOURCE          ACTION        REASON
+keep-infra        namespace  openshift-gitops  excluded      excluded by ".*"
+e2e-namespaces    namespace  build-e2e-abcd    would-delete  older than 24h0m0s
+e2e-namespaces    namespace  has-e2e-efgh      would-delete  older than 24h0m0s
+test-images-tags  quay-tag   test-images/old   would-delete  older than 168h0m0s
+dry run: 3 would be deleted, 1 excluded
+`, out.String())
+}
+
+func TestRun(t *testing.T) {
+	backend := &fakeBackend{resources: testResources(), failing: map[string]bool{"has-e2e-efgh": true}}
+	j := newTestJanitor(t, false, backend)
+	j.Workers = 2
+	report, err := j.Run(context.Background())
+	assert.EqualError(t, err, "cleanup failed:\ne2e-namespaces: failed to delete 1 resource(s)")
+
+	sort.Strings(backend.deleted)
+	assert.Equal(t, []string{"build-e2e-abcd", "test-images/old"}, backend.deleted)
+	assert.Equal(t, 2, report.Count(Deleted))
+	assert.Equal(t, 1, report.Count(Excluded))
+	assert.Equal(t, ReportEntry{Rule: "e2e-namespaces", Resource: backend.resources[2], Action: Failed, Reason: "older than 24h0m0s", Error: "has-e2e-efgh is locked"}, report.Entries[2])
+
+	out := &strings.Builder{}
+	assert.NoError(t, report.Print(out))
+	assert.Contains(t, out.String(), "has-e2e-efgh      failed    has-e2e-efgh is locked\n")
+	assert.True(t, strings.HasSuffix(out.String(), "2 deleted, 1 failed, 1 excluded\n"))
+
+	dir := t.TempDir()
+	assert.NoError(t, report.Write(dir, ReportFile))
+	data, err := os.ReadFile(filepath.Join(dir, ReportFile))
+	assert.NoError(t, err)
+	written := &Report{}
+	assert.NoError(t, json.Unmarshal(data, written))
+	assert.Equal(t, report, written)
+}
+
+func TestRunFailingRules(t *testing.T) {
+	policy, err := LoadPolicy([]byte(testPolicy + `
+- name: e2e-usersignups
+  resource: usersignup
+  olderThan: 24h
+`))
+	assert.NoError(t, err)
+	j := New(policy, false)
+	j.now = func() time.Time { return testNow }
+	j.Register(&fakeBackend{listErr: fmt.Errorf("connection refused")}, Namespace)
+	j.Register(&fakeBackend{resources: testResources()}, QuayTag)
+
+	report, err := j.Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, map[string]string{
+		"keep-infra":      "failed to list resources of type namespace: connection refused",
+		"e2e-namespaces":  "failed to list resources of type namespace: connection refused",
+		"e2e-usersignups": "no backend is registered for resources of type usersignup",
+	}, report.Errors)
+	// other rules still run
+	assert.Equal(t, 1, report.Count(Deleted))
+}
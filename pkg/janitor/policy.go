@@ -0,0 +1,206 @@
+package janitor
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+)
+
+// ResourceType is a type of resources cleaned up by the janitor
+type ResourceType string
+
+const (
+	GithubRepository  ResourceType = "github-repository"
+	GithubBranch      ResourceType = "github-branch"
+	GithubWebhook     ResourceType = "github-webhook"
+	GithubPullRequest ResourceType = "github-pull-request"
+	QuayRepository    ResourceType = "quay-repository"
+	QuayTag           ResourceType = "quay-tag"
+	QuayRobot         ResourceType = "quay-robot"
+	SprayProxyBackend ResourceType = "sprayproxy-backend"
+	Namespace         ResourceType = "namespace"
+	UserSignup        ResourceType = "usersignup"
+)
+
+// scopedTypes are types of resources which belong to a repository, rules of these types have to list the repositories
+var scopedTypes = []ResourceType{GithubBranch, GithubWebhook, GithubPullRequest, QuayTag}
+
+var resourceTypes = []ResourceType{GithubRepository, GithubBranch, GithubWebhook, GithubPullRequest, QuayRepository, QuayTag, QuayRobot, SprayProxyBackend, Namespace, UserSignup}
+
+// Policy declares which resources the janitor deletes
+type Policy struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule selects resources of one type to delete. A resource is selected if its name (or description) matches any
+// of the patterns, it's not excluded and it's older than the threshold or stale.
+type Rule struct {
+	Name     string       `json:"name"`
+	Resource ResourceType `json:"resource"`
+	// Scopes are repositories of branches, webhooks, pull requests and tags
+	Scopes []string `json:"scopes,omitempty"`
+	// NamePatterns are regular expressions matching names of the resources, all names match if there are no patterns
+	NamePatterns []string `json:"namePatterns,omitempty"`
+	// DescriptionPatterns are regular expressions matching descriptions of Github repositories and titles of pull requests
+	DescriptionPatterns []string `json:"descriptionPatterns,omitempty"`
+	// Exclude are regular expressions matching names of resources which are never deleted
+	Exclude []string `json:"exclude,omitempty"`
+	// OlderThan is the age threshold, resources are as old as their creation or last modification (Quay repositories)
+	OlderThan metav1.Duration `json:"olderThan,omitempty"`
+	// StaleOnly selects only resources detected as stale, e.g. SprayProxy backends of clusters which are gone
+	StaleOnly bool `json:"staleOnly,omitempty"`
+	// PrivateOnly selects only private Quay repositories
+	PrivateOnly bool `json:"privateOnly,omitempty"`
+
+	namePatterns        []*regexp.Regexp
+	descriptionPatterns []*regexp.Regexp
+	exclude             []*regexp.Regexp
+}
+
+// LoadPolicy parses and validates a policy
+func LoadPolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("error when parsing janitor policy: %v", err)
+	}
+	names := map[string]bool{}
+	for _, r := range policy.Rules {
+		if r.Name == "" || names[r.Name] {
+			return nil, fmt.Errorf("rules of janitor policy need unique names, got %q", r.Name)
+		}
+		names[r.Name] = true
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("invalid rule %s: %v", r.Name, err)
+		}
+	}
+	return policy, nil
+}
+
+// Select returns a policy with the named rules only
+func (p *Policy) Select(names ...string) (*Policy, error) {
+	selected := &Policy{}
+	for _, name := range names {
+		rule := p.Rule(name)
+		if rule == nil {
+			return nil, fmt.Errorf("janitor policy has no rule %s", name)
+		}
+		selected.Rules = append(selected.Rules, rule)
+	}
+	return selected, nil
+}
+
+// Rule returns the named rule, or nil if there is none
+func (p *Policy) Rule(name string) *Rule {
+	for _, r := range p.Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+func (r *Rule) compile() error {
+	if !containsType(resourceTypes, r.Resource) {
+		return fmt.Errorf("unknown resource type %q", r.Resource)
+	}
+	if containsType(scopedTypes, r.Resource) && len(r.Scopes) == 0 {
+		return fmt.Errorf("resources of type %s need scopes", r.Resource)
+	}
+	if r.OlderThan.Duration <= 0 && !r.StaleOnly {
+		return fmt.Errorf("either olderThan or staleOnly has to be set")
+	}
+	var err error
+	if r.namePatterns, err = compilePatterns(r.NamePatterns); err != nil {
+		return err
+	}
+	if r.descriptionPatterns, err = compilePatterns(r.DescriptionPatterns); err != nil {
+		return err
+	}
+	r.exclude, err = compilePatterns(r.Exclude)
+	return err
+}
+
+// SetNamePatterns replaces name patterns of the rule
+func (r *Rule) SetNamePatterns(patterns ...string) error {
+	compiled, err := compilePatterns(patterns)
+	if err != nil {
+		return err
+	}
+	r.NamePatterns, r.namePatterns = patterns, compiled
+	return nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := []*regexp.Regexp{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func containsType(types []ResourceType, t ResourceType) bool {
+	return utils.Contains(resourceTypeStrings(types), string(t))
+}
+
+func resourceTypeStrings(types []ResourceType) []string {
+	s := []string{}
+	for _, t := range types {
+		s = append(s, string(t))
+	}
+	return s
+}
+
+// evaluate decides what to do with the resource. It returns false if the rule doesn't select the resource.
+func (r *Rule) evaluate(res Resource, now time.Time) (Action, string, bool) {
+	if !r.matches(res) || (r.PrivateOnly && !res.Private) {
+		return "", "", false
+	}
+	for _, re := range r.exclude {
+		if re.MatchString(res.Name) {
+			return Excluded, fmt.Sprintf("excluded by %q", re), true
+		}
+	}
+	reason := ""
+	if r.StaleOnly {
+		if res.StaleReason == "" {
+			return "", "", false
+		}
+		reason = "stale: " + res.StaleReason
+	}
+	if r.OlderThan.Duration > 0 {
+		if res.Timestamp.IsZero() || now.Sub(res.Timestamp) <= r.OlderThan.Duration {
+			return "", "", false
+		}
+		if reason != "" {
+			reason += ", "
+		}
+		reason += fmt.Sprintf("older than %s", r.OlderThan.Duration)
+	}
+	return Deleted, reason, true
+}
+
+func (r *Rule) matches(res Resource) bool {
+	if len(r.namePatterns) == 0 && len(r.descriptionPatterns) == 0 {
+		return true
+	}
+	for _, re := range r.namePatterns {
+		if re.MatchString(res.Name) {
+			return true
+		}
+	}
+	for _, re := range r.descriptionPatterns {
+		if res.Description != "" && re.MatchString(res.Description) {
+			return true
+		}
+	}
+	return false
+}
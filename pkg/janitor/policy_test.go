@@ -0,0 +1,104 @@
+package janitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	policy, err := LoadPolicy([]byte(`
+rules:
+- name: gitops-repositories
+  resource: github-repository
+  namePatterns: ["^e2e-", "petclinic"]
+  descriptionPatterns: ["^GitOps Repository$"]
+  exclude: ["^e2e-tests$"]
+  olderThan: 24h
+- name: test-images-tags
+  resource: quay-tag
+  scopes: [test-images]
+  olderThan: 168h
+- name: stale-backends
+  resource: sprayproxy-backend
+  staleOnly: true
+`))
+	assert.NoError(t, err)
+	assert.Len(t, policy.Rules, 3)
+	assert.Equal(t, 24*time.Hour, policy.Rule("gitops-repositories").OlderThan.Duration)
+	assert.Nil(t, policy.Rule("unknown"))
+
+	selected, err := policy.Select("stale-backends", "test-images-tags")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Rule{policy.Rules[2], policy.Rules[1]}, selected.Rules)
+	_, err = policy.Select("unknown")
+	assert.EqualError(t, err, "janitor policy has no rule unknown")
+
+	for policy, expectedErr := range map[string]string{
+		"rules: [{name: a, resource: github-repository, olderThan: 1h}, {name: a, resource: quay-robot, olderThan: 1h}]": `rules of janitor policy need unique names, got "a"`,
+		"rules: [{resource: github-repository, olderThan: 1h}]":                                                          `rules of janitor policy need unique names, got ""`,
+		"rules: [{name: a, resource: gitlab-repository, olderThan: 1h}]":                                                 `invalid rule a: unknown resource type "gitlab-repository"`,
+		"rules: [{name: a, resource: github-webhook, olderThan: 1h}]":                                                    "invalid rule a: resources of type github-webhook need scopes",
+		"rules: [{name: a, resource: namespace}]":                                                                        "invalid rule a: either olderThan or staleOnly has to be set",
+		"rules: [{name: a, resource: namespace, olderThan: 1h, exclude: ['(']}]":                                         "invalid rule a: invalid pattern \"(\": error parsing regexp: missing closing ): `(`",
+	} {
+		_, err := LoadPolicy([]byte(policy))
+		assert.EqualError(t, err, expectedErr, policy)
+	}
+	_, err = LoadPolicy([]byte("rules: [{name: a, resource: namespace, olderThan: 1h, maxAge: 1h}]"))
+	assert.ErrorContains(t, err, "error when parsing janitor policy")
+}
+
+func TestRuleEvaluate(t *testing.T) {
+	now := time.Now()
+	policy, err := LoadPolicy([]byte(`
+rules:
+- name: repositories
+  resource: github-repository
+  namePatterns: ["^e2e-"]
+  descriptionPatterns: ["^GitOps Repository$"]
+  exclude: ["-keep$"]
+  olderThan: 24h
+- name: private-repositories
+  resource: quay-repository
+  privateOnly: true
+  olderThan: 1h
+- name: stale-backends
+  resource: sprayproxy-backend
+  staleOnly: true
+`))
+	assert.NoError(t, err)
+	old := now.Add(-25 * time.Hour)
+
+	for _, tc := range []struct {
+		rule     string
+		resource Resource
+		action   Action
+		reason   string
+		selected bool
+	}{
+		{"repositories", Resource{Name: "e2e-app", Timestamp: old}, Deleted, "older than 24h0m0s", true},
+		{"repositories", Resource{Name: "devfile-sample", Description: "GitOps Repository", Timestamp: old}, Deleted, "older than 24h0m0s", true},
+		{"repositories", Resource{Name: "e2e-app", Timestamp: now.Add(-time.Hour)}, "", "", false},
+		{"repositories", Resource{Name: "e2e-app"}, "", "", false},
+		{"repositories", Resource{Name: "my-tests", Timestamp: old}, "", "", false},
+		{"repositories", Resource{Name: "e2e-app-keep", Timestamp: now}, Excluded, `excluded by "-keep$"`, true},
+		{"private-repositories", Resource{Name: "build-e2e/app", Private: true, Timestamp: old}, Deleted, "older than 1h0m0s", true},
+		{"private-repositories", Resource{Name: "build-e2e/app", Timestamp: old}, "", "", false},
+		{"stale-backends", Resource{Name: "https://pac.example.com", StaleReason: "cluster is gone"}, Deleted, "stale: cluster is gone", true},
+		{"stale-backends", Resource{Name: "https://pac.example.com"}, "", "", false},
+	} {
+		action, reason, selected := policy.Rule(tc.rule).evaluate(tc.resource, now)
+		assert.Equal(t, tc.selected, selected, "%s: %s", tc.rule, tc.resource)
+		assert.Equal(t, tc.action, action, "%s: %s", tc.rule, tc.resource)
+		assert.Equal(t, tc.reason, reason, "%s: %s", tc.rule, tc.resource)
+	}
+
+	assert.NoError(t, policy.Rule("repositories").SetNamePatterns("^test-"))
+	_, _, selected := policy.Rule("repositories").evaluate(Resource{Name: "e2e-app", Timestamp: old}, now)
+	assert.False(t, selected)
+	_, _, selected = policy.Rule("repositories").evaluate(Resource{Name: "test-app", Timestamp: old}, now)
+	assert.True(t, selected)
+	assert.Error(t, policy.Rule("repositories").SetNamePatterns("("))
+}
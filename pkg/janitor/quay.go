@@ -0,0 +1,122 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/image-controller/pkg/quay"
+)
+
+// quayTimeFormat is the format of creation times of robot accounts returned by Quay
+const quayTimeFormat = "Mon, 02 Jan 2006 15:04:05 -0700"
+
+// QuayBackend handles repositories, tags and robot accounts of a Quay organization.
+// Robot accounts are listed by their short names, without the organization prefix.
+type QuayBackend struct {
+	Client       quay.QuayService
+	Organization string
+}
+
+// QuayResourceTypes are the types handled by QuayBackend
+var QuayResourceTypes = []ResourceType{QuayRepository, QuayTag, QuayRobot}
+
+func (b *QuayBackend) List(_ context.Context, resourceType ResourceType, scopes []string) ([]Resource, error) {
+	resources := []Resource{}
+	switch resourceType {
+	case QuayRepository:
+		repos, err := b.Client.GetAllRepositories(b.Organization)
+		if err != nil {
+			return nil, err
+		}
+		var robots []Resource
+		for _, repo := range repos {
+			var modified time.Time
+			if repo.LastModified != 0 {
+				modified = time.Unix(int64(repo.LastModified), 0)
+			} else {
+				// Quay doesn't return the last modification time of repositories without any pushed tag, the creation time
+				// of the robot account created with the repository is used instead. Repositories without it are kept.
+				if robots == nil {
+					if robots, err = b.listRobots(); err != nil {
+						return nil, err
+					}
+				}
+				robotName := strings.ReplaceAll(repo.Name, "/", "")
+				for _, robot := range robots {
+					if robot.Name == robotName {
+						modified = robot.Timestamp
+					}
+				}
+			}
+			resources = append(resources, Resource{Type: resourceType, Name: repo.Name, Description: repo.Description, Private: !repo.IsPublic, Timestamp: modified})
+		}
+	case QuayRobot:
+		robots, err := b.listRobots()
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, robots...)
+	case QuayTag:
+		for _, repository := range scopes {
+			tags, err := b.listTags(repository)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, tags...)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+	return resources, nil
+}
+
+func (b *QuayBackend) listRobots() ([]Resource, error) {
+	robots, err := b.Client.GetAllRobotAccounts(b.Organization)
+	if err != nil {
+		return nil, err
+	}
+	resources := []Resource{}
+	for _, robot := range robots {
+		created, err := time.Parse(quayTimeFormat, robot.Created)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse creation time of robot account %s: %v", robot.Name, err)
+		}
+		name := strings.TrimPrefix(robot.Name, b.Organization+"+")
+		resources = append(resources, Resource{Type: QuayRobot, Name: name, Description: robot.Description, Timestamp: created})
+	}
+	return resources, nil
+}
+
+func (b *QuayBackend) listTags(repository string) ([]Resource, error) {
+	resources := []Resource{}
+	for page := 1; ; page++ {
+		tags, hasAdditional, err := b.Client.GetTagsFromPage(b.Organization, repository, page)
+		if err != nil {
+			return nil, fmt.Errorf("error getting tags of `%s` repository of `%s` organization on page `%d`, error: %s", repository, b.Organization, page, err)
+		}
+		for _, tag := range tags {
+			resources = append(resources, Resource{Type: QuayTag, Scope: repository, Name: tag.Name, Timestamp: time.Unix(tag.StartTS, 0)})
+		}
+		if !hasAdditional {
+			return resources, nil
+		}
+	}
+}
+
+// Delete deletes the resource, resources which have already been deleted are not reported as failures
+func (b *QuayBackend) Delete(_ context.Context, r Resource) error {
+	var err error
+	switch r.Type {
+	case QuayRepository:
+		_, err = b.Client.DeleteRepository(b.Organization, r.Name)
+	case QuayRobot:
+		_, err = b.Client.DeleteRobotAccount(b.Organization, r.Name)
+	case QuayTag:
+		_, err = b.Client.DeleteTag(b.Organization, r.Scope, r.Name)
+	default:
+		err = fmt.Errorf("unsupported resource type %s", r.Type)
+	}
+	return err
+}
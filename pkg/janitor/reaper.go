@@ -0,0 +1,313 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox"
+)
+
+// Types of resources handled only by the reaper
+const (
+	Environment           ResourceType = "environment"
+	DeploymentTargetClaim ResourceType = "deploymenttargetclaim"
+)
+
+// Stages of the reaper, dependents are deleted before the resources they live in
+const (
+	deploymentTargetClaimsStage = "deploymenttargetclaims"
+	environmentsStage           = "environments"
+	namespacesStage             = "namespaces"
+	userSignupsStage            = "usersignups"
+)
+
+const (
+	defaultFinalizerTimeout = 5 * time.Minute
+	defaultPollInterval     = 5 * time.Second
+
+	// tenantNamespaceSuffix is appended to the compliant username of a UserSignup to get its tenant namespace
+	tenantNamespaceSuffix = "-tenant"
+)
+
+// NamespaceDeleter deletes a namespace and waits until it's gone, e.g. common.SuiteController
+type NamespaceDeleter interface {
+	DeleteNamespace(name string) error
+}
+
+// UserSignupDeleter deletes a UserSignup and waits until it's gone, e.g. sandbox.SandboxController
+type UserSignupDeleter interface {
+	DeleteUserSignup(userName string) (bool, error)
+}
+
+// GitopsDeleter deletes Environments and DeploymentTargetClaims, e.g. gitops.GitopsController
+type GitopsDeleter interface {
+	DeleteEnvironment(environment *appservice.Environment) error
+	DeleteDeploymentTargetClaim(deploymentTargetClaim *appservice.DeploymentTargetClaim) error
+}
+
+// Reaper deletes cluster resources left behind by aborted e2e runs. It selects UserSignups, namespaces and
+// Environments labelled by the framework (see constants.E2ETestLabelKey) which are older than TTL, together with
+// Environments and DeploymentTargetClaims in the namespaces being deleted. Resources are deleted in dependency order,
+// a namespace or UserSignup is kept if resources in it could not be deleted.
+type Reaper struct {
+	Client      crclient.Client
+	Namespaces  NamespaceDeleter
+	UserSignups UserSignupDeleter
+	Gitops      GitopsDeleter
+	TTL         time.Duration
+	DryRun      bool
+	// FinalizerTimeout is how long to wait for a deleted Environment or DeploymentTargetClaim to be gone
+	// before its finalizers are removed
+	FinalizerTimeout time.Duration
+	// Workers is the number of resources deleted in parallel
+	Workers int
+
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+// NewReaper returns a reaper of resources older than the ttl
+func NewReaper(client crclient.Client, namespaces NamespaceDeleter, userSignups UserSignupDeleter, gitops GitopsDeleter, ttl time.Duration, dryRun bool) *Reaper {
+	return &Reaper{
+		Client:           client,
+		Namespaces:       namespaces,
+		UserSignups:      userSignups,
+		Gitops:           gitops,
+		TTL:              ttl,
+		DryRun:           dryRun,
+		FinalizerTimeout: defaultFinalizerTimeout,
+		Workers:          defaultWorkers,
+		pollInterval:     defaultPollInterval,
+		now:              time.Now,
+	}
+}
+
+// reaperPlan are the entries of all stages
+type reaperPlan struct {
+	claims, environments, namespaces, userSignups []ReportEntry
+	// tenantNamespaces are tenant namespaces of UserSignups by the UserSignup names
+	tenantNamespaces map[string]string
+}
+
+// namespaceOf returns the namespace which has to be emptied before the namespace or UserSignup is deleted
+func (p *reaperPlan) namespaceOf(r Resource) string {
+	switch r.Type {
+	case Namespace:
+		return r.Name
+	case UserSignup:
+		return p.tenantNamespaces[r.Name]
+	}
+	return ""
+}
+
+// Run deletes stale resources stage by stage. Stages fail independently, the returned error lists all failures
+// while the report contains everything done before and after them.
+func (r *Reaper) Run(ctx context.Context) (*Report, error) {
+	report := &Report{DryRun: r.DryRun, Errors: map[string]string{}}
+	plan, err := r.plan(ctx)
+	if err != nil {
+		report.Errors["plan"] = err.Error()
+		return report, report.Err()
+	}
+
+	stages := []struct {
+		name    string
+		entries []ReportEntry
+		del     func(ctx context.Context, entry *ReportEntry) error
+	}{
+		{deploymentTargetClaimsStage, plan.claims, r.deleteDeploymentTargetClaim},
+		{environmentsStage, plan.environments, r.deleteEnvironment},
+		{namespacesStage, plan.namespaces, r.deleteNamespace},
+		{userSignupsStage, plan.userSignups, r.deleteUserSignup},
+	}
+	// failedNamespaces are namespaces with resources which were not deleted
+	failedNamespaces := map[string]bool{}
+	for _, stage := range stages {
+		failed := 0
+		for i := range stage.entries {
+			entry := &stage.entries[i]
+			if namespace := plan.namespaceOf(entry.Resource); entry.Action == Deleted && failedNamespaces[namespace] {
+				entry.Action, entry.Error = Failed, fmt.Sprintf("resources in namespace %s were not deleted", namespace)
+				failed++
+			}
+		}
+		failed += deleteEntries(stage.entries, r.Workers, func(entry *ReportEntry) error {
+			return stage.del(ctx, entry)
+		})
+		for _, entry := range stage.entries {
+			if entry.Action == Failed && entry.Resource.Scope != "" {
+				failedNamespaces[entry.Resource.Scope] = true
+			}
+		}
+		if failed > 0 {
+			report.Errors[stage.name] = fmt.Sprintf("failed to delete %d resource(s)", failed)
+		}
+		report.Entries = append(report.Entries, stage.entries...)
+	}
+	return report, report.Err()
+}
+
+func (r *Reaper) plan(ctx context.Context) (*reaperPlan, error) {
+	now := r.now()
+	plan := &reaperPlan{tenantNamespaces: map[string]string{}}
+	action := Deleted
+	if r.DryRun {
+		action = WouldDelete
+	}
+	ttlReason := fmt.Sprintf("older than %s", r.TTL)
+	labelled := crclient.MatchingLabels{constants.E2ETestLabelKey: constants.E2ETestLabelValue}
+
+	// reapedNamespaces are namespaces whose Environments and DeploymentTargetClaims are deleted, with the reason
+	reapedNamespaces := map[string]string{}
+	userSignups := &toolchainApi.UserSignupList{}
+	if err := r.Client.List(ctx, userSignups, crclient.InNamespace(sandbox.DEFAULT_TOOLCHAIN_NAMESPACE), labelled); err != nil {
+		return nil, fmt.Errorf("failed to list UserSignups: %v", err)
+	}
+	for _, us := range userSignups.Items {
+		if !r.isStale(us.ObjectMeta, now) {
+			continue
+		}
+		if us.Status.CompliantUsername != "" {
+			tenantNamespace := us.Status.CompliantUsername + tenantNamespaceSuffix
+			plan.tenantNamespaces[us.Name] = tenantNamespace
+			reapedNamespaces[tenantNamespace] = fmt.Sprintf("in tenant namespace of UserSignup %s", us.Name)
+		}
+		plan.userSignups = append(plan.userSignups, ReportEntry{
+			Rule: userSignupsStage, Action: action, Reason: ttlReason,
+			Resource: Resource{Type: UserSignup, Name: us.Name, Description: plan.tenantNamespaces[us.Name], Timestamp: us.CreationTimestamp.Time},
+		})
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.Client.List(ctx, namespaces, labelled); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %v", err)
+	}
+	for _, ns := range namespaces.Items {
+		// tenant namespaces are deleted together with their UserSignups
+		if _, ok := reapedNamespaces[ns.Name]; ok || !r.isStale(ns.ObjectMeta, now) {
+			continue
+		}
+		reapedNamespaces[ns.Name] = fmt.Sprintf("in reaped namespace %s", ns.Name)
+		plan.namespaces = append(plan.namespaces, ReportEntry{
+			Rule: namespacesStage, Action: action, Reason: ttlReason,
+			Resource: Resource{Type: Namespace, Name: ns.Name, Timestamp: ns.CreationTimestamp.Time},
+		})
+	}
+
+	// claimed are DeploymentTargetClaims of reaped Environments with the reason
+	claimed := map[string]string{}
+	environments := &appservice.EnvironmentList{}
+	if err := r.Client.List(ctx, environments); err != nil {
+		return nil, fmt.Errorf("failed to list Environments: %v", err)
+	}
+	for _, env := range environments.Items {
+		reason, ok := reapedNamespaces[env.Namespace]
+		if !ok {
+			if env.Labels[constants.E2ETestLabelKey] != constants.E2ETestLabelValue || !r.isStale(env.ObjectMeta, now) {
+				continue
+			}
+			reason = ttlReason
+		}
+		if claim := env.GetDeploymentTargetClaimName(); claim != "" {
+			claimed[env.Namespace+"/"+claim] = fmt.Sprintf("claimed by reaped Environment %s", env.Name)
+		}
+		plan.environments = append(plan.environments, ReportEntry{
+			Rule: environmentsStage, Action: action, Reason: reason,
+			Resource: Resource{Type: Environment, Scope: env.Namespace, Name: env.Name, Timestamp: env.CreationTimestamp.Time},
+		})
+	}
+
+	claims := &appservice.DeploymentTargetClaimList{}
+	if err := r.Client.List(ctx, claims); err != nil {
+		return nil, fmt.Errorf("failed to list DeploymentTargetClaims: %v", err)
+	}
+	for _, dtc := range claims.Items {
+		reason, ok := reapedNamespaces[dtc.Namespace]
+		if !ok {
+			if reason, ok = claimed[dtc.Namespace+"/"+dtc.Name]; !ok {
+				continue
+			}
+		}
+		plan.claims = append(plan.claims, ReportEntry{
+			Rule: deploymentTargetClaimsStage, Action: action, Reason: reason,
+			Resource: Resource{Type: DeploymentTargetClaim, Scope: dtc.Namespace, Name: dtc.Name, Timestamp: dtc.CreationTimestamp.Time},
+		})
+	}
+
+	for _, entries := range [][]ReportEntry{plan.claims, plan.environments, plan.namespaces, plan.userSignups} {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Resource.String() < entries[j].Resource.String() })
+	}
+	return plan, nil
+}
+
+func (r *Reaper) isStale(obj metav1.ObjectMeta, now time.Time) bool {
+	return now.Sub(obj.CreationTimestamp.Time) > r.TTL
+}
+
+func (r *Reaper) deleteDeploymentTargetClaim(ctx context.Context, entry *ReportEntry) error {
+	dtc := &appservice.DeploymentTargetClaim{ObjectMeta: metav1.ObjectMeta{Name: entry.Resource.Name, Namespace: entry.Resource.Scope}}
+	if err := r.Gitops.DeleteDeploymentTargetClaim(dtc); err != nil {
+		return err
+	}
+	return r.waitForDeletion(ctx, entry, dtc)
+}
+
+func (r *Reaper) deleteEnvironment(ctx context.Context, entry *ReportEntry) error {
+	env := &appservice.Environment{ObjectMeta: metav1.ObjectMeta{Name: entry.Resource.Name, Namespace: entry.Resource.Scope}}
+	if err := r.Gitops.DeleteEnvironment(env); err != nil {
+		return err
+	}
+	return r.waitForDeletion(ctx, entry, env)
+}
+
+func (r *Reaper) deleteNamespace(_ context.Context, entry *ReportEntry) error {
+	return r.Namespaces.DeleteNamespace(entry.Resource.Name)
+}
+
+func (r *Reaper) deleteUserSignup(_ context.Context, entry *ReportEntry) error {
+	if _, err := r.UserSignups.DeleteUserSignup(entry.Resource.Name); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// waitForDeletion waits until the deleted object is gone. Finalizers of objects which are still there after
+// FinalizerTimeout are removed, e.g. when the controller handling them is not running anymore.
+func (r *Reaper) waitForDeletion(ctx context.Context, entry *ReportEntry, obj crclient.Object) error {
+	key := crclient.ObjectKeyFromObject(obj)
+	err := wait.PollUntilContextTimeout(ctx, r.pollInterval, r.FinalizerTimeout, true, func(ctx context.Context) (bool, error) {
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return false, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if !wait.Interrupted(err) {
+		return fmt.Errorf("failed to wait for deletion: %v", err)
+	}
+
+	finalizers := obj.GetFinalizers()
+	patch := crclient.MergeFrom(obj.DeepCopyObject().(crclient.Object))
+	obj.SetFinalizers(nil)
+	if err := r.Client.Patch(ctx, obj, patch); err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("not deleted in %s, failed to remove finalizers %v: %v", r.FinalizerTimeout, finalizers, err)
+	}
+	entry.Reason += fmt.Sprintf(", finalizers %v removed", finalizers)
+	return nil
+}
@@ -0,0 +1,157 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox"
+)
+
+// fakeDeleters delete resources from the fake client without waiting, deleting resources listed in failing fails
+type fakeDeleters struct {
+	client  crclient.Client
+	mu      sync.Mutex
+	deleted []string
+	failing map[string]bool
+}
+
+func (f *fakeDeleters) delete(obj crclient.Object) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failing[obj.GetName()] {
+		return fmt.Errorf("%s is locked", obj.GetName())
+	}
+	if err := f.client.Delete(context.Background(), obj); err != nil {
+		return err
+	}
+	f.deleted = append(f.deleted, obj.GetName())
+	return nil
+}
+
+func (f *fakeDeleters) DeleteNamespace(name string) error {
+	return f.delete(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+}
+
+func (f *fakeDeleters) DeleteUserSignup(userName string) (bool, error) {
+	err := f.delete(&toolchainApi.UserSignup{ObjectMeta: metav1.ObjectMeta{Name: userName, Namespace: sandbox.DEFAULT_TOOLCHAIN_NAMESPACE}})
+	return err == nil, err
+}
+
+func (f *fakeDeleters) DeleteEnvironment(environment *appservice.Environment) error {
+	return f.delete(environment)
+}
+
+func (f *fakeDeleters) DeleteDeploymentTargetClaim(deploymentTargetClaim *appservice.DeploymentTargetClaim) error {
+	return f.delete(deploymentTargetClaim)
+}
+
+func newTestReaper(t *testing.T, dryRun bool, failing ...string) (*Reaper, *fakeDeleters) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, toolchainApi.AddToScheme(scheme))
+	assert.NoError(t, appservice.AddToScheme(scheme))
+
+	old, recent := metav1.NewTime(testNow.Add(-48*time.Hour)), metav1.NewTime(testNow.Add(-time.Hour))
+	labels := map[string]string{constants.E2ETestLabelKey: constants.E2ETestLabelValue}
+	meta := func(name, namespace string, created metav1.Time, labels map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: created, Labels: labels}
+	}
+	toolchainNs := sandbox.DEFAULT_TOOLCHAIN_NAMESPACE
+	stuckClaim := &appservice.DeploymentTargetClaim{ObjectMeta: meta("stuck-dtc", "build-e2e-abcd", old, nil)}
+	stuckClaim.Finalizers = []string{"dtc.finalizer.appstudio.redhat.com"}
+	ephemeral := &appservice.Environment{ObjectMeta: meta("ephemeral", "spi-e2e-new", old, labels)}
+	ephemeral.Spec.Configuration.Target.DeploymentTargetClaim.ClaimName = "ephemeral-dtc"
+
+	client := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&toolchainApi.UserSignup{ObjectMeta: meta("e2e-old", toolchainNs, old, labels), Status: toolchainApi.UserSignupStatus{CompliantUsername: "e2e-old"}},
+		&toolchainApi.UserSignup{ObjectMeta: meta("e2e-new", toolchainNs, recent, labels), Status: toolchainApi.UserSignupStatus{CompliantUsername: "e2e-new"}},
+		&toolchainApi.UserSignup{ObjectMeta: meta("real-user", toolchainNs, old, nil)},
+		&corev1.Namespace{ObjectMeta: meta("build-e2e-abcd", "", old, labels)},
+		&corev1.Namespace{ObjectMeta: meta("spi-e2e-new", "", recent, labels)},
+		&corev1.Namespace{ObjectMeta: meta("openshift-gitops", "", old, nil)},
+		&corev1.Namespace{ObjectMeta: meta("e2e-old-tenant", "", old, nil)},
+		&appservice.Environment{ObjectMeta: meta("development", "build-e2e-abcd", old, nil)},
+		&appservice.Environment{ObjectMeta: meta("production", "e2e-old-tenant", old, nil)},
+		&appservice.Environment{ObjectMeta: meta("development", "spi-e2e-new", old, nil)},
+		ephemeral,
+		&appservice.DeploymentTargetClaim{ObjectMeta: meta("ephemeral-dtc", "spi-e2e-new", old, nil)},
+		&appservice.DeploymentTargetClaim{ObjectMeta: meta("other-dtc", "spi-e2e-new", old, nil)},
+		stuckClaim,
+	).Build()
+
+	deleters := &fakeDeleters{client: client, failing: map[string]bool{}}
+	for _, name := range failing {
+		deleters.failing[name] = true
+	}
+	r := NewReaper(client, deleters, deleters, deleters, 24*time.Hour, dryRun)
+	r.FinalizerTimeout, r.pollInterval = 50*time.Millisecond, 10*time.Millisecond
+	r.now = func() time.Time { return testNow }
+	return r, deleters
+}
+
+func TestReaperDryRun(t *testing.T) {
+	r, deleters := newTestReaper(t, true)
+	report, err := r.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, deleters.deleted)
+
+	out := &strings.Builder{}
+	assert.NoError(t, report.Print(out))
+	assert.Equal(t, `RULE                    TYPE                   RESOURCE                    ACTION        REASON
+deploymenttargetclaims  deploymenttargetclaim  build-e2e-abcd/stuck-dtc    would-delete  in reaped namespace build-e2e-abcd
+deploymenttargetclaims  deploymenttargetclaim  spi-e2e-new/ephemeral-dtc   would-delete  claimed by reaped Environment ephemeral
+environments            environment            build-e2e-abcd/development  would-delete  in reaped namespace build-e2e-abcd
+environments            environment            e2e-old-tenant/production   would-delete  in tenant namespace of UserSignup e2e-old
+environments            environment            spi-e2e-new/ephemeral       would-delete  older than 24h0m0s
+namespaces              namespace              build-e2e-abcd              would-delete  older than 24h0m0s
+usersignups             usersignup             e2e-old                     would-delete  older than 24h0m0s
+dry run: 7 would be deleted, 0 excluded
+`, out.String())
+}
+
+func TestReaperRun(t *testing.T) {
+	r, deleters := newTestReaper(t, false, "production")
+	report, err := r.Run(context.Background())
+	assert.EqualError(t, err, "cleanup failed:\nenvironments: failed to delete 1 resource(s)\nusersignups: failed to delete 1 resource(s)")
+
+	sort.Strings(deleters.deleted)
+	assert.Equal(t, []string{"build-e2e-abcd", "development", "ephemeral", "ephemeral-dtc", "stuck-dtc"}, deleters.deleted)
+	assert.Equal(t, 5, report.Count(Deleted))
+	assert.Equal(t, 2, report.Count(Failed))
+
+	// finalizers of the claim blocking its deletion are removed
+	assert.Equal(t, "in reaped namespace build-e2e-abcd, finalizers [dtc.finalizer.appstudio.redhat.com] removed", report.Entries[0].Reason)
+	err = r.Client.Get(context.Background(), crclient.ObjectKey{Name: "stuck-dtc", Namespace: "build-e2e-abcd"}, &appservice.DeploymentTargetClaim{})
+	assert.True(t, k8sErrors.IsNotFound(err))
+
+	// the UserSignup is kept until everything in its tenant namespace is deleted
+	assert.Equal(t, "production is locked", report.Entries[3].Error)
+	userSignup := report.Entries[6]
+	assert.Equal(t, "e2e-old", userSignup.Resource.Name)
+	assert.Equal(t, Failed, userSignup.Action)
+	assert.Equal(t, "resources in namespace e2e-old-tenant were not deleted", userSignup.Error)
+}
+
+func TestReaperPlanFails(t *testing.T) {
+	r := NewReaper(crfake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(), nil, nil, nil, time.Hour, false)
+	report, err := r.Run(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, report.Errors["plan"], "failed to list UserSignups")
+	assert.Empty(t, report.Entries)
+}
@@ -0,0 +1,43 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/clients/sprayproxy"
+)
+
+// SprayProxyServersBackend handles PaC servers registered in SprayProxy. Backends which don't pass the check are stale.
+type SprayProxyServersBackend struct {
+	Client *sprayproxy.SprayProxyConfig
+	// Check decides whether a backend is alive, sprayproxy.CheckBackend if nil
+	Check sprayproxy.BackendCheckFunc
+}
+
+func (b *SprayProxyServersBackend) List(ctx context.Context, resourceType ResourceType, _ []string) ([]Resource, error) {
+	if resourceType != SprayProxyBackend {
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+	check := b.Check
+	if check == nil {
+		check = sprayproxy.CheckBackend
+	}
+	servers, err := b.Client.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registered PaC servers from SprayProxy: %v", err)
+	}
+	resources := []Resource{}
+	for _, server := range servers {
+		res := Resource{Type: resourceType, Name: server}
+		if status := check(ctx, server); !status.Alive {
+			res.StaleReason = status.Reason
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+func (b *SprayProxyServersBackend) Delete(ctx context.Context, r Resource) error {
+	_, err := b.Client.UnregisterServer(ctx, r.Name)
+	return err
+}
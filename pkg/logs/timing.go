@@ -0,0 +1,124 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	types "github.com/onsi/ginkgo/v2/types"
+)
+
+const (
+	// StepTimingReportEntryName is the name of the (hidden) report entry used to record framework wait helper timings
+	StepTimingReportEntryName = "e2e-step-timing"
+
+	// StepKindBy marks a timing recorded for a Ginkgo By step
+	StepKindBy = "by"
+	// StepKindWait marks a timing recorded for a framework wait helper
+	StepKindWait = "wait"
+)
+
+// StepTiming describes how long a single step of a spec took.
+type StepTiming struct {
+	Name     string        `json:"name"`
+	Kind     string        `json:"kind"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// Location is the code location of the By step or of the wait helper call
+	Location string `json:"location,omitempty"`
+}
+
+// TrackTiming starts measuring a framework wait helper and returns a function that records the measured
+// duration into the current spec report. It is meant to be deferred at the top of the helper:
+//
+//	defer logs.TrackTiming("WaitForSnapshotToGetCreated")()
+//
+// Outside of a running Ginkgo spec (e.g. in load tests) it does nothing.
+func TrackTiming(name string) func() {
+	start := time.Now()
+	location := types.NewCodeLocation(2)
+	return func() {
+		if CurrentSpecReport().StartTime.IsZero() {
+			return
+		}
+		AddReportEntry(StepTimingReportEntryName, ReportEntryVisibilityNever, StepTiming{
+			Name:     name,
+			Kind:     StepKindWait,
+			Start:    start,
+			Duration: time.Since(start),
+			Location: location.String(),
+		})
+	}
+}
+
+// GetStepTimings returns timings of all By steps and tracked framework wait helpers of the given spec, ordered by start time.
+// By steps without a callback are considered finished when the next By step (or the node they belong to) ends.
+func GetStepTimings(report types.SpecReport) []StepTiming {
+	timings := []StepTiming{}
+	endTime := report.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+
+	for i, event := range report.SpecEvents {
+		if event.SpecEventType != types.SpecEventByStart {
+			continue
+		}
+		start := event.TimelineLocation.Time
+		end, found := endTime, false
+		// By steps with a callback have a matching end event
+		for _, next := range report.SpecEvents[i+1:] {
+			if next.SpecEventType == types.SpecEventByEnd && next.CodeLocation == event.CodeLocation && next.TimelineLocation.Time.Add(-next.Duration).Equal(start) {
+				end, found = next.TimelineLocation.Time, true
+				break
+			}
+		}
+		if !found {
+			for _, next := range report.SpecEvents[i+1:] {
+				if next.SpecEventType.Is(types.SpecEventByStart | types.SpecEventNodeEnd) {
+					end = next.TimelineLocation.Time
+					break
+				}
+			}
+		}
+		timings = append(timings, StepTiming{
+			Name:     event.Message,
+			Kind:     StepKindBy,
+			Start:    start,
+			Duration: end.Sub(start),
+			Location: event.CodeLocation.String(),
+		})
+	}
+
+	for _, entry := range report.ReportEntries {
+		if entry.Name != StepTimingReportEntryName {
+			continue
+		}
+		// when running in parallel the raw value is not available, so decode it from its JSON representation
+		timing, ok := entry.GetRawValue().(StepTiming)
+		if !ok {
+			if err := json.Unmarshal([]byte(entry.Value.AsJSON), &timing); err != nil {
+				continue
+			}
+		}
+		timings = append(timings, timing)
+	}
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Start.Before(timings[j].Start)
+	})
+
+	return timings
+}
+
+// FormatStepTimings returns a human readable breakdown of the given step timings.
+func FormatStepTimings(timings []StepTiming) string {
+	var sb strings.Builder
+	for _, t := range timings {
+		sb.WriteString(fmt.Sprintf("[%s] %s: %s (started at %s)\n", t.Kind, t.Name, t.Duration.Round(time.Millisecond), t.Start.Format(time.RFC3339)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStepTimings(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) types.TimelineLocation {
+		return types.TimelineLocation{Time: start.Add(time.Duration(seconds) * time.Second)}
+	}
+	first := types.CodeLocation{FileName: "spec.go", LineNumber: 10}
+	second := types.CodeLocation{FileName: "spec.go", LineNumber: 20}
+	withCallback := types.CodeLocation{FileName: "spec.go", LineNumber: 30}
+
+	report := types.SpecReport{
+		StartTime: start,
+		EndTime:   start.Add(100 * time.Second),
+		SpecEvents: types.SpecEvents{
+			{SpecEventType: types.SpecEventByStart, Message: "creating component", CodeLocation: first, TimelineLocation: at(1)},
+			{SpecEventType: types.SpecEventByStart, Message: "waiting for build", CodeLocation: second, TimelineLocation: at(5)},
+			{SpecEventType: types.SpecEventByStart, Message: "checking image", CodeLocation: withCallback, TimelineLocation: at(50)},
+			{SpecEventType: types.SpecEventByEnd, Message: "checking image", CodeLocation: withCallback, TimelineLocation: at(60), Duration: 10 * time.Second},
+			{SpecEventType: types.SpecEventNodeEnd, TimelineLocation: at(70)},
+		},
+		ReportEntries: types.ReportEntries{
+			{Name: "unrelated entry"},
+			{Name: StepTimingReportEntryName, Value: types.WrapEntryValue(StepTiming{Name: "WaitForComponentPipelineToBeFinished", Kind: StepKindWait, Start: start.Add(6 * time.Second), Duration: 40 * time.Second})},
+		},
+	}
+
+	timings := GetStepTimings(report)
+
+	assert.Len(t, timings, 4)
+	assert.Equal(t, "creating component", timings[0].Name)
+	assert.Equal(t, 4*time.Second, timings[0].Duration)
+	assert.Equal(t, "waiting for build", timings[1].Name)
+	assert.Equal(t, 45*time.Second, timings[1].Duration)
+	assert.Equal(t, "WaitForComponentPipelineToBeFinished", timings[2].Name)
+	assert.Equal(t, StepKindWait, timings[2].Kind)
+	assert.Equal(t, 40*time.Second, timings[2].Duration)
+	assert.Equal(t, "checking image", timings[3].Name)
+	assert.Equal(t, 10*time.Second, timings[3].Duration)
+}
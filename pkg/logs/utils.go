@@ -6,14 +6,22 @@ import (
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
-	. "github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"sigs.k8s.io/yaml"
 )
 
+// ArtifactDir returns the directory for storing artifacts of specs, ARTIFACT_DIR or ./tmp by default
+func ArtifactDir() string {
+	if dir := config.Get("ARTIFACT_DIR"); dir != "" {
+		return dir
+	}
+	wd, _ := os.Getwd()
+	return fmt.Sprintf("%s/tmp", wd)
+}
+
 // createArtifactDirectory creates directory for storing artifacts of current spec.
 func createArtifactDirectory() (string, error) {
-	wd, _ := os.Getwd()
-	artifactDir := GetEnv("ARTIFACT_DIR", fmt.Sprintf("%s/tmp", wd))
+	artifactDir := ArtifactDir()
 	classname := ShortenStringAddHash(CurrentSpecReport())
 	testLogsDir := fmt.Sprintf("%s/%s", artifactDir, classname)
 
@@ -62,6 +70,9 @@ func StoreTestTiming() error {
 	}
 
 	testTime := "Test started at: " + CurrentSpecReport().StartTime.String() + "\nTest ended at: " + time.Now().String()
+	if timings := GetStepTimings(CurrentSpecReport()); len(timings) > 0 {
+		testTime += "\n\nStep timings:\n" + FormatStepTimings(timings)
+	}
 	filePath := fmt.Sprintf("%s/test-timing", artifactsDirectory)
 	if err := os.WriteFile(filePath, []byte(testTime), 0644); err != nil {
 		return fmt.Errorf("failed to store test timing: %v", err)
@@ -0,0 +1,206 @@
+// Package matchers provides Gomega matchers for AppStudio custom resources and Tekton runs.
+// Failure messages print the relevant part of the resource status, e.g.
+//
+//	Expect(pipelineRun).To(matchers.HaveSucceeded())
+//	Expect(release).To(matchers.BeReleased())
+//	Expect(snapshot).To(matchers.HaveIntegrationTestStatus(scenarioName, intgteststat.IntegrationTestStatusTestPassed))
+package matchers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// ResourceMatcher is a matcher of a Kubernetes resource. Failure messages describe the resource and print its status.
+type ResourceMatcher struct {
+	expectation string
+	match       func(actual interface{}) (bool, error)
+	status      func(actual interface{}) string
+}
+
+// Match matches the matcher with a given resource.
+func (matcher *ResourceMatcher) Match(actual interface{}) (success bool, err error) {
+	if actual == nil || (reflect.ValueOf(actual).Kind() == reflect.Ptr && reflect.ValueOf(actual).IsNil()) {
+		return false, fmt.Errorf("expected a resource, got nil")
+	}
+	return matcher.match(actual)
+}
+
+// FailureMessage returns failure message for a resource matcher.
+func (matcher *ResourceMatcher) FailureMessage(actual interface{}) (message string) {
+	return matcher.message(actual, "to")
+}
+
+// NegatedFailureMessage returns negated failure message for a resource matcher.
+func (matcher *ResourceMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return matcher.message(actual, "not to")
+}
+
+func (matcher *ResourceMatcher) message(actual interface{}, to string) string {
+	status := conditionsStatus
+	if matcher.status != nil {
+		status = matcher.status
+	}
+	return fmt.Sprintf("Expected %s %s %s\n%s", describe(actual), to, matcher.expectation, format.IndentString(status(actual), 1))
+}
+
+// describe returns the kind and the namespaced name of the resource, e.g. "PipelineRun tenant/build-abcd"
+func describe(actual interface{}) string {
+	kind := reflect.Indirect(reflect.ValueOf(actual)).Type().Name()
+	if o, err := meta.Accessor(actual); err == nil {
+		if o.GetNamespace() == "" {
+			return fmt.Sprintf("%s %s", kind, o.GetName())
+		}
+		return fmt.Sprintf("%s %s/%s", kind, o.GetNamespace(), o.GetName())
+	}
+	return kind
+}
+
+// conditionsOf returns conditions of the resource status, Knative conditions used by Tekton are converted
+// to metav1 conditions
+func conditionsOf(actual interface{}) ([]metav1.Condition, error) {
+	if conditions, ok := actual.([]metav1.Condition); ok {
+		return conditions, nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(actual))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a resource with status conditions, got %T", actual)
+	}
+	status := v.FieldByName("Status")
+	if !status.IsValid() || status.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T has no status", actual)
+	}
+	field := status.FieldByName("Conditions")
+	if !field.IsValid() {
+		return nil, fmt.Errorf("status of %T has no conditions", actual)
+	}
+	switch c := field.Interface().(type) {
+	case []metav1.Condition:
+		return c, nil
+	case []apis.Condition:
+		return convertConditions(c), nil
+	}
+	if field.Type().ConvertibleTo(reflect.TypeOf([]apis.Condition{})) {
+		return convertConditions(field.Convert(reflect.TypeOf([]apis.Condition{})).Interface().([]apis.Condition)), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s of status conditions of %T", field.Type(), actual)
+}
+
+func convertConditions(conditions []apis.Condition) []metav1.Condition {
+	converted := []metav1.Condition{}
+	for _, c := range conditions {
+		converted = append(converted, metav1.Condition{
+			Type:               string(c.Type),
+			Status:             metav1.ConditionStatus(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Inner,
+		})
+	}
+	return converted
+}
+
+// conditionsStatus prints the status conditions of the resource
+func conditionsStatus(actual interface{}) string {
+	conditions, err := conditionsOf(actual)
+	if err != nil {
+		return err.Error()
+	}
+	if len(conditions) == 0 {
+		return "status has no conditions"
+	}
+	lines := []string{"status conditions:"}
+	for _, c := range conditions {
+		lines = append(lines, fmt.Sprintf("  - type: %s, status: %s, reason: %s, message: %s", c.Type, c.Status, c.Reason, c.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HaveCondition succeeds if the resource status has the condition of the type with the status. If the reason
+// is not empty, the condition has to have the reason as well. Tekton (Knative) conditions are supported.
+func HaveCondition(conditionType string, status metav1.ConditionStatus, reason string) types.GomegaMatcher {
+	expectation := fmt.Sprintf("have condition %s=%s", conditionType, status)
+	if reason != "" {
+		expectation += fmt.Sprintf(" with reason %s", reason)
+	}
+	return &ResourceMatcher{
+		expectation: expectation,
+		match: func(actual interface{}) (bool, error) {
+			conditions, err := conditionsOf(actual)
+			if err != nil {
+				return false, err
+			}
+			c := meta.FindStatusCondition(conditions, conditionType)
+			return c != nil && c.Status == status && (reason == "" || c.Reason == reason), nil
+		},
+	}
+}
+
+// HaveAnnotation succeeds if the resource has the annotation. If a value is given, the annotation value has to
+// be equal to it, or satisfy it in case of a matcher.
+func HaveAnnotation(key string, value ...interface{}) types.GomegaMatcher {
+	expectation := fmt.Sprintf("have annotation %s", key)
+	if len(value) > 0 {
+		expectation += fmt.Sprintf(" with value %s", format.Object(value[0], 0))
+	}
+	return &ResourceMatcher{
+		expectation: expectation,
+		match: func(actual interface{}) (bool, error) {
+			o, err := meta.Accessor(actual)
+			if err != nil {
+				return false, err
+			}
+			annotationValue, found := o.GetAnnotations()[key]
+			if !found || len(value) == 0 {
+				return found, nil
+			}
+			if m, ok := value[0].(types.GomegaMatcher); ok {
+				return m.Match(annotationValue)
+			}
+			return annotationValue == value[0], nil
+		},
+		status: func(actual interface{}) string {
+			if o, err := meta.Accessor(actual); err == nil {
+				return fmt.Sprintf("annotations: %v", o.GetAnnotations())
+			}
+			return ""
+		},
+	}
+}
+
+// HaveOwnerReference succeeds if the resource is owned by a resource of the kind with the name.
+func HaveOwnerReference(kind, name string) types.GomegaMatcher {
+	return &ResourceMatcher{
+		expectation: fmt.Sprintf("have owner reference to %s %s", kind, name),
+		match: func(actual interface{}) (bool, error) {
+			o, err := meta.Accessor(actual)
+			if err != nil {
+				return false, err
+			}
+			for _, ref := range o.GetOwnerReferences() {
+				if ref.Kind == kind && ref.Name == name {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		status: func(actual interface{}) string {
+			o, err := meta.Accessor(actual)
+			if err != nil {
+				return ""
+			}
+			owners := []string{}
+			for _, ref := range o.GetOwnerReferences() {
+				owners = append(owners, fmt.Sprintf("%s %s", ref.Kind, ref.Name))
+			}
+			return fmt.Sprintf("owner references: [%s]", strings.Join(owners, ", "))
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package matchers
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	intgteststat "github.com/redhat-appstudio/integration-service/pkg/integrationteststatus"
+	releaseApi "github.com/redhat-appstudio/release-service/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func pipelineRunWithStatus(status corev1.ConditionStatus, reason string) *pipeline.PipelineRun {
+	pr := &pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "tenant"}}
+	pr.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status, Reason: reason, Message: "Tasks Completed: 2 (Failed: 1)"}}}
+	pr.Status.ChildReferences = []pipeline.ChildStatusReference{{Name: "build-clone", PipelineTaskName: "clone"}}
+	return pr
+}
+
+func TestRunMatchers(t *testing.T) {
+	g := gomega.NewWithT(t)
+	succeeded := pipelineRunWithStatus(corev1.ConditionTrue, "Succeeded")
+	failed := pipelineRunWithStatus(corev1.ConditionFalse, "Failed")
+	running := pipelineRunWithStatus(corev1.ConditionUnknown, "Running")
+
+	g.Expect(succeeded).To(HaveSucceeded())
+	g.Expect(failed).To(HaveFailed())
+	g.Expect(running).NotTo(gomega.Or(HaveSucceeded(), HaveFailed()))
+	g.Expect(failed).To(HaveCondition("Succeeded", metav1.ConditionFalse, "Failed"))
+	g.Expect(failed).NotTo(HaveCondition("Succeeded", metav1.ConditionFalse, "PipelineRunTimeout"))
+
+	tr := &pipeline.TaskRun{}
+	tr.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}}}
+	g.Expect(tr).To(HaveSucceeded())
+
+	message := HaveSucceeded().FailureMessage(failed)
+	assert.Contains(t, message, "Expected PipelineRun tenant/build to have succeeded")
+	assert.Contains(t, message, "type: Succeeded, status: False, reason: Failed, message: Tasks Completed: 2 (Failed: 1)")
+	assert.Contains(t, message, "child references: build-clone (clone)")
+
+	_, err := HaveSucceeded().Match(&releaseApi.Release{})
+	assert.ErrorContains(t, err, "expected a *PipelineRun or a *TaskRun")
+	_, err = HaveSucceeded().Match((*pipeline.PipelineRun)(nil))
+	assert.ErrorContains(t, err, "got nil")
+}
+
+func TestAppStudioMatchers(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	component := &appservice.Component{ObjectMeta: metav1.ObjectMeta{Name: "comp"}}
+	g.Expect(component).NotTo(BeReady())
+	component.Status.Conditions = []metav1.Condition{{Type: "Created", Status: metav1.ConditionTrue, Message: "Component has been successfully created"}}
+	g.Expect(component).To(BeReady())
+	component.Status.Conditions = append(component.Status.Conditions, metav1.Condition{Type: "Updated", Status: metav1.ConditionFalse, Reason: "Error"})
+	g.Expect(component).NotTo(BeReady())
+	assert.Contains(t, BeReady().FailureMessage(component), "type: Updated, status: False, reason: Error")
+
+	release := &releaseApi.Release{}
+	g.Expect(release).NotTo(BeReleased())
+	release.Status.Conditions = []metav1.Condition{{Type: "Released", Status: metav1.ConditionTrue, Reason: "Succeeded"}}
+	release.Status.Processing.PipelineRun = "managed/release-abcd"
+	g.Expect(release).To(BeReleased())
+	assert.Contains(t, BeReleased().NegatedFailureMessage(release), "release PipelineRun: managed/release-abcd")
+
+	releasePlan := &releaseApi.ReleasePlan{}
+	g.Expect(releasePlan).NotTo(HaveMatchedReleasePlanAdmission(""))
+	releasePlan.Status.Conditions = []metav1.Condition{{Type: releaseApi.MatchedConditionType.String(), Status: metav1.ConditionTrue}}
+	releasePlan.Status.ReleasePlanAdmission.Name = "managed/rpa"
+	g.Expect(releasePlan).To(HaveMatchedReleasePlanAdmission(""))
+	g.Expect(releasePlan).To(HaveMatchedReleasePlanAdmission("managed/rpa"))
+	g.Expect(releasePlan).NotTo(HaveMatchedReleasePlanAdmission("managed/other"))
+
+	snapshot := &appservice.Snapshot{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		snapshotTestsStatusAnnotation: `[{"scenario":"e2e","status":"TestPassed","lastUpdateTime":"2024-01-01T00:00:00Z","details":"passed"}]`,
+	}}}
+	g.Expect(snapshot).To(HaveIntegrationTestStatus("e2e", intgteststat.IntegrationTestStatusTestPassed))
+	g.Expect(snapshot).NotTo(HaveIntegrationTestStatus("e2e", intgteststat.IntegrationTestStatusTestFail))
+	g.Expect(snapshot).NotTo(HaveIntegrationTestStatus("other", intgteststat.IntegrationTestStatusTestPassed))
+	assert.Contains(t, HaveIntegrationTestStatus("e2e", intgteststat.IntegrationTestStatusTestFail).FailureMessage(snapshot), `"status":"TestPassed"`)
+}
+
+func TestMetadataMatchers(t *testing.T) {
+	g := gomega.NewWithT(t)
+	pr := &pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:            "build",
+		Annotations:     map[string]string{"build.appstudio.redhat.com/commit_sha": "abcd"},
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Component", Name: "comp"}},
+	}}
+
+	g.Expect(pr).To(HaveAnnotation("build.appstudio.redhat.com/commit_sha"))
+	g.Expect(pr).To(HaveAnnotation("build.appstudio.redhat.com/commit_sha", "abcd"))
+	g.Expect(pr).To(HaveAnnotation("build.appstudio.redhat.com/commit_sha", gomega.HavePrefix("ab")))
+	g.Expect(pr).NotTo(HaveAnnotation("build.appstudio.redhat.com/commit_sha", "efgh"))
+	g.Expect(pr).NotTo(HaveAnnotation("missing"))
+	g.Expect(pr).To(HaveOwnerReference("Component", "comp"))
+	g.Expect(pr).NotTo(HaveOwnerReference("Application", "comp"))
+	assert.Contains(t, HaveOwnerReference("Application", "comp").FailureMessage(pr), "owner references: [Component comp]")
+
+	_, err := HaveCondition("Ready", metav1.ConditionTrue, "").Match("string")
+	assert.ErrorContains(t, err, "expected a resource with status conditions")
+}
@@ -0,0 +1,157 @@
+package matchers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/types"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	intgteststat "github.com/redhat-appstudio/integration-service/pkg/integrationteststatus"
+	releaseApi "github.com/redhat-appstudio/release-service/api/v1alpha1"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// snapshotTestsStatusAnnotation holds integration test statuses of a Snapshot
+const snapshotTestsStatusAnnotation = "test.appstudio.openshift.io/status"
+
+// BeReady succeeds if the resource has the Ready condition set to True. A Component is ready when
+// the application service created it and the last update (if any) succeeded.
+func BeReady() types.GomegaMatcher {
+	return &ResourceMatcher{
+		expectation: "be ready",
+		match: func(actual interface{}) (bool, error) {
+			conditions, err := conditionsOf(actual)
+			if err != nil {
+				return false, err
+			}
+			if _, ok := actual.(*appservice.Component); ok {
+				updated := meta.FindStatusCondition(conditions, "Updated")
+				return meta.IsStatusConditionTrue(conditions, "Created") && (updated == nil || updated.Status == metav1.ConditionTrue), nil
+			}
+			return meta.IsStatusConditionTrue(conditions, "Ready"), nil
+		},
+	}
+}
+
+// runSucceededStatus returns the status of the Succeeded condition of a PipelineRun or a TaskRun
+func runSucceededStatus(actual interface{}) (*apis.Condition, error) {
+	switch run := actual.(type) {
+	case *pipeline.PipelineRun:
+		return run.Status.GetCondition(apis.ConditionSucceeded), nil
+	case *pipeline.TaskRun:
+		return run.Status.GetCondition(apis.ConditionSucceeded), nil
+	}
+	return nil, fmt.Errorf("expected a *PipelineRun or a *TaskRun, got %T", actual)
+}
+
+// runStatus prints conditions of the run and, for a PipelineRun, its child TaskRuns
+func runStatus(actual interface{}) string {
+	status := conditionsStatus(actual)
+	if pr, ok := actual.(*pipeline.PipelineRun); ok && len(pr.Status.ChildReferences) > 0 {
+		children := []string{}
+		for _, c := range pr.Status.ChildReferences {
+			children = append(children, fmt.Sprintf("%s (%s)", c.Name, c.PipelineTaskName))
+		}
+		status += fmt.Sprintf("\nchild references: %s", strings.Join(children, ", "))
+	}
+	return status
+}
+
+// HaveSucceeded succeeds if the PipelineRun or the TaskRun finished successfully.
+func HaveSucceeded() types.GomegaMatcher {
+	return &ResourceMatcher{
+		expectation: "have succeeded",
+		match: func(actual interface{}) (bool, error) {
+			c, err := runSucceededStatus(actual)
+			return c.IsTrue(), err
+		},
+		status: runStatus,
+	}
+}
+
+// HaveFailed succeeds if the PipelineRun or the TaskRun finished unsuccessfully.
+func HaveFailed() types.GomegaMatcher {
+	return &ResourceMatcher{
+		expectation: "have failed",
+		match: func(actual interface{}) (bool, error) {
+			c, err := runSucceededStatus(actual)
+			return c.IsFalse(), err
+		},
+		status: runStatus,
+	}
+}
+
+// BeReleased succeeds if the Release finished successfully.
+func BeReleased() types.GomegaMatcher {
+	return &ResourceMatcher{
+		expectation: "be released",
+		match: func(actual interface{}) (bool, error) {
+			release, ok := actual.(*releaseApi.Release)
+			if !ok {
+				return false, fmt.Errorf("expected a *Release, got %T", actual)
+			}
+			return release.IsReleased(), nil
+		},
+		status: func(actual interface{}) string {
+			status := conditionsStatus(actual)
+			if release, ok := actual.(*releaseApi.Release); ok && release.Status.Processing.PipelineRun != "" {
+				status += fmt.Sprintf("\nrelease PipelineRun: %s", release.Status.Processing.PipelineRun)
+			}
+			return status
+		},
+	}
+}
+
+// HaveMatchedReleasePlanAdmission succeeds if the ReleasePlan is matched to the ReleasePlanAdmission
+// of the namespaced name (<namespace>/<name>), or to any ReleasePlanAdmission if the name is empty.
+func HaveMatchedReleasePlanAdmission(namespacedName string) types.GomegaMatcher {
+	expectation := "have matched ReleasePlanAdmission"
+	if namespacedName != "" {
+		expectation += " " + namespacedName
+	}
+	return &ResourceMatcher{
+		expectation: expectation,
+		match: func(actual interface{}) (bool, error) {
+			releasePlan, ok := actual.(*releaseApi.ReleasePlan)
+			if !ok {
+				return false, fmt.Errorf("expected a *ReleasePlan, got %T", actual)
+			}
+			return releasePlan.IsMatched() && (namespacedName == "" || releasePlan.Status.ReleasePlanAdmission.Name == namespacedName), nil
+		},
+		status: func(actual interface{}) string {
+			status := conditionsStatus(actual)
+			if releasePlan, ok := actual.(*releaseApi.ReleasePlan); ok {
+				status += fmt.Sprintf("\nmatched ReleasePlanAdmission: %q", releasePlan.Status.ReleasePlanAdmission.Name)
+			}
+			return status
+		},
+	}
+}
+
+// HaveIntegrationTestStatus succeeds if the integration test status of the scenario reported in the Snapshot annotation is the status.
+func HaveIntegrationTestStatus(scenario string, status intgteststat.IntegrationTestStatus) types.GomegaMatcher {
+	return &ResourceMatcher{
+		expectation: fmt.Sprintf("have integration test status %s for scenario %s", status, scenario),
+		match: func(actual interface{}) (bool, error) {
+			snapshot, ok := actual.(*appservice.Snapshot)
+			if !ok {
+				return false, fmt.Errorf("expected a *Snapshot, got %T", actual)
+			}
+			statuses, err := intgteststat.NewSnapshotIntegrationTestStatuses(snapshot.GetAnnotations()[snapshotTestsStatusAnnotation])
+			if err != nil {
+				return false, fmt.Errorf("failed to parse integration test statuses of the snapshot: %v", err)
+			}
+			detail, found := statuses.GetScenarioStatus(scenario)
+			return found && detail.Status == status, nil
+		},
+		status: func(actual interface{}) string {
+			if snapshot, ok := actual.(*appservice.Snapshot); ok {
+				return fmt.Sprintf("integration test statuses: %s", snapshot.GetAnnotations()[snapshotTestsStatusAnnotation])
+			}
+			return ""
+		},
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/codeready-toolchain/toolchain-e2e/testsupport/md5"
 	. "github.com/onsi/ginkgo/v2"
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
@@ -161,7 +162,10 @@ func (s *SandboxController) ReconcileUserCreationStage(userName, toolchainApiUrl
 	if err != nil {
 		return nil, err
 	}
-	kubeconfigPath := utils.GetEnv(constants.USER_KUBE_CONFIG_PATH_ENV, fmt.Sprintf("%s/tmp/%s.kubeconfig", wd, userName))
+	kubeconfigPath := config.Get(constants.USER_KUBE_CONFIG_PATH_ENV)
+	if kubeconfigPath == "" {
+		kubeconfigPath = fmt.Sprintf("%s/tmp/%s.kubeconfig", wd, userName)
+	}
 
 	userToken, err := s.GetKeycloakTokenStage(userName, keycloakUrl, offlineToken)
 	if err != nil {
@@ -178,7 +182,10 @@ func (s *SandboxController) ReconcileUserCreation(userName string) (*SandboxUser
 	if err != nil {
 		return nil, err
 	}
-	kubeconfigPath := utils.GetEnv(constants.USER_KUBE_CONFIG_PATH_ENV, fmt.Sprintf("%s/tmp/%s.kubeconfig", wd, userName))
+	kubeconfigPath := config.Get(constants.USER_KUBE_CONFIG_PATH_ENV)
+	if kubeconfigPath == "" {
+		kubeconfigPath = fmt.Sprintf("%s/tmp/%s.kubeconfig", wd, userName)
+	}
 
 	toolchainApiUrl, err := s.GetOpenshiftRouteHost(DEFAULT_TOOLCHAIN_NAMESPACE, DEFAULT_TOOLCHAIN_INSTANCE_NAME)
 	if err != nil {
@@ -376,9 +383,9 @@ func getUserSignupSpecsWithState(username string, state toolchainApi.UserSignupS
 			Annotations: map[string]string{
 				"toolchain.dev.openshift.com/user-email": fmt.Sprintf("%s@user.us", username),
 			},
-			Labels: map[string]string{
+			Labels: utils.WithE2ETestLabel(map[string]string{
 				"toolchain.dev.openshift.com/email-hash": md5.CalcMd5(fmt.Sprintf("%s@user.us", username)),
-			},
+			}),
 		},
 		Spec: toolchainApi.UserSignupSpec{
 			Userid:   username,
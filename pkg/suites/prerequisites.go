@@ -0,0 +1,171 @@
+package suites
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+)
+
+// PrerequisiteKind is the kind of a thing specs need to run
+type PrerequisiteKind string
+
+const (
+	EnvPrerequisite        PrerequisiteKind = "env var"
+	BinaryPrerequisite     PrerequisiteKind = "binary"
+	CRDPrerequisite        PrerequisiteKind = "CRD"
+	CapabilityPrerequisite PrerequisiteKind = "cluster capability"
+	ComponentPrerequisite  PrerequisiteKind = "component"
+)
+
+// Prerequisite is a thing specs of a suite need to run. Specs fail if their required prerequisites
+// are not met and they are skipped if their optional prerequisites are not met.
+type Prerequisite struct {
+	Kind       PrerequisiteKind
+	Name       string
+	IsOptional bool
+}
+
+// Env is a prerequisite of a non-empty env var
+func Env(name string) Prerequisite {
+	return Prerequisite{Kind: EnvPrerequisite, Name: name}
+}
+
+// Binary is a prerequisite of a binary in PATH
+func Binary(name string) Prerequisite {
+	return Prerequisite{Kind: BinaryPrerequisite, Name: name}
+}
+
+// CRD is a prerequisite of a custom resource installed in the cluster, e.g. "components.appstudio.redhat.com"
+func CRD(name string) Prerequisite {
+	return Prerequisite{Kind: CRDPrerequisite, Name: name}
+}
+
+// Capability is a prerequisite of an API group served by the cluster, e.g. "route.openshift.io"
+func Capability(name string) Prerequisite {
+	return Prerequisite{Kind: CapabilityPrerequisite, Name: name}
+}
+
+// Component is a prerequisite of an AppStudio component with ready deployments, e.g. "multi-platform-controller"
+// (see kubeCl.ComponentNamespaces)
+func Component(name string) Prerequisite {
+	return Prerequisite{Kind: ComponentPrerequisite, Name: name}
+}
+
+// Optional makes specs skip instead of fail if the prerequisite is not met
+func (p Prerequisite) Optional() Prerequisite {
+	p.IsOptional = true
+	return p
+}
+
+func (p Prerequisite) String() string {
+	return fmt.Sprintf("%s %s", p.Kind, p.Name)
+}
+
+// RequiresCluster returns true if the prerequisite can be checked only with a bootstrapped cluster
+func (p Prerequisite) RequiresCluster() bool {
+	return p.Kind == CRDPrerequisite || p.Kind == CapabilityPrerequisite || p.Kind == ComponentPrerequisite
+}
+
+// UnmetPrerequisite is a prerequisite which is not met, with the reason
+type UnmetPrerequisite struct {
+	Prerequisite
+	Reason string
+}
+
+// PrerequisiteChecker checks prerequisites and caches results of the checks, except failures of the discovery
+// of the cluster's capabilities
+type PrerequisiteChecker struct {
+	mu      sync.Mutex
+	results map[Prerequisite]string
+	// capabilities returns capabilities of the cluster
+	capabilities func() (*kubeCl.Capabilities, error)
+}
+
+// NewPrerequisiteChecker returns a checker discovering capabilities of the cluster with the function.
+// It can be nil if only prerequisites not requiring a cluster are checked.
+func NewPrerequisiteChecker(capabilities func() (*kubeCl.Capabilities, error)) *PrerequisiteChecker {
+	return &PrerequisiteChecker{
+		results:      map[Prerequisite]string{},
+		capabilities: capabilities,
+	}
+}
+
+// Unmet returns the prerequisites which are not met. Prerequisites requiring a cluster are checked
+// only if withCluster is true.
+func (c *PrerequisiteChecker) Unmet(prerequisites []Prerequisite, withCluster bool) []UnmetPrerequisite {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	unmet := []UnmetPrerequisite{}
+	for _, p := range prerequisites {
+		if p.RequiresCluster() && !withCluster {
+			continue
+		}
+		key := Prerequisite{Kind: p.Kind, Name: p.Name}
+		reason, ok := c.results[key]
+		if !ok {
+			var definitive bool
+			reason, definitive = c.check(p)
+			if definitive {
+				c.results[key] = reason
+			}
+		}
+		if reason != "" {
+			unmet = append(unmet, UnmetPrerequisite{Prerequisite: p, Reason: reason})
+		}
+	}
+	return unmet
+}
+
+// check returns the reason why the prerequisite is not met, or an empty string if it is met. The result isn't
+// definitive if the cluster's capabilities couldn't be discovered, the check is then repeated next time.
+func (c *PrerequisiteChecker) check(p Prerequisite) (string, bool) {
+	switch p.Kind {
+	case EnvPrerequisite:
+		if os.Getenv(p.Name) == "" {
+			return fmt.Sprintf("env var %s is not set", p.Name), true
+		}
+	case BinaryPrerequisite:
+		if _, err := exec.LookPath(p.Name); err != nil {
+			return fmt.Sprintf("binary %s not found in PATH", p.Name), true
+		}
+	case CRDPrerequisite, CapabilityPrerequisite, ComponentPrerequisite:
+		if c.capabilities == nil {
+			return fmt.Sprintf("cannot discover %s without a cluster", p), true
+		}
+		caps, err := c.capabilities()
+		if err != nil {
+			return fmt.Sprintf("cannot discover %s: %v", p, err), false
+		}
+		switch {
+		case p.Kind == CRDPrerequisite && !caps.HasCRD(p.Name):
+			return fmt.Sprintf("CRD %s is not installed in the cluster", p.Name), true
+		case p.Kind == CapabilityPrerequisite && !caps.HasAPIGroup(p.Name):
+			return fmt.Sprintf("cluster doesn't serve API group %s", p.Name), true
+		case p.Kind == ComponentPrerequisite && !caps.HasComponent(p.Name):
+			return fmt.Sprintf("component %s is not installed or not ready", p.Name), true
+		}
+	}
+	return "", true
+}
+
+// UnmetMessage describes either required or optional unmet prerequisites of the suite, it's empty if there are none
+func UnmetMessage(suite *Suite, unmet []UnmetPrerequisite, optional bool) string {
+	reasons := []string{}
+	for _, u := range unmet {
+		if u.IsOptional == optional {
+			reasons = append(reasons, u.Reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	kind := "required"
+	if optional {
+		kind = "optional"
+	}
+	return fmt.Sprintf("%s prerequisites of %s are not met: %s", kind, suite.Name, strings.Join(reasons, ", "))
+}
@@ -0,0 +1,94 @@
+// Package suites declares suites of the e2e tests and prerequisites of their specs. It doesn't register
+// anything in Ginkgo, so it can be imported by tooling which doesn't run the specs, e.g. magefiles.
+package suites
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// Suite is a group of specs described by one of the *SuiteDescribe functions of pkg/framework
+type Suite struct {
+	Name string
+	// DescribeFunc is the name of the framework function describing specs of the suite
+	DescribeFunc  string
+	Prerequisites []Prerequisite
+}
+
+var all []*Suite
+
+// register declares a suite and its prerequisites, checked before each of its specs runs
+func register(name, describeFunc string, prerequisites ...Prerequisite) *Suite {
+	s := &Suite{Name: name, DescribeFunc: describeFunc, Prerequisites: prerequisites}
+	all = append(all, s)
+	return s
+}
+
+// Suites described by the framework functions, with their prerequisites. PreflightChecks evaluates prerequisites
+// of the suites selected by the label filter before the tests run, and they are checked again before each spec
+// of the suite (see pkg/framework/prerequisites.go).
+var (
+	Byoc = register("byoc-suite", "ByocSuiteDescribe",
+		CRD("environments.appstudio.redhat.com"), Capability("route.openshift.io"))
+	Common        = register("common-suite", "CommonSuiteDescribe")
+	Build         = register("build-service-suite", "BuildSuiteDescribe", CRD("components.appstudio.redhat.com"), Env("QUAY_TOKEN"))
+	JVMBuild      = register("jvm-build-service-suite", "JVMBuildSuiteDescribe", CRD("jbsconfigs.jvmbuildservice.io"))
+	MultiPlatform = register("multi-platform-build-service-suite", "MultiPlatformBuildSuiteDescribe",
+		Component("multi-platform-controller").Optional(), Env("MULTI_PLATFORM_AWS_ACCESS_KEY").Optional(), Env("MULTI_PLATFORM_AWS_SECRET_ACCESS_KEY").Optional(), Env("MULTI_PLATFORM_AWS_SSH_KEY").Optional())
+	IntegrationService = register("integration-service-suite", "IntegrationServiceSuiteDescribe", CRD("integrationtestscenarios.appstudio.redhat.com"))
+	RhtapDemo          = register("rhtap-demo-suite", "RhtapDemoSuiteDescribe")
+	SPI                = register("spi-suite", "SPISuiteDescribe", CRD("spiaccesstokens.appstudio.redhat.com"))
+	RemoteSecret       = register("remotesecret-suite", "RemoteSecretSuiteDescribe", CRD("remotesecrets.appstudio.redhat.com"))
+	EnterpriseContract = register("enterprise-contract-suite", "EnterpriseContractSuiteDescribe", CRD("enterprisecontractpolicies.appstudio.redhat.com"))
+	Upgrade            = register("upgrade-suite", "UpgradeSuiteDescribe")
+	ReleasePipelines   = register("release-pipelines-suite", "ReleasePipelinesSuiteDescribe", CRD("releaseplanadmissions.appstudio.redhat.com"))
+	ReleaseService     = register("release-service-suite", "ReleaseServiceSuiteDescribe", CRD("releaseplanadmissions.appstudio.redhat.com"))
+)
+
+// All returns all declared suites
+func All() []*Suite {
+	return all
+}
+
+// DescribeText returns the text of the suite's top-level container
+func (s *Suite) DescribeText(text string) string {
+	if text == "" {
+		return "[" + s.Name + "]"
+	}
+	return "[" + s.Name + " " + text + "]"
+}
+
+// Selected returns suites with specs selected by the label filter. specLabels maps names of the framework
+// describe functions to the full label sets of specs they describe (see testspecs.SpecLabels in magefiles),
+// the filter is tested against each of the sets like Ginkgo does.
+func Selected(labelFilter string, specLabels map[string][][]string) ([]*Suite, error) {
+	filter, err := types.ParseLabelFilter(labelFilter)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing label filter %q: %v", labelFilter, err)
+	}
+	selected := []*Suite{}
+	for _, s := range all {
+		for _, labels := range specLabels[s.DescribeFunc] {
+			if filter(labels) {
+				selected = append(selected, s)
+				break
+			}
+		}
+	}
+	return selected, nil
+}
+
+// Of returns the suite of the spec, or nil if the spec is not described by a *SuiteDescribe function
+func Of(report types.SpecReport) *Suite {
+	if len(report.ContainerHierarchyTexts) == 0 {
+		return nil
+	}
+	for _, s := range all {
+		if strings.HasPrefix(report.ContainerHierarchyTexts[0], "["+s.Name+" ") || report.ContainerHierarchyTexts[0] == "["+s.Name+"]" {
+			return s
+		}
+	}
+	return nil
+}
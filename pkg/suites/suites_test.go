@@ -0,0 +1,119 @@
+package suites
+
+import (
+	"fmt"
+	"testing"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+)
+
+func TestSelected(t *testing.T) {
+	specLabels := map[string][][]string{
+		"BuildSuiteDescribe":            {{"build", "HACBS"}, {"build", "build-templates", "HACBS"}},
+		"ReleaseServiceSuiteDescribe":   {{"release-service", "happy-path"}},
+		"ReleasePipelinesSuiteDescribe": {{"release-pipelines", "fbc-tests"}},
+		"RhtapDemoSuiteDescribe":        {{}, {"rhtap-demo", "verify-stage"}},
+	}
+	names := func(selected []*Suite) []string {
+		names := []string{}
+		for _, s := range selected {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	selected, err := Selected("build-templates", specLabels)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"build-service-suite"}, names(selected))
+
+	selected, err = Selected("release-pipelines && fbc-tests || happy-path", specLabels)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"release-pipelines-suite", "release-service-suite"}, names(selected))
+
+	// specs without labels are selected by negations only
+	selected, err = Selected("!build && !release-service && !release-pipelines", specLabels)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rhtap-demo-suite"}, names(selected))
+
+	_, err = Selected("build &&", specLabels)
+	assert.ErrorContains(t, err, "error when parsing label filter")
+}
+
+func TestOf(t *testing.T) {
+	assert.Equal(t, Build, Of(types.SpecReport{ContainerHierarchyTexts: []string{"[build-service-suite Build service E2E tests]", "nested"}}))
+	assert.Equal(t, SPI, Of(types.SpecReport{ContainerHierarchyTexts: []string{"[spi-suite]"}}))
+	assert.Nil(t, Of(types.SpecReport{ContainerHierarchyTexts: []string{"[build-service-suite-other]"}}))
+	assert.Nil(t, Of(types.SpecReport{}))
+}
+
+func TestPrerequisiteChecker(t *testing.T) {
+	t.Setenv("E2E_SET_ENV", "value")
+	t.Setenv("E2E_UNSET_ENV", "")
+	discoveries := 0
+	checker := NewPrerequisiteChecker(func() (*kubeCl.Capabilities, error) {
+		discoveries++
+		return &kubeCl.Capabilities{
+			APIVersions: []string{"appstudio.redhat.com/v1alpha1", "route.openshift.io/v1"},
+			CRDs:        []string{"components.appstudio.redhat.com"},
+			Operators: []kubeCl.Operator{
+				{Component: "build-service", Name: "build-service-controller-manager", Ready: true},
+				{Component: "multi-platform-controller", Name: "multi-platform-controller", Ready: false},
+			},
+		}, nil
+	})
+
+	prerequisites := []Prerequisite{
+		Env("E2E_SET_ENV"), Env("E2E_UNSET_ENV"), Binary("go"), Binary("e2e-missing-binary").Optional(),
+		CRD("components.appstudio.redhat.com"), CRD("releases.appstudio.redhat.com"),
+		Capability("route.openshift.io"), Capability("config.openshift.io").Optional(),
+		Component("build-service"), Component("multi-platform-controller").Optional(),
+	}
+	assert.Equal(t, []UnmetPrerequisite{
+		{Prerequisite: Env("E2E_UNSET_ENV"), Reason: "env var E2E_UNSET_ENV is not set"},
+		{Prerequisite: Binary("e2e-missing-binary").Optional(), Reason: "binary e2e-missing-binary not found in PATH"},
+	}, checker.Unmet(prerequisites, false))
+	assert.Equal(t, 0, discoveries)
+
+	unmet := checker.Unmet(prerequisites, true)
+	assert.Equal(t, []UnmetPrerequisite{
+		{Prerequisite: Env("E2E_UNSET_ENV"), Reason: "env var E2E_UNSET_ENV is not set"},
+		{Prerequisite: Binary("e2e-missing-binary").Optional(), Reason: "binary e2e-missing-binary not found in PATH"},
+		{Prerequisite: CRD("releases.appstudio.redhat.com"), Reason: "CRD releases.appstudio.redhat.com is not installed in the cluster"},
+		{Prerequisite: Capability("config.openshift.io").Optional(), Reason: "cluster doesn't serve API group config.openshift.io"},
+		{Prerequisite: Component("multi-platform-controller").Optional(), Reason: "component multi-platform-controller is not installed or not ready"},
+	}, unmet)
+	// results are cached
+	checker.Unmet(prerequisites, true)
+	assert.Equal(t, 6, discoveries)
+
+	suite := &Suite{Name: "test-suite"}
+	assert.Equal(t, "required prerequisites of test-suite are not met: env var E2E_UNSET_ENV is not set, CRD releases.appstudio.redhat.com is not installed in the cluster", UnmetMessage(suite, unmet, false))
+	assert.Equal(t, "optional prerequisites of test-suite are not met: binary e2e-missing-binary not found in PATH, cluster doesn't serve API group config.openshift.io, component multi-platform-controller is not installed or not ready", UnmetMessage(suite, unmet, true))
+	assert.Empty(t, UnmetMessage(suite, nil, true))
+
+	checker = NewPrerequisiteChecker(func() (*kubeCl.Capabilities, error) {
+		return nil, fmt.Errorf("no kubeconfig")
+	})
+	assert.Equal(t, "cannot discover CRD components.appstudio.redhat.com: no kubeconfig", checker.Unmet([]Prerequisite{CRD("components.appstudio.redhat.com")}, true)[0].Reason)
+
+	// failed discovery is repeated by the next check
+	failures := 1
+	checker = NewPrerequisiteChecker(func() (*kubeCl.Capabilities, error) {
+		if failures > 0 {
+			failures--
+			return nil, fmt.Errorf("connection refused")
+		}
+		return &kubeCl.Capabilities{CRDs: []string{"components.appstudio.redhat.com"}}, nil
+	})
+	crds := []Prerequisite{CRD("components.appstudio.redhat.com"), CRD("releases.appstudio.redhat.com")}
+	assert.Equal(t, []UnmetPrerequisite{
+		{Prerequisite: crds[0], Reason: "cannot discover CRD components.appstudio.redhat.com: connection refused"},
+	}, checker.Unmet(crds[:1], true))
+	assert.Equal(t, []UnmetPrerequisite{
+		{Prerequisite: crds[1], Reason: "CRD releases.appstudio.redhat.com is not installed in the cluster"},
+	}, checker.Unmet(crds, true))
+	assert.Equal(t, "cannot discover CRD components.appstudio.redhat.com without a cluster", NewPrerequisiteChecker(nil).Unmet([]Prerequisite{CRD("components.appstudio.redhat.com")}, true)[0].Reason)
+}
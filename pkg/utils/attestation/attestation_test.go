@@ -0,0 +1,89 @@
+package attestation
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	imageDigest = "sha256:2c43f9b1b34a2ba0dd2e1d2e8f6a8ba9adc1b8a36b2e15d5ff86a4d9a4e5d3a1"
+	commit      = "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"
+)
+
+const statementV02 = `{
+  "_type": "https://in-toto.io/Statement/v0.1",
+  "predicateType": "https://slsa.dev/provenance/v0.2",
+  "subject": [{"name": "quay.io/org/repo", "digest": {"sha256": "2c43f9b1b34a2ba0dd2e1d2e8f6a8ba9adc1b8a36b2e15d5ff86a4d9a4e5d3a1"}}],
+  "predicate": {
+    "builder": {"id": "https://tekton.dev/chains/v2"},
+    "buildType": "tekton.dev/v1beta1/PipelineRun",
+    "invocation": {"parameters": {"git-url": "https://github.com/org/repo", "dockerfile": "Dockerfile"}},
+    "materials": [
+      {"uri": "quay.io/redhat-appstudio/task-git-clone", "digest": {"sha256": "ffff"}},
+      {"uri": "git+https://github.com/org/repo.git", "digest": {"sha1": "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"}}
+    ]
+  }
+}`
+
+const statementV1 = `{
+  "_type": "https://in-toto.io/Statement/v1",
+  "predicateType": "https://slsa.dev/provenance/v1",
+  "subject": [{"name": "quay.io/org/repo", "digest": {"sha256": "2c43f9b1b34a2ba0dd2e1d2e8f6a8ba9adc1b8a36b2e15d5ff86a4d9a4e5d3a1"}}],
+  "predicate": {
+    "buildDefinition": {
+      "buildType": "https://tekton.dev/chains/v2/slsa",
+      "externalParameters": {"runSpec": {"params": [{"name": "git-url", "value": "https://github.com/org/repo"}, {"name": "dockerfile", "value": "Dockerfile"}]}},
+      "resolvedDependencies": [{"uri": "git+https://github.com/org/repo.git", "digest": {"sha1": "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"}, "name": "inputs/result"}]
+    },
+    "runDetails": {"builder": {"id": "https://tekton.dev/chains/v2"}}
+  }
+}`
+
+func TestParseProvenance(t *testing.T) {
+	for _, payload := range []string{statementV02, statementV1} {
+		statement, err := ParseStatement([]byte(payload))
+		assert.NoError(t, err)
+		provenance, err := ParseProvenance(statement)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "https://tekton.dev/chains/v2", provenance.BuilderID)
+		assert.Equal(t, "https://github.com/org/repo", provenance.Parameters["git-url"])
+		assert.Equal(t, "Dockerfile", provenance.Parameters["dockerfile"])
+		gitMaterial := provenance.Materials[len(provenance.Materials)-1]
+		assert.Equal(t, "git+https://github.com/org/repo.git", gitMaterial.URI)
+		assert.Equal(t, commit, gitMaterial.Digest["sha1"])
+	}
+
+	_, err := ParseStatement([]byte(`{"foo": "bar"}`))
+	assert.ErrorContains(t, err, "not an in-toto statement")
+	_, err = ParseProvenance(&Statement{PredicateType: "https://spdx.dev/Document"})
+	assert.ErrorContains(t, err, "is not a SLSA provenance")
+}
+
+func TestProvenanceMatchers(t *testing.T) {
+	g := gomega.NewWithT(t)
+	for _, payload := range []string{statementV02, statementV1} {
+		statement, err := ParseStatement([]byte(payload))
+		assert.NoError(t, err)
+		provenance, err := ParseProvenance(statement)
+		assert.NoError(t, err)
+
+		g.Expect(provenance).To(HaveBuilderID("https://tekton.dev/chains/v2"))
+		g.Expect(provenance).To(HaveBuildType(gomega.ContainSubstring("tekton.dev")))
+		g.Expect(provenance).To(HaveInvocationParameter("dockerfile", "Dockerfile"))
+		g.Expect(provenance).NotTo(HaveInvocationParameter("dockerfile", "Containerfile"))
+		g.Expect(provenance).To(HaveGitMaterial("https://github.com/org/repo", commit))
+		g.Expect(provenance).NotTo(HaveGitMaterial("https://github.com/org/repo", "0000"))
+		g.Expect(provenance).NotTo(HaveGitMaterial("https://github.com/org/other", commit))
+		g.Expect(provenance).To(HaveSubjectDigest("quay.io/org/repo:tag@" + imageDigest))
+		g.Expect(statement).To(HaveSubjectDigest(imageDigest))
+		g.Expect(provenance).NotTo(HaveSubjectDigest("sha256:0000"))
+	}
+
+	_, err := HaveInvocationParameter("missing", "").Match(&Provenance{})
+	assert.ErrorContains(t, err, `parameter "missing" not found`)
+	_, err = HaveSubjectDigest("abcd").Match(&Provenance{})
+	assert.ErrorContains(t, err, "is not in the <algorithm>:<hex> form")
+}
@@ -0,0 +1,147 @@
+package attestation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// toMatcher wraps expected values which aren't matchers with the Equal matcher
+func toMatcher(expected interface{}) types.GomegaMatcher {
+	if m, ok := expected.(types.GomegaMatcher); ok {
+		return m
+	}
+	return gomega.Equal(expected)
+}
+
+// HaveBuilderID succeeds if the builder ID of the provenance equals the value or satisfies the matcher.
+func HaveBuilderID(expected interface{}) types.GomegaMatcher {
+	return gomega.WithTransform(func(p *Provenance) string { return p.BuilderID }, toMatcher(expected))
+}
+
+// HaveBuildType succeeds if the build type of the provenance equals the value or satisfies the matcher.
+func HaveBuildType(expected interface{}) types.GomegaMatcher {
+	return gomega.WithTransform(func(p *Provenance) string { return p.BuildType }, toMatcher(expected))
+}
+
+// HaveInvocationParameter succeeds if the provenance records the build parameter with a value which equals
+// the expected value or satisfies the matcher.
+func HaveInvocationParameter(name string, expected interface{}) types.GomegaMatcher {
+	return gomega.WithTransform(func(p *Provenance) (interface{}, error) {
+		value, found := p.Parameters[name]
+		if !found {
+			return nil, fmt.Errorf("parameter %q not found in the provenance parameters %v", name, p.Parameters)
+		}
+		return value, nil
+	}, toMatcher(expected))
+}
+
+type GitMaterialMatcher struct {
+	url    string
+	commit string
+}
+
+// normalizeGitURL strips the "git+" scheme prefix and the ".git" suffix used in materials
+func normalizeGitURL(url string) string {
+	url = strings.TrimPrefix(url, "git+")
+	url = strings.TrimSuffix(url, "/")
+	return strings.ToLower(strings.TrimSuffix(url, ".git"))
+}
+
+// Match matches the matcher with a given provenance.
+func (matcher *GitMaterialMatcher) Match(actual interface{}) (success bool, err error) {
+	p, ok := actual.(*Provenance)
+	if !ok {
+		return false, fmt.Errorf("HaveGitMaterial matcher expects a *Provenance, got %T", actual)
+	}
+	for _, m := range p.Materials {
+		if normalizeGitURL(m.URI) != normalizeGitURL(matcher.url) {
+			continue
+		}
+		for _, digest := range m.Digest {
+			if digest == matcher.commit {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// FailureMessage returns failure message for a GitMaterial matcher.
+func (matcher *GitMaterialMatcher) FailureMessage(actual interface{}) (message string) {
+	return format.Message(materialsOf(actual), "to contain git material", fmt.Sprintf("%s@%s", matcher.url, matcher.commit))
+}
+
+// NegatedFailureMessage returns negated failure message for a GitMaterial matcher.
+func (matcher *GitMaterialMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return format.Message(materialsOf(actual), "not to contain git material", fmt.Sprintf("%s@%s", matcher.url, matcher.commit))
+}
+
+func materialsOf(actual interface{}) interface{} {
+	if p, ok := actual.(*Provenance); ok {
+		return p.Materials
+	}
+	return actual
+}
+
+// HaveGitMaterial succeeds if the provenance contains the git repository at the commit among its materials,
+// e.g. the git source URL of the Component and the commit SHA the PipelineRun was triggered for.
+func HaveGitMaterial(url, commit string) types.GomegaMatcher {
+	return &GitMaterialMatcher{url: url, commit: commit}
+}
+
+type SubjectDigestMatcher struct {
+	digest string
+}
+
+// Match matches the matcher with a given provenance or statement.
+func (matcher *SubjectDigestMatcher) Match(actual interface{}) (success bool, err error) {
+	subjects, err := subjectsOf(actual)
+	if err != nil {
+		return false, err
+	}
+	algorithm, hex, found := strings.Cut(matcher.digest, ":")
+	if !found {
+		return false, fmt.Errorf("digest %q is not in the <algorithm>:<hex> form", matcher.digest)
+	}
+	for _, s := range subjects {
+		if s.Digest[algorithm] == hex {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FailureMessage returns failure message for a SubjectDigest matcher.
+func (matcher *SubjectDigestMatcher) FailureMessage(actual interface{}) (message string) {
+	subjects, _ := subjectsOf(actual)
+	return format.Message(subjects, "to contain subject with digest", matcher.digest)
+}
+
+// NegatedFailureMessage returns negated failure message for a SubjectDigest matcher.
+func (matcher *SubjectDigestMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	subjects, _ := subjectsOf(actual)
+	return format.Message(subjects, "not to contain subject with digest", matcher.digest)
+}
+
+func subjectsOf(actual interface{}) ([]Subject, error) {
+	switch a := actual.(type) {
+	case *Provenance:
+		return a.Subjects, nil
+	case *Statement:
+		return a.Subject, nil
+	}
+	return nil, fmt.Errorf("HaveSubjectDigest matcher expects a *Provenance or a *Statement, got %T", actual)
+}
+
+// HaveSubjectDigest succeeds if one of the subjects has the digest of the image, which is either
+// a digest (sha256:abcd...) or an image reference with a digest (quay.io/org/repo@sha256:abcd...).
+func HaveSubjectDigest(image string) types.GomegaMatcher {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		image = image[i+1:]
+	}
+	return &SubjectDigestMatcher{digest: image}
+}
@@ -0,0 +1,123 @@
+package attestation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+const (
+	SlsaProvenanceV02 = "https://slsa.dev/provenance/v0.2"
+	SlsaProvenanceV1  = "https://slsa.dev/provenance/v1"
+)
+
+// Provenance holds the fields of a SLSA provenance predicate which are common to v0.2 and v1, so tests
+// can assert them regardless of the provenance format Tekton Chains is configured to produce.
+type Provenance struct {
+	PredicateType string
+	BuilderID     string
+	BuildType     string
+	// Materials are the materials (v0.2) or the resolved dependencies (v1) of the build
+	Materials []Material
+	// Parameters are the invocation parameters (v0.2) or the external parameters (v1) of the build,
+	// parameters of the PipelineRun spec are flattened into name/value pairs
+	Parameters map[string]interface{}
+	Subjects   []Subject
+	Predicate  json.RawMessage
+}
+
+// Material is an artifact the build consumed, e.g. the git repository at a commit.
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+	Name   string            `json:"name,omitempty"`
+}
+
+type provenancePredicateV02 struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	BuildType  string `json:"buildType"`
+	Invocation struct {
+		Parameters map[string]interface{} `json:"parameters"`
+	} `json:"invocation"`
+	Materials []Material `json:"materials"`
+}
+
+type provenancePredicateV1 struct {
+	BuildDefinition struct {
+		BuildType            string                 `json:"buildType"`
+		ExternalParameters   map[string]interface{} `json:"externalParameters"`
+		ResolvedDependencies []Material             `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+}
+
+// ParseProvenance parses the SLSA v0.2 or v1 provenance predicate of the statement.
+func ParseProvenance(statement *Statement) (*Provenance, error) {
+	p := &Provenance{PredicateType: statement.PredicateType, Subjects: statement.Subject, Predicate: statement.Predicate}
+	switch statement.PredicateType {
+	case SlsaProvenanceV02:
+		predicate := provenancePredicateV02{}
+		if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+			return nil, fmt.Errorf("error when parsing SLSA v0.2 provenance: %v", err)
+		}
+		p.BuilderID = predicate.Builder.ID
+		p.BuildType = predicate.BuildType
+		p.Materials = predicate.Materials
+		p.Parameters = predicate.Invocation.Parameters
+	case SlsaProvenanceV1:
+		predicate := provenancePredicateV1{}
+		if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+			return nil, fmt.Errorf("error when parsing SLSA v1 provenance: %v", err)
+		}
+		p.BuilderID = predicate.RunDetails.Builder.ID
+		p.BuildType = predicate.BuildDefinition.BuildType
+		p.Materials = predicate.BuildDefinition.ResolvedDependencies
+		p.Parameters = externalParameters(predicate.BuildDefinition.ExternalParameters)
+	default:
+		return nil, fmt.Errorf("predicate type %q is not a SLSA provenance", statement.PredicateType)
+	}
+	if p.Parameters == nil {
+		p.Parameters = map[string]interface{}{}
+	}
+	return p, nil
+}
+
+// externalParameters returns the external parameters with params of the PipelineRun/TaskRun spec
+// (recorded by Tekton Chains under "runSpec") added as name/value pairs
+func externalParameters(external map[string]interface{}) map[string]interface{} {
+	parameters := map[string]interface{}{}
+	for k, v := range external {
+		parameters[k] = v
+	}
+	runSpec, _ := external["runSpec"].(map[string]interface{})
+	params, _ := runSpec["params"].([]interface{})
+	for _, param := range params {
+		if p, ok := param.(map[string]interface{}); ok {
+			if name, ok := p["name"].(string); ok {
+				parameters[name] = p["value"]
+			}
+		}
+	}
+	return parameters
+}
+
+// GetProvenance fetches and verifies the attestations of the image and returns the first SLSA provenance.
+func GetProvenance(imageRef string, publicKey []byte, opts ...remote.Option) (*Provenance, error) {
+	statements, err := FetchStatements(imageRef, publicKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range statements {
+		if statement.PredicateType == SlsaProvenanceV02 || statement.PredicateType == SlsaProvenanceV1 {
+			return ParseProvenance(statement)
+		}
+	}
+	return nil, fmt.Errorf("no SLSA provenance found in %d attestation(s) of image %s", len(statements), imageRef)
+}
@@ -0,0 +1,53 @@
+package attestation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+)
+
+// Statement is an in-toto statement, see https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+type Statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []Subject       `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// Subject is an artifact the statement is about, e.g. the built image.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// ParseStatement parses the payload of a DSSE envelope as an in-toto statement.
+func ParseStatement(payload []byte) (*Statement, error) {
+	statement := &Statement{}
+	if err := json.Unmarshal(payload, statement); err != nil {
+		return nil, fmt.Errorf("error when parsing in-toto statement: %v", err)
+	}
+	if statement.Type == "" || statement.PredicateType == "" {
+		return nil, fmt.Errorf("payload is not an in-toto statement, _type or predicateType is missing")
+	}
+	return statement, nil
+}
+
+// FetchStatements downloads the attestations of the image, verifies their DSSE envelopes against the PEM encoded
+// public key (e.g. the Tekton Chains one) and returns the decoded in-toto statements.
+func FetchStatements(imageRef string, publicKey []byte, opts ...remote.Option) ([]*Statement, error) {
+	payloads, err := tekton.VerifyImageAttestations(imageRef, publicKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	statements := []*Statement{}
+	for _, payload := range payloads {
+		statement, err := ParseStatement(payload)
+		if err != nil {
+			return nil, fmt.Errorf("attestation of image %s is invalid: %v", imageRef, err)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
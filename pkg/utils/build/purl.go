@@ -0,0 +1,137 @@
+package build
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	purlTypeRegex      = regexp.MustCompile(`^[a-zA-Z.+-][a-zA-Z0-9.+-]*$`)
+	purlQualifierRegex = regexp.MustCompile(`^[a-zA-Z.\-_][a-zA-Z0-9.\-_]*$`)
+)
+
+// Purl is a parsed package URL, see https://github.com/package-url/purl-spec
+type Purl struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// ParsePurl parses and validates a package URL in the form pkg:type/namespace/name@version?qualifiers#subpath.
+func ParsePurl(purl string) (*Purl, error) {
+	remainder, found := strings.CutPrefix(purl, "pkg:")
+	if !found {
+		return nil, fmt.Errorf("purl %q doesn't start with the \"pkg:\" scheme", purl)
+	}
+	p := &Purl{Qualifiers: map[string]string{}}
+
+	var err error
+	if i := strings.LastIndex(remainder, "#"); i >= 0 {
+		if p.Subpath, err = unescapePurlPath(remainder[i+1:]); err != nil {
+			return nil, fmt.Errorf("invalid subpath of purl %q: %v", purl, err)
+		}
+		remainder = remainder[:i]
+	}
+	if i := strings.LastIndex(remainder, "?"); i >= 0 {
+		for _, pair := range strings.Split(remainder[i+1:], "&") {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || value == "" {
+				return nil, fmt.Errorf("invalid qualifier %q of purl %q", pair, purl)
+			}
+			if !purlQualifierRegex.MatchString(key) {
+				return nil, fmt.Errorf("invalid qualifier key %q of purl %q", key, purl)
+			}
+			if p.Qualifiers[strings.ToLower(key)], err = url.PathUnescape(value); err != nil {
+				return nil, fmt.Errorf("invalid qualifier value %q of purl %q: %v", value, purl, err)
+			}
+		}
+		remainder = remainder[:i]
+	}
+
+	remainder = strings.Trim(remainder, "/")
+	typ, path, found := strings.Cut(remainder, "/")
+	if !found || !purlTypeRegex.MatchString(typ) {
+		return nil, fmt.Errorf("purl %q has an invalid or missing type", purl)
+	}
+	p.Type = strings.ToLower(typ)
+
+	// "@" may also appear in an unescaped namespace, e.g. pkg:npm/@angular/core
+	if i := strings.LastIndex(path, "@"); i > strings.LastIndex(path, "/") {
+		if p.Version, err = url.PathUnescape(path[i+1:]); err != nil || p.Version == "" {
+			return nil, fmt.Errorf("invalid version of purl %q", purl)
+		}
+		path = path[:i]
+	}
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		if p.Namespace, err = unescapePurlPath(path[:i]); err != nil {
+			return nil, fmt.Errorf("invalid namespace of purl %q: %v", purl, err)
+		}
+		path = path[i+1:]
+	}
+	if p.Name, err = url.PathUnescape(path); err != nil || p.Name == "" {
+		return nil, fmt.Errorf("purl %q has an invalid or missing name", purl)
+	}
+	return p, nil
+}
+
+func unescapePurlPath(path string) (string, error) {
+	segments := []string{}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return "", err
+		}
+		segments = append(segments, unescaped)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// Key identifies the package regardless of its version, e.g. "rpm/redhat/openssl?arch=x86_64".
+// The arch qualifier is kept as packages built for different architectures are distinct.
+func (p *Purl) Key() string {
+	key := p.Type + "/" + p.Name
+	if p.Namespace != "" {
+		key = p.Type + "/" + p.Namespace + "/" + p.Name
+	}
+	if arch := p.Qualifiers["arch"]; arch != "" {
+		key += "?arch=" + arch
+	}
+	return key
+}
+
+// String returns the canonical form of the purl with sorted qualifiers.
+func (p *Purl) String() string {
+	var sb strings.Builder
+	sb.WriteString("pkg:" + p.Type + "/")
+	if p.Namespace != "" {
+		for _, segment := range strings.Split(p.Namespace, "/") {
+			sb.WriteString(url.PathEscape(segment) + "/")
+		}
+	}
+	sb.WriteString(url.PathEscape(p.Name))
+	if p.Version != "" {
+		sb.WriteString("@" + url.PathEscape(p.Version))
+	}
+	if len(p.Qualifiers) > 0 {
+		keys := []string{}
+		for k := range p.Qualifiers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := []string{}
+		for _, k := range keys {
+			pairs = append(pairs, k+"="+url.PathEscape(p.Qualifiers[k]))
+		}
+		sb.WriteString("?" + strings.Join(pairs, "&"))
+	}
+	if p.Subpath != "" {
+		sb.WriteString("#" + p.Subpath)
+	}
+	return sb.String()
+}
@@ -10,16 +10,16 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/openshift/library-go/pkg/image/reference"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	quay "github.com/redhat-appstudio/image-controller/pkg/quay"
 	corev1 "k8s.io/api/core/v1"
 )
 
 var (
 	quayApiUrl = "https://quay.io/api/v1"
-	quayOrg    = utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
-	quayToken  = utils.GetEnv("DEFAULT_QUAY_ORG_TOKEN", "")
+	quayOrg    = config.Get(constants.DEFAULT_QUAY_ORG_ENV)
+	quayToken  = config.Get("DEFAULT_QUAY_ORG_TOKEN")
 	quayClient = quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayToken, quayApiUrl)
 )
 
@@ -33,16 +33,77 @@ type SbomPurl struct {
 	} `json:"image_contents"`
 }
 
+// SbomCyclonedx is a CycloneDX 1.4/1.5 JSON document, see https://cyclonedx.org/docs/1.5/json/
 type SbomCyclonedx struct {
-	BomFormat   string
-	SpecVersion string
-	Version     int
-	Components  []struct {
-		Name    string `json:"name"`
-		Purl    string `json:"purl"`
-		Type    string `json:"type"`
-		Version string `json:"version"`
-	} `json:"components"`
+	BomFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber,omitempty"`
+	Version      int                   `json:"version"`
+	Metadata     *CyclonedxMetadata    `json:"metadata,omitempty"`
+	Components   []CyclonedxComponent  `json:"components"`
+	Dependencies []CyclonedxDependency `json:"dependencies,omitempty"`
+}
+
+type CyclonedxMetadata struct {
+	Timestamp string              `json:"timestamp,omitempty"`
+	Component *CyclonedxComponent `json:"component,omitempty"`
+	// Tools is an array of tools in CycloneDX 1.4 and an object with components and services in 1.5
+	Tools json.RawMessage `json:"tools,omitempty"`
+}
+
+type CyclonedxComponent struct {
+	BOMRef     string               `json:"bom-ref,omitempty"`
+	Type       string               `json:"type"`
+	Group      string               `json:"group,omitempty"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version,omitempty"`
+	Purl       string               `json:"purl,omitempty"`
+	CPE        string               `json:"cpe,omitempty"`
+	Hashes     []CyclonedxHash      `json:"hashes,omitempty"`
+	Licenses   []CyclonedxLicense   `json:"licenses,omitempty"`
+	Properties []CyclonedxProperty  `json:"properties,omitempty"`
+	Components []CyclonedxComponent `json:"components,omitempty"`
+}
+
+type CyclonedxHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type CyclonedxLicense struct {
+	License *struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"license,omitempty"`
+	Expression string `json:"expression,omitempty"`
+}
+
+type CyclonedxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CyclonedxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// ParseSbomPurl parses the sbom-purl.json content.
+func ParseSbomPurl(data []byte) (*SbomPurl, error) {
+	sbom := &SbomPurl{}
+	if err := json.Unmarshal(data, sbom); err != nil {
+		return nil, fmt.Errorf("error when parsing sbom PURL json: %v", err)
+	}
+	return sbom, nil
+}
+
+// ParseSbomCyclonedx parses a CycloneDX JSON document.
+func ParseSbomCyclonedx(data []byte) (*SbomCyclonedx, error) {
+	sbom := &SbomCyclonedx{}
+	if err := json.Unmarshal(data, sbom); err != nil {
+		return nil, fmt.Errorf("error when parsing sbom CycloneDX json: %v", err)
+	}
+	return sbom, nil
 }
 
 func getSbomPurlContent(rootDir string) (*SbomPurl, error) {
@@ -59,12 +120,7 @@ func getSbomPurlContent(rootDir string) (*SbomPurl, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error when reading sbom file %s: %v", sbomPurlFilePath, err)
 	}
-	sbom := &SbomPurl{}
-	if err := json.Unmarshal(b, sbom); err != nil {
-		return nil, fmt.Errorf("error when parsing sbom PURL json: %v", err)
-	}
-
-	return sbom, nil
+	return ParseSbomPurl(b)
 }
 
 func getSbomCyclonedxContent(rootDir string) (*SbomCyclonedx, error) {
@@ -81,10 +137,5 @@ func getSbomCyclonedxContent(rootDir string) (*SbomCyclonedx, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error when reading sbom file %s: %v", sbomCyclonedxFilePath, err)
 	}
-	sbom := &SbomCyclonedx{}
-	if err := json.Unmarshal(b, sbom); err != nil {
-		return nil, fmt.Errorf("error when parsing sbom CycloneDX json: %v", err)
-	}
-
-	return sbom, nil
+	return ParseSbomCyclonedx(b)
 }
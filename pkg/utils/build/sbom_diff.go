@@ -0,0 +1,164 @@
+package build
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SbomPackage is a package listed in an SBOM, identified by its purl.
+type SbomPackage struct {
+	Name    string
+	Version string
+	Purl    string
+}
+
+// key identifies the package regardless of its version
+func (p SbomPackage) key() string {
+	if purl, err := ParsePurl(p.Purl); err == nil {
+		return purl.Key()
+	}
+	return p.Name
+}
+
+// SbomPackages returns all components of the CycloneDX SBOM which have a purl, including nested components.
+func (s *SbomCyclonedx) SbomPackages() []SbomPackage {
+	packages := []SbomPackage{}
+	var collect func(components []CyclonedxComponent)
+	collect = func(components []CyclonedxComponent) {
+		for _, c := range components {
+			if c.Purl != "" {
+				packages = append(packages, SbomPackage{Name: c.Name, Version: c.Version, Purl: c.Purl})
+			}
+			collect(c.Components)
+		}
+	}
+	collect(s.Components)
+	return packages
+}
+
+// SbomPackages returns all packages of the SPDX SBOM which have a purl.
+func (s *SbomSpdx) SbomPackages() []SbomPackage {
+	packages := []SbomPackage{}
+	for _, p := range s.Packages {
+		for _, purl := range p.Purls() {
+			packages = append(packages, SbomPackage{Name: p.Name, Version: p.VersionInfo, Purl: purl})
+		}
+	}
+	return packages
+}
+
+// SbomPackages returns all packages of the purl SBOM.
+func (s *SbomPurl) SbomPackages() []SbomPackage {
+	packages := []SbomPackage{}
+	for _, d := range s.ImageContents.Dependencies {
+		p := SbomPackage{Purl: d.Purl}
+		if purl, err := ParsePurl(d.Purl); err == nil {
+			p.Name, p.Version = purl.Name, purl.Version
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+// SbomPackageChange is a package present in both SBOMs in different versions.
+type SbomPackageChange struct {
+	Key         string
+	OldVersions []string
+	NewVersions []string
+}
+
+// SbomDiff lists differences between the package sets of two SBOMs.
+type SbomDiff struct {
+	Added   []SbomPackage
+	Removed []SbomPackage
+	// Upgraded are packages present in both SBOMs in different versions (it includes downgrades as well)
+	Upgraded []SbomPackageChange
+}
+
+// IsEmpty returns true if both SBOMs describe the same packages.
+func (d *SbomDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Upgraded) == 0
+}
+
+// FindUpgrade returns the change of the package with the given name (or purl key, e.g. "maven/org.slf4j/slf4j-api"), or nil.
+func (d *SbomDiff) FindUpgrade(name string) *SbomPackageChange {
+	for i, c := range d.Upgraded {
+		if c.Key == name || strings.HasSuffix(c.Key, "/"+name) {
+			return &d.Upgraded[i]
+		}
+	}
+	return nil
+}
+
+func (d *SbomDiff) String() string {
+	if d.IsEmpty() {
+		return "SBOMs describe the same packages"
+	}
+	var sb strings.Builder
+	for _, p := range d.Added {
+		fmt.Fprintf(&sb, "+ %s\n", p.Purl)
+	}
+	for _, p := range d.Removed {
+		fmt.Fprintf(&sb, "- %s\n", p.Purl)
+	}
+	for _, c := range d.Upgraded {
+		fmt.Fprintf(&sb, "~ %s: %s -> %s\n", c.Key, strings.Join(c.OldVersions, ", "), strings.Join(c.NewVersions, ", "))
+	}
+	return sb.String()
+}
+
+// DiffSbomPackages compares package sets of two SBOMs. Packages are matched by their purl without the version,
+// so a package which changed its version is reported as upgraded rather than as removed and added.
+func DiffSbomPackages(oldPackages, newPackages []SbomPackage) *SbomDiff {
+	group := func(packages []SbomPackage) map[string][]SbomPackage {
+		grouped := map[string][]SbomPackage{}
+		for _, p := range packages {
+			grouped[p.key()] = append(grouped[p.key()], p)
+		}
+		return grouped
+	}
+	versions := func(packages []SbomPackage) []string {
+		set := map[string]bool{}
+		for _, p := range packages {
+			set[p.Version] = true
+		}
+		return sortedKeys(set)
+	}
+	oldGrouped, newGrouped := group(oldPackages), group(newPackages)
+
+	diff := &SbomDiff{}
+	for key, packages := range newGrouped {
+		old, found := oldGrouped[key]
+		if !found {
+			diff.Added = append(diff.Added, packages...)
+			continue
+		}
+		if oldVersions, newVersions := versions(old), versions(packages); strings.Join(oldVersions, ",") != strings.Join(newVersions, ",") {
+			diff.Upgraded = append(diff.Upgraded, SbomPackageChange{Key: key, OldVersions: oldVersions, NewVersions: newVersions})
+		}
+	}
+	for key, packages := range oldGrouped {
+		if _, found := newGrouped[key]; !found {
+			diff.Removed = append(diff.Removed, packages...)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Purl < diff.Added[j].Purl })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Purl < diff.Removed[j].Purl })
+	sort.Slice(diff.Upgraded, func(i, j int) bool { return diff.Upgraded[i].Key < diff.Upgraded[j].Key })
+	return diff
+}
+
+// GetSbomDiffBetweenImages extracts CycloneDX SBOMs of both images and compares their package sets.
+func GetSbomDiffBetweenImages(oldImage, newImage string) (*SbomDiff, error) {
+	_, oldSbom, err := GetParsedSbomFilesContentFromImage(oldImage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SBOM of image %s: %v", oldImage, err)
+	}
+	_, newSbom, err := GetParsedSbomFilesContentFromImage(newImage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SBOM of image %s: %v", newImage, err)
+	}
+	return DiffSbomPackages(oldSbom.SbomPackages(), newSbom.SbomPackages()), nil
+}
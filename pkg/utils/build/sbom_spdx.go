@@ -0,0 +1,83 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SbomSpdx is an SPDX 2.3 JSON document, see https://spdx.github.io/spdx-spec/v2.3/
+type SbomSpdx struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SpdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []SpdxPackage      `json:"packages"`
+	Files             []SpdxFile         `json:"files,omitempty"`
+	Relationships     []SpdxRelationship `json:"relationships,omitempty"`
+}
+
+type SpdxCreationInfo struct {
+	Created            string   `json:"created"`
+	Creators           []string `json:"creators"`
+	LicenseListVersion string   `json:"licenseListVersion,omitempty"`
+}
+
+type SpdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Supplier         string            `json:"supplier,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    *bool             `json:"filesAnalyzed,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded,omitempty"`
+	LicenseDeclared  string            `json:"licenseDeclared,omitempty"`
+	CopyrightText    string            `json:"copyrightText,omitempty"`
+	Checksums        []SpdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []SpdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SpdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type SpdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SpdxFile struct {
+	SPDXID    string         `json:"SPDXID"`
+	FileName  string         `json:"fileName"`
+	Checksums []SpdxChecksum `json:"checksums,omitempty"`
+}
+
+type SpdxRelationship struct {
+	SpdxElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// Purls returns package URLs referenced by the package.
+func (p SpdxPackage) Purls() []string {
+	purls := []string{}
+	for _, ref := range p.ExternalRefs {
+		if ref.ReferenceType == "purl" {
+			purls = append(purls, ref.ReferenceLocator)
+		}
+	}
+	return purls
+}
+
+// ParseSbomSpdx parses an SPDX JSON document.
+func ParseSbomSpdx(data []byte) (*SbomSpdx, error) {
+	sbom := &SbomSpdx{}
+	if err := json.Unmarshal(data, sbom); err != nil {
+		return nil, fmt.Errorf("error when parsing sbom SPDX json: %v", err)
+	}
+	return sbom, nil
+}
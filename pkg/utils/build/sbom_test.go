@@ -0,0 +1,124 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cyclonedxSbom = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "serialNumber": "urn:uuid:1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+  "version": 1,
+  "metadata": {"timestamp": "2024-01-01T10:00:00Z", "tools": {"components": [{"type": "application", "name": "syft"}]}},
+  "components": [
+    {"bom-ref": "a", "type": "library", "name": "slf4j-api", "version": "1.7.36", "purl": "pkg:maven/org.slf4j/slf4j-api@1.7.36",
+     "hashes": [{"alg": "SHA-256", "content": "d3c4ad2b2a3e53b4a3b4ba44d1e0ee5ce5abe2a3fbd5e7dd0b4f4d5b6a7c8d9e"}]},
+    {"bom-ref": "b", "type": "library", "name": "core", "version": "16.2.0", "purl": "pkg:npm/@angular/core@16.2.0"},
+    {"bom-ref": "c", "type": "operating-system", "name": "rhel", "version": "9.2"}
+  ],
+  "dependencies": [{"ref": "a", "dependsOn": ["b"]}]
+}`
+
+func TestParsePurl(t *testing.T) {
+	p, err := ParsePurl("pkg:rpm/redhat/openssl-libs@1:3.0.7-24.el9?arch=x86_64&distro=rhel-9.2")
+	assert.NoError(t, err)
+	assert.Equal(t, &Purl{Type: "rpm", Namespace: "redhat", Name: "openssl-libs", Version: "1:3.0.7-24.el9", Qualifiers: map[string]string{"arch": "x86_64", "distro": "rhel-9.2"}}, p)
+	assert.Equal(t, "rpm/redhat/openssl-libs?arch=x86_64", p.Key())
+	assert.Equal(t, "pkg:rpm/redhat/openssl-libs@1:3.0.7-24.el9?arch=x86_64&distro=rhel-9.2", p.String())
+
+	p, err = ParsePurl("pkg:npm/@angular/core")
+	assert.NoError(t, err)
+	assert.Equal(t, "@angular", p.Namespace)
+	assert.Empty(t, p.Version)
+
+	p, err = ParsePurl("pkg:golang/github.com/gorilla/context@v1.1.1#api")
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/gorilla", p.Namespace)
+	assert.Equal(t, "api", p.Subpath)
+
+	for _, invalid := range []string{"maven/org.slf4j/slf4j-api@1.7.36", "pkg:slf4j-api", "pkg:1maven/x@1", "pkg:maven/org.slf4j/@1.0", "pkg:pypi/django@1.0?arch"} {
+		_, err := ParsePurl(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestValidateCyclonedx(t *testing.T) {
+	sbom, err := ParseSbomCyclonedx([]byte(cyclonedxSbom))
+	assert.NoError(t, err)
+	assert.NoError(t, sbom.Validate())
+
+	sbom.SpecVersion = "1.2"
+	sbom.Components = append(sbom.Components, CyclonedxComponent{BOMRef: "a", Type: "binary", Name: "broken", Purl: "pkg:broken"})
+	sbom.Dependencies = append(sbom.Dependencies, CyclonedxDependency{Ref: "missing"})
+	err = sbom.Validate()
+	assert.ErrorContains(t, err, `specVersion "1.2" is not one of [1.4 1.5]`)
+	assert.ErrorContains(t, err, `components[3] (broken) has unknown type "binary"`)
+	assert.ErrorContains(t, err, `components[3] (broken): purl "pkg:broken" has an invalid or missing type`)
+	assert.ErrorContains(t, err, `components[3] (broken) has duplicate bom-ref "a"`)
+	assert.ErrorContains(t, err, `dependency refers to unknown bom-ref "missing"`)
+}
+
+func TestValidateSpdx(t *testing.T) {
+	sbom, err := ParseSbomSpdx([]byte(`{
+  "spdxVersion": "SPDX-2.3", "dataLicense": "CC0-1.0", "SPDXID": "SPDXRef-DOCUMENT", "name": "image",
+  "documentNamespace": "https://example.com/spdx/image-1",
+  "creationInfo": {"created": "2024-01-01T10:00:00Z", "creators": ["Tool: syft"]},
+  "documentDescribes": ["SPDXRef-image"],
+  "packages": [
+    {"SPDXID": "SPDXRef-image", "name": "image", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-slf4j", "name": "slf4j-api", "versionInfo": "1.7.36", "downloadLocation": "NOASSERTION",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:maven/org.slf4j/slf4j-api@1.7.36"}]}
+  ],
+  "relationships": [{"spdxElementId": "SPDXRef-image", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-slf4j"}]
+}`))
+	assert.NoError(t, err)
+	assert.NoError(t, sbom.Validate())
+	assert.Equal(t, []SbomPackage{{Name: "slf4j-api", Version: "1.7.36", Purl: "pkg:maven/org.slf4j/slf4j-api@1.7.36"}}, sbom.SbomPackages())
+
+	sbom.Packages[1].SPDXID = "slf4j"
+	sbom.Relationships[0].RelatedSpdxElement = "SPDXRef-missing"
+	sbom.CreationInfo.Created = "yesterday"
+	err = sbom.Validate()
+	assert.ErrorContains(t, err, `packages[1] slf4j-api has invalid SPDXID "slf4j"`)
+	assert.ErrorContains(t, err, `refers to unknown element "SPDXRef-missing"`)
+	assert.ErrorContains(t, err, `creationInfo.created "yesterday" is not a RFC3339 timestamp`)
+}
+
+func TestCheckSbomConsistency(t *testing.T) {
+	cyclonedx, err := ParseSbomCyclonedx([]byte(cyclonedxSbom))
+	assert.NoError(t, err)
+	purl, err := ParseSbomPurl([]byte(`{"image_contents": {"dependencies": [
+		{"purl": "pkg:maven/org.slf4j/slf4j-api@1.7.36"}, {"purl": "pkg:npm/%40angular/core@16.2.0"}
+	]}}`))
+	assert.NoError(t, err)
+	assert.NoError(t, CheckSbomConsistency(purl, cyclonedx))
+
+	purl.ImageContents.Dependencies[1].Purl = "pkg:npm/lodash@4.17.21"
+	err = CheckSbomConsistency(purl, cyclonedx)
+	assert.ErrorContains(t, err, "pkg:npm/lodash@4.17.21 is in the purl SBOM but not in the CycloneDX SBOM")
+	assert.ErrorContains(t, err, "pkg:npm/@angular/core@16.2.0 is in the CycloneDX SBOM but not in the purl SBOM")
+}
+
+func TestDiffSbomPackages(t *testing.T) {
+	old := []SbomPackage{
+		{Name: "slf4j-api", Version: "1.7.36", Purl: "pkg:maven/org.slf4j/slf4j-api@1.7.36"},
+		{Name: "commons-io", Version: "2.11.0", Purl: "pkg:maven/commons-io/commons-io@2.11.0"},
+		{Name: "openssl", Version: "3.0.7", Purl: "pkg:rpm/redhat/openssl@3.0.7?arch=x86_64"},
+	}
+	updated := []SbomPackage{
+		{Name: "slf4j-api", Version: "2.0.9", Purl: "pkg:maven/org.slf4j/slf4j-api@2.0.9"},
+		{Name: "jackson-core", Version: "2.15.2", Purl: "pkg:maven/com.fasterxml.jackson.core/jackson-core@2.15.2"},
+		{Name: "openssl", Version: "3.0.7", Purl: "pkg:rpm/redhat/openssl@3.0.7?arch=x86_64&distro=rhel-9"},
+	}
+
+	diff := DiffSbomPackages(old, updated)
+	assert.Equal(t, []SbomPackage{updated[1]}, diff.Added)
+	assert.Equal(t, []SbomPackage{old[1]}, diff.Removed)
+	assert.Equal(t, []SbomPackageChange{{Key: "maven/org.slf4j/slf4j-api", OldVersions: []string{"1.7.36"}, NewVersions: []string{"2.0.9"}}}, diff.Upgraded)
+	assert.NotNil(t, diff.FindUpgrade("slf4j-api"))
+	assert.Nil(t, diff.FindUpgrade("openssl"))
+	assert.Contains(t, diff.String(), "~ maven/org.slf4j/slf4j-api: 1.7.36 -> 2.0.9")
+	assert.True(t, DiffSbomPackages(old, old).IsEmpty())
+}
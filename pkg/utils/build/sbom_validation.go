@@ -0,0 +1,235 @@
+package build
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	cyclonedxSpecVersions   = []string{"1.4", "1.5"}
+	cyclonedxComponentTypes = []string{
+		"application", "framework", "library", "container", "operating-system", "device", "firmware", "file",
+		// added in CycloneDX 1.5
+		"platform", "device-driver", "machine-learning-model", "data",
+	}
+	cyclonedxHashAlgorithms = []string{
+		"MD5", "SHA-1", "SHA-256", "SHA-384", "SHA-512", "SHA3-256", "SHA3-384", "SHA3-512",
+		"BLAKE2b-256", "BLAKE2b-384", "BLAKE2b-512", "BLAKE3",
+	}
+	spdxRelationshipElements = []string{"NOASSERTION", "NONE"}
+)
+
+// sbomProblems collects problems found when validating an SBOM
+type sbomProblems []string
+
+func (p *sbomProblems) addf(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+func (p sbomProblems) err(what string) error {
+	if len(p) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s is invalid:\n  - %s", what, strings.Join(p, "\n  - "))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the structure of the CycloneDX document: the format and spec version, required component fields,
+// component types, purl syntax, hashes, uniqueness of bom-refs and references of the dependency graph.
+func (s *SbomCyclonedx) Validate() error {
+	problems := sbomProblems{}
+	if s.BomFormat != "CycloneDX" {
+		problems.addf("bomFormat is %q, expected \"CycloneDX\"", s.BomFormat)
+	}
+	if !contains(cyclonedxSpecVersions, s.SpecVersion) {
+		problems.addf("specVersion %q is not one of %v", s.SpecVersion, cyclonedxSpecVersions)
+	}
+	if s.Version < 1 {
+		problems.addf("version has to be at least 1, got %d", s.Version)
+	}
+	if s.SerialNumber != "" && !strings.HasPrefix(s.SerialNumber, "urn:uuid:") {
+		problems.addf("serialNumber %q is not a UUID URN", s.SerialNumber)
+	}
+	if s.Metadata != nil && s.Metadata.Timestamp != "" {
+		if _, err := time.Parse(time.RFC3339, s.Metadata.Timestamp); err != nil {
+			problems.addf("metadata.timestamp %q is not a RFC3339 timestamp", s.Metadata.Timestamp)
+		}
+	}
+
+	refs := map[string]bool{}
+	var validateComponent func(path string, c CyclonedxComponent)
+	validateComponent = func(path string, c CyclonedxComponent) {
+		if c.Name == "" {
+			problems.addf("%s has no name", path)
+		}
+		if !contains(cyclonedxComponentTypes, c.Type) {
+			problems.addf("%s (%s) has unknown type %q", path, c.Name, c.Type)
+		}
+		if c.Purl != "" {
+			if _, err := ParsePurl(c.Purl); err != nil {
+				problems.addf("%s (%s): %v", path, c.Name, err)
+			}
+		}
+		if c.BOMRef != "" {
+			if refs[c.BOMRef] {
+				problems.addf("%s (%s) has duplicate bom-ref %q", path, c.Name, c.BOMRef)
+			}
+			refs[c.BOMRef] = true
+		}
+		for _, h := range c.Hashes {
+			if !contains(cyclonedxHashAlgorithms, h.Algorithm) {
+				problems.addf("%s (%s) has a hash with unknown algorithm %q", path, c.Name, h.Algorithm)
+			} else if _, err := hex.DecodeString(h.Content); err != nil || h.Content == "" {
+				problems.addf("%s (%s) has an invalid %s hash %q", path, c.Name, h.Algorithm, h.Content)
+			}
+		}
+		for i, nested := range c.Components {
+			validateComponent(fmt.Sprintf("%s.components[%d]", path, i), nested)
+		}
+	}
+	if s.Metadata != nil && s.Metadata.Component != nil {
+		validateComponent("metadata.component", *s.Metadata.Component)
+	}
+	for i, c := range s.Components {
+		validateComponent(fmt.Sprintf("components[%d]", i), c)
+	}
+
+	for _, d := range s.Dependencies {
+		if !refs[d.Ref] {
+			problems.addf("dependency refers to unknown bom-ref %q", d.Ref)
+		}
+		for _, dependsOn := range d.DependsOn {
+			if !refs[dependsOn] {
+				problems.addf("dependency %q depends on unknown bom-ref %q", d.Ref, dependsOn)
+			}
+		}
+	}
+	return problems.err("CycloneDX SBOM")
+}
+
+// Validate checks the structure of the SPDX 2.3 document: the document creation information, required package
+// fields, uniqueness of SPDX identifiers, purl syntax of package external references and references of relationships.
+func (s *SbomSpdx) Validate() error {
+	problems := sbomProblems{}
+	if s.SPDXVersion != "SPDX-2.3" {
+		problems.addf("spdxVersion is %q, expected \"SPDX-2.3\"", s.SPDXVersion)
+	}
+	if s.DataLicense != "CC0-1.0" {
+		problems.addf("dataLicense is %q, expected \"CC0-1.0\"", s.DataLicense)
+	}
+	if s.SPDXID != "SPDXRef-DOCUMENT" {
+		problems.addf("SPDXID of the document is %q, expected \"SPDXRef-DOCUMENT\"", s.SPDXID)
+	}
+	if s.Name == "" {
+		problems.addf("document has no name")
+	}
+	if u, err := url.Parse(s.DocumentNamespace); err != nil || u.Scheme == "" || strings.Contains(s.DocumentNamespace, "#") {
+		problems.addf("documentNamespace %q is not an absolute URI without a fragment", s.DocumentNamespace)
+	}
+	if _, err := time.Parse(time.RFC3339, s.CreationInfo.Created); err != nil {
+		problems.addf("creationInfo.created %q is not a RFC3339 timestamp", s.CreationInfo.Created)
+	}
+	if len(s.CreationInfo.Creators) == 0 {
+		problems.addf("creationInfo.creators is empty")
+	}
+
+	ids := map[string]bool{s.SPDXID: true}
+	addID := func(kind, name, id string) {
+		if !strings.HasPrefix(id, "SPDXRef-") {
+			problems.addf("%s %s has invalid SPDXID %q", kind, name, id)
+		}
+		if ids[id] {
+			problems.addf("%s %s has duplicate SPDXID %q", kind, name, id)
+		}
+		ids[id] = true
+	}
+	for i, p := range s.Packages {
+		addID(fmt.Sprintf("packages[%d]", i), p.Name, p.SPDXID)
+		if p.Name == "" {
+			problems.addf("packages[%d] has no name", i)
+		}
+		if p.DownloadLocation == "" {
+			problems.addf("packages[%d] (%s) has no downloadLocation", i, p.Name)
+		}
+		for _, purl := range p.Purls() {
+			if _, err := ParsePurl(purl); err != nil {
+				problems.addf("packages[%d] (%s): %v", i, p.Name, err)
+			}
+		}
+	}
+	for i, f := range s.Files {
+		addID(fmt.Sprintf("files[%d]", i), f.FileName, f.SPDXID)
+	}
+
+	for _, described := range s.DocumentDescribes {
+		if !ids[described] {
+			problems.addf("documentDescribes refers to unknown element %q", described)
+		}
+	}
+	for _, r := range s.Relationships {
+		for _, element := range []string{r.SpdxElementID, r.RelatedSpdxElement} {
+			// elements of other documents are referenced as DocumentRef-<id>:SPDXRef-<id>
+			if !ids[element] && !contains(spdxRelationshipElements, element) && !strings.HasPrefix(element, "DocumentRef-") {
+				problems.addf("relationship %s %s %s refers to unknown element %q", r.SpdxElementID, r.RelationshipType, r.RelatedSpdxElement, element)
+			}
+		}
+	}
+	return problems.err("SPDX SBOM")
+}
+
+// CheckSbomConsistency verifies that the purl SBOM and the CycloneDX SBOM extracted from an image describe
+// the same set of packages (library and application components of the CycloneDX SBOM).
+func CheckSbomConsistency(purlSbom *SbomPurl, cyclonedx *SbomCyclonedx) error {
+	fromPurl := map[string]bool{}
+	for _, d := range purlSbom.ImageContents.Dependencies {
+		fromPurl[normalizePurl(d.Purl)] = true
+	}
+	fromCyclonedx := map[string]bool{}
+	for _, c := range cyclonedx.Components {
+		if (c.Type == "library" || c.Type == "application") && c.Purl != "" {
+			fromCyclonedx[normalizePurl(c.Purl)] = true
+		}
+	}
+
+	problems := sbomProblems{}
+	for _, purl := range sortedKeys(fromPurl) {
+		if !fromCyclonedx[purl] {
+			problems.addf("%s is in the purl SBOM but not in the CycloneDX SBOM", purl)
+		}
+	}
+	for _, purl := range sortedKeys(fromCyclonedx) {
+		if !fromPurl[purl] {
+			problems.addf("%s is in the CycloneDX SBOM but not in the purl SBOM", purl)
+		}
+	}
+	return problems.err("purl SBOM and CycloneDX SBOM consistency")
+}
+
+// normalizePurl returns the canonical form of the purl, or the purl itself if it cannot be parsed.
+func normalizePurl(purl string) string {
+	if p, err := ParsePurl(purl); err == nil {
+		return p.String()
+	}
+	return purl
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
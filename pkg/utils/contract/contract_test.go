@@ -0,0 +1,113 @@
+package contract
+
+import (
+	"testing"
+
+	ecp "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	"github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestPolicySpecBuilder(t *testing.T) {
+	base := ecp.EnterpriseContractPolicySpec{
+		Sources: []ecp.Source{
+			{Name: "release", Policy: []string{"oci::quay.io/enterprise-contract/ec-release-policy:latest"}, RuleData: &extv1.JSON{Raw: []byte(`{"a":1}`)}},
+			{Name: "pipeline", Policy: []string{"oci::quay.io/enterprise-contract/ec-pipeline-policy:latest"}, Config: &ecp.SourceConfig{Include: []string{"old"}}},
+		},
+	}
+	builder := NewPolicySpecFrom(base).
+		WithDescription("e2e").
+		WithCollections("minimal").
+		WithInclude("attestation_type").
+		WithExclude("cve").
+		WithVolatileExclude("tasks.required_tasks_found", "", "2024-01-01T00:00:00Z").
+		WithRuleData("allowed_registry_prefixes", []string{"quay.io/"}).
+		WithPublicKey("k8s://ns/cosign-public-key").
+		WithEffectiveTime("2023-06-01T00:00:00Z")
+
+	spec, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "e2e", spec.Description)
+	assert.Equal(t, "k8s://ns/cosign-public-key", spec.PublicKey)
+	assert.Equal(t, "2023-06-01T00:00:00Z", builder.EffectiveTime())
+	generator := &tekton.VerifyEnterpriseContract{EffectiveTime: "now"}
+	builder.ApplyTo(generator)
+	assert.Equal(t, "2023-06-01T00:00:00Z", generator.EffectiveTime)
+	for _, s := range spec.Sources {
+		assert.Equal(t, &ecp.SourceConfig{Include: []string{"@minimal", "attestation_type"}, Exclude: []string{"cve"}}, s.Config)
+		assert.Equal(t, []ecp.VolatileCriteria{{Value: "tasks.required_tasks_found", EffectiveUntil: "2024-01-01T00:00:00Z"}}, s.VolatileConfig.Exclude)
+	}
+	assert.JSONEq(t, `{"a":1,"allowed_registry_prefixes":["quay.io/"]}`, string(spec.Sources[0].RuleData.Raw))
+	assert.JSONEq(t, `{"allowed_registry_prefixes":["quay.io/"]}`, string(spec.Sources[1].RuleData.Raw))
+	// the base spec is not modified
+	assert.Equal(t, []string{"old"}, base.Sources[1].Config.Include)
+
+	_, err = NewPolicySpec().WithName("empty").Build()
+	assert.ErrorContains(t, err, `policy "empty" has no sources`)
+	assert.Equal(t, "now", NewPolicySpec().EffectiveTime())
+	generator = &tekton.VerifyEnterpriseContract{}
+	NewPolicySpec().ApplyTo(generator)
+	assert.Equal(t, "now", generator.EffectiveTime)
+}
+
+const report = `
+success: false
+ec-version: v0.2
+effective-time: "2023-11-01T00:00:00Z"
+components:
+- name: component
+  containerImage: quay.io/org/repo@sha256:abcd
+  success: false
+  violations:
+  - msg: Pipeline task 'build' uses an unacceptable task bundle
+    metadata:
+      code: attestation_task_bundle.task_ref_bundles_acceptable
+      title: Task bundles are acceptable
+      collections: [minimal]
+  warnings:
+  - msg: Pipeline task 'clone' uses an unpinned task bundle reference
+    metadata:
+      code: attestation_task_bundle.task_ref_bundles_pinned
+  successes:
+  - msg: Pass
+    metadata:
+      code: attestation_type.known_attestation_type
+`
+
+func TestReportMatchers(t *testing.T) {
+	r, err := ParseReport([]byte(report))
+	assert.NoError(t, err)
+	assert.False(t, r.Success)
+	assert.Equal(t, "quay.io/org/repo@sha256:abcd", r.Components[0].ContainerImage)
+	assert.Len(t, r.Violations(), 1)
+	assert.Equal(t, []string{"minimal"}, r.Violations()[0].Metadata.Collections)
+
+	g := gomega.NewWithT(t)
+	g.Expect(r).To(HaveViolation("attestation_task_bundle.task_ref_bundles_acceptable"))
+	g.Expect(r).To(HaveViolation("attestation_task_bundle"))
+	g.Expect(r).NotTo(HaveViolation("attestation_task"))
+	g.Expect(r).NotTo(HaveViolation("attestation_task_bundle.task_ref_bundles_pinned"))
+	g.Expect(r).To(HaveWarning("attestation_task_bundle.task_ref_bundles_pinned"))
+	g.Expect(r).To(HaveSuccess("attestation_type.known_attestation_type"))
+
+	assert.Contains(t, HaveViolation("tasks").FailureMessage(r), "attestation_task_bundle.task_ref_bundles_acceptable: Pipeline task 'build' uses an unacceptable task bundle")
+
+	_, err = HaveViolation("tasks").Match("report")
+	assert.ErrorContains(t, err, "expects a *contract.Report")
+}
+
+func TestGetTestOutput(t *testing.T) {
+	output, err := GetTestOutput([]pipeline.TaskRunResult{
+		{Name: "HACBS_TEST_OUTPUT", Value: *pipeline.NewStructuredValues(`{"result":"SUCCESS"}`)},
+		{Name: "TEST_OUTPUT", Value: *pipeline.NewStructuredValues(`{"result":"WARNING","successes":3,"failures":0,"warnings":1}`)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "WARNING", output.Result)
+	assert.Equal(t, 1, output.Warnings)
+
+	_, err = GetTestOutput(nil)
+	assert.ErrorContains(t, err, "TEST_OUTPUT result not found")
+}
@@ -0,0 +1,67 @@
+package contract
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+type RuleResultMatcher struct {
+	kind    string
+	code    string
+	results func(r *Report) []RuleResult
+}
+
+// Match matches the matcher with a given report.
+func (matcher *RuleResultMatcher) Match(actual interface{}) (success bool, err error) {
+	report, ok := actual.(*Report)
+	if !ok {
+		return false, fmt.Errorf("%s matcher expects a *contract.Report, got %T", matcher.kind, actual)
+	}
+	for _, r := range matcher.results(report) {
+		if r.Matches(matcher.code) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FailureMessage returns failure message for a RuleResult matcher.
+func (matcher *RuleResultMatcher) FailureMessage(actual interface{}) (message string) {
+	return format.Message(matcher.codesOf(actual), fmt.Sprintf("to contain %s", matcher.kind), matcher.code)
+}
+
+// NegatedFailureMessage returns negated failure message for a RuleResult matcher.
+func (matcher *RuleResultMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return format.Message(matcher.codesOf(actual), fmt.Sprintf("not to contain %s", matcher.kind), matcher.code)
+}
+
+// codesOf returns codes and messages of the rule results of the report for failure messages
+func (matcher *RuleResultMatcher) codesOf(actual interface{}) interface{} {
+	report, ok := actual.(*Report)
+	if !ok {
+		return actual
+	}
+	codes := []string{}
+	for _, r := range matcher.results(report) {
+		codes = append(codes, fmt.Sprintf("%s: %s", r.Metadata.Code, r.Msg))
+	}
+	return codes
+}
+
+// HaveViolation succeeds if a component of the report violates the rule (e.g. "tasks.required_tasks_found")
+// or a rule of the package (e.g. "tasks").
+func HaveViolation(code string) types.GomegaMatcher {
+	return &RuleResultMatcher{kind: "violation", code: code, results: (*Report).Violations}
+}
+
+// HaveWarning succeeds if the rule or a rule of the package produced a warning for a component of the report.
+func HaveWarning(code string) types.GomegaMatcher {
+	return &RuleResultMatcher{kind: "warning", code: code, results: (*Report).Warnings}
+}
+
+// HaveSuccess succeeds if the rule or a rule of the package passed for a component of the report.
+func HaveSuccess(code string) types.GomegaMatcher {
+	return &RuleResultMatcher{kind: "success", code: code, results: (*Report).Successes}
+}
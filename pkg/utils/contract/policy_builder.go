@@ -0,0 +1,167 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ecp "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// PolicySpecBuilder builds an EnterpriseContractPolicySpec, e.g.
+//
+//	spec, err := contract.NewPolicySpecFrom(defaultECP.Spec).
+//		WithCollections("minimal").
+//		WithExclude("cve").
+//		WithPublicKey(publicKey).
+//		Build()
+type PolicySpecBuilder struct {
+	spec          ecp.EnterpriseContractPolicySpec
+	include       []string
+	exclude       []string
+	volatile      *ecp.VolatileSourceConfig
+	ruleData      map[string]interface{}
+	effectiveTime string
+}
+
+// NewPolicySpec returns a builder of an empty policy.
+func NewPolicySpec() *PolicySpecBuilder {
+	return &PolicySpecBuilder{}
+}
+
+// NewPolicySpecFrom returns a builder of a policy based on a deep copy of the spec, e.g. of the default
+// EnterpriseContractPolicy. Include and exclude rules of its sources are replaced when any are added.
+func NewPolicySpecFrom(spec ecp.EnterpriseContractPolicySpec) *PolicySpecBuilder {
+	return &PolicySpecBuilder{spec: *spec.DeepCopy()}
+}
+
+// WithName sets the name of the policy.
+func (b *PolicySpecBuilder) WithName(name string) *PolicySpecBuilder {
+	b.spec.Name = name
+	return b
+}
+
+// WithDescription sets the description of the policy.
+func (b *PolicySpecBuilder) WithDescription(description string) *PolicySpecBuilder {
+	b.spec.Description = description
+	return b
+}
+
+// WithSource adds a source of policy rules and data, e.g. oci::quay.io/enterprise-contract/ec-release-policy:latest.
+func (b *PolicySpecBuilder) WithSource(name string, policy, data []string) *PolicySpecBuilder {
+	b.spec.Sources = append(b.spec.Sources, ecp.Source{Name: name, Policy: policy, Data: data})
+	return b
+}
+
+// WithInclude adds rules (package or package.rule codes) every source is restricted to.
+func (b *PolicySpecBuilder) WithInclude(rules ...string) *PolicySpecBuilder {
+	b.include = append(b.include, rules...)
+	return b
+}
+
+// WithExclude adds rules (package or package.rule codes) every source skips.
+func (b *PolicySpecBuilder) WithExclude(rules ...string) *PolicySpecBuilder {
+	b.exclude = append(b.exclude, rules...)
+	return b
+}
+
+// WithCollections includes rule collections, e.g. "minimal" or "slsa3".
+func (b *PolicySpecBuilder) WithCollections(collections ...string) *PolicySpecBuilder {
+	for _, c := range collections {
+		b.include = append(b.include, "@"+c)
+	}
+	return b
+}
+
+// WithVolatileExclude excludes the rule only between effectiveOn and effectiveUntil (RFC3339 timestamps, either can be empty).
+func (b *PolicySpecBuilder) WithVolatileExclude(rule, effectiveOn, effectiveUntil string) *PolicySpecBuilder {
+	if b.volatile == nil {
+		b.volatile = &ecp.VolatileSourceConfig{}
+	}
+	b.volatile.Exclude = append(b.volatile.Exclude, ecp.VolatileCriteria{Value: rule, EffectiveOn: effectiveOn, EffectiveUntil: effectiveUntil})
+	return b
+}
+
+// WithRuleData sets a rule data key of every source, e.g. allowed_registry_prefixes.
+func (b *PolicySpecBuilder) WithRuleData(key string, value interface{}) *PolicySpecBuilder {
+	if b.ruleData == nil {
+		b.ruleData = map[string]interface{}{}
+	}
+	b.ruleData[key] = value
+	return b
+}
+
+// WithPublicKey sets the public key used to verify signatures, either PEM encoded or a reference such as k8s://<namespace>/<secret>.
+func (b *PolicySpecBuilder) WithPublicKey(publicKey string) *PolicySpecBuilder {
+	b.spec.PublicKey = publicKey
+	return b
+}
+
+// WithRekorURL sets the URL of the Rekor instance.
+func (b *PolicySpecBuilder) WithRekorURL(url string) *PolicySpecBuilder {
+	b.spec.RekorUrl = url
+	return b
+}
+
+// WithEffectiveTime sets the time the policy is evaluated at, "now", "attestation" or an RFC3339 timestamp.
+// The policy spec has no effective time, ApplyTo sets it on the verify-enterprise-contract task.
+func (b *PolicySpecBuilder) WithEffectiveTime(effectiveTime string) *PolicySpecBuilder {
+	b.effectiveTime = effectiveTime
+	return b
+}
+
+// EffectiveTime returns the effective time set by WithEffectiveTime, "now" by default.
+func (b *PolicySpecBuilder) EffectiveTime() string {
+	if b.effectiveTime == "" {
+		return "now"
+	}
+	return b.effectiveTime
+}
+
+// ApplyTo sets the effective time of the policy on the verify-enterprise-contract pipeline.
+func (b *PolicySpecBuilder) ApplyTo(generator *tekton.VerifyEnterpriseContract) {
+	generator.EffectiveTime = b.EffectiveTime()
+}
+
+// Build returns the policy spec. Include and exclude rules, volatile config and rule data are applied to all sources.
+func (b *PolicySpecBuilder) Build() (ecp.EnterpriseContractPolicySpec, error) {
+	spec := *b.spec.DeepCopy()
+	if len(spec.Sources) == 0 {
+		return spec, fmt.Errorf("policy %q has no sources", spec.Name)
+	}
+	for i := range spec.Sources {
+		source := &spec.Sources[i]
+		if len(b.include) > 0 || len(b.exclude) > 0 {
+			source.Config = &ecp.SourceConfig{Include: b.include, Exclude: b.exclude}
+		}
+		if b.volatile != nil {
+			source.VolatileConfig = b.volatile.DeepCopy()
+		}
+		if len(b.ruleData) > 0 {
+			ruleData, err := mergeRuleData(source.RuleData, b.ruleData)
+			if err != nil {
+				return spec, fmt.Errorf("invalid rule data of source %q: %v", source.Name, err)
+			}
+			source.RuleData = ruleData
+		}
+	}
+	return spec, nil
+}
+
+func mergeRuleData(existing *extv1.JSON, data map[string]interface{}) (*extv1.JSON, error) {
+	merged := map[string]interface{}{}
+	if existing != nil && len(existing.Raw) > 0 {
+		if err := json.Unmarshal(existing.Raw, &merged); err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return &extv1.JSON{Raw: raw}, nil
+}
@@ -0,0 +1,106 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/build"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Report is the report of the ec validate command printed by the report step of the verify-enterprise-contract task.
+type Report struct {
+	Success       bool              `json:"success"`
+	Components    []ReportComponent `json:"components"`
+	Key           string            `json:"key,omitempty"`
+	EffectiveTime string            `json:"effective-time,omitempty"`
+	ECVersion     string            `json:"ec-version,omitempty"`
+}
+
+// ReportComponent holds the results of the policy rules evaluated for a component image.
+type ReportComponent struct {
+	Name           string       `json:"name"`
+	ContainerImage string       `json:"containerImage"`
+	Success        bool         `json:"success"`
+	Violations     []RuleResult `json:"violations,omitempty"`
+	Warnings       []RuleResult `json:"warnings,omitempty"`
+	Successes      []RuleResult `json:"successes,omitempty"`
+}
+
+// RuleResult is the outcome of a policy rule.
+type RuleResult struct {
+	Msg      string       `json:"msg"`
+	Metadata RuleMetadata `json:"metadata,omitempty"`
+}
+
+type RuleMetadata struct {
+	Code        string   `json:"code"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Solution    string   `json:"solution,omitempty"`
+	Collections []string `json:"collections,omitempty"`
+	EffectiveOn string   `json:"effective_on,omitempty"`
+	Term        string   `json:"term,omitempty"`
+}
+
+// Matches checks if the rule result is of the rule code, e.g. "attestation_type.known_attestation_type",
+// or of any rule of the package if only the package name is given, e.g. "attestation_type".
+func (r RuleResult) Matches(code string) bool {
+	return r.Metadata.Code == code || strings.HasPrefix(r.Metadata.Code, code+".")
+}
+
+// ParseReport parses the report of the verify-enterprise-contract task, which is either JSON or YAML.
+func ParseReport(data []byte) (*Report, error) {
+	report := &Report{}
+	if err := yaml.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("error when parsing enterprise contract report: %v", err)
+	}
+	return report, nil
+}
+
+// Violations returns the violations of all components.
+func (r *Report) Violations() []RuleResult {
+	return r.collect(func(c ReportComponent) []RuleResult { return c.Violations })
+}
+
+// Warnings returns the warnings of all components.
+func (r *Report) Warnings() []RuleResult {
+	return r.collect(func(c ReportComponent) []RuleResult { return c.Warnings })
+}
+
+// Successes returns the successes of all components.
+func (r *Report) Successes() []RuleResult {
+	return r.collect(func(c ReportComponent) []RuleResult { return c.Successes })
+}
+
+func (r *Report) collect(results func(c ReportComponent) []RuleResult) []RuleResult {
+	collected := []RuleResult{}
+	for _, c := range r.Components {
+		collected = append(collected, results(c)...)
+	}
+	return collected
+}
+
+// ParseTestOutput parses the TEST_OUTPUT result of the verify-enterprise-contract task.
+func ParseTestOutput(data string) (*build.TestOutput, error) {
+	output := &build.TestOutput{}
+	if err := json.Unmarshal([]byte(data), output); err != nil {
+		return nil, fmt.Errorf("error when parsing %s: %v", constants.TektonTaskTestOutputName, err)
+	}
+	return output, nil
+}
+
+// GetTestOutput finds and parses the TEST_OUTPUT (or the deprecated HACBS_TEST_OUTPUT) result among the TaskRun results.
+func GetTestOutput(results []pipeline.TaskRunResult) (*build.TestOutput, error) {
+	for _, name := range []string{constants.TektonTaskTestOutputName, constants.OldTektonTaskTestOutputName} {
+		for _, r := range results {
+			if r.Name == name {
+				return ParseTestOutput(r.Value.StringVal)
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s result not found", constants.TektonTaskTestOutputName)
+}
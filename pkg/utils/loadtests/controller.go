@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/ci"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 )
 
@@ -60,18 +61,10 @@ func GetJobName(name string) string {
 	var jobName string
 	if name != "" {
 		jobName = name
+	} else if jobID := ci.Detect().JobID(); utils.CheckIfEnvironmentExists("CI") && jobID != "" {
+		jobName = jobID
 	} else {
-		if utils.CheckIfEnvironmentExists("CI") {
-			if utils.CheckIfEnvironmentExists("GITHUB_ACTIONS") {
-				jobName = utils.GetEnv("GITHUB_RUN_ID", "")
-			} else if utils.CheckIfEnvironmentExists("PROW_JOB_ID") && utils.CheckIfEnvironmentExists("BUILD_ID") {
-				jobName = utils.GetEnv("BUILD_ID", "")
-			} else {
-				jobName = time.Now().String()
-			}
-		} else {
-			jobName = time.Now().String()
-		}
+		jobName = time.Now().String()
 	}
 	return jobName
 }
@@ -1,38 +1,107 @@
 package pipeline
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	resultsAPIPath = "apis/results.tekton.dev/v1alpha2/parents"
+
+	// AllResults can be used as a result ID for listing records of all results of a parent
+	AllResults = "-"
+
+	defaultPageSize = 50
+)
+
 type ResultClient struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	Token      string
 }
 
-func NewClient(url, token string) *ResultClient {
-	return &ResultClient{
-		BaseURL: url,
+// ClientOption configures the Tekton Results client.
+type ClientOption func(*ResultClient)
+
+// WithInsecureSkipVerify disables verification of the Tekton Results API server certificate.
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *ResultClient) {
+		if transport, ok := c.HTTPClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.InsecureSkipVerify = true // #nosec G402
+		}
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for talking to Tekton Results API.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *ResultClient) {
+		c.HTTPClient = client
+	}
+}
+
+func NewClient(url, token string, opts ...ClientOption) *ResultClient {
+	c := &ResultClient{
+		BaseURL: strings.TrimSuffix(url, "/"),
 		HTTPClient: &http.Client{
 			Timeout: time.Minute,
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
 			},
 		},
 		Token: token,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-func (c *ResultClient) sendRequest(path string) (body []byte, err error) {
+// ListOptions are the common options of Tekton Results list calls.
+type ListOptions struct {
+	// Filter is a CEL expression, e.g. `data_type == "tekton.dev/v1.PipelineRun"` or `summary.status == SUCCESS`
+	Filter    string
+	OrderBy   string
+	PageSize  int
+	PageToken string
+}
+
+func (o *ListOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Filter != "" {
+		query.Set("filter", o.Filter)
+	}
+	if o.OrderBy != "" {
+		query.Set("order_by", o.OrderBy)
+	}
+	if o.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.PageToken != "" {
+		query.Set("page_token", o.PageToken)
+	}
+	return query
+}
+
+// open sends a GET request to the given path of Tekton Results API and returns the response body on success.
+// The caller is responsible for closing the body.
+func (c *ResultClient) open(ctx context.Context, path string, query url.Values) (io.ReadCloser, error) {
 	requestURL := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -45,73 +114,131 @@ func (c *ResultClient) sendRequest(path string) (body []byte, err error) {
 		return nil, err
 	}
 
-	body, err = io.ReadAll(res.Body)
 	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
 		return nil, fmt.Errorf("failed to access Tekton Result Service with status code: %d and\nbody: %s", res.StatusCode, string(body))
 	}
 
-	defer res.Body.Close()
-
-	return body, err
+	return res.Body, nil
 }
 
-func (c *ResultClient) GetRecords(namespace, resultId string) (*Records, error) {
-	path := fmt.Sprintf("apis/results.tekton.dev/v1alpha2/parents/%s/results/%s/records", namespace, resultId)
-
-	body, err := c.sendRequest(path)
+func (c *ResultClient) get(ctx context.Context, path string, query url.Values, into interface{}) error {
+	body, err := c.open(ctx, path, query)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var records *Records
-	err = json.Unmarshal(body, &records)
-	if err != nil {
-		return nil, err
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(into); err != nil {
+		return fmt.Errorf("failed to decode response of Tekton Result Service: %v", err)
 	}
-	return records, nil
+	return nil
 }
 
-func (c *ResultClient) GetLogs(namespace, resultId string) (*Logs, error) {
-	path := fmt.Sprintf("apis/results.tekton.dev/v1alpha2/parents/%s/results/%s/logs", namespace, resultId)
-
-	body, err := c.sendRequest(path)
-	if err != nil {
+// ListResults returns one page of results of the given parent (namespace).
+func (c *ResultClient) ListResults(ctx context.Context, parent string, opts *ListOptions) (*Results, error) {
+	results := &Results{}
+	if err := c.get(ctx, fmt.Sprintf("%s/%s/results", resultsAPIPath, parent), opts.query(), results); err != nil {
 		return nil, err
 	}
+	return results, nil
+}
 
-	var logs *Logs
-	err = json.Unmarshal(body, &logs)
-	if err != nil {
-		return nil, err
+// ListAllResults returns results of the given parent (namespace) matching the CEL filter, following all pages.
+func (c *ResultClient) ListAllResults(ctx context.Context, parent, filter string) ([]Result, error) {
+	all := []Result{}
+	opts := &ListOptions{Filter: filter, PageSize: defaultPageSize}
+	for {
+		results, err := c.ListResults(ctx, parent, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, results.Results...)
+		if results.NextPageToken == "" {
+			return all, nil
+		}
+		opts.PageToken = results.NextPageToken
 	}
-	return logs, nil
 }
 
-func (c *ResultClient) GetLogByName(logName string) (string, error) {
-	path := fmt.Sprintf("apis/results.tekton.dev/v1alpha2/parents/%s", logName)
+// ListRecords returns one page of records of the given result. Use AllResults as resultID to list records across all results of the parent.
+func (c *ResultClient) ListRecords(ctx context.Context, parent, resultID string, opts *ListOptions) (*Records, error) {
+	records := &Records{}
+	if err := c.get(ctx, fmt.Sprintf("%s/%s/results/%s/records", resultsAPIPath, parent, resultID), opts.query(), records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
 
-	body, err := c.sendRequest(path)
-	if err != nil {
-		return "", err
+// ListAllRecords returns records of the given result matching the CEL filter, following all pages.
+func (c *ResultClient) ListAllRecords(ctx context.Context, parent, resultID, filter string) ([]Record, error) {
+	all := []Record{}
+	opts := &ListOptions{Filter: filter, PageSize: defaultPageSize}
+	for {
+		records, err := c.ListRecords(ctx, parent, resultID, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, records.Record...)
+		if records.NextPageToken == "" {
+			return all, nil
+		}
+		opts.PageToken = records.NextPageToken
 	}
+}
 
-	return string(body), nil
+// ListRecordsOfKind returns all records of the given result holding objects of the given kinds (RecordKindPipelineRun, RecordKindTaskRun, RecordKindLog).
+func (c *ResultClient) ListRecordsOfKind(ctx context.Context, parent, resultID string, kinds ...string) ([]Record, error) {
+	return c.ListAllRecords(ctx, parent, resultID, RecordTypeFilter(kinds...))
 }
 
-type Record struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
-	UID  string `json:"uid"`
+func (c *ResultClient) GetRecords(namespace, resultId string) (*Records, error) {
+	return c.ListRecords(context.Background(), namespace, resultId, nil)
 }
 
-type Records struct {
-	Record []Record `json:"records"`
+func (c *ResultClient) GetLogs(namespace, resultId string) (*Logs, error) {
+	logs := &Logs{}
+	if err := c.get(context.Background(), fmt.Sprintf("%s/%s/results/%s/logs", resultsAPIPath, namespace, resultId), nil, logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
 }
 
-type Log struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
-	UID  string `json:"uid"`
+// StreamLog writes content of the log with the given name (e.g. "<namespace>/results/<result>/logs/<log>") into w,
+// as it is received from the server.
+func (c *ResultClient) StreamLog(ctx context.Context, logName string, w io.Writer) error {
+	body, err := c.open(ctx, fmt.Sprintf("%s/%s", resultsAPIPath, logName), nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// the log is streamed as a sequence of JSON messages, each holding a base64 encoded chunk of the log
+	decoder := json.NewDecoder(body)
+	for {
+		chunk := logChunk{}
+		if err := decoder.Decode(&chunk); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode log %s: %v", logName, err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("failed to get log %s: %s", logName, chunk.Error.Message)
+		}
+		if chunk.Result == nil {
+			continue
+		}
+		if _, err := w.Write(chunk.Result.Data); err != nil {
+			return err
+		}
+	}
 }
-type Logs struct {
-	Record []Record `json:"records"`
+
+func (c *ResultClient) GetLogByName(logName string) (string, error) {
+	var sb strings.Builder
+	if err := c.StreamLog(context.Background(), logName, &sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
 }
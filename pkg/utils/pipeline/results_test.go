@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeResultsServer serves a minimal subset of Tekton Results REST API from memory
+type fakeResultsServer struct {
+	token   string
+	results []Result
+	records map[string][]Record
+	logs    map[string][]string
+}
+
+func (f *fakeResultsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+f.token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/"+resultsAPIPath+"/")
+	parts := strings.Split(path, "/")
+	query := r.URL.Query()
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+	start, _ := strconv.Atoi(query.Get("page_token"))
+
+	switch {
+	case len(parts) == 2 && parts[1] == "results":
+		page, next := paginate(len(f.results), start, pageSize)
+		_ = json.NewEncoder(w).Encode(Results{Results: f.results[page[0]:page[1]], NextPageToken: next})
+	case len(parts) == 4 && parts[3] == "records":
+		records := []Record{}
+		for result, rs := range f.records {
+			if parts[2] != AllResults && parts[2] != result {
+				continue
+			}
+			for _, record := range rs {
+				if filter := query.Get("filter"); filter == "" || strings.Contains(filter, fmt.Sprintf("%q", record.Data.Type)) {
+					records = append(records, record)
+				}
+			}
+		}
+		page, next := paginate(len(records), start, pageSize)
+		_ = json.NewEncoder(w).Encode(Records{Record: records[page[0]:page[1]], NextPageToken: next})
+	case len(parts) == 5 && parts[3] == "logs":
+		chunks, ok := f.logs[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		for _, chunk := range chunks {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"data": []byte(chunk)}})
+			w.(http.Flusher).Flush()
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func paginate(total, start, pageSize int) ([2]int, string) {
+	if pageSize == 0 || start+pageSize >= total {
+		return [2]int{start, total}, ""
+	}
+	return [2]int{start, start + pageSize}, strconv.Itoa(start + pageSize)
+}
+
+func newFakeResultsClient(t *testing.T, f *fakeResultsServer) *ResultClient {
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, f.token, WithHTTPClient(server.Client()))
+}
+
+func TestListAllResultsFollowsPageTokens(t *testing.T) {
+	f := &fakeResultsServer{token: "token"}
+	for i := 0; i < 120; i++ {
+		f.results = append(f.results, Result{Name: fmt.Sprintf("ns/results/%d", i), UID: strconv.Itoa(i)})
+	}
+	client := newFakeResultsClient(t, f)
+
+	results, err := client.ListAllResults(context.Background(), "ns", "")
+	assert.NoError(t, err)
+	assert.Len(t, results, 120)
+	assert.Equal(t, "ns/results/119", results[119].Name)
+
+	client.Token = "wrong"
+	_, err = client.ListAllResults(context.Background(), "ns", "")
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestListRecordsOfKindDecodesObjects(t *testing.T) {
+	prV1, _ := json.Marshal(v1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build-v1"}})
+	prV1beta1, _ := json.Marshal(v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build-v1beta1"}, Spec: v1beta1.PipelineRunSpec{ServiceAccountName: "appstudio-pipeline"}})
+	tr, _ := json.Marshal(v1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "build-v1-buildah"}})
+
+	f := &fakeResultsServer{token: "token", records: map[string][]Record{
+		"a": {
+			{Name: "ns/results/a/records/1", Data: RecordData{Type: "tekton.dev/v1.PipelineRun", Value: prV1}},
+			{Name: "ns/results/a/records/2", Data: RecordData{Type: "tekton.dev/v1.TaskRun", Value: tr}},
+			{Name: "ns/results/a/records/3", Data: RecordData{Type: "results.tekton.dev/v1alpha2.Log", Value: []byte("{}")}},
+		},
+		"b": {
+			{Name: "ns/results/b/records/1", Data: RecordData{Type: "tekton.dev/v1beta1.PipelineRun", Value: prV1beta1}},
+		},
+	}}
+	client := newFakeResultsClient(t, f)
+
+	records, err := client.ListRecordsOfKind(context.Background(), "ns", AllResults, RecordKindPipelineRun)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	names := []string{}
+	for _, record := range records {
+		pr, err := record.PipelineRun()
+		assert.NoError(t, err)
+		names = append(names, pr.Name)
+		if pr.Name == "build-v1beta1" {
+			assert.Equal(t, "appstudio-pipeline", pr.Spec.TaskRunTemplate.ServiceAccountName)
+		}
+	}
+	assert.ElementsMatch(t, []string{"build-v1", "build-v1beta1"}, names)
+
+	records, err = client.ListRecordsOfKind(context.Background(), "ns", "a", RecordKindTaskRun)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	taskRun, err := records[0].TaskRun()
+	assert.NoError(t, err)
+	assert.Equal(t, "build-v1-buildah", taskRun.Name)
+	_, err = records[0].PipelineRun()
+	assert.Error(t, err)
+}
+
+func TestStreamLog(t *testing.T) {
+	f := &fakeResultsServer{token: "token", logs: map[string][]string{
+		"ns/results/a/logs/1": {"step-build: building\n", "step-build: done\n"},
+	}}
+	client := newFakeResultsClient(t, f)
+
+	log, err := client.GetLogByName("ns/results/a/logs/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "step-build: building\nstep-build: done\n", log)
+
+	_, err = client.GetLogByName("ns/results/a/logs/2")
+	assert.ErrorContains(t, err, "404")
+}
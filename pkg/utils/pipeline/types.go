@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+const (
+	RecordKindPipelineRun = "PipelineRun"
+	RecordKindTaskRun     = "TaskRun"
+	RecordKindLog         = "Log"
+)
+
+// recordTypes maps record kinds to data types stored by Tekton Results
+var recordTypes = map[string][]string{
+	RecordKindPipelineRun: {"tekton.dev/v1.PipelineRun", "tekton.dev/v1beta1.PipelineRun"},
+	RecordKindTaskRun:     {"tekton.dev/v1.TaskRun", "tekton.dev/v1beta1.TaskRun"},
+	RecordKindLog:         {"results.tekton.dev/v1alpha2.Log", "results.tekton.dev/v1alpha3.Log"},
+}
+
+// RecordTypeFilter returns a CEL filter matching records holding objects of the given kinds.
+func RecordTypeFilter(kinds ...string) string {
+	types := []string{}
+	for _, kind := range kinds {
+		for _, t := range recordTypes[kind] {
+			types = append(types, fmt.Sprintf("%q", t))
+		}
+	}
+	return fmt.Sprintf("data_type in [%s]", strings.Join(types, ", "))
+}
+
+// AnnotationFilter returns a CEL filter matching results with the given annotation.
+func AnnotationFilter(key, value string) string {
+	return fmt.Sprintf("annotations[%q] == %q", key, value)
+}
+
+type Result struct {
+	Name        string            `json:"name"`
+	ID          string            `json:"id"`
+	UID         string            `json:"uid"`
+	CreateTime  *time.Time        `json:"createTime,omitempty"`
+	UpdateTime  *time.Time        `json:"updateTime,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Etag        string            `json:"etag,omitempty"`
+	Summary     *RecordSummary    `json:"summary,omitempty"`
+}
+
+// RecordSummary summarizes the main record (e.g. the PipelineRun) of a result.
+type RecordSummary struct {
+	Record    string     `json:"record"`
+	Type      string     `json:"type"`
+	Status    string     `json:"status"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+type Results struct {
+	Results       []Result `json:"results"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+type Record struct {
+	Name       string     `json:"name"`
+	ID         string     `json:"id"`
+	UID        string     `json:"uid"`
+	Data       RecordData `json:"data"`
+	Etag       string     `json:"etag,omitempty"`
+	CreateTime *time.Time `json:"createTime,omitempty"`
+	UpdateTime *time.Time `json:"updateTime,omitempty"`
+}
+
+// RecordData holds the stored object. Value is the JSON encoded object (base64 encoded in the API response).
+type RecordData struct {
+	Type  string `json:"type"`
+	Value []byte `json:"value"`
+}
+
+// Kind returns the kind of the object stored in the record (RecordKindPipelineRun, RecordKindTaskRun, RecordKindLog),
+// or the raw data type if it is unknown.
+func (r Record) Kind() string {
+	for kind, types := range recordTypes {
+		for _, t := range types {
+			if t == r.Data.Type {
+				return kind
+			}
+		}
+	}
+	return r.Data.Type
+}
+
+// PipelineRun decodes the PipelineRun stored in the record, converting it to v1 if it was stored as v1beta1.
+func (r Record) PipelineRun() (*v1.PipelineRun, error) {
+	if r.Kind() != RecordKindPipelineRun {
+		return nil, fmt.Errorf("record %s holds %s, not a PipelineRun", r.Name, r.Data.Type)
+	}
+	pr := &v1.PipelineRun{}
+	if strings.HasPrefix(r.Data.Type, "tekton.dev/v1beta1") {
+		v1beta1PR := &v1beta1.PipelineRun{}
+		if err := json.Unmarshal(r.Data.Value, v1beta1PR); err != nil {
+			return nil, fmt.Errorf("failed to decode PipelineRun from record %s: %v", r.Name, err)
+		}
+		if err := v1beta1PR.ConvertTo(context.Background(), pr); err != nil {
+			return nil, fmt.Errorf("failed to convert PipelineRun from record %s to v1: %v", r.Name, err)
+		}
+		return pr, nil
+	}
+	if err := json.Unmarshal(r.Data.Value, pr); err != nil {
+		return nil, fmt.Errorf("failed to decode PipelineRun from record %s: %v", r.Name, err)
+	}
+	return pr, nil
+}
+
+// TaskRun decodes the TaskRun stored in the record, converting it to v1 if it was stored as v1beta1.
+func (r Record) TaskRun() (*v1.TaskRun, error) {
+	if r.Kind() != RecordKindTaskRun {
+		return nil, fmt.Errorf("record %s holds %s, not a TaskRun", r.Name, r.Data.Type)
+	}
+	tr := &v1.TaskRun{}
+	if strings.HasPrefix(r.Data.Type, "tekton.dev/v1beta1") {
+		v1beta1TR := &v1beta1.TaskRun{}
+		if err := json.Unmarshal(r.Data.Value, v1beta1TR); err != nil {
+			return nil, fmt.Errorf("failed to decode TaskRun from record %s: %v", r.Name, err)
+		}
+		if err := v1beta1TR.ConvertTo(context.Background(), tr); err != nil {
+			return nil, fmt.Errorf("failed to convert TaskRun from record %s to v1: %v", r.Name, err)
+		}
+		return tr, nil
+	}
+	if err := json.Unmarshal(r.Data.Value, tr); err != nil {
+		return nil, fmt.Errorf("failed to decode TaskRun from record %s: %v", r.Name, err)
+	}
+	return tr, nil
+}
+
+type Records struct {
+	Record        []Record `json:"records"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+type Log struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	UID  string `json:"uid"`
+}
+type Logs struct {
+	Record []Record `json:"records"`
+}
+
+// logChunk is a single message of a streamed log
+type logChunk struct {
+	Result *struct {
+		ContentType string `json:"contentType,omitempty"`
+		Data        []byte `json:"data"`
+	} `json:"result,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// maxSummaryLength limits the length of a state summary printed on a state transition
+const maxSummaryLength = 256
+
+// PollFunc checks a condition. It returns whether the condition is met, the currently observed state
+// (e.g. the fetched object) and an error. An error does not stop the polling, it's remembered and
+// reported on timeout, unless it is wrapped with StopPolling.
+type PollFunc func(ctx context.Context) (done bool, state interface{}, err error)
+
+// Poller polls a PollFunc until the condition is met, the timeout expires or the context is cancelled.
+// It remembers the last observed state and error, logs their transitions and returns a PollTimeoutError
+// describing them when the condition is not met in time, e.g.
+//
+//	err := utils.NewPoller(fmt.Sprintf("component %s to be ready", name), 10*time.Minute).
+//		Poll(ctx, func(ctx context.Context) (bool, interface{}, error) {
+//			c, err := h.GetComponent(name, namespace)
+//			if err != nil {
+//				return false, nil, err
+//			}
+//			return isReady(c), c, nil
+//		})
+type Poller struct {
+	// Description of what is being waited for, e.g. "component foo to be ready"
+	Description string
+	// Interval between two polls, 1 second by default
+	Interval time.Duration
+	// Timeout of the polling, no timeout other than the one of the context if zero
+	Timeout time.Duration
+	// Backoff multiplies the interval after each poll if greater than 1
+	Backoff float64
+	// MaxInterval caps the interval grown by the backoff if not zero
+	MaxInterval time.Duration
+	// Jitter adds a random duration up to Jitter*interval to each interval if greater than 0
+	Jitter float64
+	// Summary returns a short summary of the observed state used to detect and log state transitions
+	Summary func(state interface{}) string
+	// Out is the writer state transitions are logged to, GinkgoWriter by default
+	Out io.Writer
+}
+
+// NewPoller returns a Poller polling each second until the timeout.
+func NewPoller(description string, timeout time.Duration) *Poller {
+	return &Poller{Description: description, Interval: time.Second, Timeout: timeout}
+}
+
+// WithInterval sets the interval between two polls.
+func (p *Poller) WithInterval(interval time.Duration) *Poller {
+	p.Interval = interval
+	return p
+}
+
+// WithBackoff multiplies the interval by the factor after each poll, up to the maxInterval.
+func (p *Poller) WithBackoff(factor float64, maxInterval time.Duration) *Poller {
+	p.Backoff = factor
+	p.MaxInterval = maxInterval
+	return p
+}
+
+// WithJitter adds a random duration up to jitter*interval to each interval.
+func (p *Poller) WithJitter(jitter float64) *Poller {
+	p.Jitter = jitter
+	return p
+}
+
+// WithOutput sets the writer state transitions are logged to.
+func (p *Poller) WithOutput(out io.Writer) *Poller {
+	p.Out = out
+	return p
+}
+
+// PollTimeoutError is returned by a Poller when the condition is not met before the timeout expires
+// or the context is cancelled. It describes the last observed state and error.
+type PollTimeoutError struct {
+	Description  string
+	Timeout      time.Duration
+	Attempts     int
+	LastObserved interface{}
+	LastErr      error
+	// Cause is the error of the context, i.e. context.DeadlineExceeded or context.Canceled
+	Cause error
+}
+
+func (e *PollTimeoutError) Error() string {
+	description := e.Description
+	if description == "" {
+		description = "the condition"
+	}
+	var b strings.Builder
+	if errors.Is(e.Cause, context.Canceled) {
+		fmt.Fprintf(&b, "cancelled while waiting for %s (%d attempts)", description, e.Attempts)
+	} else {
+		fmt.Fprintf(&b, "timed out after %v waiting for %s (%d attempts)", e.Timeout, description, e.Attempts)
+	}
+	if e.LastErr != nil {
+		fmt.Fprintf(&b, ", last error: %v", e.LastErr)
+	}
+	if e.LastObserved != nil {
+		fmt.Fprintf(&b, "\nlast observed state:\n%s", ToPrettyJSONString(e.LastObserved))
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of the context, so that wait.Interrupted recognizes the error.
+func (e *PollTimeoutError) Unwrap() error {
+	return e.Cause
+}
+
+type stopPollingError struct {
+	err error
+}
+
+func (e *stopPollingError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stopPollingError) Unwrap() error {
+	return e.err
+}
+
+// StopPolling wraps an error returned by a PollFunc to stop the polling immediately. The Poller returns the wrapped error.
+func StopPolling(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stopPollingError{err: err}
+}
+
+// Poll calls the PollFunc immediately and then after each interval until it reports the condition is met.
+func (p *Poller) Poll(ctx context.Context, poll PollFunc) error {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	out := p.Out
+	if out == nil {
+		out = ginkgo.GinkgoWriter
+	}
+	summary := p.Summary
+	if summary == nil {
+		summary = SummarizeState
+	}
+
+	start := time.Now()
+	interval := p.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	timeoutErr := &PollTimeoutError{Description: p.Description, Timeout: p.Timeout}
+	lastSummary, lastErrMessage := "", ""
+	for {
+		timeoutErr.Attempts++
+		done, state, err := poll(ctx)
+
+		var stop *stopPollingError
+		if errors.As(err, &stop) {
+			return stop.err
+		}
+		if state != nil {
+			timeoutErr.LastObserved = state
+			if s := summary(state); s != lastSummary {
+				fmt.Fprintf(out, "[%v] %s: %s\n", time.Since(start).Round(time.Second), p.description(), s)
+				lastSummary = s
+			}
+		}
+		if err != nil {
+			timeoutErr.LastErr = err
+			if err.Error() != lastErrMessage {
+				fmt.Fprintf(out, "[%v] %s: error: %v\n", time.Since(start).Round(time.Second), p.description(), err)
+				lastErrMessage = err.Error()
+			}
+		} else if done {
+			return nil
+		}
+
+		delay := interval
+		if p.Jitter > 0 {
+			delay = wait.Jitter(interval, p.Jitter)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			timeoutErr.Cause = ctx.Err()
+			return timeoutErr
+		case <-timer.C:
+		}
+
+		if p.Backoff > 1 {
+			interval = time.Duration(float64(interval) * p.Backoff)
+			if p.MaxInterval > 0 && interval > p.MaxInterval {
+				interval = p.MaxInterval
+			}
+		}
+	}
+}
+
+// PollCondition polls a wait.ConditionFunc. As with the wait package, an error returned by the condition stops the polling.
+func (p *Poller) PollCondition(ctx context.Context, cond wait.ConditionFunc) error {
+	return p.Poll(ctx, func(context.Context) (bool, interface{}, error) {
+		done, err := cond()
+		return done, nil, StopPolling(err)
+	})
+}
+
+func (p *Poller) description() string {
+	if p.Description == "" {
+		return "waiting for the condition"
+	}
+	return "waiting for " + p.Description
+}
+
+// SummarizeState returns a short summary of an observed state: status conditions of a resource, its resource
+// version if it has no conditions, or its JSON representation otherwise.
+func SummarizeState(state interface{}) string {
+	switch s := state.(type) {
+	case string:
+		return s
+	case error:
+		return s.Error()
+	}
+	if conditions, ok := conditionsSummary(state); ok {
+		return conditions
+	}
+	// Kubernetes objects are checked before fmt.Stringer, as generated types print all their fields in String()
+	if o, err := meta.Accessor(state); err == nil {
+		return fmt.Sprintf("resourceVersion: %s", o.GetResourceVersion())
+	}
+	if s, ok := state.(fmt.Stringer); ok {
+		return s.String()
+	}
+	j, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Sprintf("%v", state)
+	}
+	if len(j) > maxSummaryLength {
+		return string(j[:maxSummaryLength]) + "..."
+	}
+	return string(j)
+}
+
+// conditionsSummary prints Kubernetes or Knative status conditions of a resource as "Type=Status (Reason)"
+func conditionsSummary(state interface{}) (string, bool) {
+	v := reflect.Indirect(reflect.ValueOf(state))
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	status := v.FieldByName("Status")
+	if !status.IsValid() || status.Kind() != reflect.Struct {
+		return "", false
+	}
+	conditions := status.FieldByName("Conditions")
+	if !conditions.IsValid() || conditions.Kind() != reflect.Slice || conditions.Len() == 0 {
+		return "", false
+	}
+	summaries := []string{}
+	for i := 0; i < conditions.Len(); i++ {
+		c := reflect.Indirect(conditions.Index(i))
+		if c.Kind() != reflect.Struct || !c.FieldByName("Type").IsValid() || !c.FieldByName("Status").IsValid() {
+			return "", false
+		}
+		summary := fmt.Sprintf("%v=%v", c.FieldByName("Type").Interface(), c.FieldByName("Status").Interface())
+		if reason := c.FieldByName("Reason"); reason.IsValid() && reason.String() != "" {
+			summary += fmt.Sprintf(" (%s)", reason.String())
+		}
+		summaries = append(summaries, summary)
+	}
+	return "conditions: " + strings.Join(summaries, ", "), true
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type resource struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Status            struct {
+		Conditions []metav1.Condition `json:"conditions"`
+	} `json:"status"`
+}
+
+func TestPollerTimeout(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := &resource{ObjectMeta: metav1.ObjectMeta{Name: "comp"}}
+	attempt := 0
+	err := NewPoller("component comp to be ready", 50*time.Millisecond).WithInterval(time.Millisecond).WithOutput(out).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			attempt++
+			switch {
+			case attempt == 1:
+				return false, nil, fmt.Errorf("connection refused")
+			case attempt < 5:
+				r.Status.Conditions = []metav1.Condition{{Type: "Created", Status: metav1.ConditionFalse, Reason: "Pending"}}
+			default:
+				r.Status.Conditions = []metav1.Condition{{Type: "Created", Status: metav1.ConditionFalse, Reason: "Error", Message: "devfile not found"}}
+			}
+			return false, r, nil
+		})
+
+	var timeoutErr *PollTimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.True(t, wait.Interrupted(err))
+	assert.Equal(t, attempt, timeoutErr.Attempts)
+	assert.EqualError(t, timeoutErr.LastErr, "connection refused")
+	assert.Contains(t, err.Error(), "timed out after 50ms waiting for component comp to be ready")
+	assert.Contains(t, err.Error(), "last error: connection refused")
+	assert.Contains(t, err.Error(), `"message": "devfile not found"`)
+
+	// only transitions are logged
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("error: connection refused")))
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("conditions: Created=False (Pending)")))
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("conditions: Created=False (Error)")))
+}
+
+func TestPollerSucceeds(t *testing.T) {
+	attempts := 0
+	start := time.Now()
+	err := NewPoller("", time.Minute).WithInterval(time.Millisecond).WithBackoff(2, 8*time.Millisecond).WithJitter(0.1).WithOutput(&bytes.Buffer{}).
+		Poll(context.Background(), func(ctx context.Context) (bool, interface{}, error) {
+			attempts++
+			return attempts == 6, attempts, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, attempts)
+	// 1 + 2 + 4 + 8 + 8 milliseconds without the jitter
+	assert.GreaterOrEqual(t, time.Since(start), 23*time.Millisecond)
+}
+
+func TestPollerStopAndCancel(t *testing.T) {
+	err := NewPoller("", time.Minute).PollCondition(context.Background(), func() (bool, error) {
+		return false, fmt.Errorf("forbidden")
+	})
+	assert.EqualError(t, err, "forbidden")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = NewPoller("release to succeed", time.Minute).WithOutput(&bytes.Buffer{}).Poll(ctx, func(ctx context.Context) (bool, interface{}, error) {
+		return false, "Progressing", nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "cancelled while waiting for release to succeed (1 attempts)")
+
+	err = WaitUntilWithInterval(func() (bool, error) { return false, nil }, time.Millisecond, 10*time.Millisecond)
+	assert.Contains(t, err.Error(), "waiting for the condition")
+}
+
+func TestSummarizeState(t *testing.T) {
+	assert.Equal(t, "Running", SummarizeState("Running"))
+	assert.Equal(t, "resourceVersion: 12", SummarizeState(&resource{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "12"}}))
+	assert.Equal(t, `["comp-a","comp-b"]`, SummarizeState([]string{"comp-a", "comp-b"}))
+}
@@ -3,6 +3,22 @@ package tekton
 import (
 	"fmt"
 	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+const (
+	cosignSignatureTagSuffix   = ".sig"
+	cosignAttestationTagSuffix = ".att"
+)
+
+var (
+	// artifact types of signatures and attestations attached to an image using the OCI referrers API
+	cosignSignatureArtifactTypes   = []string{"application/vnd.dev.cosign.artifact.sig.v1+json"}
+	cosignAttestationArtifactTypes = []string{"application/vnd.dsse.envelope.v1+json", "application/vnd.in-toto+json"}
 )
 
 type CosignResult struct {
@@ -10,34 +26,31 @@ type CosignResult struct {
 	AttestationImageRef string
 }
 
-// FindCosignResultsForImage looks for .sig and .att image tags in the OpenShift image stream for the provided image reference.
-// If none can be found errors.IsNotFound(err) is true, when err is nil CosignResult contains image references for signature and attestation images, otherwise other errors could be returned.
-func FindCosignResultsForImage(imageRef string) (*CosignResult, error) {
-	var errMsg string
-	// Split the image ref into image repo+tag (e.g quay.io/repo/name:tag), and image digest (sha256:abcd...)
-	imageInfo := strings.Split(imageRef, "@")
-	imageRegistryName := strings.Split(imageInfo[0], "/")[0]
-	// imageRepoName is stripped from container registry name and a tag e.g. "quay.io/<org>/<repo>:tagprefix" => "<org>/<repo>"
-	imageRepoName := strings.Split(strings.TrimPrefix(imageInfo[0], fmt.Sprintf("%s/", imageRegistryName)), ":")[0]
-	// Cosign creates tags for attestation and signature based on the image digest. Compute
-	// the expected prefix for later usage: sha256:abcd... -> sha256-abcd...
-	// Also, this prefix is really the prefix of the image tag resource which follows the
-	// format: <image-repo>:<tag-name>
-	imageTagPrefix := strings.Replace(imageInfo[1], ":", "-", 1)
+// FindCosignResultsForImage looks for the signature and attestation images of the provided image reference (in a form
+// <registry>/<repo>[:<tag>]@<digest>) using the OCI distribution API, so it works with any container registry.
+// Cosign stores them as sha256-<hex>.sig and sha256-<hex>.att tags in the image repository, if the tags don't exist
+// the OCI referrers API is queried. Options can be used e.g. to provide registry credentials,
+// by default credentials are read from the docker config.
+// When err is nil CosignResult contains image references for signature and attestation images.
+func FindCosignResultsForImage(imageRef string, opts ...remote.Option) (*CosignResult, error) {
+	digest, err := name.NewDigest(imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image reference %s: %v", imageRef, err)
+	}
+	opts = cosignRemoteOptions(opts)
 
+	var errMsg string
 	results := CosignResult{}
-	signatureTag, err := getImageInfoFromQuay(imageRepoName, imageTagPrefix+".sig")
-	if err != nil {
-		errMsg += fmt.Sprintf("error when getting signature tag: %+v\n", err)
+	if signatureRef, err := findCosignImage(digest, cosignSignatureTagSuffix, cosignSignatureArtifactTypes, opts); err != nil {
+		errMsg += fmt.Sprintf("error when getting signature: %+v\n", err)
 	} else {
-		results.SignatureImageRef = signatureTag.ImageRef
+		results.SignatureImageRef = signatureRef.String()
 	}
 
-	attestationTag, err := getImageInfoFromQuay(imageRepoName, imageTagPrefix+".att")
-	if err != nil {
-		errMsg += fmt.Sprintf("error when getting attestation tag: %+v\n", err)
+	if attestationRef, err := findCosignImage(digest, cosignAttestationTagSuffix, cosignAttestationArtifactTypes, opts); err != nil {
+		errMsg += fmt.Sprintf("error when getting attestation: %+v\n", err)
 	} else {
-		results.AttestationImageRef = attestationTag.ImageRef
+		results.AttestationImageRef = attestationRef.String()
 	}
 
 	if len(errMsg) > 0 {
@@ -47,6 +60,72 @@ func FindCosignResultsForImage(imageRef string) (*CosignResult, error) {
 	return &results, nil
 }
 
+// cosignRemoteOptions prepends the default options, so the provided ones take precedence
+func cosignRemoteOptions(opts []remote.Option) []remote.Option {
+	return append([]remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}, opts...)
+}
+
+// cosignTagPrefix computes the prefix of tags cosign creates for the image: sha256:abcd... -> sha256-abcd...
+func cosignTagPrefix(digest name.Digest) string {
+	return strings.Replace(digest.DigestStr(), ":", "-", 1)
+}
+
+// findCosignImage returns a reference of the cosign image with the given tag suffix, or of the latest image
+// referring to the digest with one of the given artifact types.
+func findCosignImage(digest name.Digest, tagSuffix string, artifactTypes []string, opts []remote.Option) (name.Digest, error) {
+	tag := digest.Context().Tag(cosignTagPrefix(digest) + tagSuffix)
+	desc, tagErr := remote.Get(tag, opts...)
+	if tagErr == nil {
+		return cosignImageWithLayers(digest.Context().Digest(desc.Digest.String()), opts)
+	}
+
+	index, err := remote.Referrers(digest, opts...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("cannot get %s image: %v, cannot get referrers of %s: %v", tag, tagErr, digest, err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("cannot get referrers of %s: %v", digest, err)
+	}
+	var found *v1.Descriptor
+	for i, m := range manifest.Manifests {
+		if !contains(artifactTypes, m.ArtifactType) {
+			continue
+		}
+		if found == nil || m.Annotations["org.opencontainers.image.created"] > found.Annotations["org.opencontainers.image.created"] {
+			found = &manifest.Manifests[i]
+		}
+	}
+	if found == nil {
+		return name.Digest{}, fmt.Errorf("cannot get %s image: %v, no referrers of %s with artifact type %s", tag, tagErr, digest, strings.Join(artifactTypes, " or "))
+	}
+	return cosignImageWithLayers(digest.Context().Digest(found.Digest.String()), opts)
+}
+
+func cosignImageWithLayers(ref name.Digest, opts []remote.Option) (name.Digest, error) {
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("cannot get %s image from container registry: %v", ref, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("cannot get manifest of %s image: %v", ref, err)
+	}
+	if len(manifest.Layers) < 1 {
+		return name.Digest{}, fmt.Errorf("cannot get layers from %s image", ref)
+	}
+	return ref, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // IsPresent checks if CosignResult is present.
 func (c CosignResult) IsPresent() bool {
 	return c.SignatureImageRef != "" && c.AttestationImageRef != ""
@@ -0,0 +1,239 @@
+package tekton
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// CosignSignatureAnnotation is the annotation of a signature image layer holding the base64 encoded signature of the layer.
+const CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// SimpleSigningPayload is the payload signed by cosign, see https://github.com/containers/image/blob/main/docs/containers-signature.5.md
+type SimpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional,omitempty"`
+}
+
+// DSSEEnvelope is a signed attestation stored in the attestation image, see https://github.com/secure-systems-lab/dsse
+type DSSEEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []DSSESignature `json:"signatures"`
+}
+
+type DSSESignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// ParseCosignPublicKey parses a PEM encoded public key, e.g. the one returned by GetTektonChainsPublicKey.
+func ParseCosignPublicKey(publicKey []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, fmt.Errorf("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %v", err)
+	}
+	return key, nil
+}
+
+// verifySignature verifies the signature of the payload the same way cosign does for the given key type
+func verifySignature(key crypto.PublicKey, payload, signature []byte) error {
+	digest := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], signature) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("invalid RSA signature: %v", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, signature) {
+			return fmt.Errorf("invalid ED25519 signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	return nil
+}
+
+// pae is the DSSE pre-authentication encoding of the payload which is what gets signed
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Verify checks that at least one signature of the envelope was made with the key and returns the decoded payload.
+func (e *DSSEEnvelope) Verify(key crypto.PublicKey) ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode DSSE payload: %v", err)
+	}
+	if len(e.Signatures) == 0 {
+		return nil, fmt.Errorf("DSSE envelope has no signatures")
+	}
+	errs := []string{}
+	for _, s := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to decode signature: %v", err))
+			continue
+		}
+		if err := verifySignature(key, pae(e.PayloadType, payload), sig); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return payload, nil
+	}
+	return nil, fmt.Errorf("no valid signature of DSSE envelope: %s", strings.Join(errs, ", "))
+}
+
+// VerifyImageSignatures finds the cosign signature image of the image and verifies its signatures against
+// the PEM encoded public key. It returns the verified payloads and an error if no signature could be verified
+// or no signature was made for the image digest.
+func VerifyImageSignatures(imageRef string, publicKey []byte, opts ...remote.Option) ([]SimpleSigningPayload, error) {
+	digest, key, err := parseVerificationInputs(imageRef, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	opts = cosignRemoteOptions(opts)
+	signatureRef, err := findCosignImage(digest, cosignSignatureTagSuffix, cosignSignatureArtifactTypes, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signature of image %s: %v", imageRef, err)
+	}
+
+	verified := []SimpleSigningPayload{}
+	err = forEachCosignLayer(signatureRef, opts, func(desc v1.Descriptor, content []byte) error {
+		sig, err := base64.StdEncoding.DecodeString(desc.Annotations[CosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			return fmt.Errorf("missing or invalid %s annotation", CosignSignatureAnnotation)
+		}
+		if err := verifySignature(key, content, sig); err != nil {
+			return err
+		}
+		payload := SimpleSigningPayload{}
+		if err := json.Unmarshal(content, &payload); err != nil {
+			return fmt.Errorf("failed to parse signed payload: %v", err)
+		}
+		if payload.Critical.Image.DockerManifestDigest != digest.DigestStr() {
+			return fmt.Errorf("signature was made for %s", payload.Critical.Image.DockerManifestDigest)
+		}
+		verified = append(verified, payload)
+		return nil
+	})
+	if len(verified) == 0 {
+		return nil, fmt.Errorf("no valid signature of image %s in %s: %v", imageRef, signatureRef, err)
+	}
+	return verified, nil
+}
+
+// VerifyImageAttestations finds the cosign attestation image of the image and verifies its DSSE envelopes against
+// the PEM encoded public key. It returns the verified payloads (in-toto statements) and an error if no attestation
+// could be verified.
+func VerifyImageAttestations(imageRef string, publicKey []byte, opts ...remote.Option) ([][]byte, error) {
+	digest, key, err := parseVerificationInputs(imageRef, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	opts = cosignRemoteOptions(opts)
+	attestationRef, err := findCosignImage(digest, cosignAttestationTagSuffix, cosignAttestationArtifactTypes, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attestation of image %s: %v", imageRef, err)
+	}
+
+	verified := [][]byte{}
+	err = forEachCosignLayer(attestationRef, opts, func(_ v1.Descriptor, content []byte) error {
+		envelope := DSSEEnvelope{}
+		if err := json.Unmarshal(content, &envelope); err != nil {
+			return fmt.Errorf("failed to parse DSSE envelope: %v", err)
+		}
+		payload, err := envelope.Verify(key)
+		if err != nil {
+			return err
+		}
+		verified = append(verified, payload)
+		return nil
+	})
+	if len(verified) == 0 {
+		return nil, fmt.Errorf("no valid attestation of image %s in %s: %v", imageRef, attestationRef, err)
+	}
+	return verified, nil
+}
+
+func parseVerificationInputs(imageRef string, publicKey []byte) (name.Digest, crypto.PublicKey, error) {
+	digest, err := name.NewDigest(imageRef)
+	if err != nil {
+		return name.Digest{}, nil, fmt.Errorf("failed to parse image reference %s: %v", imageRef, err)
+	}
+	key, err := ParseCosignPublicKey(publicKey)
+	if err != nil {
+		return name.Digest{}, nil, err
+	}
+	return digest, key, nil
+}
+
+// forEachCosignLayer calls the function with the descriptor and the content of each layer of the image.
+// Errors returned by the function are collected and returned together.
+func forEachCosignLayer(ref name.Digest, opts []remote.Option, f func(desc v1.Descriptor, content []byte) error) error {
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return fmt.Errorf("cannot get %s image from container registry: %v", ref, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return fmt.Errorf("cannot get manifest of %s image: %v", ref, err)
+	}
+	errs := []string{}
+	for i, desc := range manifest.Layers {
+		content, err := readLayer(img, desc.Digest)
+		if err == nil {
+			err = f(desc, content)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("layer %d (%s): %v", i, desc.Digest, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// readLayer returns the layer blob as stored in the registry, cosign layers are not compressed
+func readLayer(img v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := img.LayerByDigest(digest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get layer: %v", err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read layer: %v", err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
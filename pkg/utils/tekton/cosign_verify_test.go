@@ -0,0 +1,181 @@
+package tekton
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type cosignTestSigner struct {
+	key       *ecdsa.PrivateKey
+	publicKey []byte
+}
+
+func newCosignTestSigner(t *testing.T) *cosignTestSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	return &cosignTestSigner{key: key, publicKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})}
+}
+
+func (s *cosignTestSigner) sign(t *testing.T, payload []byte) string {
+	digest := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, s.key, digest[:])
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+// signatureLayer creates a cosign signature layer for the image digest
+func (s *cosignTestSigner) signatureLayer(t *testing.T, digest string) mutate.Addendum {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"test"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"}}`, digest))
+	return mutate.Addendum{
+		Layer:       static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{CosignSignatureAnnotation: s.sign(t, payload)},
+	}
+}
+
+// attestationLayer creates a DSSE envelope layer with the statement
+func (s *cosignTestSigner) attestationLayer(t *testing.T, statement string) mutate.Addendum {
+	envelope := DSSEEnvelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString([]byte(statement)),
+	}
+	envelope.Signatures = []DSSESignature{{Sig: s.sign(t, pae(envelope.PayloadType, []byte(statement)))}}
+	content, err := json.Marshal(envelope)
+	assert.NoError(t, err)
+	return mutate.Addendum{Layer: static.NewLayer(content, "application/vnd.dsse.envelope.v1+json")}
+}
+
+func pushTestImage(t *testing.T, ref name.Reference, img v1.Image) v1.Hash {
+	assert.NoError(t, remote.Write(ref, img))
+	digest, err := img.Digest()
+	assert.NoError(t, err)
+	return digest
+}
+
+func cosignTestImage(t *testing.T, layers ...mutate.Addendum) v1.Image {
+	img, err := mutate.Append(empty.Image, layers...)
+	assert.NoError(t, err)
+	return img
+}
+
+// setupCosignTestImage starts an in-memory registry and pushes an image into it, returning the image reference with digest
+func setupCosignTestImage(t *testing.T, referrers bool) (name.Digest, func()) {
+	server := httptest.NewServer(registry.New(registry.WithReferrersSupport(referrers), registry.Logger(log.New(io.Discard, "", 0))))
+	repo, err := name.NewRepository(strings.TrimPrefix(server.URL, "http://") + "/test/repo")
+	assert.NoError(t, err)
+	img, err := random.Image(100, 1)
+	assert.NoError(t, err)
+	digest := pushTestImage(t, repo.Tag("latest"), img)
+	return repo.Digest(digest.String()), server.Close
+}
+
+func TestVerifyImageSignaturesAndAttestationsFromTags(t *testing.T) {
+	image, closeRegistry := setupCosignTestImage(t, false)
+	defer closeRegistry()
+	signer := newCosignTestSigner(t)
+	prefix := cosignTagPrefix(image)
+
+	pushTestImage(t, image.Context().Tag(prefix+".sig"), cosignTestImage(t, signer.signatureLayer(t, image.DigestStr())))
+	pushTestImage(t, image.Context().Tag(prefix+".att"), cosignTestImage(t, signer.attestationLayer(t, `{"_type":"https://in-toto.io/Statement/v0.1"}`)))
+
+	result, err := FindCosignResultsForImage(image.String())
+	assert.NoError(t, err)
+	assert.True(t, result.IsPresent())
+
+	signatures, err := VerifyImageSignatures(image.String(), signer.publicKey)
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 1)
+	assert.Equal(t, image.DigestStr(), signatures[0].Critical.Image.DockerManifestDigest)
+
+	statements, err := VerifyImageAttestations(image.String(), signer.publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`)}, statements)
+
+	otherSigner := newCosignTestSigner(t)
+	_, err = VerifyImageSignatures(image.String(), otherSigner.publicKey)
+	assert.ErrorContains(t, err, "invalid ECDSA signature")
+	_, err = VerifyImageAttestations(image.String(), otherSigner.publicKey)
+	assert.ErrorContains(t, err, "no valid signature of DSSE envelope")
+}
+
+func TestVerifyImageSignatureOfDifferentDigest(t *testing.T) {
+	image, closeRegistry := setupCosignTestImage(t, false)
+	defer closeRegistry()
+	signer := newCosignTestSigner(t)
+
+	otherDigest := "sha256:" + strings.Repeat("0", 64)
+	pushTestImage(t, image.Context().Tag(cosignTagPrefix(image)+".sig"), cosignTestImage(t, signer.signatureLayer(t, otherDigest)))
+
+	_, err := VerifyImageSignatures(image.String(), signer.publicKey)
+	assert.ErrorContains(t, err, "signature was made for "+otherDigest)
+}
+
+func TestFindCosignResultsUsingReferrers(t *testing.T) {
+	for _, referrersAPI := range []bool{true, false} {
+		t.Run(fmt.Sprintf("referrers API supported: %t", referrersAPI), func(t *testing.T) {
+			image, closeRegistry := setupCosignTestImage(t, referrersAPI)
+			defer closeRegistry()
+			signer := newCosignTestSigner(t)
+
+			subject, err := remote.Head(image)
+			assert.NoError(t, err)
+			refer := func(img v1.Image, artifactType types.MediaType) v1.Image {
+				return mutate.Subject(mutate.ConfigMediaType(img, artifactType), *subject).(v1.Image)
+			}
+			signature := refer(cosignTestImage(t, signer.signatureLayer(t, image.DigestStr())), "application/vnd.dev.cosign.artifact.sig.v1+json")
+			attestation := refer(cosignTestImage(t, signer.attestationLayer(t, `{}`)), "application/vnd.dsse.envelope.v1+json")
+			signatureDigest := pushTestImage(t, image.Context().Digest(must(signature.Digest()).String()), signature)
+			attestationDigest := pushTestImage(t, image.Context().Digest(must(attestation.Digest()).String()), attestation)
+			if !referrersAPI {
+				// without the referrers API clients maintain the index of referrers in the sha256-<hex> tag
+				index := mutate.AppendManifests(empty.Index,
+					mutate.IndexAddendum{Add: signature, Descriptor: v1.Descriptor{ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json"}},
+					mutate.IndexAddendum{Add: attestation, Descriptor: v1.Descriptor{ArtifactType: "application/vnd.dsse.envelope.v1+json"}})
+				assert.NoError(t, remote.WriteIndex(image.Context().Tag(cosignTagPrefix(image)), mutate.IndexMediaType(index, types.OCIImageIndex)))
+			}
+
+			result, err := FindCosignResultsForImage(image.String())
+			assert.NoError(t, err)
+			assert.Equal(t, &CosignResult{
+				SignatureImageRef:   image.Context().Digest(signatureDigest.String()).String(),
+				AttestationImageRef: image.Context().Digest(attestationDigest.String()).String(),
+			}, result)
+
+			_, err = VerifyImageSignatures(image.String(), signer.publicKey)
+			assert.NoError(t, err)
+			_, err = VerifyImageAttestations(image.String(), signer.publicKey)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func must(h v1.Hash, err error) v1.Hash {
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
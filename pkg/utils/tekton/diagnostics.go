@@ -0,0 +1,287 @@
+package tekton
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/apis"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultDiagnosisLogLines = 30
+
+// resolutionFailureReasons are reasons of PipelineRun/TaskRun conditions caused by a failure to resolve
+// or validate the pipeline/task definition, rather than by a failure of the task itself
+var resolutionFailureReasons = []string{
+	"CouldntGetPipeline", "CouldntGetTask", "CouldntGetResource", "PipelineValidationFailed",
+	"TaskRunValidationFailed", "TaskRunResolutionFailed", "PipelineRunResolutionFailed", "InvalidGraph",
+	"InvalidTaskResultReference", "InvalidWorkspaceBindings", "InvalidParamValue", "ParameterMissing",
+	"ParameterTypeMismatch", "ObjectParameterMissKeys", "ParamArrayIndexingInvalid", "InvalidTaskRunSpecs",
+	"InvalidPipelineResultReference", "RequiredWorkspaceMarkedOptional", "ResourceVerificationFailed",
+}
+
+// StepDiagnosis describes a step or sidecar container which failed or didn't start.
+type StepDiagnosis struct {
+	Name      string `json:"name"`
+	Container string `json:"container"`
+	// ExitCode is nil if the container didn't terminate
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Reason is the termination reason (e.g. "Error", "OOMKilled") or the waiting reason (e.g. "ImagePullBackOff")
+	Reason  string   `json:"reason,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Log     []string `json:"log,omitempty"`
+}
+
+// TaskRunDiagnosis describes a TaskRun which failed, timed out, was cancelled or was still running
+// when its PipelineRun failed.
+type TaskRunDiagnosis struct {
+	Name            string          `json:"name"`
+	PipelineTask    string          `json:"pipelineTask"`
+	PodName         string          `json:"podName,omitempty"`
+	Reason          string          `json:"reason,omitempty"`
+	Message         string          `json:"message,omitempty"`
+	ResolutionError bool            `json:"resolutionError,omitempty"`
+	Retries         int             `json:"retries,omitempty"`
+	Steps           []StepDiagnosis `json:"steps,omitempty"`
+	Sidecars        []StepDiagnosis `json:"sidecars,omitempty"`
+	// PodEvents are warning events of the TaskRun pod (e.g. FailedScheduling)
+	PodEvents []string `json:"podEvents,omitempty"`
+}
+
+// PipelineRunDiagnosis is a structured report of why a PipelineRun didn't succeed.
+type PipelineRunDiagnosis struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	// ResolutionError is true if the PipelineRun failed to resolve or validate the pipeline definition
+	ResolutionError bool               `json:"resolutionError,omitempty"`
+	TaskRuns        []TaskRunDiagnosis `json:"taskRuns,omitempty"`
+	// Errors encountered while collecting the diagnosis (e.g. a log which couldn't be fetched)
+	Errors []string `json:"errors,omitempty"`
+}
+
+// DiagnoseOptions configure DiagnosePipelineRun.
+type DiagnoseOptions struct {
+	// LogLines is the number of last log lines collected from each failed step, defaults to 30
+	LogLines int64
+}
+
+// DiagnosePipelineRun collects a structured report of the PipelineRun failure: every failed, timed out or cancelled
+// TaskRun together with exit codes and termination reasons of its steps and sidecars, the last lines of logs
+// of failed steps, warning events of the TaskRun pod and resolution/validation errors.
+// Problems with collecting optional details (logs, events) are recorded in the report rather than returned.
+func DiagnosePipelineRun(c crclient.Client, ki kubernetes.Interface, pipelineRun *pipeline.PipelineRun, opts DiagnoseOptions) (*PipelineRunDiagnosis, error) {
+	if opts.LogLines <= 0 {
+		opts.LogLines = defaultDiagnosisLogLines
+	}
+	d := &PipelineRunDiagnosis{Name: pipelineRun.Name, Namespace: pipelineRun.Namespace, Status: string(corev1.ConditionUnknown)}
+	if condition := pipelineRun.Status.GetCondition(apis.ConditionSucceeded); condition != nil {
+		d.Status, d.Reason, d.Message = string(condition.Status), condition.Reason, condition.Message
+		d.ResolutionError = isResolutionFailure(condition.Reason)
+	}
+	pipelineRunDone := pipelineRun.IsDone()
+
+	for _, chr := range pipelineRun.Status.ChildReferences {
+		if chr.Kind != "" && chr.Kind != "TaskRun" {
+			continue
+		}
+		taskRun := &pipeline.TaskRun{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: pipelineRun.Namespace, Name: chr.Name}, taskRun); err != nil {
+			if errors.IsNotFound(err) {
+				d.Errors = append(d.Errors, fmt.Sprintf("TaskRun %s of pipeline task %s not found", chr.Name, chr.PipelineTaskName))
+				continue
+			}
+			return nil, fmt.Errorf("failed to get TaskRun %s of PipelineRun %s: %v", chr.Name, pipelineRun.Name, err)
+		}
+		condition := taskRun.Status.GetCondition(apis.ConditionSucceeded)
+		if condition.IsTrue() || (!pipelineRunDone && (condition == nil || condition.IsUnknown())) {
+			continue
+		}
+		d.TaskRuns = append(d.TaskRuns, d.diagnoseTaskRun(ki, taskRun, chr.PipelineTaskName, opts))
+	}
+	return d, nil
+}
+
+func (d *PipelineRunDiagnosis) diagnoseTaskRun(ki kubernetes.Interface, taskRun *pipeline.TaskRun, pipelineTask string, opts DiagnoseOptions) TaskRunDiagnosis {
+	td := TaskRunDiagnosis{
+		Name:         taskRun.Name,
+		PipelineTask: pipelineTask,
+		PodName:      taskRun.Status.PodName,
+		Reason:       "Running",
+		Retries:      len(taskRun.Status.RetriesStatus),
+	}
+	if condition := taskRun.Status.GetCondition(apis.ConditionSucceeded); condition != nil && !condition.IsUnknown() {
+		td.Reason, td.Message = condition.Reason, condition.Message
+		td.ResolutionError = isResolutionFailure(condition.Reason)
+	}
+
+	for _, step := range taskRun.Status.Steps {
+		if sd, failed := diagnoseContainer(step.Name, step.Container, step.ContainerState); failed {
+			if sd.ExitCode != nil && td.PodName != "" {
+				sd.Log = d.tailLog(ki, taskRun.Namespace, td.PodName, step.Container, opts.LogLines)
+			}
+			td.Steps = append(td.Steps, sd)
+		}
+	}
+	for _, sidecar := range taskRun.Status.Sidecars {
+		if sd, failed := diagnoseContainer(sidecar.Name, sidecar.Container, sidecar.ContainerState); failed {
+			td.Sidecars = append(td.Sidecars, sd)
+		}
+	}
+	if td.PodName != "" {
+		td.PodEvents = d.podWarningEvents(ki, taskRun.Namespace, td.PodName)
+	}
+	return td
+}
+
+// diagnoseContainer returns details of the container state if the container failed or is stuck waiting.
+func diagnoseContainer(name, container string, state corev1.ContainerState) (StepDiagnosis, bool) {
+	sd := StepDiagnosis{Name: name, Container: container}
+	switch {
+	case state.Terminated != nil:
+		exitCode := state.Terminated.ExitCode
+		sd.ExitCode, sd.Reason, sd.Message = &exitCode, state.Terminated.Reason, state.Terminated.Message
+		return sd, exitCode != 0 || (sd.Reason != "" && sd.Reason != "Completed")
+	case state.Waiting != nil:
+		sd.Reason, sd.Message = state.Waiting.Reason, state.Waiting.Message
+		return sd, sd.Reason != "" && sd.Reason != "PodInitializing" && sd.Reason != "ContainerCreating"
+	}
+	return sd, false
+}
+
+func (d *PipelineRunDiagnosis) tailLog(ki kubernetes.Interface, namespace, podName, container string, lines int64) []string {
+	stream, err := ki.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{Container: container, TailLines: &lines}).Stream(context.Background())
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("failed to get log of container %s in pod %s: %v", container, podName, err))
+		return nil
+	}
+	defer stream.Close()
+	log, err := io.ReadAll(stream)
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("failed to read log of container %s in pod %s: %v", container, podName, err))
+	}
+	if len(log) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(log), "\n"), "\n")
+}
+
+func (d *PipelineRunDiagnosis) podWarningEvents(ki kubernetes.Interface, namespace, podName string) []string {
+	events, err := ki.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.name", podName).String(),
+	})
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("failed to list events of pod %s: %v", podName, err))
+		return nil
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+	warnings := []string{}
+	for _, event := range events.Items {
+		if event.Type == corev1.EventTypeWarning && event.InvolvedObject.Name == podName {
+			count := event.Count
+			if count == 0 {
+				count = 1
+			}
+			warnings = append(warnings, fmt.Sprintf("%s: %s (x%d)", event.Reason, event.Message, count))
+		}
+	}
+	return warnings
+}
+
+func isResolutionFailure(reason string) bool {
+	for _, r := range resolutionFailureReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON returns the diagnosis encoded as indented JSON.
+func (d *PipelineRunDiagnosis) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// String renders the diagnosis as human readable text.
+func (d *PipelineRunDiagnosis) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "PipelineRun %s/%s: status %s", d.Namespace, d.Name, d.Status)
+	if d.Reason != "" {
+		fmt.Fprintf(&sb, ", reason %s", d.Reason)
+	}
+	sb.WriteString("\n")
+	if d.Message != "" {
+		fmt.Fprintf(&sb, "  %s\n", d.Message)
+	}
+	if d.ResolutionError {
+		sb.WriteString("  the pipeline definition could not be resolved or validated\n")
+	}
+	for _, tr := range d.TaskRuns {
+		fmt.Fprintf(&sb, "\nTask %s (TaskRun %s, pod %s): %s", tr.PipelineTask, tr.Name, valueOr(tr.PodName, "<none>"), tr.Reason)
+		if tr.Retries > 0 {
+			fmt.Fprintf(&sb, " after %d retries", tr.Retries)
+		}
+		sb.WriteString("\n")
+		if tr.Message != "" {
+			fmt.Fprintf(&sb, "  %s\n", tr.Message)
+		}
+		for _, s := range tr.Steps {
+			writeStepDiagnosis(&sb, "step", s)
+		}
+		for _, s := range tr.Sidecars {
+			writeStepDiagnosis(&sb, "sidecar", s)
+		}
+		if len(tr.PodEvents) > 0 {
+			sb.WriteString("  pod events:\n")
+			for _, e := range tr.PodEvents {
+				fmt.Fprintf(&sb, "    %s\n", e)
+			}
+		}
+	}
+	if len(d.Errors) > 0 {
+		sb.WriteString("\nErrors while collecting the diagnosis:\n")
+		for _, e := range d.Errors {
+			fmt.Fprintf(&sb, "  %s\n", e)
+		}
+	}
+	return sb.String()
+}
+
+func writeStepDiagnosis(sb *strings.Builder, kind string, s StepDiagnosis) {
+	fmt.Fprintf(sb, "  %s %s (container %s): %s", kind, s.Name, s.Container, valueOr(s.Reason, "<no reason>"))
+	if s.ExitCode != nil {
+		fmt.Fprintf(sb, ", exit code %d", *s.ExitCode)
+	}
+	sb.WriteString("\n")
+	if s.Message != "" {
+		fmt.Fprintf(sb, "    %s\n", s.Message)
+	}
+	if len(s.Log) > 0 {
+		fmt.Fprintf(sb, "    last %d log lines:\n", len(s.Log))
+		for _, line := range s.Log {
+			fmt.Fprintf(sb, "      %s\n", line)
+		}
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
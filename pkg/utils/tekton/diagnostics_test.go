@@ -0,0 +1,111 @@
+package tekton
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func succeededStatus(status corev1.ConditionStatus, reason, message string) duckv1.Status {
+	return duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status, Reason: reason, Message: message}}}
+}
+
+func TestDiagnosePipelineRun(t *testing.T) {
+	pr := &pipeline.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns"},
+		Status: pipeline.PipelineRunStatus{
+			Status: succeededStatus(corev1.ConditionFalse, "Failed", "Tasks Completed: 3 (Failed: 2, Cancelled 0), Skipped: 0"),
+			PipelineRunStatusFields: pipeline.PipelineRunStatusFields{ChildReferences: []pipeline.ChildStatusReference{
+				{Name: "build-clone", PipelineTaskName: "clone"},
+				{Name: "build-buildah", PipelineTaskName: "build-container"},
+				{Name: "build-scan", PipelineTaskName: "scan"},
+				{Name: "build-missing", PipelineTaskName: "missing"},
+			}},
+		},
+	}
+	clone := &pipeline.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-clone", Namespace: "ns"},
+		Status:     pipeline.TaskRunStatus{Status: succeededStatus(corev1.ConditionTrue, "Succeeded", "")},
+	}
+	buildah := &pipeline.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-buildah", Namespace: "ns"},
+		Status: pipeline.TaskRunStatus{
+			Status: succeededStatus(corev1.ConditionFalse, "Failed", `"step-build" exited with code 137 (image: "buildah"): OOMKilled`),
+			TaskRunStatusFields: pipeline.TaskRunStatusFields{
+				PodName: "build-buildah-pod",
+				Steps: []pipeline.StepState{
+					{Name: "prepare", Container: "step-prepare", ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+					{Name: "build", Container: "step-build", ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}},
+				},
+				Sidecars: []pipeline.SidecarState{
+					{Name: "registry", Container: "sidecar-registry", ContainerState: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			},
+		},
+	}
+	scan := &pipeline.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-scan", Namespace: "ns"},
+		Status:     pipeline.TaskRunStatus{Status: succeededStatus(corev1.ConditionFalse, "TaskRunTimeout", "TaskRun \"build-scan\" failed to finish within \"1m0s\"")},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, pipeline.AddToScheme(scheme))
+	c := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(clone, buildah, scan).Build()
+	ki := k8sfake.NewSimpleClientset(&corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "build-buildah-pod.1", Namespace: "ns"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "build-buildah-pod"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off pulling image \"registry\"",
+		Count:          3,
+	})
+
+	d, err := DiagnosePipelineRun(c, ki, pr, DiagnoseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "False", d.Status)
+	assert.False(t, d.ResolutionError)
+	assert.Len(t, d.TaskRuns, 2)
+	assert.Equal(t, []string{"TaskRun build-missing of pipeline task missing not found"}, d.Errors)
+
+	build := d.TaskRuns[0]
+	assert.Equal(t, "build-container", build.PipelineTask)
+	assert.Len(t, build.Steps, 1)
+	assert.Equal(t, "OOMKilled", build.Steps[0].Reason)
+	assert.Equal(t, int32(137), *build.Steps[0].ExitCode)
+	assert.Equal(t, []string{"fake logs"}, build.Steps[0].Log)
+	assert.Equal(t, "ImagePullBackOff", build.Sidecars[0].Reason)
+	assert.Equal(t, []string{`BackOff: Back-off pulling image "registry" (x3)`}, build.PodEvents)
+	assert.Equal(t, "TaskRunTimeout", d.TaskRuns[1].Reason)
+
+	text := d.String()
+	assert.Contains(t, text, "Task build-container (TaskRun build-buildah, pod build-buildah-pod): Failed")
+	assert.Contains(t, text, "step build (container step-build): OOMKilled, exit code 137")
+	assert.Contains(t, text, "Task scan (TaskRun build-scan, pod <none>): TaskRunTimeout")
+
+	encoded, err := d.JSON()
+	assert.NoError(t, err)
+	decoded := &PipelineRunDiagnosis{}
+	assert.NoError(t, json.Unmarshal(encoded, decoded))
+	assert.Equal(t, d, decoded)
+}
+
+func TestDiagnosePipelineRunResolutionError(t *testing.T) {
+	pr := &pipeline.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns"},
+		Status:     pipeline.PipelineRunStatus{Status: succeededStatus(corev1.ConditionFalse, "CouldntGetPipeline", "bundle not found")},
+	}
+	d, err := DiagnosePipelineRun(crfake.NewClientBuilder().Build(), k8sfake.NewSimpleClientset(), pr, DiagnoseOptions{})
+	assert.NoError(t, err)
+	assert.True(t, d.ResolutionError)
+	assert.Empty(t, d.TaskRuns)
+	assert.Contains(t, d.String(), "could not be resolved or validated")
+}
@@ -9,8 +9,6 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"knative.dev/pkg/apis"
 
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
-
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -202,19 +200,13 @@ func (p ECIntegrationTestScenario) Generate() (*pipeline.PipelineRun, error) {
 	}, nil
 }
 
-// GetFailedPipelineRunLogs gets the logs of the pipelinerun failed task
+// GetFailedPipelineRunLogs returns a diagnosis of the failed pipelinerun including the last lines of logs of failed steps.
 func GetFailedPipelineRunLogs(c crclient.Client, ki kubernetes.Interface, pipelineRun *pipeline.PipelineRun) (string, error) {
-	var d *FailedPipelineRunDetails
-	var err error
-	failMessage := fmt.Sprintf("Pipelinerun '%s' didn't succeed\n", pipelineRun.Name)
-	if d, err = GetFailedPipelineRunDetails(c, pipelineRun); err != nil {
+	d, err := DiagnosePipelineRun(c, ki, pipelineRun, DiagnoseOptions{})
+	if err != nil {
 		return "", err
 	}
-	if d.FailedContainerName != "" {
-		logs, _ := utils.GetContainerLogs(ki, d.PodName, d.FailedContainerName, pipelineRun.Namespace)
-		failMessage += fmt.Sprintf("Logs from failed container '%s': \n%s", d.FailedContainerName, logs)
-	}
-	return failMessage, nil
+	return fmt.Sprintf("Pipelinerun '%s' didn't succeed\n%s", pipelineRun.Name, d), nil
 }
 
 func HasPipelineRunSucceeded(pr *pipeline.PipelineRun) bool {
@@ -0,0 +1,374 @@
+package tekton
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SpanQueued is the time between creation of the PipelineRun (TaskRun) and its start (pod creation)
+	SpanQueued = "queued"
+	// SpanPVC is the time the TaskRun pod waited for its PersistentVolumeClaims to be bound
+	SpanPVC = "pvc"
+	// SpanScheduling is the time between creation of the TaskRun pod and its scheduling to a node
+	SpanScheduling = "scheduling"
+	SpanInit       = "init"
+	SpanStep       = "step"
+	SpanTaskRun    = "taskrun"
+)
+
+// TraceSpan is a single timed phase of a PipelineRun.
+type TraceSpan struct {
+	Name  string    `json:"name"`
+	Kind  string    `json:"kind"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (s TraceSpan) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// TaskTrace holds spans of a single TaskRun of the PipelineRun, ordered by their start.
+type TaskTrace struct {
+	PipelineTask string      `json:"pipelineTask"`
+	TaskRun      string      `json:"taskRun"`
+	Pod          string      `json:"pod,omitempty"`
+	Span         TraceSpan   `json:"span"`
+	Phases       []TraceSpan `json:"phases"`
+}
+
+// PipelineRunTrace is a timeline of the PipelineRun, its TaskRuns, their pods and steps.
+type PipelineRunTrace struct {
+	PipelineRun string      `json:"pipelineRun"`
+	Namespace   string      `json:"namespace"`
+	Span        TraceSpan   `json:"span"`
+	Queued      TraceSpan   `json:"queued"`
+	Tasks       []TaskTrace `json:"tasks"`
+}
+
+// NewPipelineRunTrace builds a trace of the PipelineRun from its TaskRuns and their pods. pvcBoundTimes maps names
+// of PersistentVolumeClaims to the time they got bound, it is used for computing how long pods waited for their volumes.
+// TaskRuns or pods which are missing are traced with the information available.
+func NewPipelineRunTrace(pr *pipeline.PipelineRun, taskRuns []pipeline.TaskRun, pods []corev1.Pod, pvcBoundTimes map[string]time.Time) *PipelineRunTrace {
+	end := time.Now()
+	if pr.Status.CompletionTime != nil {
+		end = pr.Status.CompletionTime.Time
+	}
+	start := pr.CreationTimestamp.Time
+	started := end
+	if pr.Status.StartTime != nil {
+		started = pr.Status.StartTime.Time
+	}
+	t := &PipelineRunTrace{
+		PipelineRun: pr.Name,
+		Namespace:   pr.Namespace,
+		Span:        TraceSpan{Name: pr.Name, Kind: "pipelinerun", Start: start, End: end},
+		Queued:      TraceSpan{Name: SpanQueued, Kind: SpanQueued, Start: start, End: started},
+	}
+
+	podsByName := map[string]*corev1.Pod{}
+	for i := range pods {
+		podsByName[pods[i].Name] = &pods[i]
+	}
+	for i := range taskRuns {
+		t.Tasks = append(t.Tasks, newTaskTrace(&taskRuns[i], podsByName[taskRuns[i].Status.PodName], pvcBoundTimes, end))
+	}
+	sort.SliceStable(t.Tasks, func(i, j int) bool { return t.Tasks[i].Span.Start.Before(t.Tasks[j].Span.Start) })
+	return t
+}
+
+func newTaskTrace(tr *pipeline.TaskRun, pod *corev1.Pod, pvcBoundTimes map[string]time.Time, fallbackEnd time.Time) TaskTrace {
+	end := fallbackEnd
+	if tr.Status.CompletionTime != nil {
+		end = tr.Status.CompletionTime.Time
+	}
+	tt := TaskTrace{
+		PipelineTask: tr.Labels["tekton.dev/pipelineTask"],
+		TaskRun:      tr.Name,
+		Pod:          tr.Status.PodName,
+		Span:         TraceSpan{Name: tr.Name, Kind: SpanTaskRun, Start: tr.CreationTimestamp.Time, End: end},
+	}
+	if tt.PipelineTask == "" {
+		tt.PipelineTask = tr.Name
+	}
+	addPhase := func(name, kind string, start, end time.Time) {
+		if !start.IsZero() && !end.IsZero() && end.After(start) {
+			tt.Phases = append(tt.Phases, TraceSpan{Name: name, Kind: kind, Start: start, End: end})
+		}
+	}
+
+	if pod != nil {
+		addPhase(SpanQueued, SpanQueued, tr.CreationTimestamp.Time, pod.CreationTimestamp.Time)
+		var boundAt time.Time
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			if t, ok := pvcBoundTimes[volume.PersistentVolumeClaim.ClaimName]; ok && t.After(boundAt) {
+				boundAt = t
+			}
+		}
+		addPhase(SpanPVC, SpanPVC, pod.CreationTimestamp.Time, boundAt)
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+				addPhase(SpanScheduling, SpanScheduling, pod.CreationTimestamp.Time, condition.LastTransitionTime.Time)
+			}
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			if terminated := status.State.Terminated; terminated != nil {
+				addPhase(status.Name, SpanInit, terminated.StartedAt.Time, terminated.FinishedAt.Time)
+			}
+		}
+	}
+	for _, step := range tr.Status.Steps {
+		if terminated := step.Terminated; terminated != nil {
+			addPhase(step.Name, SpanStep, terminated.StartedAt.Time, terminated.FinishedAt.Time)
+		} else if running := step.Running; running != nil {
+			addPhase(step.Name, SpanStep, running.StartedAt.Time, end)
+		}
+	}
+	sort.SliceStable(tt.Phases, func(i, j int) bool { return tt.Phases[i].Start.Before(tt.Phases[j].Start) })
+	return tt
+}
+
+// CollectPipelineRunTrace fetches TaskRuns, pods and volume claims of the PipelineRun and builds its trace.
+// Pods and claims which were already deleted are skipped.
+func CollectPipelineRunTrace(c crclient.Client, ki kubernetes.Interface, pr *pipeline.PipelineRun) (*PipelineRunTrace, error) {
+	taskRuns := []pipeline.TaskRun{}
+	pods := []corev1.Pod{}
+	pvcBoundTimes := map[string]time.Time{}
+	for _, chr := range pr.Status.ChildReferences {
+		if chr.Kind != "" && chr.Kind != "TaskRun" {
+			continue
+		}
+		taskRun := pipeline.TaskRun{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: pr.Namespace, Name: chr.Name}, &taskRun); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get TaskRun %s of PipelineRun %s: %v", chr.Name, pr.Name, err)
+		}
+		taskRuns = append(taskRuns, taskRun)
+		if taskRun.Status.PodName == "" {
+			continue
+		}
+		pod, err := ki.CoreV1().Pods(pr.Namespace).Get(context.Background(), taskRun.Status.PodName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get pod %s of TaskRun %s: %v", taskRun.Status.PodName, taskRun.Name, err)
+		}
+		pods = append(pods, *pod)
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			if _, ok := pvcBoundTimes[volume.PersistentVolumeClaim.ClaimName]; ok {
+				continue
+			}
+			if boundAt, ok := pvcBoundTime(ki, pr.Namespace, volume.PersistentVolumeClaim.ClaimName); ok {
+				pvcBoundTimes[volume.PersistentVolumeClaim.ClaimName] = boundAt
+			}
+		}
+	}
+	return NewPipelineRunTrace(pr, taskRuns, pods, pvcBoundTimes), nil
+}
+
+// pvcBoundTime approximates the time the claim got bound by the creation time of its volume.
+func pvcBoundTime(ki kubernetes.Interface, namespace, claimName string) (time.Time, bool) {
+	pvc, err := ki.CoreV1().PersistentVolumeClaims(namespace).Get(context.Background(), claimName, metav1.GetOptions{})
+	if err != nil || pvc.Spec.VolumeName == "" {
+		return time.Time{}, false
+	}
+	pv, err := ki.CoreV1().PersistentVolumes().Get(context.Background(), pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return time.Time{}, false
+	}
+	return pv.CreationTimestamp.Time, true
+}
+
+type chromeTraceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat,omitempty"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur,omitempty"`
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// ChromeTrace exports the traces in Chrome Trace Event format, which can be opened in chrome://tracing or Perfetto.
+// Every PipelineRun is rendered as a process and every TaskRun as a thread of it.
+func ChromeTrace(traces ...*PipelineRunTrace) ([]byte, error) {
+	events := []chromeTraceEvent{}
+	complete := func(span TraceSpan, pid, tid int, args map[string]string) {
+		events = append(events, chromeTraceEvent{
+			Name: span.Name, Category: span.Kind, Phase: "X", PID: pid, TID: tid, Args: args,
+			Timestamp: span.Start.UnixMicro(), Duration: span.Duration().Microseconds(),
+		})
+	}
+	metadata := func(name string, pid, tid int, value string) {
+		events = append(events, chromeTraceEvent{Name: name, Phase: "M", PID: pid, TID: tid, Args: map[string]string{"name": value}})
+	}
+
+	for i, t := range traces {
+		pid := i + 1
+		metadata("process_name", pid, 0, fmt.Sprintf("%s/%s", t.Namespace, t.PipelineRun))
+		metadata("thread_name", pid, 0, "PipelineRun")
+		complete(t.Span, pid, 0, nil)
+		complete(t.Queued, pid, 0, nil)
+		for j, task := range t.Tasks {
+			tid := j + 1
+			metadata("thread_name", pid, tid, task.PipelineTask)
+			complete(task.Span, pid, tid, map[string]string{"taskRun": task.TaskRun, "pod": task.Pod})
+			for _, phase := range task.Phases {
+				complete(phase, pid, tid, nil)
+			}
+		}
+	}
+	return json.MarshalIndent(map[string]interface{}{"traceEvents": events, "displayTimeUnit": "ms"}, "", "  ")
+}
+
+// Gantt renders the trace as a text Gantt chart with bars scaled to the given width.
+func (t *PipelineRunTrace) Gantt(width int) string {
+	if width <= 0 {
+		width = 80
+	}
+	total := t.Span.Duration()
+	bar := func(span TraceSpan, char string) string {
+		if total <= 0 {
+			return strings.Repeat(" ", width)
+		}
+		offset := int(float64(width) * float64(span.Start.Sub(t.Span.Start)) / float64(total))
+		length := int(math.Ceil(float64(width) * float64(span.Duration()) / float64(total)))
+		offset = clamp(offset, 0, width-1)
+		length = clamp(length, 1, width-offset)
+		return strings.Repeat(" ", offset) + strings.Repeat(char, length) + strings.Repeat(" ", width-offset-length)
+	}
+
+	type row struct {
+		label string
+		span  TraceSpan
+		char  string
+	}
+	rows := []row{{t.PipelineRun, t.Span, "="}, {"  " + SpanQueued, t.Queued, "."}}
+	for _, task := range t.Tasks {
+		rows = append(rows, row{task.PipelineTask, task.Span, "="})
+		for _, phase := range task.Phases {
+			char := "#"
+			if phase.Kind != SpanStep {
+				char = "."
+			}
+			rows = append(rows, row{fmt.Sprintf("  %s %s", phase.Kind, phase.Name), phase, char})
+		}
+	}
+	labelWidth := 0
+	for _, r := range rows {
+		if len(r.label) > labelWidth {
+			labelWidth = len(r.label)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "PipelineRun %s/%s, total %s\n", t.Namespace, t.PipelineRun, total.Round(time.Second))
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "%-*s |%s| %s\n", labelWidth, r.label, bar(r.span, r.char), r.span.Duration().Round(time.Second))
+	}
+	return sb.String()
+}
+
+// TaskDurationStats are percentiles of durations of a pipeline task (or of one of its phases) across PipelineRuns.
+type TaskDurationStats struct {
+	// Name is the pipeline task name, optionally followed by "/<phase kind>/<phase name>"
+	Name  string        `json:"name"`
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P95   time.Duration `json:"p95"`
+	Max   time.Duration `json:"max"`
+}
+
+// AggregateTaskDurations computes duration percentiles of every pipeline task and its phases across the traces,
+// sorted by the p90 duration (the slowest first).
+func AggregateTaskDurations(traces ...*PipelineRunTrace) []TaskDurationStats {
+	durations := map[string][]time.Duration{}
+	for _, t := range traces {
+		durations[SpanQueued] = append(durations[SpanQueued], t.Queued.Duration())
+		for _, task := range t.Tasks {
+			durations[task.PipelineTask] = append(durations[task.PipelineTask], task.Span.Duration())
+			for _, phase := range task.Phases {
+				name := fmt.Sprintf("%s/%s/%s", task.PipelineTask, phase.Kind, phase.Name)
+				durations[name] = append(durations[name], phase.Duration())
+			}
+		}
+	}
+
+	stats := []TaskDurationStats{}
+	for name, ds := range durations {
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+		var sum time.Duration
+		for _, d := range ds {
+			sum += d
+		}
+		stats = append(stats, TaskDurationStats{
+			Name:  name,
+			Count: len(ds),
+			Mean:  sum / time.Duration(len(ds)),
+			P50:   percentile(ds, 50),
+			P90:   percentile(ds, 90),
+			P95:   percentile(ds, 95),
+			Max:   ds[len(ds)-1],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].P90 == stats[j].P90 {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].P90 > stats[j].P90
+	})
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[clamp(rank, 1, len(sorted))-1]
+}
+
+func clamp(value, lower, upper int) int {
+	if value < lower {
+		return lower
+	}
+	if value > upper {
+		return upper
+	}
+	return value
+}
+
+// FormatTaskDurationStats renders the stats as a text table.
+func FormatTaskDurationStats(stats []TaskDurationStats) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-60s %6s %10s %10s %10s %10s %10s\n", "TASK", "COUNT", "MEAN", "P50", "P90", "P95", "MAX")
+	for _, s := range stats {
+		fmt.Fprintf(&sb, "%-60s %6d %10s %10s %10s %10s %10s\n", s.Name, s.Count,
+			s.Mean.Round(time.Second), s.P50.Round(time.Second), s.P90.Round(time.Second), s.P95.Round(time.Second), s.Max.Round(time.Second))
+	}
+	return sb.String()
+}
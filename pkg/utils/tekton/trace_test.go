@@ -0,0 +1,100 @@
+package tekton
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTracedPipelineRun(start time.Time, buildDuration time.Duration) (*pipeline.PipelineRun, []pipeline.TaskRun, []corev1.Pod, map[string]time.Time) {
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(start.Add(d)) }
+	atPtr := func(d time.Duration) *metav1.Time { t := at(d); return &t }
+
+	pr := &pipeline.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns", CreationTimestamp: at(0)},
+		Status: pipeline.PipelineRunStatus{PipelineRunStatusFields: pipeline.PipelineRunStatusFields{
+			StartTime: atPtr(2 * time.Second), CompletionTime: atPtr(20*time.Second + buildDuration),
+		}},
+	}
+	taskRuns := []pipeline.TaskRun{{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-buildah", CreationTimestamp: at(10 * time.Second), Labels: map[string]string{"tekton.dev/pipelineTask": "build-container"}},
+		Status: pipeline.TaskRunStatus{TaskRunStatusFields: pipeline.TaskRunStatusFields{
+			PodName: "build-buildah-pod", CompletionTime: atPtr(20*time.Second + buildDuration),
+			Steps: []pipeline.StepState{{Name: "build", ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: at(18 * time.Second), FinishedAt: at(18*time.Second + buildDuration)}}}},
+		}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "build-clone", CreationTimestamp: at(3 * time.Second), Labels: map[string]string{"tekton.dev/pipelineTask": "clone"}},
+		Status:     pipeline.TaskRunStatus{TaskRunStatusFields: pipeline.TaskRunStatusFields{CompletionTime: atPtr(9 * time.Second)}},
+	}}
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-buildah-pod", CreationTimestamp: at(11 * time.Second)},
+		Spec:       corev1.PodSpec{Volumes: []corev1.Volume{{Name: "ws", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "ws-pvc"}}}}},
+		Status: corev1.PodStatus{
+			Conditions:            []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: at(15 * time.Second)}},
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "prepare", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: at(16 * time.Second), FinishedAt: at(17 * time.Second)}}}},
+		},
+	}}
+	return pr, taskRuns, pods, map[string]time.Time{"ws-pvc": start.Add(14 * time.Second)}
+}
+
+func TestNewPipelineRunTrace(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	trace := NewPipelineRunTrace(newTracedPipelineRun(start, time.Minute))
+
+	assert.Equal(t, 80*time.Second, trace.Span.Duration())
+	assert.Equal(t, 2*time.Second, trace.Queued.Duration())
+	assert.Len(t, trace.Tasks, 2)
+	assert.Equal(t, "clone", trace.Tasks[0].PipelineTask, "tasks should be ordered by start")
+	assert.Empty(t, trace.Tasks[0].Phases)
+
+	build := trace.Tasks[1]
+	phases := []string{}
+	for _, p := range build.Phases {
+		phases = append(phases, p.Kind+"/"+p.Name+"="+p.Duration().String())
+	}
+	assert.Equal(t, []string{"queued/queued=1s", "pvc/pvc=3s", "scheduling/scheduling=4s", "init/prepare=1s", "step/build=1m0s"}, phases)
+
+	gantt := trace.Gantt(40)
+	assert.Contains(t, gantt, "PipelineRun ns/build, total 1m20s")
+	assert.Contains(t, gantt, "step build")
+	for _, line := range strings.Split(strings.TrimSpace(gantt), "\n")[1:] {
+		assert.Equal(t, 40, strings.Index(line[strings.Index(line, "|")+1:], "|"), "bars should have the same width: %q", line)
+	}
+
+	chromeTrace, err := ChromeTrace(trace)
+	assert.NoError(t, err)
+	decoded := struct {
+		TraceEvents []chromeTraceEvent `json:"traceEvents"`
+	}{}
+	assert.NoError(t, json.Unmarshal(chromeTrace, &decoded))
+	for _, e := range decoded.TraceEvents {
+		if e.Name == "build" && e.Category == SpanStep {
+			assert.Equal(t, start.Add(18*time.Second).UnixMicro(), e.Timestamp)
+			assert.Equal(t, time.Minute.Microseconds(), e.Duration)
+			assert.Equal(t, 2, e.TID)
+		}
+	}
+}
+
+func TestAggregateTaskDurations(t *testing.T) {
+	start := time.Now()
+	traces := []*PipelineRunTrace{}
+	for i := 1; i <= 10; i++ {
+		traces = append(traces, NewPipelineRunTrace(newTracedPipelineRun(start, time.Duration(i)*time.Minute)))
+	}
+	stats := AggregateTaskDurations(traces...)
+
+	assert.Equal(t, "build-container", stats[0].Name)
+	assert.Equal(t, 10, stats[0].Count)
+	assert.Equal(t, 5*time.Minute+10*time.Second, stats[0].P50)
+	assert.Equal(t, 9*time.Minute+10*time.Second, stats[0].P90)
+	assert.Equal(t, 10*time.Minute+10*time.Second, stats[0].Max)
+	assert.Equal(t, "build-container/step/build", stats[1].Name)
+	assert.Contains(t, FormatTaskDurationStats(stats), "build-container/step/build")
+}
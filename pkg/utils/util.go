@@ -28,6 +28,7 @@ import (
 
 	"github.com/devfile/library/v2/pkg/util"
 	"github.com/mitchellh/go-homedir"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"k8s.io/klog/v2"
 
@@ -90,7 +91,7 @@ func GetEnvOrFunc(key string, defaultFunc func() (string, error)) (string, error
 }
 
 func GetQuayIOOrganization() string {
-	return GetEnv(constants.QUAY_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
+	return config.Get(constants.QUAY_E2E_ORGANIZATION_ENV)
 }
 
 func IsPrivateHostname(url string) bool {
@@ -120,14 +121,29 @@ func GetOpenshiftToken() (token string, err error) {
 	return strings.TrimSuffix(string(tokenBytes), "\n"), nil
 }
 
+// WithE2ETestLabel adds the label marking cluster resources created by the e2e framework to the labels
+func WithE2ETestLabel(labels map[string]string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constants.E2ETestLabelKey] = constants.E2ETestLabelValue
+	return labels
+}
+
 func GetGeneratedNamespace(name string) string {
 	return name + "-" + util.GenerateRandomString(4)
 }
 
+// WaitUntilWithInterval polls the condition in the interval until it's met or the timeout expires.
+//
+// Deprecated: use a Poller, which reports the last observed state on timeout.
 func WaitUntilWithInterval(cond wait.ConditionFunc, interval time.Duration, timeout time.Duration) error {
-	return wait.PollUntilContextTimeout(context.Background(), interval, timeout, true, func(ctx context.Context) (bool, error) { return cond() })
+	return NewPoller("", timeout).WithInterval(interval).PollCondition(context.Background(), cond)
 }
 
+// WaitUntil polls the condition each second until it's met or the timeout expires.
+//
+// Deprecated: use a Poller, which reports the last observed state on timeout.
 func WaitUntil(cond wait.ConditionFunc, timeout time.Duration) error {
 	return WaitUntilWithInterval(cond, time.Second, timeout)
 }
@@ -256,7 +272,10 @@ func ExtractGitRepositoryNameFromURL(url string) (name string) {
 }
 
 func GetGithubAppID() (int64, error) {
-	appIDStr := GetEnv("E2E_PAC_GITHUB_APP_ID", constants.DefaultPaCGitHubAppID)
+	appIDStr := config.Get("E2E_PAC_GITHUB_APP_ID")
+	if appIDStr == "" {
+		appIDStr = constants.DefaultPaCGitHubAppID
+	}
 
 	id, err := strconv.ParseInt(appIDStr, 10, 64)
 	if err != nil {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -16,6 +15,7 @@ import (
 	"github.com/google/go-github/v44/github"
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/build"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 
@@ -54,11 +54,9 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		var prHeadSha string
 
 		BeforeAll(func() {
-			if os.Getenv(constants.SKIP_PAC_TESTS_ENV) == "true" {
-				Skip("Skipping this test due to configuration issue with Spray proxy")
-			}
+			framework.SkipUnlessPipelinesAsCode()
 
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.AcquireFramework(buildUserPoolPrefix)
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -70,7 +68,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				Skip("Using private cluster (not reachable from Github), skipping...")
 			}
 
-			quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "")
+			quayOrg := config.Get(constants.DEFAULT_QUAY_ORG_ENV)
 			supports, err := build.DoesQuayOrgSupportPrivateRepo()
 			Expect(err).ShouldNot(HaveOccurred(), fmt.Sprintf("error while checking if quay org supports private repo: %+v", err))
 			if !supports {
@@ -89,20 +87,20 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				Succeed(), fmt.Sprintf("timed out waiting for gitops content to be created for app %s in namespace %s: %+v", app.Name, app.Namespace, err),
 			)
 
-			componentName = fmt.Sprintf("%s-%s", "test-component-pac", util.GenerateRandomString(6))
+			componentName = framework.UniqueName("test-component-pac")
 			pacBranchName = constants.PaCPullRequestBranchPrefix + componentName
-			componentBaseBranchName = fmt.Sprintf("base-%s", util.GenerateRandomString(6))
+			componentBaseBranchName = framework.UniqueName("base")
 
 			err = f.AsKubeAdmin.CommonController.Github.CreateRef(helloWorldComponentGitSourceRepoName, helloWorldComponentDefaultBranch, helloWorldComponentRevision, componentBaseBranchName)
 			Expect(err).ShouldNot(HaveOccurred())
 
-			defaultBranchTestComponentName = fmt.Sprintf("test-custom-default-branch-%s", util.GenerateRandomString(6))
+			defaultBranchTestComponentName = framework.UniqueName("test-custom-default-branch")
 		})
 
 		AfterAll(func() {
 			if !CurrentSpecReport().Failed() {
 				Expect(f.AsKubeAdmin.HasController.DeleteApplication(applicationName, testNamespace, false)).To(Succeed())
-				Expect(f.SandboxController.DeleteUserSignup(f.UserName)).To(BeTrue())
+				Expect(framework.ReleaseFramework(buildUserPoolPrefix, f)).To(Succeed())
 			}
 
 			// Delete new branches created by PaC and a testing branch used as a component's base branch
@@ -413,7 +411,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 				expiration, ok := labels["quay.expires-after"]
 				Expect(ok).To(BeTrue())
-				Expect(expiration).To(Equal(utils.GetEnv(constants.IMAGE_TAG_EXPIRATION_ENV, constants.DefaultImageTagExpiration)))
+				Expect(expiration).To(Equal(config.Get(constants.IMAGE_TAG_EXPIRATION_ENV)))
 			})
 			It("eventually leads to the PipelineRun status report at Checks tab", func() {
 				validateChecks()
@@ -631,10 +629,8 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		var timeout time.Duration
 
 		BeforeAll(func() {
-			if os.Getenv(constants.SKIP_PAC_TESTS_ENV) == "true" {
-				Skip("Skipping this test due to configuration issue with Spray proxy")
-			}
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			framework.SkipUnlessPipelinesAsCode()
+			f, err = framework.AcquireFramework(buildUserPoolPrefix)
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -653,19 +649,19 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				Succeed(), fmt.Sprintf("timed out waiting for gitops content to be created for app %s in namespace %s: %+v", app.Name, app.Namespace, err),
 			)
 
-			multiComponentBaseBranchName = fmt.Sprintf("multi-component-base-%s", util.GenerateRandomString(6))
+			multiComponentBaseBranchName = framework.UniqueName("multi-component-base")
 			err = f.AsKubeAdmin.CommonController.Github.CreateRef(multiComponentGitSourceRepoName, multiComponentDefaultBranch, multiComponentGitRevision, multiComponentBaseBranchName)
 			Expect(err).ShouldNot(HaveOccurred())
 
 			//Branch for creating pull request
-			multiComponentPRBranchName = fmt.Sprintf("%s-%s", "pr-branch", util.GenerateRandomString(6))
+			multiComponentPRBranchName = framework.UniqueName("pr-branch")
 
 		})
 
 		AfterAll(func() {
 			if !CurrentSpecReport().Failed() {
 				Expect(f.AsKubeAdmin.HasController.DeleteApplication(applicationName, testNamespace, false)).To(Succeed())
-				Expect(f.SandboxController.DeleteUserSignup(f.UserName)).To(BeTrue())
+				Expect(framework.ReleaseFramework(buildUserPoolPrefix, f)).To(Succeed())
 			}
 
 			// Delete new branches created by PaC and a testing branch used as a component's base branch
@@ -692,7 +688,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 			for _, contextDir := range multiComponentContextDirs {
 				contextDir := contextDir
-				componentName := fmt.Sprintf("%s-%s", contextDir, util.GenerateRandomString(6))
+				componentName := framework.UniqueName(contextDir)
 				pacBranchName := constants.PaCPullRequestBranchPrefix + componentName
 				pacBranchNames = append(pacBranchNames, pacBranchName)
 
@@ -887,7 +883,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		var timeout, interval time.Duration
 
 		BeforeAll(func() {
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.AcquireFramework(buildUserPoolPrefix)
 			Expect(err).ShouldNot(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -908,7 +904,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		AfterAll(func() {
 			if !CurrentSpecReport().Failed() {
 				Expect(f.AsKubeAdmin.HasController.DeleteApplication(applicationName, testNamespace, false)).To(Succeed())
-				Expect(f.SandboxController.DeleteUserSignup(f.UserName)).To(BeTrue())
+				Expect(framework.ReleaseFramework(buildUserPoolPrefix, f)).To(Succeed())
 			}
 
 		})
@@ -1092,7 +1088,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 		BeforeAll(func() {
 			applicationName = fmt.Sprintf("test-app-%s", util.GenerateRandomString(4))
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.AcquireFramework(buildUserPoolPrefix)
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -1130,7 +1126,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				Expect(f.AsKubeAdmin.HasController.DeleteApplication(applicationName, testNamespace, false)).To(Succeed())
 				Expect(f.AsKubeAdmin.HasController.DeleteComponent(componentName, testNamespace, false)).To(Succeed())
 				Expect(f.AsKubeAdmin.TektonController.DeleteAllPipelineRunsInASpecificNamespace(testNamespace)).To(Succeed())
-				Expect(f.SandboxController.DeleteUserSignup(f.UserName)).To(BeTrue())
+				Expect(framework.ReleaseFramework(buildUserPoolPrefix, f)).To(Succeed())
 			}
 		})
 
@@ -1423,11 +1419,11 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		var parentPostPacMergeDigest string
 		var parentImageNameWithNoDigest string
 		const distributionRepository = "quay.io/redhat-appstudio-qe/release-repository"
-		quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "")
+		quayOrg := config.Get(constants.DEFAULT_QUAY_ORG_ENV)
 
 		var managedNamespace string
 		BeforeAll(func() {
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.AcquireFramework(buildUserPoolPrefix)
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -1488,7 +1484,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		AfterAll(func() {
 			if !CurrentSpecReport().Failed() {
 				Expect(f.AsKubeAdmin.HasController.DeleteApplication(applicationName, testNamespace, false)).To(Succeed())
-				Expect(f.SandboxController.DeleteUserSignup(f.UserName)).To(BeTrue())
+				Expect(framework.ReleaseFramework(buildUserPoolPrefix, f)).To(Succeed())
 			}
 			Expect(f.AsKubeAdmin.CommonController.DeleteNamespace(managedNamespace)).ShouldNot(HaveOccurred())
 
@@ -3,7 +3,6 @@ package build
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -17,9 +16,11 @@ import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/github"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
 	kubeapi "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/attestation"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/build"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/contract"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/pipeline"
@@ -52,18 +53,18 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 		var pipelineRunsWithE2eFinalizer []string
 
 		BeforeAll(func() {
-			if os.Getenv("APP_SUFFIX") != "" {
-				applicationName = fmt.Sprintf("test-app-%s", os.Getenv("APP_SUFFIX"))
+			if config.Get("APP_SUFFIX") != "" {
+				applicationName = fmt.Sprintf("test-app-%s", config.Get("APP_SUFFIX"))
 			} else {
 				applicationName = fmt.Sprintf("test-app-%s", util.GenerateRandomString(4))
 			}
-			testNamespace = os.Getenv(constants.E2E_APPLICATIONS_NAMESPACE_ENV)
+			testNamespace = config.Get(constants.E2E_APPLICATIONS_NAMESPACE_ENV)
 			if len(testNamespace) > 0 {
 				asAdminClient, err := kubeapi.NewAdminKubernetesClient()
 				Expect(err).ShouldNot(HaveOccurred())
 				kubeadminClient, err = framework.InitControllerHub(asAdminClient)
 				Expect(err).ShouldNot(HaveOccurred())
-				_, err = kubeadminClient.CommonController.CreateTestNamespace(testNamespace)
+				_, err = kubeadminClient.CommonController.CreateProvidedTestNamespace(testNamespace)
 				Expect(err).ShouldNot(HaveOccurred())
 			} else {
 				f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
@@ -131,7 +132,7 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 			if !CurrentSpecReport().Failed() {
 				// Clean up only Application CR (Component and Pipelines are included) in case we are targeting specific namespace
 				// Used e.g. in build-definitions e2e tests, where we are targeting build-templates-e2e namespace
-				if os.Getenv(constants.E2E_APPLICATIONS_NAMESPACE_ENV) != "" {
+				if config.Get(constants.E2E_APPLICATIONS_NAMESPACE_ENV) != "" {
 					DeferCleanup(kubeadminClient.HasController.DeleteApplication, applicationName, testNamespace, false)
 				} else {
 					Expect(kubeadminClient.TektonController.DeleteAllPipelineRunsInASpecificNamespace(testNamespace)).To(Succeed())
@@ -196,6 +197,14 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 				Expect(err).ShouldNot(HaveOccurred())
 				Expect(kubeadminClient.HasController.WaitForComponentPipelineToBeFinished(component, "",
 					kubeadminClient.TektonController, &has.RetryOptions{Retries: pipelineCompletionRetries, Always: true})).To(Succeed())
+
+				pr, err := kubeadminClient.HasController.GetComponentPipelineRun(componentNames[i], applicationName, testNamespace, "")
+				Expect(err).ShouldNot(HaveOccurred())
+				if trace, err := kubeadminClient.TektonController.StorePipelineRunTrace(pr); err != nil {
+					GinkgoWriter.Printf("failed to store trace of PipelineRun %s: %v\n", pr.GetName(), err)
+				} else {
+					GinkgoWriter.Println(trace.Gantt(100))
+				}
 			})
 
 			It(fmt.Sprintf("should ensure SBOM is shown for component with Git source URL %s", gitUrl), Label(buildTemplatesTestLabel), func() {
@@ -289,7 +298,7 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 					Expect(err).NotTo(HaveOccurred())
 
 					regProxyUrl := fmt.Sprintf("%s/plugins/tekton-results", f.ProxyUrl)
-					resultClient = pipeline.NewClient(regProxyUrl, f.UserToken)
+					resultClient = pipeline.NewClient(regProxyUrl, f.UserToken, pipeline.WithInsecureSkipVerify())
 
 					pr, err = kubeadminClient.HasController.GetComponentPipelineRun(componentNames[i], applicationName, testNamespace, "")
 					Expect(err).ShouldNot(HaveOccurred())
@@ -367,6 +376,7 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 					// to sign and attest the image built in BeforeAll.
 					err = kubeadminClient.TektonController.AwaitAttestationAndSignature(imageWithDigest, chainsAttestationTimeout)
 					Expect(err).ToNot(HaveOccurred())
+					Expect(kubeadminClient.TektonController.VerifyAttestationAndSignature(imageWithDigest)).To(Succeed())
 
 					cm, err := kubeadminClient.CommonController.GetConfigMap("ec-defaults", "enterprise-contract-service")
 					Expect(err).ToNot(HaveOccurred())
@@ -469,8 +479,28 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 
 					for _, dependency := range purl.ImageContents.Dependencies {
 						Expect(dependency.Purl).ToNot(BeEmpty())
+						_, err := build.ParsePurl(dependency.Purl)
+						Expect(err).NotTo(HaveOccurred())
 					}
 				})
+				It("has SLSA provenance matching the component source", Label(buildTemplatesTestLabel), func() {
+					Expect(kubeadminClient.TektonController.AwaitAttestationAndSignature(imageWithDigest, chainsAttestationTimeout)).To(Succeed())
+
+					pipelineRun, err := kubeadminClient.HasController.GetComponentPipelineRun(componentNames[i], applicationName, testNamespace, "")
+					Expect(err).ToNot(HaveOccurred())
+					revision := pipelineRun.Annotations["build.appstudio.redhat.com/commit_sha"]
+					Expect(revision).ToNot(BeEmpty())
+
+					provenance, err := kubeadminClient.TektonController.GetImageProvenance(imageWithDigest)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(provenance).To(And(
+						attestation.HaveBuilderID(ContainSubstring("tekton.dev/chains")),
+						attestation.HaveBuildType(ContainSubstring("PipelineRun")),
+						attestation.HaveSubjectDigest(imageWithDigest),
+						attestation.HaveGitMaterial(gitUrl, revision),
+						attestation.HaveInvocationParameter("git-url", ContainSubstring(strings.TrimSuffix(gitUrl, ".git"))),
+					))
+				})
 			})
 
 			Context("build-definitions ec pipelines", Label(buildTemplatesTestLabel), func() {
@@ -486,8 +516,6 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 
 				defaultGHOrg := "redhat-appstudio"
 				defaultGHRepo := "build-definitions"
-				defaultGitURL := fmt.Sprintf("https://github.com/%s/%s", defaultGHOrg, defaultGHRepo)
-				defaultGitRevision := "main"
 
 				BeforeAll(func() {
 					// If we are testing the changes from a pull request, APP_SUFFIX may contain the
@@ -497,17 +525,17 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 					// the source repo URL: https://issues.redhat.com/browse/SRVKP-3427. Once that's
 					// implemented, remove the APP_SUFFIX support below and simply rely on the other
 					// environment variables to set the git revision and URL directly.
-					appSuffix := os.Getenv("APP_SUFFIX")
+					appSuffix := config.Get("APP_SUFFIX")
 					if pullRequestID, err := strconv.ParseInt(appSuffix, 10, 64); err == nil {
-						gh, err := github.NewGithubClient(utils.GetEnv(constants.GITHUB_TOKEN_ENV, ""), defaultGHOrg)
+						gh, err := github.NewGithubClient(config.Get(constants.GITHUB_TOKEN_ENV), defaultGHOrg)
 						Expect(err).NotTo(HaveOccurred())
 						pullRequest, err := gh.GetPullRequest(defaultGHRepo, int(pullRequestID))
 						Expect(err).NotTo(HaveOccurred())
 						gitURL = *pullRequest.Head.Repo.CloneURL
 						gitRevision = *pullRequest.Head.Ref
 					} else {
-						gitRevision = utils.GetEnv(constants.EC_PIPELINES_REPO_REVISION_ENV, defaultGitRevision)
-						gitURL = utils.GetEnv(constants.EC_PIPELINES_REPO_URL_ENV, defaultGitURL)
+						gitRevision = config.Get(constants.EC_PIPELINES_REPO_REVISION_ENV)
+						gitURL = config.Get(constants.EC_PIPELINES_REPO_URL_ENV)
 					}
 
 					// Double check that the component has finished. There's an earlier test that
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 )
 
 const (
@@ -17,6 +17,8 @@ const (
 	dummyPipelineBundleRef           = "quay.io/redhat-appstudio-qe/dummy-pipeline-bundle@sha256:9805fc3f309af8f838622e49d3e7705d8364eb5c8287043d5725f3ef12232f24"
 	buildTemplatesTestLabel          = "build-templates-e2e"
 	buildTemplatesKcpTestLabel       = "build-templates-kcp-e2e"
+	// users of the pool are reused by containers of the build suite which don't leave configuration in the namespace
+	buildUserPoolPrefix = "build-e2e"
 
 	helloWorldComponentGitSourceRepoName = "devfile-sample-hello-world"
 	helloWorldComponentDefaultBranch     = "default"
@@ -46,9 +48,9 @@ const (
 )
 
 var (
-	componentUrls                   = strings.Split(utils.GetEnv(COMPONENT_REPO_URLS_ENV, pythonComponentGitSourceURL), ",") //multiple urls
+	componentUrls                   = strings.Split(config.Get(COMPONENT_REPO_URLS_ENV), ",") //multiple urls
 	componentNames                  []string
-	gihubOrg                        = utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
+	gihubOrg                        = config.Get(constants.GITHUB_E2E_ORGANIZATION_ENV)
 	helloWorldComponentGitSourceURL = fmt.Sprintf(githubUrlFormat, gihubOrg, helloWorldComponentGitSourceRepoName)
 	annotationsTestGitSourceURL     = fmt.Sprintf(githubUrlFormat, gihubOrg, annotationsTestGitSourceRepoName)
 	multiComponentGitSourceURL      = fmt.Sprintf(githubUrlFormat, gihubOrg, multiComponentGitSourceRepoName)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
+	e2eConfig "github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 	"golang.org/x/crypto/ssh"
 	v1 "k8s.io/api/core/v1"
@@ -40,11 +41,12 @@ const (
 )
 
 var (
-	multiPlatformProjectGitUrl   = utils.GetEnv("MULTI_PLATFORM_TEST_REPO_URL", "https://github.com/devfile-samples/devfile-sample-go-basic")
-	multiPlatformProjectRevision = utils.GetEnv("MULTI_PLATFORM_TEST_REPO_REVISION", "c713067b0e65fb3de50d1f7c457eb51c2ab0dbb0")
+	multiPlatformProjectGitUrl   = e2eConfig.Get("MULTI_PLATFORM_TEST_REPO_URL")
+	multiPlatformProjectRevision = e2eConfig.Get("MULTI_PLATFORM_TEST_REPO_REVISION")
 )
 
-var _ = framework.MultiPlatformBuildSuiteDescribe("Multi Platform Controller E2E tests", Label("multi-platform"), func() {
+// The host-config ConfigMap of the multi platform controller is shared by the whole cluster
+var _ = framework.MultiPlatformBuildSuiteDescribe("Multi Platform Controller E2E tests", Label("multi-platform"), framework.SerialOnly, func() {
 	var f *framework.Framework
 	AfterEach(framework.ReportFailure(&f))
 	var err error
@@ -100,12 +102,12 @@ var _ = framework.MultiPlatformBuildSuiteDescribe("Multi Platform Controller E2E
 		keys.Name = SecretName
 		keys.Namespace = ControllerNamespace
 		keys.Labels = map[string]string{"build.appstudio.redhat.com/multi-platform-secret": "true"}
-		keys.StringData = map[string]string{"id_rsa": os.Getenv("MULTI_PLATFORM_AWS_SSH_KEY")}
+		keys.StringData = map[string]string{"id_rsa": e2eConfig.Get("MULTI_PLATFORM_AWS_SSH_KEY")}
 		_, err = f.AsKubeAdmin.CommonController.CreateSecret(ControllerNamespace, &keys)
 		Expect(err).ShouldNot(HaveOccurred())
 
 		trueBool := true
-		customBuildahRemotePipeline := os.Getenv(constants.CUSTOM_BUILDAH_REMOTE_PIPELINE_BUILD_BUNDLE_ENV)
+		customBuildahRemotePipeline := e2eConfig.Get(constants.CUSTOM_BUILDAH_REMOTE_PIPELINE_BUILD_BUNDLE_ENV)
 		Expect(customBuildahRemotePipeline).ShouldNot(BeEmpty())
 		ps := &buildservice.BuildPipelineSelector{
 			ObjectMeta: metav1.ObjectMeta{
@@ -227,7 +229,7 @@ var _ = framework.MultiPlatformBuildSuiteDescribe("Multi Platform Controller E2E
 		It("test that cleanup happened successfully", func() {
 
 			// Parse the private key
-			signer, err := ssh.ParsePrivateKey([]byte(os.Getenv("MULTI_PLATFORM_AWS_SSH_KEY")))
+			signer, err := ssh.ParsePrivateKey([]byte(e2eConfig.Get("MULTI_PLATFORM_AWS_SSH_KEY")))
 			if err != nil {
 				log.Fatalf("Unable to parse private key: %v", err)
 			}
@@ -308,5 +310,5 @@ type EnvCredentialsProvider struct {
 }
 
 func (r EnvCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
-	return aws.Credentials{AccessKeyID: os.Getenv("MULTI_PLATFORM_AWS_ACCESS_KEY"), SecretAccessKey: os.Getenv("MULTI_PLATFORM_AWS_SECRET_ACCESS_KEY")}, nil
+	return aws.Credentials{AccessKeyID: e2eConfig.Get("MULTI_PLATFORM_AWS_ACCESS_KEY"), SecretAccessKey: e2eConfig.Get("MULTI_PLATFORM_AWS_SECRET_ACCESS_KEY")}, nil
 }
@@ -7,14 +7,16 @@ import (
 	"time"
 
 	"github.com/devfile/library/v2/pkg/util"
-	"github.com/magefile/mage/sh"
 	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
 	. "github.com/onsi/gomega"
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
 	client "github.com/redhat-appstudio/e2e-tests/pkg/clients/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/vcluster"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
+	"github.com/redhat-appstudio/e2e-tests/pkg/suites"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/gitops"
 	appsv1 "k8s.io/api/apps/v1"
@@ -86,6 +88,9 @@ var _ = framework.ByocSuiteDescribe(Label("byoc"), Ordered, func() {
 
 		Describe(suite.Name, func() {
 			BeforeAll(func() {
+				if suite.Byoc.ClusterType == appservice.ConfigurationClusterType_Kubernetes {
+					framework.SkipUnlessPrerequisites(suites.Binary(vcluster.VCLUSTER_BIN))
+				}
 				fw, err = framework.NewFramework(utils.GetGeneratedNamespace("byoc"))
 				Expect(err).NotTo(HaveOccurred())
 
@@ -97,8 +102,6 @@ var _ = framework.ByocSuiteDescribe(Label("byoc"), Ordered, func() {
 				Expect(kubeIngressDomain).NotTo(BeEmpty(), "domain is not present in the cluster. Make sure your openshift cluster has the domain defined in ingress cluster object")
 
 				if suite.Byoc.ClusterType == appservice.ConfigurationClusterType_Kubernetes {
-					Expect(sh.Run("which", "vcluster")).To(Succeed(), "please install vcluster locally in order to run kubernetes suite")
-
 					vc = vcluster.NewVclusterController(fmt.Sprintf("%s/tmp", rootPath), fw.AsKubeAdmin.CommonController.CustomClient)
 
 					byocKubeconfig, err = vc.InitializeVCluster(fw.UserNamespace, fw.UserNamespace, kubeIngressDomain)
@@ -106,13 +109,17 @@ var _ = framework.ByocSuiteDescribe(Label("byoc"), Ordered, func() {
 					Expect(byocKubeconfig).NotTo(BeEmpty(), "failed to initialize vcluster. Kubeconfig not provided")
 
 				} else if suite.Byoc.ClusterType == appservice.ConfigurationClusterType_OpenShift {
-					byocKubeconfig = utils.GetEnv("BYOC_KUBECONFIG", "")
+					byocKubeconfig = config.Get("BYOC_KUBECONFIG")
 					Expect(byocKubeconfig).NotTo(BeEmpty(), "Please provide BYOC_KUBECONFIG env pointing to a valid openshift kubeconfig file")
 				}
 			})
 
 			// Remove all resources created by the tests in case the suite was successfull
 			AfterAll(func() {
+				// the scenario is skipped if its prerequisites are not met
+				if CurrentSpecReport().State.Is(types.SpecStateSkipped) {
+					return
+				}
 				if !CurrentSpecReport().Failed() {
 					// RHTAPBUGS-978: temporary timeout to 15min
 					if err := fw.AsKubeAdmin.HasController.DeleteAllComponentsInASpecificNamespace(fw.UserNamespace, 15*time.Minute); err != nil {
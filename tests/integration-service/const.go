@@ -3,8 +3,8 @@ package integration
 import (
 	"fmt"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 )
 
 const (
@@ -35,5 +35,5 @@ const (
 )
 
 var (
-	componentGitSourceURLForStatusReporting = fmt.Sprintf("https://github.com/%s/%s", utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe"), componentRepoNameForStatusReporting)
+	componentGitSourceURLForStatusReporting = fmt.Sprintf("https://github.com/%s/%s", config.Get(constants.GITHUB_E2E_ORGANIZATION_ENV), componentRepoNameForStatusReporting)
 )
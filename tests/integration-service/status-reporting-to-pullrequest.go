@@ -2,11 +2,9 @@ package integration
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
-	"github.com/devfile/library/v2/pkg/util"
 	"github.com/google/go-github/v44/github"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
@@ -39,9 +37,7 @@ var _ = framework.IntegrationServiceSuiteDescribe("Status Reporting of Integrati
 
 	Describe("with status reporting of Integration tests in CheckRuns", Ordered, func() {
 		BeforeAll(func() {
-			if os.Getenv(constants.SKIP_PAC_TESTS_ENV) == "true" {
-				Skip("Skipping this test due to configuration issue with Spray proxy")
-			}
+			framework.SkipUnlessPipelinesAsCode()
 
 			f, err = framework.NewFramework(utils.GetGeneratedNamespace("stat-rep"))
 			Expect(err).NotTo(HaveOccurred())
@@ -63,9 +59,9 @@ var _ = framework.IntegrationServiceSuiteDescribe("Status Reporting of Integrati
 			integrationTestScenarioFail, err = f.AsKubeAdmin.IntegrationController.CreateIntegrationTestScenario(applicationName, testNamespace, gitURL, revision, pathInRepoFail)
 			Expect(err).ShouldNot(HaveOccurred())
 
-			componentName = fmt.Sprintf("%s-%s", "test-component-pac", util.GenerateRandomString(6))
+			componentName = framework.UniqueName("test-component-pac")
 			pacBranchName = constants.PaCPullRequestBranchPrefix + componentName
-			componentBaseBranchName = fmt.Sprintf("base-%s", util.GenerateRandomString(6))
+			componentBaseBranchName = framework.UniqueName("base")
 
 			err = f.AsKubeAdmin.CommonController.Github.CreateRef(componentRepoNameForStatusReporting, componentDefaultBranch, componentRevision, componentBaseBranchName)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -69,6 +69,7 @@ else
         --disable-metrics="${DISABLE_METRICS:-false}" \
         --pushgateway-url "${PUSHGATEWAY_URL:-rhtapqe.com}" \
         --enable-progress-bars="${ENABLE_PROGRESS_BARS:-false}" \
+        --collect-pipeline-traces="${COLLECT_PIPELINE_TRACES:-false}" \
         --pipeline-skip-initial-checks="${PIPELINE_SKIP_INITIAL_CHECKS:-true}"
 
     DRY_RUN=false ./clear.sh "$USER_PREFIX"
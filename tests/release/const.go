@@ -1,7 +1,7 @@
 package common
 
 import (
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	corev1 "k8s.io/api/core/v1"
 	"time"
 )
@@ -47,6 +47,6 @@ var ManagednamespaceSecret = []corev1.ObjectReference{
 
 // Pipelines variables
 var (
-	RelSvcCatalogURL      string = utils.GetEnv("RELEASE_SERVICE_CATALOG_URL", "https://github.com/redhat-appstudio/release-service-catalog")
-	RelSvcCatalogRevision string = utils.GetEnv("RELEASE_SERVICE_CATALOG_REVISION", "staging")
+	RelSvcCatalogURL      string = config.Get("RELEASE_SERVICE_CATALOG_URL")
+	RelSvcCatalogRevision string = config.Get("RELEASE_SERVICE_CATALOG_REVISION")
 )
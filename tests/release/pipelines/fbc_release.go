@@ -3,7 +3,6 @@ package pipelines
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/devfile/library/v2/pkg/util"
@@ -12,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -35,8 +35,8 @@ const (
 var _ = framework.ReleasePipelinesSuiteDescribe("FBC e2e-tests", Label("release-pipelines", "fbc-tests"), func() {
 	defer GinkgoRecover()
 
-	var devWorkspace = utils.GetEnv(constants.RELEASE_DEV_WORKSPACE_ENV, constants.DevReleaseTeam)
-	var managedWorkspace = utils.GetEnv(constants.RELEASE_MANAGED_WORKSPACE_ENV, constants.ManagedReleaseTeam)
+	var devWorkspace = config.Get(constants.RELEASE_DEV_WORKSPACE_ENV)
+	var managedWorkspace = config.Get(constants.RELEASE_MANAGED_WORKSPACE_ENV)
 
 	var devNamespace = devWorkspace + "-tenant"
 	var managedNamespace = managedWorkspace + "-tenant"
@@ -66,9 +66,9 @@ var _ = framework.ReleasePipelinesSuiteDescribe("FBC e2e-tests", Label("release-
 	AfterEach(framework.ReportFailure(&devFw))
 
 	stageOptions := utils.Options{
-		ToolchainApiUrl: os.Getenv(constants.TOOLCHAIN_API_URL_ENV),
-		KeycloakUrl:     os.Getenv(constants.KEYLOAK_URL_ENV),
-		OfflineToken:    os.Getenv(constants.OFFLINE_TOKEN_ENV),
+		ToolchainApiUrl: config.Get(constants.TOOLCHAIN_API_URL_ENV),
+		KeycloakUrl:     config.Get(constants.KEYLOAK_URL_ENV),
+		OfflineToken:    config.Get(constants.OFFLINE_TOKEN_ENV),
 	}
 
 	Describe("with FBC happy path", Label("fbcHappyPath"), func() {
@@ -4,7 +4,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"os"
 	"regexp"
 	"time"
 
@@ -14,6 +13,7 @@ import (
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/release"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -59,13 +59,13 @@ var _ = framework.ReleasePipelinesSuiteDescribe("[HACBS-1571]test-release-e2e-pu
 		_, err = fw.AsKubeAdmin.CommonController.CreateTestNamespace(managedNamespace)
 		Expect(err).NotTo(HaveOccurred(), "Error when creating managedNamespace")
 
-		sourceAuthJson := utils.GetEnv("QUAY_TOKEN", "")
+		sourceAuthJson := config.Get("QUAY_TOKEN")
 		Expect(sourceAuthJson).ToNot(BeEmpty())
 
-		keyPyxisStage := os.Getenv(constants.PYXIS_STAGE_KEY_ENV)
+		keyPyxisStage := config.Get(constants.PYXIS_STAGE_KEY_ENV)
 		Expect(keyPyxisStage).ToNot(BeEmpty())
 
-		certPyxisStage := os.Getenv(constants.PYXIS_STAGE_CERT_ENV)
+		certPyxisStage := config.Get(constants.PYXIS_STAGE_CERT_ENV)
 		Expect(certPyxisStage).ToNot(BeEmpty())
 
 		// Create secret for the release registry repo "hacbs-release-tests".
@@ -5,14 +5,14 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	quay "github.com/redhat-appstudio/image-controller/pkg/quay"
 )
 
 var (
 	quayApiUrl = "https://quay.io/api/v1"
-	quayOrg    = utils.GetEnv("IMAGE_CONTROLLER_QUAY_ORG", "hacbs-release-tests")
-	quayToken  = utils.GetEnv("IMAGE_CONTROLLER_QUAY_ORG_TOKEN", "")
+	quayOrg    = config.Get("IMAGE_CONTROLLER_QUAY_ORG")
+	quayToken  = config.Get("IMAGE_CONTROLLER_QUAY_ORG_TOKEN")
 	quayClient = quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayToken, quayApiUrl)
 )
 
@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
+	"github.com/redhat-appstudio/e2e-tests/pkg/matchers"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	releasecommon "github.com/redhat-appstudio/e2e-tests/tests/release"
 	releaseApi "github.com/redhat-appstudio/release-service/api/v1alpha1"
@@ -96,7 +97,7 @@ var _ = framework.ReleaseServiceSuiteDescribe("ReleasePlan and ReleasePlanAdmiss
 					return nil
 				}, releasecommon.ReleasePlanStatusUpdateTimeout, releasecommon.DefaultInterval).Should(Succeed())
 				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
-				Expect(releasePlanCR.Status.ReleasePlanAdmission.Name).To(Equal(managedNamespace + "/" + releasecommon.TargetReleasePlanAdmissionName))
+				Expect(releasePlanCR).To(matchers.HaveMatchedReleasePlanAdmission(managedNamespace + "/" + releasecommon.TargetReleasePlanAdmissionName))
 				Expect(releasePlanCR.Status.ReleasePlanAdmission.Active).To(BeTrue())
 			})
 
@@ -141,7 +142,7 @@ var _ = framework.ReleaseServiceSuiteDescribe("ReleasePlan and ReleasePlanAdmiss
 					return nil
 				}, releasecommon.ReleasePlanStatusUpdateTimeout, releasecommon.DefaultInterval).Should(Succeed())
 				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
-				Expect(secondReleasePlanCR.Status.ReleasePlanAdmission.Name).To(Equal(managedNamespace + "/" + releasecommon.TargetReleasePlanAdmissionName))
+				Expect(secondReleasePlanCR).To(matchers.HaveMatchedReleasePlanAdmission(managedNamespace + "/" + releasecommon.TargetReleasePlanAdmissionName))
 				Expect(secondReleasePlanCR.Status.ReleasePlanAdmission.Active).To(BeTrue())
 			})
 
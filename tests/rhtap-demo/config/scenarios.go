@@ -3,8 +3,8 @@ package config
 import (
 	"fmt"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 )
 
 // All multiple components scenarios are supported in the next jira: https://issues.redhat.com/browse/DEVHAS-305
@@ -24,7 +24,7 @@ var TestScenarios = []TestSpec{
 			{
 				Name:                       "rhtap-demo-component",
 				Language:                   "Java",
-				GitSourceUrl:               fmt.Sprintf("https://github.com/%s/%s", utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe"), "hacbs-test-project"),
+				GitSourceUrl:               fmt.Sprintf("https://github.com/%s/%s", config.Get(constants.GITHUB_E2E_ORGANIZATION_ENV), "hacbs-test-project"),
 				GitSourceRevision:          "34da5a8f51fba6a8b7ec75a727d3c72ebb5e1274",
 				GitSourceContext:           "",
 				GitSourceDefaultBranchName: "main",
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -16,13 +15,13 @@ import (
 	pipelineclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 	"k8s.io/client-go/kubernetes"
 
-	"github.com/devfile/library/v2/pkg/util"
 	ecp "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
 	"github.com/google/go-github/v44/github"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/clients/has"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -112,10 +111,10 @@ var _ = framework.RhtapDemoSuiteDescribe(func() {
 			Describe(appTest.Name, Ordered, func() {
 				BeforeAll(func() {
 					if strings.Contains(GinkgoLabelFilter(), stageEnvTestLabel) {
-						token = utils.GetEnv("STAGEUSER_TOKEN", "")
-						ssourl = utils.GetEnv("STAGE_SSOURL", "")
-						apiurl = utils.GetEnv("STAGE_APIURL", "")
-						username := utils.GetEnv("STAGE_USERNAME", "")
+						token = config.Get("STAGEUSER_TOKEN")
+						ssourl = config.Get("STAGE_SSOURL")
+						apiurl = config.Get("STAGE_APIURL")
+						username := config.Get("STAGE_USERNAME")
 						fw, err = framework.NewFrameworkWithTimeout(username, stageTimeout, utils.Options{
 							ToolchainApiUrl: apiurl,
 							KeycloakUrl:     ssourl,
@@ -141,7 +140,7 @@ var _ = framework.RhtapDemoSuiteDescribe(func() {
 						err := fw.AsKubeAdmin.CommonController.GetResourceQuotaInfo(devEnvTestLabel, namespace, "appstudio-crds-spi")
 						Expect(err).NotTo(HaveOccurred())
 
-						if !(strings.EqualFold(os.Getenv("E2E_SKIP_CLEANUP"), "true")) && !CurrentSpecReport().Failed() { // RHTAPBUGS-978: temporary timeout to 15min
+						if !(strings.EqualFold(config.Get("E2E_SKIP_CLEANUP"), "true")) && !CurrentSpecReport().Failed() { // RHTAPBUGS-978: temporary timeout to 15min
 							if err := fw.AsKubeAdmin.HasController.DeleteAllComponentsInASpecificNamespace(namespace, 15*time.Minute); err != nil {
 								if err := fw.AsKubeAdmin.StoreAllArtifactsForNamespace(namespace); err != nil {
 									Fail(fmt.Sprintf("error archiving artifacts:\n%s", err))
@@ -215,11 +214,11 @@ var _ = framework.RhtapDemoSuiteDescribe(func() {
 							// Inject spi tokens to work with private components
 							if componentSpec.ContainerSource != "" {
 								// More info about manual token upload for quay.io here: https://github.com/redhat-appstudio/service-provider-integration-operator/pull/115
-								oauthCredentials := `{"access_token":"` + utils.GetEnv(constants.QUAY_OAUTH_TOKEN_ENV, "") + `", "username":"` + utils.GetEnv(constants.QUAY_OAUTH_USER_ENV, "") + `"}`
+								oauthCredentials := `{"access_token":"` + config.Get(constants.QUAY_OAUTH_TOKEN_ENV) + `", "username":"` + config.Get(constants.QUAY_OAUTH_USER_ENV) + `"}`
 
 								_ = fw.AsKubeAdmin.SPIController.InjectManualSPIToken(namespace, componentSpec.ContainerSource, oauthCredentials, corev1.SecretTypeDockerConfigJson, SPIQuaySecretName)
 							}
-							githubCredentials := `{"access_token":"` + utils.GetEnv(constants.GITHUB_TOKEN_ENV, "") + `"}`
+							githubCredentials := `{"access_token":"` + config.Get(constants.GITHUB_TOKEN_ENV) + `"}`
 							_ = fw.AsKubeDeveloper.SPIController.InjectManualSPIToken(namespace, componentSpec.GitSourceUrl, githubCredentials, corev1.SecretTypeBasicAuth, SPIGithubSecretName)
 						})
 					}
@@ -230,7 +229,7 @@ var _ = framework.RhtapDemoSuiteDescribe(func() {
 						// we need to create a new branch that we will target
 						// and that will contain the PaC configuration, so we can avoid polluting the default (main) branch
 						if componentSpec.AdvancedBuildSpec != nil {
-							componentNewBaseBranch = fmt.Sprintf("base-%s", util.GenerateRandomString(6))
+							componentNewBaseBranch = framework.UniqueName("base")
 							gitRevision = componentNewBaseBranch
 							Expect(fw.AsKubeAdmin.CommonController.Github.CreateRef(componentRepositoryName, componentSpec.GitSourceDefaultBranchName, componentSpec.GitSourceRevision, componentNewBaseBranch)).To(Succeed())
 						}
@@ -382,9 +381,7 @@ var _ = framework.RhtapDemoSuiteDescribe(func() {
 							var mergeResult *github.PullRequestMergeResult
 
 							BeforeAll(func() {
-								if os.Getenv(constants.SKIP_PAC_TESTS_ENV) == "true" {
-									Skip("Skipping this test due to configuration issue with Spray proxy")
-								}
+								framework.SkipUnlessPipelinesAsCode()
 								managedNamespace = fw.UserNamespace + "-managed"
 								component = componentList[0]
 
@@ -2,11 +2,11 @@ package spi
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -36,7 +36,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "get-file-content-rs"), fu
 
 	Describe("SVPI-402 - Get file content from a private Github repository with Remote Secret", Ordered, func() {
 		BeforeAll(func() {
-			if os.Getenv("CI") != "true" {
+			if config.Get("CI") != "true" {
 				Skip(fmt.Sprintln("test skipped on local execution"))
 			}
 			// Initialize the tests controllers
@@ -77,7 +77,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "get-file-content-rs"), fu
 
 		It("creates upload secret", func() {
 			data := map[string]string{
-				"password": utils.GetEnv(constants.GITHUB_TOKEN_ENV, ""),
+				"password": config.Get(constants.GITHUB_TOKEN_ENV),
 			}
 
 			_, err = fw.AsKubeAdmin.RemoteSecretController.CreateUploadSecret(remoteSecret.Name, namespace, remoteSecret.Name, v1.SecretTypeBasicAuth, data)
@@ -2,11 +2,11 @@ package spi
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -32,7 +32,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "get-file-content"), Pendi
 
 	Describe("SVPI-402 - Get file content from a private Github repository with SPIAccessToken", Ordered, func() {
 		BeforeAll(func() {
-			if os.Getenv("CI") != "true" {
+			if config.Get("CI") != "true" {
 				Skip(fmt.Sprintln("test skipped on local execution"))
 			}
 			// Initialize the tests controllers
@@ -94,7 +94,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "get-file-content"), Pendi
 			Expect(err).NotTo(HaveOccurred())
 
 			// build and upload the payload using the uploadURL. it should return 204
-			oauthCredentials := `{"access_token":"` + utils.GetEnv(constants.GITHUB_TOKEN_ENV, "") + `"}`
+			oauthCredentials := `{"access_token":"` + config.Get(constants.GITHUB_TOKEN_ENV) + `"}`
 			statusCode, err := fw.AsKubeDeveloper.SPIController.UploadWithRestEndpoint(uploadURL, oauthCredentials, bearerToken)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(statusCode).Should(Equal(204))
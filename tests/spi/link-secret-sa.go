@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/service-provider-integration-operator/api/v1beta1"
@@ -127,7 +128,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "link-secret-sa"), func()
 				Expect(err).NotTo(HaveOccurred())
 
 				// build and upload the payload using the uploadURL. it should return 204
-				oauthCredentials := `{"access_token":"` + utils.GetEnv(constants.GITHUB_TOKEN_ENV, "") + `"}`
+				oauthCredentials := `{"access_token":"` + config.Get(constants.GITHUB_TOKEN_ENV) + `"}`
 				statusCode, err := fw.AsKubeDeveloper.SPIController.UploadWithRestEndpoint(uploadURL, oauthCredentials, bearerToken)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(statusCode).Should(Equal(204))
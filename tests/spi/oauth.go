@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/url"
-	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/service-provider-integration-operator/api/v1beta1"
@@ -41,7 +41,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "gh-oauth-flow"), func() {
 	Describe("SVPI-395 - Github OAuth flow to upload token", Pending, Ordered, func() {
 		BeforeAll(func() {
 
-			if os.Getenv("CI") != "true" {
+			if config.Get("CI") != "true" {
 				Skip(fmt.Sprintln("test skipped on local execution"))
 			}
 			// Initialize the tests controllers
@@ -50,13 +50,13 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "gh-oauth-flow"), func() {
 			namespace = fw.UserNamespace
 			Expect(namespace).NotTo(BeEmpty())
 
-			CYPRESS_GH_USER = utils.GetEnv("CYPRESS_GH_USER", "")
+			CYPRESS_GH_USER = config.Get("CYPRESS_GH_USER")
 			Expect(CYPRESS_GH_USER).NotTo(BeEmpty(), "Please provide CYPRESS_GH_USER")
 
-			CYPRESS_GH_PASSWORD = utils.GetEnv("CYPRESS_GH_PASSWORD", "")
+			CYPRESS_GH_PASSWORD = config.Get("CYPRESS_GH_PASSWORD")
 			Expect(CYPRESS_GH_PASSWORD).NotTo(BeEmpty(), "Please provide CYPRESS_GH_PASSWORD")
 
-			CYPRESS_GH_2FA_CODE = utils.GetEnv("CYPRESS_GH_2FA_CODE", "")
+			CYPRESS_GH_2FA_CODE = config.Get("CYPRESS_GH_2FA_CODE")
 			Expect(CYPRESS_GH_2FA_CODE).NotTo(BeEmpty(), "Please provide CYPRESS_GH_2FA_CODE env")
 
 		})
@@ -64,7 +64,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "gh-oauth-flow"), func() {
 		// Clean up after running these tests and before the next tests block: can't have multiple AccessTokens in Injected phase
 		AfterAll(func() {
 
-			artifactDir := utils.GetEnv("ARTIFACT_DIR", "")
+			artifactDir := config.Get("ARTIFACT_DIR")
 			if artifactDir != "" {
 				// collect cypress recording from the pod and save it in the artifacts folder
 				err := utils.ExecuteCommandInASpecificDirectory("kubectl", []string{"cp", cypressPodName + ":/cypress-browser-oauth-flow/cypress/videos", artifactDir + "/cypress/spi-oauth/", "-n", namespace}, "")
@@ -82,9 +82,9 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "gh-oauth-flow"), func() {
 		var SPITokenBinding *v1beta1.SPIAccessTokenBinding
 		var CYPRESS_SPI_OAUTH_URL string
 		tokenBindingName := "spi-token-binding-oauth-"
-		OAUTH_REDIRECT_PROXY_URL := utils.GetEnv("OAUTH_REDIRECT_PROXY_URL", "")
+		OAUTH_REDIRECT_PROXY_URL := config.Get("OAUTH_REDIRECT_PROXY_URL")
 
-		if utils.GetEnv("CI", "") == "true" {
+		if config.Get("CI") == "true" {
 			/*
 				If we are running this test in CI, we need to handle the dynamic url the cluster is assigned with.
 				To do that, we use a redirect proxy that allows us to have a static oauth url in the providers configuration and, at the same time,
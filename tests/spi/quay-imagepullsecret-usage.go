@@ -3,13 +3,13 @@ package spi
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -45,7 +45,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "quay-imagepullsecret-usag
 
 	Describe("SVPI-407 - Check ImagePullSecret usage for the private Quay image", Ordered, func() {
 		BeforeAll(func() {
-			if os.Getenv("CI") != "true" {
+			if config.Get("CI") != "true" {
 				Skip(fmt.Sprintln("test skipped on local execution"))
 			}
 			// Initialize the tests controllers
@@ -59,8 +59,8 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "quay-imagepullsecret-usag
 			Expect(err).NotTo(HaveOccurred())
 
 			// Quay username and token are required by SPI to generate valid credentials
-			QuayAuthToken = utils.GetEnv(constants.QUAY_OAUTH_TOKEN_ENV, "")
-			QuayAuthUser = utils.GetEnv(constants.QUAY_OAUTH_USER_ENV, "")
+			QuayAuthToken = config.Get(constants.QUAY_OAUTH_TOKEN_ENV)
+			QuayAuthUser = config.Get(constants.QUAY_OAUTH_USER_ENV)
 			Expect(QuayAuthToken).NotTo(BeEmpty())
 			Expect(QuayAuthUser).NotTo(BeEmpty())
 		})
@@ -128,7 +128,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "quay-imagepullsecret-usag
 			Expect(err).NotTo(HaveOccurred())
 
 			// build and upload the payload using the uploadURL. it should return 204
-			oauthCredentials := `{"access_token":"` + utils.GetEnv(constants.QUAY_OAUTH_TOKEN_ENV, "") + `", "username":"` + utils.GetEnv(constants.QUAY_OAUTH_USER_ENV, "") + `"}`
+			oauthCredentials := `{"access_token":"` + config.Get(constants.QUAY_OAUTH_TOKEN_ENV) + `", "username":"` + config.Get(constants.QUAY_OAUTH_USER_ENV) + `"}`
 			statusCode, err := fw.AsKubeDeveloper.SPIController.UploadWithRestEndpoint(uploadURL, oauthCredentials, bearerToken)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(statusCode).Should(Equal(204))
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redhat-appstudio/e2e-tests/pkg/config"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/service-provider-integration-operator/api/v1beta1"
@@ -94,7 +95,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "token-upload-k8s"), func(
 			}, 1*time.Minute, 5*time.Second).ShouldNot(BeEmpty(), fmt.Sprintf("SPITokenBinding %s/%s '.Status.LinkedAccessTokenName' field should not be empty", SPITokenBinding.GetNamespace(), SPITokenBinding.GetName()))
 
 			linkedAccessTokenName := SPITokenBinding.Status.LinkedAccessTokenName
-			tokenData := utils.GetEnv(constants.GITHUB_TOKEN_ENV, "")
+			tokenData := config.Get(constants.GITHUB_TOKEN_ENV)
 			Expect(tokenData).NotTo(BeEmpty())
 
 			K8sSecret, err = fw.AsKubeDeveloper.SPIController.UploadWithK8sSecret(secretName, namespace, linkedAccessTokenName, RepoURL, "", tokenData)
@@ -165,7 +166,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "token-upload-k8s"), func(
 		nonExistingAccessTokenName := "new-access-token-k8s"
 
 		It("creates secret with access token and associate it to an existing SPIAccessToken", func() {
-			tokenData := utils.GetEnv(constants.GITHUB_TOKEN_ENV, "")
+			tokenData := config.Get(constants.GITHUB_TOKEN_ENV)
 			Expect(tokenData).NotTo(BeEmpty())
 
 			K8sSecret, err = fw.AsKubeDeveloper.SPIController.UploadWithK8sSecret(secretName, namespace, nonExistingAccessTokenName, RepoURL, "", tokenData)